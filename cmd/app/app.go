@@ -12,6 +12,7 @@ type application struct {
 	Cfg         *config.Config
 	Logger      *slog.Logger
 	SubsRepo    repos.SubscriptionRepository
+	KeysRepo    repos.IdempotencyKeyRepository
 	SubsService subservice.SubscriptionsService
 }
 
@@ -45,6 +46,12 @@ func WithRepo(r repos.SubscriptionRepository) option {
 	}
 }
 
+func WithIdempotencyRepo(r repos.IdempotencyKeyRepository) option {
+	return func(app *application) {
+		app.KeysRepo = r
+	}
+}
+
 func WithSubsService(s subservice.SubscriptionsService) option {
 	return func(app *application) {
 		app.SubsService = s
@@ -6,6 +6,7 @@ import (
 	"syscall"
 
 	"github.com/shrtyk/subscriptions-service/internal/config"
+	"github.com/shrtyk/subscriptions-service/internal/core/authz"
 	"github.com/shrtyk/subscriptions-service/internal/core/subservice"
 	"github.com/shrtyk/subscriptions-service/internal/infra/postgres"
 	"github.com/shrtyk/subscriptions-service/internal/infra/postgres/tx"
@@ -18,13 +19,19 @@ func main() {
 	db := postgres.MustCreateConnectionPool(&cfg.PostgresCfg)
 
 	subsRepo := postgres.NewSubsRepo(db, &cfg.RepoCfg)
+	ratesRepo := postgres.NewRatesRepo(db)
+	keysRepo := postgres.NewIdempotencyRepo(db, &cfg.RepoCfg)
+	apiKeysRepo := postgres.NewAPIKeysRepo(db)
+	auditRepo := postgres.NewAuditRepo(db, &cfg.RepoCfg)
 	txProvider := tx.NewProvider(db, &cfg.RepoCfg)
-	subsService := subservice.New(subsRepo, txProvider)
+	authorizer := authz.MustCreatePolicy(&cfg.PolicyCfg)
+	subsService := subservice.New(subsRepo, ratesRepo, apiKeysRepo, auditRepo, authorizer, txProvider)
 
 	app := NewApplication(
 		WithConfig(cfg),
 		WithLogger(l),
 		WithRepo(subsRepo),
+		WithIdempotencyRepo(keysRepo),
 		WithSubsService(subsService),
 	)
 
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"log/slog"
 	"net/http"
+	"time"
 
 	appHttp "github.com/shrtyk/subscriptions-service/internal/api/http"
 	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
@@ -12,17 +13,27 @@ import (
 )
 
 func (app *application) Serve(ctx context.Context) {
-	mws := appHttp.NewMiddlewaresProvider(app.Logger)
-	h := appHttp.NewHandler(app.SubsService)
+	mws := appHttp.NewMiddlewaresProvider(
+		app.Logger,
+		appHttp.MustCreateTokenVerifier(&app.Cfg.AuthCfg),
+		app.SubsService,
+		appHttp.MustCreateTenantResolver(&app.Cfg.AuthCfg),
+	)
+	h := appHttp.WithScopes(appHttp.NewHandler(app.SubsService, &app.Cfg.HttpCfg))
 
 	server := http.Server{
 		Addr: ":" + app.Cfg.HttpCfg.Port,
 		Handler: dto.HandlerWithOptions(h, dto.ChiServerOptions{
 			BaseURL:     "/api/v1",
-			Middlewares: []dto.MiddlewareFunc{mws.PanicRecoveryMW, mws.LoggingMW},
+			Middlewares: []dto.MiddlewareFunc{mws.DateFormatMW, mws.AuthMW, mws.PanicRecoveryMW, mws.LoggingMW},
 		}),
+		ReadTimeout:  app.Cfg.HttpCfg.ReadTimeout,
+		WriteTimeout: app.Cfg.HttpCfg.WriteTimeout,
+		IdleTimeout:  app.Cfg.HttpCfg.IdleTimeout,
 	}
 
+	go app.purgeIdempotencyKeys(ctx)
+
 	eChan := make(chan error, 1)
 	go func() {
 		<-ctx.Done()
@@ -51,3 +62,24 @@ func (app *application) Serve(ctx context.Context) {
 
 	app.Logger.Info("graceful shutdown completed successfully")
 }
+
+// purgeIdempotencyKeys deletes expired idempotency keys every purge interval
+// until ctx is done.
+func (app *application) purgeIdempotencyKeys(ctx context.Context) {
+	ticker := time.NewTicker(app.Cfg.AppCfg.IdempotencyPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := app.KeysRepo.PurgeExpired(ctx)
+			if err != nil {
+				app.Logger.Error("failed to purge idempotency keys", log.WithErr(err))
+				continue
+			}
+			app.Logger.Debug("purged idempotency keys", slog.Int64("count", n))
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+)
+
+func (h *handler) ListApiKeys(w http.ResponseWriter, r *http.Request) {
+	keys, err := h.service.ListAPIKeys(r.Context())
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	dtoKeys := make([]dto.ApiKey, 0, len(keys))
+	for _, key := range keys {
+		dtoKeys = append(dtoKeys, *toAPIKeyDTO(&key))
+	}
+
+	err = WriteJSON(w, dtoKeys, http.StatusOK, nil)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+	}
+}
+
+func (h *handler) CreateApiKey(w http.ResponseWriter, r *http.Request) {
+	var body dto.NewApiKey
+	if err := ReadJSON(w, r, &body); err != nil {
+		WriteHTTPError(w, r, BadRequestError(err))
+		return
+	}
+
+	scopes, err := validateNewAPIKey(&body)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	key, secret, err := h.service.MintAPIKey(r.Context(), strings.TrimSpace(body.Name), scopes, body.ExpiresAt)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	created := toAPIKeyDTO(key)
+	err = WriteJSON(w, dto.CreatedApiKey{
+		Id:         created.Id,
+		Name:       created.Name,
+		Prefix:     created.Prefix,
+		Scopes:     created.Scopes,
+		ExpiresAt:  created.ExpiresAt,
+		CreatedAt:  created.CreatedAt,
+		LastUsedAt: created.LastUsedAt,
+		RevokedAt:  created.RevokedAt,
+		Key:        secret,
+	}, http.StatusCreated, nil)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+	}
+}
+
+func (h *handler) RevokeApiKey(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	if err := h.service.RevokeAPIKey(r.Context(), id); err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func toAPIKeyDTO(key *domain.APIKey) *dto.ApiKey {
+	scopes := make([]dto.ApiKeyScope, len(key.Scopes))
+	for i, scope := range key.Scopes {
+		scopes[i] = dto.ApiKeyScope(scope)
+	}
+	return &dto.ApiKey{
+		Id:         key.ID,
+		Name:       key.Name,
+		Prefix:     key.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  key.ExpiresAt,
+		CreatedAt:  key.CreatedAt,
+		LastUsedAt: key.LastUsedAt,
+		RevokedAt:  key.RevokedAt,
+	}
+}
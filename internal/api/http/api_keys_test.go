@@ -0,0 +1,202 @@
+package http
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_CreateApiKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := adminCtx()
+	createdAt := time.Date(2025, 12, 22, 10, 0, 0, 0, time.UTC)
+	key := &domain.APIKey{
+		ID:        uuid.New(),
+		Name:      "billing-jobs",
+		Prefix:    "sk_abcdefgh",
+		Hash:      "hash",
+		Scopes:    []domain.Scope{domain.ScopeRead},
+		CreatedAt: createdAt,
+	}
+
+	testCases := []struct {
+		name       string
+		body       io.Reader
+		setupMocks func(th testHarness)
+		wantCode   int
+		wantMsg    string
+	}{
+		{
+			name: "Success",
+			body: strings.NewReader(`{"name": " billing-jobs ", "scopes": ["read"]}`),
+			setupMocks: func(th testHarness) {
+				th.service.On("MintAPIKey", ctx, "billing-jobs", []domain.Scope{domain.ScopeRead}, (*time.Time)(nil)).
+					Return(key, "sk_abcdefgh-secret", nil).Once()
+			},
+			wantCode: http.StatusCreated,
+		},
+		{
+			name:     "Malformed JSON",
+			body:     strings.NewReader(`{"name":`),
+			wantCode: http.StatusBadRequest,
+		},
+		{
+			name:     "Blank name",
+			body:     strings.NewReader(`{"name": " ", "scopes": ["read"]}`),
+			wantCode: http.StatusUnprocessableEntity,
+			wantMsg:  apiKeyNameMsg,
+		},
+		{
+			name:     "No scopes",
+			body:     strings.NewReader(`{"name": "billing-jobs", "scopes": []}`),
+			wantCode: http.StatusUnprocessableEntity,
+			wantMsg:  apiKeyScopesMsg,
+		},
+		{
+			name:     "Unknown scope",
+			body:     strings.NewReader(`{"name": "billing-jobs", "scopes": ["root"]}`),
+			wantCode: http.StatusUnprocessableEntity,
+			wantMsg:  invalidScopeMsg,
+		},
+		{
+			name:     "Past expiry",
+			body:     strings.NewReader(`{"name": "billing-jobs", "scopes": ["read"], "expires_at": "2020-01-01T00:00:00Z"}`),
+			wantCode: http.StatusUnprocessableEntity,
+			wantMsg:  pastExpiryMsg,
+		},
+		{
+			name: "Service error",
+			body: strings.NewReader(`{"name": "billing-jobs", "scopes": ["read"]}`),
+			setupMocks: func(th testHarness) {
+				th.service.On("MintAPIKey", ctx, "billing-jobs", []domain.Scope{domain.ScopeRead}, (*time.Time)(nil)).
+					Return(nil, "", subservice.NewErr("subservice.MintAPIKey", subservice.KindUnknown)).Once()
+			},
+			wantCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			th := setup(t)
+			if tc.setupMocks != nil {
+				tc.setupMocks(th)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/admin/api_keys", tc.body)
+			rr := httptest.NewRecorder()
+
+			th.h.CreateApiKey(rr, req.WithContext(ctx))
+
+			assert.Equal(t, tc.wantCode, rr.Code, rr.Body.String())
+			if tc.wantMsg != "" {
+				var errBody dto.Error
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&errBody))
+				assert.Equal(t, tc.wantMsg, errBody.Message)
+			}
+			if tc.wantCode == http.StatusCreated {
+				var respBody dto.CreatedApiKey
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&respBody))
+				assert.Equal(t, dto.CreatedApiKey{
+					Id:        key.ID,
+					Name:      "billing-jobs",
+					Prefix:    "sk_abcdefgh",
+					Scopes:    []dto.ApiKeyScope{dto.Read},
+					CreatedAt: createdAt,
+					Key:       "sk_abcdefgh-secret",
+				}, respBody)
+			}
+		})
+	}
+}
+
+func TestHandler_ListApiKeys(t *testing.T) {
+	t.Parallel()
+
+	ctx := adminCtx()
+	revokedAt := time.Date(2025, 12, 23, 10, 0, 0, 0, time.UTC)
+	key := domain.APIKey{
+		ID:        uuid.New(),
+		Name:      "billing-jobs",
+		Prefix:    "sk_abcdefgh",
+		Hash:      "hash",
+		Scopes:    []domain.Scope{domain.ScopeRead, domain.ScopeWrite},
+		RevokedAt: &revokedAt,
+	}
+
+	th := setup(t)
+	th.service.On("ListAPIKeys", ctx).Return([]domain.APIKey{key}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/api_keys", nil)
+	rr := httptest.NewRecorder()
+
+	th.h.ListApiKeys(rr, req.WithContext(ctx))
+
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.NotContains(t, rr.Body.String(), "hash")
+	var respBody []dto.ApiKey
+	require.NoError(t, json.NewDecoder(rr.Body).Decode(&respBody))
+	assert.Equal(t, []dto.ApiKey{{
+		Id:        key.ID,
+		Name:      "billing-jobs",
+		Prefix:    "sk_abcdefgh",
+		Scopes:    []dto.ApiKeyScope{dto.Read, dto.Write},
+		RevokedAt: &revokedAt,
+	}}, respBody)
+}
+
+func TestHandler_RevokeApiKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := adminCtx()
+	id := uuid.New()
+
+	testCases := []struct {
+		name       string
+		serviceErr error
+		wantCode   int
+	}{
+		{
+			name:     "Success",
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:       "Not Found",
+			serviceErr: subservice.NewErr("subservice.RevokeAPIKey", subservice.KindNotFound),
+			wantCode:   http.StatusNotFound,
+		},
+		{
+			name:       "Forbidden",
+			serviceErr: subservice.NewErr("subservice.RevokeAPIKey", subservice.KindForbidden),
+			wantCode:   http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			th := setup(t)
+			th.service.On("RevokeAPIKey", ctx, id).Return(tc.serviceErr).Once()
+
+			req := httptest.NewRequest(http.MethodDelete, "/admin/api_keys/"+id.String(), nil)
+			rr := httptest.NewRecorder()
+
+			th.h.RevokeApiKey(rr, req.WithContext(ctx), id)
+			assert.Equal(t, tc.wantCode, rr.Code)
+		})
+	}
+}
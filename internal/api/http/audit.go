@@ -0,0 +1,133 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+)
+
+func (h *handler) ListSubscriptionHistory(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	entries, err := h.service.History(r.Context(), id)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	err = WriteJSON(w, toAuditEntryDTOs(entries, dateLayoutFromCtx(r.Context())), http.StatusOK, nil)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+	}
+}
+
+func (h *handler) ListAudit(w http.ResponseWriter, r *http.Request, params dto.ListAuditParams) {
+	filter, err := validateListAuditParams(params)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	page, err := h.service.ListAudit(r.Context(), filter)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	dtoPage := dto.AuditPage{Items: toAuditEntryDTOs(page.Items, dateLayoutFromCtx(r.Context()))}
+	if page.Next != nil {
+		cursor := strconv.FormatInt(*page.Next, 10)
+		dtoPage.NextCursor = &cursor
+	}
+
+	err = WriteJSON(w, dtoPage, http.StatusOK, nil)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+	}
+}
+
+// validateListAuditParams maps params to a filter. The cursor is the id of the
+// last entry of the previous page.
+func validateListAuditParams(params dto.ListAuditParams) (domain.AuditFilter, error) {
+	filter := domain.AuditFilter{
+		SubscriptionID: params.SubscriptionId,
+		ActorID:        params.ActorId,
+		APIKeyID:       params.ApiKeyId,
+		From:           params.From,
+		To:             params.To,
+		Limit:          params.Limit,
+	}
+	if params.Operation != nil {
+		op := domain.AuditOp(*params.Operation)
+		if !op.IsValid() {
+			return filter, &DTOValidationError{ClientMessage: invalidAuditOpMsg}
+		}
+		filter.Op = &op
+	}
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return filter, &DTOValidationError{ClientMessage: fromAfterToMsg}
+	}
+	if params.Cursor != nil {
+		before, err := strconv.ParseInt(*params.Cursor, 10, 64)
+		if err != nil || before <= 0 {
+			return filter, &DTOValidationError{ClientMessage: invalidCursorMsg, InternalError: err}
+		}
+		filter.Before = &before
+	}
+	return filter, nil
+}
+
+func toAuditEntryDTOs(entries []domain.AuditEntry, layout DateLayout) []dto.AuditEntry {
+	dtoEntries := make([]dto.AuditEntry, 0, len(entries))
+	for _, entry := range entries {
+		dtoEntries = append(dtoEntries, *toAuditEntryDTO(&entry, layout))
+	}
+	return dtoEntries
+}
+
+func toAuditEntryDTO(entry *domain.AuditEntry, layout DateLayout) *dto.AuditEntry {
+	d := &dto.AuditEntry{
+		Id:             entry.ID,
+		SubscriptionId: entry.SubscriptionID,
+		Operation:      dto.AuditOperation(entry.Op),
+		ActorRole:      string(entry.ActorRole),
+		ApiKeyId:       entry.APIKeyID,
+		RequestId:      entry.RequestID,
+		Before:         toSnapshotDTO(entry.Before, layout),
+		After:          toSnapshotDTO(entry.After, layout),
+		CreatedAt:      entry.CreatedAt,
+	}
+	if entry.ActorID != uuid.Nil {
+		d.ActorId = &entry.ActorID
+	}
+	return d
+}
+
+func toSnapshotDTO(sub *domain.Subscription, layout DateLayout) *dto.SubscriptionSnapshot {
+	if sub == nil {
+		return nil
+	}
+	s := toSubscriptionDTO(sub, layout)
+	d := &dto.SubscriptionSnapshot{
+		Id:            s.Id,
+		ServiceName:   s.ServiceName,
+		Price:         s.Price,
+		BillingPeriod: s.BillingPeriod,
+		MonthlyCost:   s.MonthlyCost,
+		Currency:      s.Currency,
+		UserId:        s.UserId,
+		StartDate:     s.StartDate,
+		EndDate:       s.EndDate,
+		TrialEnd:      s.TrialEnd,
+		Version:       sub.Version,
+	}
+	if sub.Pauses != nil {
+		pauses := make([]dto.Pause, 0, len(sub.Pauses))
+		for _, pause := range sub.Pauses {
+			pauses = append(pauses, *toPauseDTO(&pause, layout))
+		}
+		d.Pauses = &pauses
+	}
+	return d
+}
@@ -0,0 +1,197 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_ListSubscriptionHistory(t *testing.T) {
+	t.Parallel()
+
+	ctx := adminCtx()
+	subID := uuid.New()
+	actorID := uuid.New()
+	keyID := uuid.New()
+	createdAt := time.Date(2025, 12, 22, 10, 0, 0, 0, time.UTC)
+	before := &domain.Subscription{
+		ID: subID, ServiceName: "Okko", Price: 300, BillingPeriod: domain.BillingMonthly, Currency: "RUB",
+		UserID: actorID, StartDate: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC), Version: 1,
+	}
+	after := *before
+	after.Version = 2
+	after.Pauses = []domain.Pause{{PausedFrom: time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC), CreatedAt: createdAt}}
+
+	testCases := []struct {
+		name       string
+		setupMocks func(th testHarness)
+		assertFunc func(t *testing.T, rr *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Success",
+			setupMocks: func(th testHarness) {
+				th.service.On("History", ctx, subID).Return([]domain.AuditEntry{
+					{ID: 1, SubscriptionID: subID, Op: domain.AuditCreate, ActorID: actorID, ActorRole: domain.RoleEditor, RequestID: "req-1", After: before, CreatedAt: createdAt},
+					{ID: 2, SubscriptionID: subID, Op: domain.AuditPause, ActorRole: domain.RoleService, APIKeyID: &keyID, Before: before, After: &after, CreatedAt: createdAt},
+				}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rr.Code)
+				var respBody []dto.AuditEntry
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&respBody))
+				require.Len(t, respBody, 2)
+
+				assert.Equal(t, dto.AuditOperationCreate, respBody[0].Operation)
+				assert.Equal(t, &actorID, respBody[0].ActorId)
+				assert.Equal(t, "editor", respBody[0].ActorRole)
+				assert.Equal(t, "req-1", respBody[0].RequestId)
+				assert.Nil(t, respBody[0].Before)
+				require.NotNil(t, respBody[0].After)
+				assert.Equal(t, "03-2025", respBody[0].After.StartDate)
+				assert.Equal(t, 1, respBody[0].After.Version)
+				assert.Nil(t, respBody[0].After.Pauses)
+
+				assert.Nil(t, respBody[1].ActorId)
+				assert.Equal(t, &keyID, respBody[1].ApiKeyId)
+				require.NotNil(t, respBody[1].After.Pauses)
+				assert.Equal(t, "06-2025", (*respBody[1].After.Pauses)[0].PausedFrom)
+			},
+		},
+		{
+			name: "Not Found",
+			setupMocks: func(th testHarness) {
+				th.service.On("History", ctx, subID).Return(nil, subservice.NewErr("subservice.History", subservice.KindNotFound)).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotFound, rr.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			th := setup(t)
+			tc.setupMocks(th)
+
+			req := newRequestWithChiCtx(t, http.MethodGet, "/subscriptions/"+subID.String()+"/history", nil, map[string]string{"id": subID.String()})
+			rr := httptest.NewRecorder()
+
+			th.h.ListSubscriptionHistory(rr, req.WithContext(ctx), subID)
+
+			tc.assertFunc(t, rr)
+		})
+	}
+}
+
+func TestHandler_ListAudit(t *testing.T) {
+	t.Parallel()
+
+	ctx := adminCtx()
+	subID := uuid.New()
+	update := dto.AuditOperationUpdate
+	unknown := dto.AuditOperation("rename")
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	op := domain.AuditUpdate
+	next := int64(41)
+	cursor := "42"
+	invalidCursor := "abc"
+
+	testCases := []struct {
+		name       string
+		params     dto.ListAuditParams
+		setupMocks func(th testHarness)
+		wantCode   int
+		wantMsg    string
+		wantCursor *string
+	}{
+		{
+			name:   "Success",
+			params: dto.ListAuditParams{SubscriptionId: &subID, Operation: &update, From: &from, To: &to, Cursor: &cursor},
+			setupMocks: func(th testHarness) {
+				before := int64(42)
+				th.service.On("ListAudit", ctx, domain.AuditFilter{
+					SubscriptionID: &subID, Op: &op, From: &from, To: &to, Before: &before,
+				}).Return(&domain.AuditPage{
+					Items: []domain.AuditEntry{{ID: next, SubscriptionID: subID, Op: op}},
+					Next:  &next,
+				}, nil).Once()
+			},
+			wantCode:   http.StatusOK,
+			wantCursor: ptr("41"),
+		},
+		{
+			name: "Last page",
+			setupMocks: func(th testHarness) {
+				th.service.On("ListAudit", ctx, domain.AuditFilter{}).Return(&domain.AuditPage{Items: []domain.AuditEntry{}}, nil).Once()
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Unknown operation",
+			params:   dto.ListAuditParams{Operation: &unknown},
+			wantCode: http.StatusUnprocessableEntity,
+			wantMsg:  invalidAuditOpMsg,
+		},
+		{
+			name:     "From after to",
+			params:   dto.ListAuditParams{From: &to, To: &from},
+			wantCode: http.StatusUnprocessableEntity,
+			wantMsg:  fromAfterToMsg,
+		},
+		{
+			name:     "Invalid cursor",
+			params:   dto.ListAuditParams{Cursor: &invalidCursor},
+			wantCode: http.StatusUnprocessableEntity,
+			wantMsg:  invalidCursorMsg,
+		},
+		{
+			name: "Forbidden",
+			setupMocks: func(th testHarness) {
+				th.service.On("ListAudit", ctx, domain.AuditFilter{}).
+					Return(nil, subservice.NewErr("subservice.ListAudit", subservice.KindForbidden)).Once()
+			},
+			wantCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			th := setup(t)
+			if tc.setupMocks != nil {
+				tc.setupMocks(th)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/audit", nil)
+			rr := httptest.NewRecorder()
+
+			th.h.ListAudit(rr, req.WithContext(ctx), tc.params)
+
+			assert.Equal(t, tc.wantCode, rr.Code, rr.Body.String())
+			if tc.wantMsg != "" {
+				var errBody dto.Error
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&errBody))
+				assert.Equal(t, tc.wantMsg, errBody.Message)
+			}
+			if tc.wantCode == http.StatusOK {
+				var respBody dto.AuditPage
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&respBody))
+				assert.NotNil(t, respBody.Items)
+				assert.Equal(t, tc.wantCursor, respBody.NextCursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/netip"
+	"os"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/config"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
+	"github.com/shrtyk/subscriptions-service/pkg/errkit"
+	"github.com/shrtyk/subscriptions-service/pkg/jwt"
+	"github.com/shrtyk/subscriptions-service/pkg/log"
+)
+
+// minHMACSecretLen is the size of SHA-256 hashes, shorter HS256 secrets are
+// easier to guess than the signatures made with them (RFC 7518, section 3.2).
+const minHMACSecretLen = 32
+
+// MustCreateTokenVerifier loads the keys of cfg.
+func MustCreateTokenVerifier(cfg *config.AuthCfg) *jwt.Verifier {
+	opts := []jwt.Option{
+		jwt.WithIssuer(cfg.Issuer),
+		jwt.WithAudience(cfg.Audience),
+		jwt.WithLeeway(cfg.Leeway),
+	}
+
+	if cfg.HMACSecretPath != "" {
+		secret, err := os.ReadFile(cfg.HMACSecretPath)
+		if err != nil {
+			panic(fmt.Sprintf("failed to read HS256 secret: %s", err))
+		}
+		secret = bytes.TrimSpace(secret)
+		if len(secret) < minHMACSecretLen {
+			panic(fmt.Sprintf("HS256 secret should be at least %d bytes long", minHMACSecretLen))
+		}
+		opts = append(opts, jwt.WithHMACSecret(secret))
+	}
+
+	if cfg.JWKSPath != "" {
+		keys, err := jwt.LoadJWKS(cfg.JWKSPath)
+		if err != nil {
+			panic(fmt.Sprintf("failed to load JWK set: %s", err))
+		}
+		opts = append(opts, jwt.WithRSAKeys(keys))
+	}
+
+	return jwt.NewVerifier(opts...)
+}
+
+// TenantResolver picks the tenant of users whose token doesn't name one.
+type TenantResolver struct {
+	header   string
+	proxies  []netip.Prefix // Peers the header is taken from
+	fallback domain.TenantID
+}
+
+// MustCreateTenantResolver takes the tenant header, the proxies setting it and
+// the default tenant of cfg.
+func MustCreateTenantResolver(cfg *config.AuthCfg) TenantResolver {
+	fallback := domain.TenantID(cfg.DefaultTenant)
+	if fallback != "" && !fallback.IsValid() {
+		panic(fmt.Sprintf("invalid default tenant %q", cfg.DefaultTenant))
+	}
+	if cfg.TenantHeader != "" && len(cfg.TrustedProxies) == 0 {
+		panic("tenant header is set without trusted proxies")
+	}
+	proxies := make([]netip.Prefix, len(cfg.TrustedProxies))
+	for i, proxy := range cfg.TrustedProxies {
+		prefix, err := parseProxy(proxy)
+		if err != nil {
+			panic(fmt.Sprintf("invalid trusted proxy %q: %v", proxy, err))
+		}
+		proxies[i] = prefix
+	}
+	return TenantResolver{header: cfg.TenantHeader, proxies: proxies, fallback: fallback}
+}
+
+// parseProxy parses a CIDR or a single address.
+func parseProxy(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		return netip.ParsePrefix(s)
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()), nil
+}
+
+// resolve the tenant of r. The header of trusted proxies wins over the default
+// tenant, requests with neither are refused. Any other caller could pick a
+// tenant with the header, so it is ignored for them.
+func (tr TenantResolver) resolve(r *http.Request) (domain.TenantID, *HttpError) {
+	if tr.header != "" && tr.fromProxy(r) {
+		if v := r.Header.Get(tr.header); v != "" {
+			tenant := domain.TenantID(v)
+			if !tenant.IsValid() {
+				return "", NewHTTPError(http.StatusBadRequest, invalidTenantMsg, nil)
+			}
+			return tenant, nil
+		}
+	}
+	if tr.fallback == "" {
+		return "", NewHTTPError(http.StatusUnauthorized, missingTenantMsg, nil)
+	}
+	return tr.fallback, nil
+}
+
+// fromProxy reports whether r comes straight from one of the trusted proxies.
+func (tr TenantResolver) fromProxy(r *http.Request) bool {
+	peer, err := netip.ParseAddrPort(r.RemoteAddr)
+	if err != nil {
+		return false
+	}
+	addr := peer.Addr().Unmap()
+	for _, proxy := range tr.proxies {
+		if proxy.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenClaims are the claims of bearer tokens the principal is made of.
+type tokenClaims struct {
+	Subject  string `json:"sub"`
+	Role     string `json:"role"`
+	TenantID string `json:"tenant_id"`
+}
+
+// principal of the claims. Roles are left to the authorization policy, tokens
+// without one get RoleEditor. API keys alone act as RoleService.
+func (c *tokenClaims) principal() (*domain.Principal, error) {
+	userID, err := uuid.Parse(c.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("sub is not a user id: %w", err)
+	}
+	tenant := domain.TenantID(c.TenantID)
+	if tenant != "" && !tenant.IsValid() {
+		return nil, fmt.Errorf("tenant_id %q is not a tenant", c.TenantID)
+	}
+	switch role := domain.Role(c.Role); role {
+	case "":
+		return &domain.Principal{TenantID: tenant, UserID: userID, Role: domain.RoleEditor}, nil
+	case domain.RoleService:
+		return nil, fmt.Errorf("role %q is reserved for API keys", c.Role)
+	default:
+		return &domain.Principal{TenantID: tenant, UserID: userID, Role: role}, nil
+	}
+}
+
+// apiKeyAuthenticator looks up the API keys of requests.
+type apiKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, secret string) (*domain.APIKey, error)
+}
+
+// AuthMW authenticates requests with the Authorization header and puts their
+// principal in the context. It takes either a JWT, e.g. "Bearer <token>", or
+// an API key, e.g. "ApiKey <key>". API keys belong to a tenant, users to the
+// one of their tenant_id claim or else of the TenantResolver.
+func (m middlewares) AuthMW(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scheme, credentials, ok := authCredentials(r.Header.Get("Authorization"))
+		if !ok {
+			w.Header().Set("WWW-Authenticate", bearerScheme+", "+apiKeyScheme)
+			WriteHTTPError(w, r, NewHTTPError(http.StatusUnauthorized, missingTokenMsg, nil))
+			return
+		}
+
+		var p *domain.Principal
+		var err error
+		if scheme == apiKeyScheme {
+			p, err = m.apiKeyPrincipal(r.Context(), credentials)
+		} else {
+			p, err = m.tokenPrincipal(credentials)
+		}
+		if err != nil {
+			var httpErr *HttpError
+			if errors.As(err, &httpErr) {
+				WriteHTTPError(w, r, httpErr)
+				return
+			}
+			log.FromCtx(r.Context()).Debug("rejected credentials", log.WithErr(err))
+			w.Header().Set("WWW-Authenticate", scheme+` error="invalid_token"`)
+			msg := invalidTokenMsg
+			if scheme == apiKeyScheme {
+				msg = invalidAPIKeyMsg
+			}
+			WriteHTTPError(w, r, NewHTTPError(http.StatusUnauthorized, msg, err))
+			return
+		}
+		if p.TenantID == "" {
+			tenant, httpErr := m.tenants.resolve(r)
+			if httpErr != nil {
+				if httpErr.DTOErr.Code == http.StatusUnauthorized {
+					w.Header().Set("WWW-Authenticate", scheme+` error="invalid_token"`)
+				}
+				WriteHTTPError(w, r, httpErr)
+				return
+			}
+			p.TenantID = tenant
+		}
+
+		ctx := domain.PrincipalToCtx(r.Context(), p)
+		l := log.FromCtx(ctx)
+		if p.APIKeyID != nil {
+			l = l.With(slog.String("api_key_id", p.APIKeyID.String()))
+		} else {
+			l = l.With(slog.String("user_id", p.UserID.String()))
+		}
+		l = l.With(slog.String("tenant_id", string(p.TenantID)))
+		next.ServeHTTP(w, r.WithContext(log.ToCtx(ctx, l)))
+	})
+}
+
+func (m middlewares) tokenPrincipal(token string) (*domain.Principal, error) {
+	var claims tokenClaims
+	if err := m.tokens.Verify(token, &claims); err != nil {
+		return nil, err
+	}
+	return claims.principal()
+}
+
+// apiKeyPrincipal fails with an HTTPError when the key could not be looked up.
+func (m middlewares) apiKeyPrincipal(ctx context.Context, secret string) (*domain.Principal, error) {
+	key, err := m.keys.AuthenticateAPIKey(ctx, secret)
+	if err != nil {
+		var svcErr *errkit.BaseErr[subservice.ServiceKind]
+		if errors.As(err, &svcErr) && svcErr.Kind == subservice.KindNotFound {
+			return nil, err
+		}
+		return nil, InternalError(err)
+	}
+	return &domain.Principal{TenantID: key.TenantID, Role: domain.RoleService, APIKeyID: &key.ID, Scopes: key.Scopes}, nil
+}
+
+const (
+	bearerScheme = "Bearer"
+	apiKeyScheme = "ApiKey"
+)
+
+// authCredentials splits authorization into its scheme, bearerScheme or
+// apiKeyScheme, and credentials.
+func authCredentials(authorization string) (string, string, bool) {
+	scheme, credentials, ok := strings.Cut(authorization, " ")
+	if !ok {
+		return "", "", false
+	}
+	credentials = strings.TrimSpace(credentials)
+	switch {
+	case credentials == "":
+		return "", "", false
+	case strings.EqualFold(scheme, bearerScheme):
+		return bearerScheme, credentials, true
+	case strings.EqualFold(scheme, apiKeyScheme):
+		return apiKeyScheme, credentials, true
+	default:
+		return "", "", false
+	}
+}
+
+// principalFromCtx returns the caller authenticated by AuthMW. Requests that
+// didn't pass it are refused.
+func principalFromCtx(ctx context.Context) (*domain.Principal, error) {
+	p, ok := domain.PrincipalFromCtx(ctx)
+	if !ok {
+		return nil, &DTOValidationError{ClientMessage: missingTokenMsg, StatusCode: http.StatusUnauthorized}
+	}
+	return p, nil
+}
@@ -0,0 +1,359 @@
+package http
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
+	"github.com/shrtyk/subscriptions-service/internal/config"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
+	ssmocks "github.com/shrtyk/subscriptions-service/internal/core/ports/subservice/mocks"
+	"github.com/shrtyk/subscriptions-service/pkg/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var testTokenSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func signToken(t *testing.T, claims map[string]any) string {
+	t.Helper()
+	segment := func(v any) string {
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		return base64.RawURLEncoding.EncodeToString(b)
+	}
+	signed := segment(map[string]any{"alg": "HS256", "typ": "JWT"}) + "." + segment(claims)
+	mac := hmac.New(sha256.New, testTokenSecret)
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestMiddlewares_AuthMW(t *testing.T) {
+	t.Parallel()
+
+	userID := uuid.New()
+	exp := time.Now().Add(time.Hour).Unix()
+	apiKey := &domain.APIKey{ID: uuid.New(), TenantID: "acme", Scopes: []domain.Scope{domain.ScopeRead}}
+
+	tests := []struct {
+		name          string
+		authorization string
+		tenant        string
+		remoteAddr    string
+		wantCode      int
+		wantChallenge string
+		wantPrincipal *domain.Principal
+	}{
+		{
+			name:          "User",
+			authorization: "Bearer " + signToken(t, map[string]any{"sub": userID.String(), "aud": "subscriptions", "exp": exp}),
+			wantCode:      http.StatusOK,
+			wantPrincipal: &domain.Principal{TenantID: "default", UserID: userID, Role: domain.RoleEditor},
+		},
+		{
+			name:          "Admin",
+			authorization: "bearer " + signToken(t, map[string]any{"sub": userID.String(), "aud": "subscriptions", "exp": exp, "role": "admin"}),
+			wantCode:      http.StatusOK,
+			wantPrincipal: &domain.Principal{TenantID: "default", UserID: userID, Role: domain.RoleAdmin},
+		},
+		{
+			name:          "API key",
+			authorization: "ApiKey sk_live",
+			wantCode:      http.StatusOK,
+			wantPrincipal: &domain.Principal{TenantID: "acme", Role: domain.RoleService, APIKeyID: &apiKey.ID, Scopes: apiKey.Scopes},
+		},
+		{
+			name:          "API key ignores the tenant header",
+			authorization: "ApiKey sk_live",
+			tenant:        "globex",
+			wantCode:      http.StatusOK,
+			wantPrincipal: &domain.Principal{TenantID: "acme", Role: domain.RoleService, APIKeyID: &apiKey.ID, Scopes: apiKey.Scopes},
+		},
+		{
+			name:          "Tenant claim",
+			authorization: "Bearer " + signToken(t, map[string]any{"sub": userID.String(), "aud": "subscriptions", "exp": exp, "tenant_id": "acme"}),
+			tenant:        "globex",
+			wantCode:      http.StatusOK,
+			wantPrincipal: &domain.Principal{TenantID: "acme", UserID: userID, Role: domain.RoleEditor},
+		},
+		{
+			name:          "Tenant header",
+			authorization: "Bearer " + signToken(t, map[string]any{"sub": userID.String(), "aud": "subscriptions", "exp": exp}),
+			tenant:        "globex",
+			wantCode:      http.StatusOK,
+			wantPrincipal: &domain.Principal{TenantID: "globex", UserID: userID, Role: domain.RoleEditor},
+		},
+		{
+			name:          "Tenant header from an untrusted peer",
+			authorization: "Bearer " + signToken(t, map[string]any{"sub": userID.String(), "aud": "subscriptions", "exp": exp}),
+			tenant:        "globex",
+			remoteAddr:    "198.51.100.7:1234",
+			wantCode:      http.StatusOK,
+			wantPrincipal: &domain.Principal{TenantID: "default", UserID: userID, Role: domain.RoleEditor},
+		},
+		{
+			name:          "Invalid tenant claim",
+			authorization: "Bearer " + signToken(t, map[string]any{"sub": userID.String(), "aud": "subscriptions", "exp": exp, "tenant_id": "Acme Inc"}),
+			wantCode:      http.StatusUnauthorized,
+			wantChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			name:          "Invalid tenant header",
+			authorization: "Bearer " + signToken(t, map[string]any{"sub": userID.String(), "aud": "subscriptions", "exp": exp}),
+			tenant:        "../acme",
+			wantCode:      http.StatusBadRequest,
+		},
+		{
+			name:          "Missing token",
+			wantCode:      http.StatusUnauthorized,
+			wantChallenge: "Bearer, ApiKey",
+		},
+		{
+			name:          "Other scheme",
+			authorization: "Basic dXNlcjpwYXNz",
+			wantCode:      http.StatusUnauthorized,
+			wantChallenge: "Bearer, ApiKey",
+		},
+		{
+			name:          "Unknown API key",
+			authorization: "ApiKey sk_revoked",
+			wantCode:      http.StatusUnauthorized,
+			wantChallenge: `ApiKey error="invalid_token"`,
+		},
+		{
+			name:          "API key lookup fails",
+			authorization: "ApiKey sk_unlucky",
+			wantCode:      http.StatusInternalServerError,
+		},
+		{
+			name:          "Expired token",
+			authorization: "Bearer " + signToken(t, map[string]any{"sub": userID.String(), "aud": "subscriptions", "exp": time.Now().Add(-time.Hour).Unix()}),
+			wantCode:      http.StatusUnauthorized,
+			wantChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			name:          "Other audience",
+			authorization: "Bearer " + signToken(t, map[string]any{"sub": userID.String(), "aud": "billing", "exp": exp}),
+			wantCode:      http.StatusUnauthorized,
+			wantChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			name:          "Subject is not a user id",
+			authorization: "Bearer " + signToken(t, map[string]any{"sub": "alice", "aud": "subscriptions", "exp": exp}),
+			wantCode:      http.StatusUnauthorized,
+			wantChallenge: `Bearer error="invalid_token"`,
+		},
+		{
+			name:          "Role is left to the policy",
+			authorization: "Bearer " + signToken(t, map[string]any{"sub": userID.String(), "aud": "subscriptions", "exp": exp, "role": "finance"}),
+			wantCode:      http.StatusOK,
+			wantPrincipal: &domain.Principal{TenantID: "default", UserID: userID, Role: domain.RoleFinance},
+		},
+		{
+			name:          "Role of API keys",
+			authorization: "Bearer " + signToken(t, map[string]any{"sub": userID.String(), "aud": "subscriptions", "exp": exp, "role": "service"}),
+			wantCode:      http.StatusUnauthorized,
+			wantChallenge: `Bearer error="invalid_token"`,
+		},
+	}
+
+	keys := ssmocks.NewMockSubscriptionsService(t)
+	keys.On("AuthenticateAPIKey", mock.Anything, "sk_live").Return(apiKey, nil).Twice()
+	keys.On("AuthenticateAPIKey", mock.Anything, "sk_revoked").
+		Return(nil, subservice.NewErr("subservice.AuthenticateAPIKey", subservice.KindNotFound)).Once()
+	keys.On("AuthenticateAPIKey", mock.Anything, "sk_unlucky").Return(nil, errors.New("db is down")).Once()
+
+	mws := NewMiddlewaresProvider(slog.New(slog.DiscardHandler), jwt.NewVerifier(
+		jwt.WithHMACSecret(testTokenSecret),
+		jwt.WithAudience("subscriptions"),
+	), keys, TenantResolver{
+		header:   "X-Tenant-ID",
+		proxies:  []netip.Prefix{netip.MustParsePrefix("192.0.2.0/24")},
+		fallback: "default",
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotPrincipal *domain.Principal
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPrincipal, _ = domain.PrincipalFromCtx(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/subscriptions", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			if tt.tenant != "" {
+				req.Header.Set("X-Tenant-ID", tt.tenant)
+			}
+			if tt.remoteAddr != "" {
+				req.RemoteAddr = tt.remoteAddr
+			}
+			rr := httptest.NewRecorder()
+
+			mws.AuthMW(next).ServeHTTP(rr, req)
+
+			assert.Equal(t, tt.wantCode, rr.Code)
+			assert.Equal(t, tt.wantChallenge, rr.Header().Get("WWW-Authenticate"))
+			assert.Equal(t, tt.wantPrincipal, gotPrincipal)
+		})
+	}
+}
+
+func TestMiddlewares_AuthMW_NoDefaultTenant(t *testing.T) {
+	t.Parallel()
+
+	mws := NewMiddlewaresProvider(slog.New(slog.DiscardHandler), jwt.NewVerifier(
+		jwt.WithHMACSecret(testTokenSecret),
+	), nil, TenantResolver{})
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request without a tenant was let through")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/subscriptions", nil)
+	req.Header.Set("Authorization", "Bearer "+signToken(t, map[string]any{"sub": uuid.NewString(), "exp": time.Now().Add(time.Hour).Unix()}))
+	rr := httptest.NewRecorder()
+
+	mws.AuthMW(next).ServeHTTP(rr, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rr.Code)
+	assert.Contains(t, rr.Body.String(), missingTenantMsg)
+}
+
+func TestMustCreateTenantResolver(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		cfg       config.AuthCfg
+		wantPanic bool
+	}{
+		{name: "No header", cfg: config.AuthCfg{DefaultTenant: "default"}},
+		{name: "Header with proxies", cfg: config.AuthCfg{TenantHeader: "X-Tenant-ID", TrustedProxies: []string{"10.0.0.0/8", "192.0.2.1"}}},
+		{name: "Header without proxies", cfg: config.AuthCfg{TenantHeader: "X-Tenant-ID"}, wantPanic: true},
+		{name: "Invalid proxy", cfg: config.AuthCfg{TenantHeader: "X-Tenant-ID", TrustedProxies: []string{"proxy.local"}}, wantPanic: true},
+		{name: "Invalid default tenant", cfg: config.AuthCfg{DefaultTenant: "Acme Inc"}, wantPanic: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			create := func() { MustCreateTenantResolver(&tt.cfg) }
+			if tt.wantPanic {
+				assert.Panics(t, create)
+			} else {
+				assert.NotPanics(t, create)
+			}
+		})
+	}
+}
+
+func TestHandler_Forbidden(t *testing.T) {
+	t.Parallel()
+
+	callerID := uuid.New()
+	userCtx := domain.PrincipalToCtx(context.Background(), &domain.Principal{UserID: callerID, Role: domain.RoleViewer})
+	denied := subservice.WrapErr("subservice.Op", subservice.KindForbidden, errors.New("denied"))
+	subID := uuid.New()
+
+	testCases := []struct {
+		name       string
+		method     string
+		url        string
+		body       string
+		setupMocks func(th testHarness)
+		wantCode   int
+	}{
+		{
+			name:   "Get",
+			method: http.MethodGet,
+			url:    "/subscriptions/" + subID.String(),
+			setupMocks: func(th testHarness) {
+				th.service.On("GetByID", mock.Anything, subID).Return(nil, denied).Once()
+			},
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:   "Delete",
+			method: http.MethodDelete,
+			url:    "/subscriptions/" + subID.String(),
+			setupMocks: func(th testHarness) {
+				th.service.On("Delete", mock.Anything, subID, (*int)(nil)).Return(denied).Once()
+			},
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:   "Currency rates",
+			method: http.MethodGet,
+			url:    "/admin/currency_rates",
+			setupMocks: func(th testHarness) {
+				th.service.On("ListCurrencyRates", mock.Anything).Return(nil, denied).Once()
+			},
+			wantCode: http.StatusForbidden,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			th := setup(t)
+			tc.setupMocks(th)
+
+			req := httptest.NewRequest(tc.method, tc.url, strings.NewReader(tc.body))
+			rr := httptest.NewRecorder()
+
+			router := dto.HandlerFromMux(th.h, chi.NewRouter())
+			router.ServeHTTP(rr, req.WithContext(userCtx))
+
+			assert.Equal(t, tc.wantCode, rr.Code, rr.Body.String())
+			assert.Contains(t, rr.Body.String(), forbiddenMsg)
+		})
+	}
+
+	t.Run("Batch", func(t *testing.T) {
+		t.Parallel()
+
+		th := setup(t)
+		sub := &domain.Subscription{
+			ID: uuid.New(), ServiceName: "Okko", Price: 300, BillingPeriod: domain.BillingMonthly, Currency: "RUB",
+			UserID: callerID, StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Version: 1,
+		}
+		th.service.On("Batch", mock.Anything, mock.Anything, false).
+			Return([]domain.BatchResult{{Sub: sub}, {Err: denied}}, nil).Once()
+
+		body := `{"mode":"best_effort","operations":[` +
+			`{"op":"create","subscription":{"service_name":"Okko","price":300,"user_id":"` + callerID.String() + `","start_date":"01-2025"}},` +
+			`{"op":"delete","id":"` + subID.String() + `"}]}`
+		req := httptest.NewRequest(http.MethodPost, "/subscriptions:batch", strings.NewReader(body))
+		rr := httptest.NewRecorder()
+
+		router := dto.HandlerFromMux(th.h, chi.NewRouter())
+		router.ServeHTTP(rr, req.WithContext(userCtx))
+
+		require.Equal(t, http.StatusMultiStatus, rr.Code)
+		var resp dto.BatchResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&resp))
+		require.Len(t, resp.Results, 2)
+		assert.Equal(t, http.StatusCreated, resp.Results[0].Status)
+		assert.Equal(t, http.StatusForbidden, resp.Results[1].Status)
+	})
+}
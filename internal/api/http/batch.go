@@ -0,0 +1,169 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
+	"github.com/shrtyk/subscriptions-service/pkg/log"
+)
+
+func (h *handler) BatchSubscriptions(w http.ResponseWriter, r *http.Request) {
+	var body dto.BatchRequest
+	if err := ReadJSON(w, r, &body); err != nil {
+		WriteHTTPError(w, r, BadRequestError(err))
+		return
+	}
+
+	atomic, err := validateBatchRequest(&body, h.cfg.MaxBatchSize)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	// Operations that fail validation never reach the service,
+	// index maps the ones that do back to their position in the request.
+	results := make([]dto.BatchResult, len(body.Operations))
+	ops := make([]domain.BatchOp, 0, len(body.Operations))
+	index := make([]int, 0, len(body.Operations))
+	for i := range body.Operations {
+		op, err := fromBatchOperationDTO(&body.Operations[i])
+		if err != nil {
+			results[i] = batchErrResult(r, i, err)
+			continue
+		}
+		ops = append(ops, *op)
+		index = append(index, i)
+	}
+
+	if atomic && len(ops) < len(body.Operations) {
+		for _, i := range index {
+			results[i] = batchErrResult(r, i, subservice.ErrNotApplied)
+		}
+		writeBatchResponse(w, r, results, atomic)
+		return
+	}
+
+	applied, err := h.service.Batch(r.Context(), ops, atomic)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	layout := dateLayoutFromCtx(r.Context())
+	for j, res := range applied {
+		i := index[j]
+		if res.Err != nil {
+			results[i] = batchErrResult(r, i, res.Err)
+			continue
+		}
+		switch ops[j].Kind {
+		case domain.BatchCreate:
+			results[i] = dto.BatchResult{Status: http.StatusCreated, Subscription: toSubscriptionDTO(res.Sub, layout)}
+		case domain.BatchUpdate:
+			results[i] = dto.BatchResult{Status: http.StatusOK, Subscription: toSubscriptionDTO(res.Sub, layout)}
+		default:
+			results[i] = dto.BatchResult{Status: http.StatusNoContent}
+		}
+	}
+
+	writeBatchResponse(w, r, results, atomic)
+}
+
+// validateBatchRequest reports whether the batch is atomic.
+func validateBatchRequest(body *dto.BatchRequest, maxSize int) (bool, error) {
+	if len(body.Operations) == 0 {
+		return false, &DTOValidationError{ClientMessage: emptyBatchMsg}
+	}
+	if len(body.Operations) > maxSize {
+		return false, &DTOValidationError{
+			ClientMessage: fmt.Sprintf(batchTooLargeMsg, maxSize),
+			StatusCode:    http.StatusRequestEntityTooLarge,
+		}
+	}
+
+	return validateBatchMode(body.Mode)
+}
+
+// validateBatchMode reports whether mode is atomic, the default.
+func validateBatchMode(mode *dto.BatchMode) (bool, error) {
+	if mode == nil {
+		return true, nil
+	}
+	switch *mode {
+	case dto.Atomic:
+		return true, nil
+	case dto.BestEffort:
+		return false, nil
+	default:
+		return false, &DTOValidationError{ClientMessage: invalidBatchModeMsg}
+	}
+}
+
+func fromBatchOperationDTO(d *dto.BatchOperation) (*domain.BatchOp, error) {
+	switch d.Op {
+	case dto.BatchOperationOpCreate:
+		if d.Subscription == nil {
+			return nil, &DTOValidationError{ClientMessage: batchCreateMsg}
+		}
+		sub, err := fromNewSubscriptionDTO(d.Subscription)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.BatchOp{Kind: domain.BatchCreate, Sub: *sub}, nil
+	case dto.BatchOperationOpUpdate:
+		if d.Id == nil || d.Replacement == nil {
+			return nil, &DTOValidationError{ClientMessage: batchUpdateMsg}
+		}
+		update, err := fromReplaceSubscriptionDTO(d.Replacement)
+		if err != nil {
+			return nil, err
+		}
+		return &domain.BatchOp{Kind: domain.BatchUpdate, ID: uuid.UUID(*d.Id), Version: d.Version, Update: *update}, nil
+	case dto.BatchOperationOpDelete:
+		if d.Id == nil {
+			return nil, &DTOValidationError{ClientMessage: batchDeleteMsg}
+		}
+		return &domain.BatchOp{Kind: domain.BatchDelete, ID: uuid.UUID(*d.Id), Version: d.Version}, nil
+	default:
+		return nil, &DTOValidationError{ClientMessage: invalidBatchOpMsg}
+	}
+}
+
+// batchErrResult is the result of the operation at index i that failed with err,
+// with the status it would have gotten on its own.
+func batchErrResult(r *http.Request, i int, err error) dto.BatchResult {
+	httpErr := processAppError(err)
+	if errors.Is(err, subservice.ErrNotApplied) {
+		httpErr = NewHTTPError(http.StatusFailedDependency, "Not applied, another operation of the batch failed", err)
+	}
+	if httpErr.DTOErr.Code >= 500 {
+		log.FromCtx(r.Context()).Error("batch operation failed", slog.Int("index", i), log.WithErr(httpErr))
+	}
+	return dto.BatchResult{Status: int(httpErr.DTOErr.Code), Error: &httpErr.DTOErr}
+}
+
+// writeBatchResponse responds with 200 when all operations succeeded, 422 when
+// an atomic batch was rolled back and 207 for partially applied batches.
+func writeBatchResponse(w http.ResponseWriter, r *http.Request, results []dto.BatchResult, atomic bool) {
+	status := http.StatusOK
+	for _, res := range results {
+		if res.Error == nil {
+			continue
+		}
+		status = http.StatusMultiStatus
+		if atomic {
+			status = http.StatusUnprocessableEntity
+		}
+		break
+	}
+
+	if err := WriteJSON(w, dto.BatchResponse{Results: results}, status, nil); err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+	}
+}
@@ -0,0 +1,210 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_BatchSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	ctx := adminCtx()
+	userID := uuid.New()
+	subID := uuid.New()
+
+	newSub := dto.NewSubscription{ServiceName: "Okko", Price: ptr(400), UserId: userID, StartDate: "01-2025"}
+	createSub, err := fromNewSubscriptionDTO(&newSub)
+	require.NoError(t, err)
+	replacement := dto.ReplaceSubscription{ServiceName: "Okko", Price: 500, UserId: userID, StartDate: "01-2025"}
+	update, err := fromReplaceSubscriptionDTO(&replacement)
+	require.NoError(t, err)
+
+	createOp := dto.BatchOperation{Op: dto.BatchOperationOpCreate, Subscription: &newSub}
+	updateOp := dto.BatchOperation{Op: dto.BatchOperationOpUpdate, Id: &subID, Version: ptr(2), Replacement: &replacement}
+	deleteOp := dto.BatchOperation{Op: dto.BatchOperationOpDelete, Id: &subID}
+	ops := []domain.BatchOp{
+		{Kind: domain.BatchCreate, Sub: *createSub},
+		{Kind: domain.BatchUpdate, ID: subID, Version: ptr(2), Update: *update},
+		{Kind: domain.BatchDelete, ID: subID},
+	}
+
+	created := &domain.Subscription{ID: uuid.New(), ServiceName: "Okko", Price: 400, UserID: userID, StartDate: createSub.StartDate}
+	updated := &domain.Subscription{ID: subID, ServiceName: "Okko", Price: 500, UserID: userID, StartDate: createSub.StartDate}
+	serviceErrNotFound := subservice.NewErr("subservice.Batch", subservice.KindNotFound)
+
+	batch := func(mode *dto.BatchMode, ops ...dto.BatchOperation) io.Reader {
+		return mustMarshal(t, dto.BatchRequest{Mode: mode, Operations: ops})
+	}
+	decode := func(t *testing.T, rr *httptest.ResponseRecorder) []dto.BatchResult {
+		var body dto.BatchResponse
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+		return body.Results
+	}
+	statuses := func(results []dto.BatchResult) []int {
+		codes := make([]int, len(results))
+		for i, res := range results {
+			codes[i] = res.Status
+		}
+		return codes
+	}
+
+	testCases := []struct {
+		name       string
+		body       io.Reader
+		setupMocks func(th testHarness)
+		assertFunc func(t *testing.T, rr *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Success - Atomic",
+			body: batch(nil, createOp, updateOp, deleteOp),
+			setupMocks: func(th testHarness) {
+				th.service.On("Batch", ctx, ops, true).Return([]domain.BatchResult{{Sub: created}, {Sub: updated}, {}}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rr.Code)
+				results := decode(t, rr)
+				assert.Equal(t, []int{http.StatusCreated, http.StatusOK, http.StatusNoContent}, statuses(results))
+				assert.Equal(t, toSubscriptionDTO(created, dateLayout), results[0].Subscription)
+				assert.Equal(t, toSubscriptionDTO(updated, dateLayout), results[1].Subscription)
+				assert.Nil(t, results[2].Subscription)
+			},
+		},
+		{
+			name: "Atomic Rolled Back",
+			body: batch(ptr(dto.Atomic), createOp, updateOp, deleteOp),
+			setupMocks: func(th testHarness) {
+				th.service.On("Batch", ctx, ops, true).Return([]domain.BatchResult{
+					{Err: subservice.ErrNotApplied}, {Err: serviceErrNotFound}, {Err: subservice.ErrNotApplied},
+				}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+				results := decode(t, rr)
+				assert.Equal(t, []int{http.StatusFailedDependency, http.StatusNotFound, http.StatusFailedDependency}, statuses(results))
+				require.NotNil(t, results[1].Error)
+				assert.Equal(t, int32(http.StatusNotFound), results[1].Error.Code)
+			},
+		},
+		{
+			name: "Best Effort Partially Applied",
+			body: batch(ptr(dto.BestEffort), createOp, deleteOp),
+			setupMocks: func(th testHarness) {
+				th.service.On("Batch", ctx, []domain.BatchOp{ops[0], ops[2]}, false).
+					Return([]domain.BatchResult{{Sub: created}, {Err: serviceErrNotFound}}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusMultiStatus, rr.Code)
+				assert.Equal(t, []int{http.StatusCreated, http.StatusNotFound}, statuses(decode(t, rr)))
+			},
+		},
+		{
+			name: "Best Effort Delete At A Stale Version",
+			body: batch(ptr(dto.BestEffort), dto.BatchOperation{Op: dto.BatchOperationOpDelete, Id: &subID, Version: ptr(3)}),
+			setupMocks: func(th testHarness) {
+				th.service.On("Batch", ctx, []domain.BatchOp{{Kind: domain.BatchDelete, ID: subID, Version: ptr(3)}}, false).
+					Return([]domain.BatchResult{{Err: subservice.NewErr("subservice.Batch", subservice.KindConflict)}}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusMultiStatus, rr.Code)
+				assert.Equal(t, []int{http.StatusPreconditionFailed}, statuses(decode(t, rr)))
+			},
+		},
+		{
+			name: "Best Effort Skips Invalid Operations",
+			body: batch(ptr(dto.BestEffort), dto.BatchOperation{Op: dto.BatchOperationOpUpdate, Id: &subID}, deleteOp),
+			setupMocks: func(th testHarness) {
+				th.service.On("Batch", ctx, []domain.BatchOp{ops[2]}, false).Return([]domain.BatchResult{{}}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusMultiStatus, rr.Code)
+				results := decode(t, rr)
+				assert.Equal(t, []int{http.StatusUnprocessableEntity, http.StatusNoContent}, statuses(results))
+				assert.Equal(t, batchUpdateMsg, results[0].Error.Message)
+			},
+		},
+		{
+			name: "Atomic With Invalid Operation",
+			body: batch(nil, createOp, dto.BatchOperation{Op: "merge"}),
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+				results := decode(t, rr)
+				assert.Equal(t, []int{http.StatusFailedDependency, http.StatusUnprocessableEntity}, statuses(results))
+				assert.Equal(t, invalidBatchOpMsg, results[1].Error.Message)
+			},
+		},
+		{
+			name: "Empty Batch",
+			body: batch(nil),
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var errBody dto.Error
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&errBody))
+				assert.Equal(t, int32(http.StatusUnprocessableEntity), errBody.Code)
+				assert.Equal(t, emptyBatchMsg, errBody.Message)
+			},
+		},
+		{
+			name: "Too Many Operations",
+			body: batch(nil, deleteOp, deleteOp, deleteOp, deleteOp),
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+			},
+		},
+		{
+			name: "Invalid Mode",
+			body: batch(ptr(dto.BatchMode("eventual")), deleteOp),
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var errBody dto.Error
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&errBody))
+				assert.Equal(t, invalidBatchModeMsg, errBody.Message)
+			},
+		},
+		{
+			name: "Bad Request - Invalid JSON",
+			body: strings.NewReader("{invalid json"),
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rr.Code)
+			},
+		},
+		{
+			name: "Service Error",
+			body: batch(nil, deleteOp),
+			setupMocks: func(th testHarness) {
+				th.service.On("Batch", ctx, []domain.BatchOp{ops[2]}, true).
+					Return(nil, subservice.WrapErr("subservice.Batch", subservice.KindUnknown, errors.New("db is down"))).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, rr.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			th := setup(t)
+			if tc.setupMocks != nil {
+				tc.setupMocks(th)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/subscriptions:batch", tc.body)
+			rr := httptest.NewRecorder()
+
+			th.h.BatchSubscriptions(rr, req.WithContext(ctx))
+
+			tc.assertFunc(t, rr)
+		})
+	}
+}
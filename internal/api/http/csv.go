@@ -0,0 +1,333 @@
+package http
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
+	"github.com/shrtyk/subscriptions-service/pkg/log"
+)
+
+const csvMediaType = "text/csv"
+
+var (
+	// csvColumns are the columns of exports, in order.
+	csvColumns = []string{
+		"id", "service_name", "price", "billing_period", "monthly_cost", "currency",
+		"user_id", "start_date", "end_date", "trial_end", "version",
+	}
+	requiredCSVColumns = []string{"service_name", "price", "user_id", "start_date"}
+)
+
+func (h *handler) ImportSubscriptions(w http.ResponseWriter, r *http.Request, params dto.ImportSubscriptionsParams) {
+	if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != csvMediaType {
+		WriteHTTPError(w, r, processAppError(&DTOValidationError{
+			ClientMessage: unsupportedImportMsg,
+			StatusCode:    http.StatusUnsupportedMediaType,
+		}))
+		return
+	}
+
+	atomic, err := validateBatchMode(params.Mode)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+	dryRun := params.DryRun != nil && *params.DryRun
+
+	rows, err := readImportRows(http.MaxBytesReader(w, r.Body, int64(maxRequestBodyBytes)), h.cfg.MaxImportRows)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	report := dto.ImportReport{DryRun: dryRun, Rows: len(rows), Errors: []dto.ImportRowError{}}
+	ops := make([]domain.BatchOp, 0, len(rows))
+	lines := make([]int, 0, len(rows))
+	for _, row := range rows {
+		if row.err != nil {
+			addImportErr(r, &report, row.line, row.err)
+			continue
+		}
+		ops = append(ops, *row.op)
+		lines = append(lines, row.line)
+	}
+
+	if dryRun || len(ops) == 0 || (atomic && report.Failed > 0) {
+		if !atomic || report.Failed == 0 {
+			for _, op := range ops {
+				countImported(&report, op.Kind)
+			}
+		}
+		writeImportReport(w, r, &report, atomic)
+		return
+	}
+
+	applied, err := h.service.Batch(r.Context(), ops, atomic)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+	for j, res := range applied {
+		switch {
+		case res.Err == nil:
+			countImported(&report, ops[j].Kind)
+		case !errors.Is(res.Err, subservice.ErrNotApplied):
+			addImportErr(r, &report, lines[j], res.Err)
+		}
+	}
+
+	writeImportReport(w, r, &report, atomic)
+}
+
+// importRow is a data row of an import, either as the operation it makes or the
+// error that makes it invalid.
+type importRow struct {
+	line int
+	op   *domain.BatchOp
+	err  error
+}
+
+// readImportRows reads the rows of a CSV import with a header row, up to maxRows.
+// Malformed CSV fails the whole import, invalid rows only themselves.
+func readImportRows(body io.Reader, maxRows int) ([]importRow, error) {
+	reader := csv.NewReader(body)
+
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, &DTOValidationError{ClientMessage: "body must not be empty", StatusCode: http.StatusBadRequest}
+		}
+		return nil, csvReadErr(err)
+	}
+	cols, err := importColumns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+			return nil, csvReadErr(err)
+		}
+		if len(rows) == maxRows {
+			return nil, &DTOValidationError{
+				ClientMessage: fmt.Sprintf(importTooLargeMsg, maxRows),
+				StatusCode:    http.StatusRequestEntityTooLarge,
+			}
+		}
+
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			rows = append(rows, importRow{line: line, err: &DTOValidationError{ClientMessage: err.Error()}})
+			continue
+		}
+		op, err := fromCSVRecord(record, cols)
+		rows = append(rows, importRow{line: line, op: op, err: err})
+	}
+}
+
+func csvReadErr(err error) error {
+	var maxBytesError *http.MaxBytesError
+	if errors.As(err, &maxBytesError) {
+		return &DTOValidationError{
+			ClientMessage: fmt.Sprintf("body must not be larger than %d bytes", maxBytesError.Limit),
+			StatusCode:    http.StatusRequestEntityTooLarge,
+		}
+	}
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &DTOValidationError{ClientMessage: "body contains malformed CSV: " + err.Error(), StatusCode: http.StatusBadRequest}
+	}
+	return err
+}
+
+// importColumns maps the columns of header to their index. Names are matched
+// ignoring case and surrounding spaces.
+func importColumns(header []string) (map[string]int, error) {
+	cols := make(map[string]int, len(header))
+	for i, name := range header {
+		// Spreadsheets save UTF-8 CSV with a byte order mark.
+		if i == 0 {
+			name = strings.TrimPrefix(name, "\ufeff")
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !slices.Contains(csvColumns, name) {
+			return nil, &DTOValidationError{ClientMessage: fmt.Sprintf(unknownColumnMsg, name)}
+		}
+		if _, ok := cols[name]; ok {
+			return nil, &DTOValidationError{ClientMessage: fmt.Sprintf(repeatedColumnMsg, name)}
+		}
+		cols[name] = i
+	}
+
+	for _, name := range requiredCSVColumns {
+		if _, ok := cols[name]; !ok {
+			return nil, &DTOValidationError{ClientMessage: fmt.Sprintf(missingColumnMsg, name)}
+		}
+	}
+
+	return cols, nil
+}
+
+// fromCSVRecord validates record like a new subscription. Records with an id
+// replace that subscription, the others create one. Cells quoted by csvCell are
+// read back as they were.
+func fromCSVRecord(record []string, cols map[string]int) (*domain.BatchOp, error) {
+	field := func(name string) string {
+		if i, ok := cols[name]; ok {
+			return fromCSVCell(strings.TrimSpace(record[i]))
+		}
+		return ""
+	}
+	optional := func(name string) *string {
+		if v := field(name); v != "" {
+			return &v
+		}
+		return nil
+	}
+
+	d := dto.NewSubscription{
+		ServiceName: field("service_name"),
+		Currency:    optional("currency"),
+		StartDate:   field("start_date"),
+		EndDate:     optional("end_date"),
+		TrialEnd:    optional("trial_end"),
+	}
+	if period := optional("billing_period"); period != nil {
+		d.BillingPeriod = (*dto.BillingPeriod)(period)
+	}
+	price, err := csvInt(optional("price"), "price")
+	if err != nil {
+		return nil, err
+	}
+	d.Price = price
+	if d.UserId, err = csvUUID(field("user_id"), "user_id"); err != nil {
+		return nil, err
+	}
+
+	sub, err := fromNewSubscriptionDTO(&d)
+	if err != nil {
+		return nil, err
+	}
+
+	id := field("id")
+	if id == "" {
+		return &domain.BatchOp{Kind: domain.BatchCreate, Sub: *sub}, nil
+	}
+	subID, err := csvUUID(id, "id")
+	if err != nil {
+		return nil, err
+	}
+	version, err := csvInt(optional("version"), "version")
+	if err != nil {
+		return nil, err
+	}
+	return &domain.BatchOp{Kind: domain.BatchUpdate, ID: subID, Version: version, Update: *replacementOf(sub, nil)}, nil
+}
+
+func csvInt(value *string, column string) (*int, error) {
+	if value == nil {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(*value)
+	if err != nil {
+		return nil, &DTOValidationError{ClientMessage: fmt.Sprintf(csvIntegerMsg, column), InternalError: err}
+	}
+	return &n, nil
+}
+
+func csvUUID(value, column string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, &DTOValidationError{ClientMessage: fmt.Sprintf(csvUUIDMsg, column), InternalError: err}
+	}
+	return id, nil
+}
+
+func toCSVRecord(sub *domain.Subscription) []string {
+	d := toSubscriptionDTO(sub, isoDateLayout)
+	var endDate, trialEnd string
+	if d.EndDate != nil {
+		endDate = *d.EndDate
+	}
+	if d.TrialEnd != nil {
+		trialEnd = *d.TrialEnd
+	}
+
+	record := []string{
+		d.Id.String(), d.ServiceName, strconv.Itoa(d.Price), string(d.BillingPeriod), strconv.Itoa(d.MonthlyCost), d.Currency,
+		d.UserId.String(), d.StartDate, endDate, trialEnd, strconv.Itoa(sub.Version),
+	}
+	for i, cell := range record {
+		record[i] = csvCell(cell)
+	}
+	return record
+}
+
+// csvCell keeps spreadsheets from evaluating cell as a formula by prefixing it
+// with a quote when it starts like one.
+func csvCell(cell string) string {
+	if startsLikeFormula(cell) {
+		return "'" + cell
+	}
+	return cell
+}
+
+func fromCSVCell(cell string) string {
+	if quoted, ok := strings.CutPrefix(cell, "'"); ok && startsLikeFormula(quoted) {
+		return quoted
+	}
+	return cell
+}
+
+func startsLikeFormula(cell string) bool {
+	return cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0]))
+}
+
+func countImported(report *dto.ImportReport, kind domain.BatchOpKind) {
+	if kind == domain.BatchUpdate {
+		report.Updated++
+	} else {
+		report.Created++
+	}
+}
+
+func addImportErr(r *http.Request, report *dto.ImportReport, line int, err error) {
+	httpErr := processAppError(err)
+	if httpErr.DTOErr.Code >= 500 {
+		log.FromCtx(r.Context()).Error("import row failed", slog.Int("line", line), log.WithErr(httpErr))
+	}
+	report.Failed++
+	report.Errors = append(report.Errors, dto.ImportRowError{Line: line, Error: httpErr.DTOErr})
+}
+
+// writeImportReport responds like writeBatchResponse.
+func writeImportReport(w http.ResponseWriter, r *http.Request, report *dto.ImportReport, atomic bool) {
+	status := http.StatusOK
+	if report.Failed > 0 {
+		status = http.StatusMultiStatus
+		if atomic {
+			status = http.StatusUnprocessableEntity
+		}
+	}
+
+	if err := WriteJSON(w, report, status, nil); err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+	}
+}
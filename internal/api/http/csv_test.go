@@ -0,0 +1,270 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_ImportSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	ctx := adminCtx()
+	userID := uuid.MustParse("3fa85f64-5717-4562-b3fc-2c963f66afa6")
+	subID := uuid.MustParse("0199a2c4-7f1e-7c3a-9d2b-5e8f4a6b1c2d")
+	start := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC)
+
+	const header = "service_name,price,user_id,start_date,end_date,id,version\n"
+	createRow := "Yandex Plus,400,3fa85f64-5717-4562-b3fc-2c963f66afa6,11-2025,10-2026,,\n"
+	updateRow := "Okko,300,3fa85f64-5717-4562-b3fc-2c963f66afa6,2025-11-01,,0199a2c4-7f1e-7c3a-9d2b-5e8f4a6b1c2d,2\n"
+	invalidRow := "Ivi,300,3fa85f64-5717-4562-b3fc-2c963f66afa6,11-2025,10-2024,,\n"
+
+	createOp := domain.BatchOp{Kind: domain.BatchCreate, Sub: domain.Subscription{
+		ServiceName: "Yandex Plus", Price: 400, BillingPeriod: domain.BillingMonthly, Currency: "RUB",
+		UserID: userID, StartDate: start, EndDate: &endDate,
+	}}
+	updateOp := domain.BatchOp{Kind: domain.BatchUpdate, ID: subID, Version: ptr(2), Update: *replacementOf(&domain.Subscription{
+		ServiceName: "Okko", Price: 300, BillingPeriod: domain.BillingMonthly, Currency: "RUB", UserID: userID, StartDate: start,
+	}, nil)}
+	serviceErrNotFound := subservice.NewErr("subservice.Batch", subservice.KindNotFound)
+
+	decode := func(t *testing.T, rr *httptest.ResponseRecorder) dto.ImportReport {
+		var report dto.ImportReport
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&report))
+		return report
+	}
+	errBody := func(t *testing.T, rr *httptest.ResponseRecorder) dto.Error {
+		var body dto.Error
+		require.NoError(t, json.NewDecoder(rr.Body).Decode(&body))
+		return body
+	}
+
+	testCases := []struct {
+		name        string
+		query       string
+		contentType string
+		body        string
+		setupMocks  func(th testHarness)
+		assertFunc  func(t *testing.T, rr *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Success",
+			body: header + createRow + updateRow,
+			setupMocks: func(th testHarness) {
+				th.service.On("Batch", mock.Anything, []domain.BatchOp{createOp, updateOp}, true).
+					Return([]domain.BatchResult{{Sub: &createOp.Sub}, {Sub: &domain.Subscription{ID: subID}}}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, dto.ImportReport{Rows: 2, Created: 1, Updated: 1, Errors: []dto.ImportRowError{}}, decode(t, rr))
+			},
+		},
+		{
+			name:        "Header In Any Order With Byte Order Mark",
+			contentType: "text/csv; charset=utf-8",
+			body: "\ufeffStart_Date, User_ID ,price,service_name,end_date,monthly_cost\n" +
+				"11-2025,3fa85f64-5717-4562-b3fc-2c963f66afa6,400,Yandex Plus,10-2026,400\n",
+			setupMocks: func(th testHarness) {
+				th.service.On("Batch", mock.Anything, []domain.BatchOp{createOp}, true).
+					Return([]domain.BatchResult{{Sub: &createOp.Sub}}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, 1, decode(t, rr).Created)
+			},
+		},
+		{
+			name: "Quoted Formula",
+			body: header + "'@Yandex Plus,400,3fa85f64-5717-4562-b3fc-2c963f66afa6,11-2025,10-2026,,\n",
+			setupMocks: func(th testHarness) {
+				op := createOp
+				op.Sub.ServiceName = "@Yandex Plus"
+				th.service.On("Batch", mock.Anything, []domain.BatchOp{op}, true).
+					Return([]domain.BatchResult{{Sub: &op.Sub}}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, 1, decode(t, rr).Created)
+			},
+		},
+		{
+			name:  "Dry Run",
+			query: "?dry_run=true&mode=best_effort",
+			body:  header + createRow + invalidRow + updateRow,
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusMultiStatus, rr.Code)
+				report := decode(t, rr)
+				assert.True(t, report.DryRun)
+				assert.Equal(t, 3, report.Rows)
+				assert.Equal(t, 1, report.Created)
+				assert.Equal(t, 1, report.Updated)
+				assert.Equal(t, 1, report.Failed)
+				require.Len(t, report.Errors, 1)
+				assert.Equal(t, 3, report.Errors[0].Line)
+				assert.Equal(t, startDateAfterEndDateMsg, report.Errors[0].Error.Message)
+			},
+		},
+		{
+			name: "Atomic With Invalid Rows",
+			body: header + createRow + "Okko,lots,not-a-uuid,11-2025,,,\n" + "Ivi,300,3fa85f64-5717-4562-b3fc-2c963f66afa6,11-2025,,not-a-uuid,\n",
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+				report := decode(t, rr)
+				assert.Zero(t, report.Created)
+				assert.Equal(t, 2, report.Failed)
+				require.Len(t, report.Errors, 2)
+				assert.Equal(t, "price must be an integer", report.Errors[0].Error.Message)
+				assert.Equal(t, "id must be a UUID", report.Errors[1].Error.Message)
+			},
+		},
+		{
+			name: "Atomic Rolled Back",
+			body: header + createRow + updateRow,
+			setupMocks: func(th testHarness) {
+				th.service.On("Batch", mock.Anything, []domain.BatchOp{createOp, updateOp}, true).
+					Return([]domain.BatchResult{{Err: subservice.ErrNotApplied}, {Err: serviceErrNotFound}}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+				report := decode(t, rr)
+				assert.Equal(t, 1, report.Failed)
+				require.Len(t, report.Errors, 1)
+				assert.Equal(t, 3, report.Errors[0].Line)
+				assert.Equal(t, int32(http.StatusNotFound), report.Errors[0].Error.Code)
+			},
+		},
+		{
+			name:  "Best Effort Partially Applied",
+			query: "?mode=best_effort",
+			body:  header + createRow + "Okko,300\n" + updateRow,
+			setupMocks: func(th testHarness) {
+				th.service.On("Batch", mock.Anything, []domain.BatchOp{createOp, updateOp}, false).
+					Return([]domain.BatchResult{{Sub: &createOp.Sub}, {Err: serviceErrNotFound}}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusMultiStatus, rr.Code)
+				report := decode(t, rr)
+				assert.Equal(t, 1, report.Created)
+				assert.Equal(t, 2, report.Failed)
+				require.Len(t, report.Errors, 2)
+				assert.Equal(t, "record on line 3: wrong number of fields", report.Errors[0].Error.Message)
+				assert.Equal(t, 4, report.Errors[1].Line)
+			},
+		},
+		{
+			name: "Only Header",
+			body: header,
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, dto.ImportReport{Errors: []dto.ImportRowError{}}, decode(t, rr))
+			},
+		},
+		{
+			name: "Unknown Column",
+			body: "service_name,price,user_id,start_date,owner\n",
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+				assert.Equal(t, `unknown column "owner"`, errBody(t, rr).Message)
+			},
+		},
+		{
+			name: "Repeated Column",
+			body: "service_name,price,user_id,start_date,Price\n",
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, `column "price" is repeated`, errBody(t, rr).Message)
+			},
+		},
+		{
+			name: "Missing Column",
+			body: "service_name,user_id,start_date\n",
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, `column "price" is required`, errBody(t, rr).Message)
+			},
+		},
+		{
+			name: "Too Many Rows",
+			body: header + createRow + createRow + createRow + createRow,
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusRequestEntityTooLarge, rr.Code)
+			},
+		},
+		{
+			name: "Malformed CSV",
+			body: header + `Okko,"300` + "\n",
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rr.Code)
+			},
+		},
+		{
+			name: "Empty Body",
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rr.Code)
+			},
+		},
+		{
+			name:        "Unsupported Content Type",
+			contentType: "application/json",
+			body:        header + createRow,
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+			},
+		},
+		{
+			name:  "Invalid Mode",
+			query: "?mode=eventual",
+			body:  header + createRow,
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, invalidBatchModeMsg, errBody(t, rr).Message)
+			},
+		},
+		{
+			name: "Service Error",
+			body: header + createRow,
+			setupMocks: func(th testHarness) {
+				th.service.On("Batch", mock.Anything, mock.Anything, true).
+					Return(nil, subservice.WrapErr("subservice.Batch", subservice.KindUnknown, errors.New("db is down"))).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, rr.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			th := setup(t)
+			if tc.setupMocks != nil {
+				tc.setupMocks(th)
+			}
+
+			req := httptest.NewRequest(http.MethodPost, "/subscriptions/import"+tc.query, strings.NewReader(tc.body))
+			contentType := tc.contentType
+			if contentType == "" {
+				contentType = csvMediaType
+			}
+			req.Header.Set("Content-Type", contentType)
+			rr := httptest.NewRecorder()
+
+			router := dto.HandlerFromMux(th.h, chi.NewRouter())
+			router.ServeHTTP(rr, req.WithContext(ctx))
+
+			tc.assertFunc(t, rr)
+		})
+	}
+}
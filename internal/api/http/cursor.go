@@ -0,0 +1,34 @@
+package http
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+)
+
+var errMalformedCursor = errors.New("malformed cursor")
+
+// encodeCursor hides the sort values of a cursor from clients.
+func encodeCursor(c domain.Cursor) string {
+	raw, _ := json.Marshal(c) // Can't fail, the cursor has no maps, channels or funcs.
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(s string) (domain.Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return domain.Cursor{}, err
+	}
+
+	var c domain.Cursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return domain.Cursor{}, err
+	}
+	if !c.BillingPeriod.IsValid() {
+		return domain.Cursor{}, errMalformedCursor
+	}
+
+	return c, nil
+}
@@ -0,0 +1,55 @@
+package http
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_roundTrip(t *testing.T) {
+	t.Parallel()
+
+	cursor := domain.Cursor{
+		CreatedAt:     time.Date(2025, time.March, 1, 12, 30, 0, 123456000, time.UTC),
+		StartDate:     time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC),
+		ServiceName:   "Yandex Plus",
+		Price:         400,
+		BillingPeriod: domain.BillingQuarterly,
+		ID:            uuid.New(),
+	}
+
+	got, err := decodeCursor(encodeCursor(cursor))
+	require.NoError(t, err)
+	assert.Equal(t, cursor, got)
+}
+
+func Test_decodeCursor(t *testing.T) {
+	t.Parallel()
+
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "Not base64", cursor: "not a cursor"},
+		{name: "Not JSON", cursor: encode("2025-03-01T12:30:00Z")},
+		{name: "Invalid time", cursor: encode(`{"CreatedAt":"yesterday","BillingPeriod":"monthly"}`)},
+		{name: "Invalid id", cursor: encode(`{"ID":"42","BillingPeriod":"monthly"}`)},
+		{name: "Invalid billing period", cursor: encode(`{"BillingPeriod":"daily"}`)},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := decodeCursor(tc.cursor)
+			assert.Error(t, err)
+		})
+	}
+}
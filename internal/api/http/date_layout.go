@@ -1,15 +1,55 @@
 package http
 
-import "time"
+import (
+	"context"
+	"time"
+)
 
 type DateLayout string
 
 func (dl DateLayout) parse(dateStr string) (time.Time, error) {
-	return time.Parse(string(dateLayout), dateStr)
+	return time.Parse(string(dl), dateStr)
 }
 
 func (dl DateLayout) format(date time.Time) string {
 	return date.Format(string(dl))
 }
 
-const dateLayout DateLayout = "01-2006"
+const (
+	dateLayout    DateLayout = "01-2006"
+	isoDateLayout DateLayout = time.DateOnly
+)
+
+// parseStartDate accepts both layouts, MM-YYYY means the first day of the month.
+func parseStartDate(dateStr string) (time.Time, error) {
+	if t, err := isoDateLayout.parse(dateStr); err == nil {
+		return t, nil
+	}
+	return dateLayout.parse(dateStr)
+}
+
+// parseEndDate accepts both layouts, MM-YYYY means the last day of the month.
+func parseEndDate(dateStr string) (time.Time, error) {
+	if t, err := isoDateLayout.parse(dateStr); err == nil {
+		return t, nil
+	}
+	t, err := dateLayout.parse(dateStr)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return t.AddDate(0, 1, -1), nil
+}
+
+type dateLayoutCtxKey struct{}
+
+func dateLayoutToCtx(ctx context.Context, dl DateLayout) context.Context {
+	return context.WithValue(ctx, dateLayoutCtxKey{}, dl)
+}
+
+// dateLayoutFromCtx returns the layout of subscription dates in responses.
+func dateLayoutFromCtx(ctx context.Context) DateLayout {
+	if dl, ok := ctx.Value(dateLayoutCtxKey{}).(DateLayout); ok {
+		return dl
+	}
+	return dateLayout
+}
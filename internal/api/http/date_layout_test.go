@@ -96,3 +96,56 @@ func TestDateLayout_format(t *testing.T) {
 		})
 	}
 }
+
+func Test_parseStartAndEndDate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		dateStr   string
+		wantStart time.Time
+		wantEnd   time.Time
+		wantErr   bool
+	}{
+		{
+			name:      "Month and year",
+			dateStr:   "02-2024",
+			wantStart: time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "ISO full date",
+			dateStr:   "2024-02-14",
+			wantStart: time.Date(2024, time.February, 14, 0, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2024, time.February, 14, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "ISO year and month",
+			dateStr: "2024-02",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid day",
+			dateStr: "2024-02-30",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			gotStart, startErr := parseStartDate(tc.dateStr)
+			gotEnd, endErr := parseEndDate(tc.dateStr)
+			if tc.wantErr {
+				assert.Error(t, startErr)
+				assert.Error(t, endErr)
+			} else {
+				assert.NoError(t, startErr)
+				assert.NoError(t, endErr)
+				assert.Equal(t, tc.wantStart, gotStart)
+				assert.Equal(t, tc.wantEnd, gotEnd)
+			}
+		})
+	}
+}
@@ -4,78 +4,642 @@
 package dto
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/oapi-codegen/runtime"
 	openapi_types "github.com/oapi-codegen/runtime/types"
 )
 
+const (
+	ApiKeyAuthScopes = "apiKeyAuth.Scopes"
+	BearerAuthScopes = "bearerAuth.Scopes"
+)
+
+// Defines values for ApiKeyScope.
+const (
+	Admin ApiKeyScope = "admin"
+	Read  ApiKeyScope = "read"
+	Write ApiKeyScope = "write"
+)
+
+// Defines values for AuditOperation.
+const (
+	AuditOperationCreate AuditOperation = "create"
+	AuditOperationDelete AuditOperation = "delete"
+	AuditOperationPause  AuditOperation = "pause"
+	AuditOperationResume AuditOperation = "resume"
+	AuditOperationUpdate AuditOperation = "update"
+)
+
+// Defines values for BatchMode.
+const (
+	Atomic     BatchMode = "atomic"
+	BestEffort BatchMode = "best_effort"
+)
+
+// Defines values for BatchOperationOp.
+const (
+	BatchOperationOpCreate BatchOperationOp = "create"
+	BatchOperationOpDelete BatchOperationOp = "delete"
+	BatchOperationOpUpdate BatchOperationOp = "update"
+)
+
+// Defines values for BillingPeriod.
+const (
+	Monthly   BillingPeriod = "monthly"
+	Quarterly BillingPeriod = "quarterly"
+	Weekly    BillingPeriod = "weekly"
+	Yearly    BillingPeriod = "yearly"
+)
+
+// Defines values for CostMode.
+const (
+	Billed   CostMode = "billed"
+	Prorated CostMode = "prorated"
+)
+
+// Defines values for ExportFormat.
+const (
+	Csv    ExportFormat = "csv"
+	Ndjson ExportFormat = "ndjson"
+)
+
+// Defines values for JSONPatchOperationOp.
+const (
+	Add     JSONPatchOperationOp = "add"
+	Copy    JSONPatchOperationOp = "copy"
+	Move    JSONPatchOperationOp = "move"
+	Remove  JSONPatchOperationOp = "remove"
+	Replace JSONPatchOperationOp = "replace"
+	Test    JSONPatchOperationOp = "test"
+)
+
+// ApiKey defines model for ApiKey.
+type ApiKey struct {
+	CreatedAt  time.Time          `json:"created_at"`
+	ExpiresAt  *time.Time         `json:"expires_at,omitempty"`
+	Id         openapi_types.UUID `json:"id"`
+	LastUsedAt *time.Time         `json:"last_used_at,omitempty"`
+	Name       string             `json:"name"`
+
+	// Prefix Start of the key, to tell keys apart.
+	Prefix    string        `json:"prefix"`
+	RevokedAt *time.Time    `json:"revoked_at,omitempty"`
+	Scopes    []ApiKeyScope `json:"scopes"`
+}
+
+// ApiKeyScope defines model for ApiKeyScope.
+type ApiKeyScope string
+
+// AuditEntry defines model for AuditEntry.
+type AuditEntry struct {
+	// ActorId User who made the change, absent for API keys.
+	ActorId *openapi_types.UUID `json:"actor_id,omitempty"`
+
+	// ActorRole Role of the actor at the time.
+	ActorRole string `json:"actor_role"`
+
+	// After Subscription as of an audit entry. Absent before creates and after deletes.
+	After *SubscriptionSnapshot `json:"after,omitempty"`
+
+	// ApiKeyId API key the change was made with.
+	ApiKeyId *openapi_types.UUID `json:"api_key_id,omitempty"`
+
+	// Before Subscription as of an audit entry. Absent before creates and after deletes.
+	Before    *SubscriptionSnapshot `json:"before,omitempty"`
+	CreatedAt time.Time             `json:"created_at"`
+	Id        int64                 `json:"id"`
+	Operation AuditOperation        `json:"operation"`
+
+	// RequestId ID of the request the change was made in, the request_id of the service logs.
+	RequestId      string             `json:"request_id"`
+	SubscriptionId openapi_types.UUID `json:"subscription_id"`
+}
+
+// AuditOperation defines model for AuditOperation.
+type AuditOperation string
+
+// AuditPage defines model for AuditPage.
+type AuditPage struct {
+	Items []AuditEntry `json:"items"`
+
+	// NextCursor Cursor of the next page, absent on the last one.
+	NextCursor *string `json:"next_cursor,omitempty"`
+}
+
+// BatchMode defines model for BatchMode.
+type BatchMode string
+
+// BatchOperation create takes subscription, update takes id and replacement and replaces the
+// subscription like PUT, delete takes id.
+type BatchOperation struct {
+	// Id ID of the subscription to update or delete
+	Id           *openapi_types.UUID  `json:"id,omitempty"`
+	Op           BatchOperationOp     `json:"op"`
+	Replacement  *ReplaceSubscription `json:"replacement,omitempty"`
+	Subscription *NewSubscription     `json:"subscription,omitempty"`
+
+	// Version Version the subscription to update or delete must be at, like If-Match
+	Version *int `json:"version,omitempty"`
+}
+
+// BatchOperationOp defines model for BatchOperation.Op.
+type BatchOperationOp string
+
+// BatchRequest defines model for BatchRequest.
+type BatchRequest struct {
+	Mode       *BatchMode       `json:"mode,omitempty"`
+	Operations []BatchOperation `json:"operations"`
+}
+
+// BatchResponse defines model for BatchResponse.
+type BatchResponse struct {
+	// Results Results in the order of the operations
+	Results []BatchResult `json:"results"`
+}
+
+// BatchResult defines model for BatchResult.
+type BatchResult struct {
+	Error *Error `json:"error,omitempty"`
+
+	// Status HTTP status code of the operation on its own
+	Status       int           `json:"status"`
+	Subscription *Subscription `json:"subscription,omitempty"`
+}
+
+// BillingPeriod How often the price is charged, counting from the start date.
+type BillingPeriod string
+
+// CostBreakdown defines model for CostBreakdown.
+type CostBreakdown struct {
+	// Currency ISO-4217 currency code of all costs in the breakdown
+	Currency string `json:"currency"`
+
+	// Months One entry per month of the requested period
+	Months []MonthCost `json:"months"`
+
+	// TotalCost Total cost of subscriptions over the whole period
+	TotalCost int `json:"total_cost"`
+}
+
+// CostLineItem defines model for CostLineItem.
+type CostLineItem struct {
+	// Cost Cost of the subscription in this month
+	Cost int `json:"cost"`
+
+	// ServiceName Name of the service.
+	ServiceName string `json:"service_name"`
+
+	// SubscriptionId ID of the subscription.
+	SubscriptionId openapi_types.UUID `json:"subscription_id"`
+}
+
+// CostMode billed charges the full price on every billing date within the period.
+// prorated charges the monthly rate of every active month, by day count for partial months.
+type CostMode string
+
+// CreatedApiKey defines model for CreatedApiKey.
+type CreatedApiKey struct {
+	CreatedAt time.Time          `json:"created_at"`
+	ExpiresAt *time.Time         `json:"expires_at,omitempty"`
+	Id        openapi_types.UUID `json:"id"`
+
+	// Key The key to authenticate with. It cannot be retrieved again.
+	Key        string     `json:"key"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+	Name       string     `json:"name"`
+
+	// Prefix Start of the key, to tell keys apart.
+	Prefix    string        `json:"prefix"`
+	RevokedAt *time.Time    `json:"revoked_at,omitempty"`
+	Scopes    []ApiKeyScope `json:"scopes"`
+}
+
+// CurrencyRate defines model for CurrencyRate.
+type CurrencyRate struct {
+	// From ISO-4217 code of the source currency.
+	From string `json:"from"`
+
+	// Rate Amount of target currency per one unit of source currency.
+	Rate float64 `json:"rate"`
+
+	// To ISO-4217 code of the target currency.
+	To string `json:"to"`
+
+	// UpdatedAt Time the rate was last changed.
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
 // Error defines model for Error.
 type Error struct {
-	Code    int32  `json:"code"`
-	Message string `json:"message"`
+	Code int32 `json:"code"`
+
+	// ConflictingId ID of the existing subscription a 409 Conflict overlaps.
+	ConflictingId *openapi_types.UUID `json:"conflicting_id,omitempty"`
+	Message       string              `json:"message"`
+}
+
+// ExportFormat defines model for ExportFormat.
+type ExportFormat string
+
+// ImportReport defines model for ImportReport.
+type ImportReport struct {
+	// Created Subscriptions created, or that would be in a dry run
+	Created int              `json:"created"`
+	DryRun  bool             `json:"dry_run"`
+	Errors  []ImportRowError `json:"errors"`
+
+	// Failed Rows that are invalid or failed to apply
+	Failed int `json:"failed"`
+
+	// Rows Number of data rows
+	Rows int `json:"rows"`
+
+	// Updated Subscriptions replaced, or that would be in a dry run
+	Updated int `json:"updated"`
+}
+
+// ImportRowError defines model for ImportRowError.
+type ImportRowError struct {
+	Error Error `json:"error"`
+
+	// Line Line of the CSV the row starts on, the header is line 1
+	Line int `json:"line"`
+}
+
+// JSONPatchOperation An operation of a JSON Patch (RFC 6902)
+type JSONPatchOperation struct {
+	// From JSON Pointer to the source of move and copy
+	From *string              `json:"from,omitempty"`
+	Op   JSONPatchOperationOp `json:"op"`
+
+	// Path JSON Pointer (RFC 6901) to the target member
+	Path string `json:"path"`
+
+	// Value Value of add, replace and test
+	Value interface{} `json:"value,omitempty"`
+}
+
+// JSONPatchOperationOp defines model for JSONPatchOperation.Op.
+type JSONPatchOperationOp string
+
+// MonthCost defines model for MonthCost.
+type MonthCost struct {
+	// Items Cost of every subscription billed in this month. Costs are rounded so the items add
+	// up to total_cost of the month and the months to total_cost of the breakdown
+	Items []CostLineItem `json:"items"`
+
+	// Month Billed month (MM-YYYY).
+	Month string `json:"month"`
+
+	// TotalCost Total cost of subscriptions in this month
+	TotalCost int `json:"total_cost"`
+}
+
+// NewApiKey defines model for NewApiKey.
+type NewApiKey struct {
+	// ExpiresAt Time the key stops working, it never expires when omitted.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+
+	// Name What the key is for.
+	Name   string        `json:"name"`
+	Scopes []ApiKeyScope `json:"scopes"`
+}
+
+// NewCurrencyRate defines model for NewCurrencyRate.
+type NewCurrencyRate struct {
+	// Rate Amount of target currency per one unit of source currency.
+	Rate float64 `json:"rate"`
+}
+
+// NewPause defines model for NewPause.
+type NewPause struct {
+	// PausedFrom First paused month (MM-YYYY), the current month by default.
+	PausedFrom *string `json:"paused_from,omitempty"`
+
+	// ResumedFrom First month billed again (MM-YYYY). Leave out to pause until resumed.
+	ResumedFrom *string `json:"resumed_from,omitempty"`
 }
 
 // NewSubscription defines model for NewSubscription.
 type NewSubscription struct {
-	// EndDate End month and year (MM-YYYY)
-	EndDate     *string `json:"end_date,omitempty"`
-	MonthlyCost int     `json:"monthly_cost"`
-	ServiceName string  `json:"service_name"`
+	// BillingPeriod How often the price is charged, counting from the start date.
+	BillingPeriod *BillingPeriod `json:"billing_period,omitempty"`
+
+	// Currency ISO-4217 currency code of the price
+	Currency *string `json:"currency,omitempty"`
+
+	// EndDate Last day of the subscription (MM-YYYY or YYYY-MM-DD)
+	EndDate *string `json:"end_date,omitempty"`
+
+	// MonthlyCost Same as price with a monthly billing period. Use price instead.
+	// Deprecated: this property has been marked as deprecated upstream, but no `x-deprecated-reason` was set
+	MonthlyCost *int `json:"monthly_cost,omitempty"`
+
+	// Price Amount charged once per billing period. Required unless monthly_cost is set.
+	Price       *int   `json:"price,omitempty"`
+	ServiceName string `json:"service_name"`
+
+	// StartDate Start date (MM-YYYY or YYYY-MM-DD)
+	StartDate string `json:"start_date"`
+
+	// TrialEnd Last day of the free trial (MM-YYYY or YYYY-MM-DD). Conflicts with trial_months.
+	TrialEnd *string `json:"trial_end,omitempty"`
+
+	// TrialMonths Length of the free trial in months from the start date. Conflicts with trial_end.
+	TrialMonths *int               `json:"trial_months,omitempty"`
+	UserId      openapi_types.UUID `json:"user_id"`
+}
+
+// Pause defines model for Pause.
+type Pause struct {
+	// CreatedAt When the pause was recorded.
+	CreatedAt time.Time `json:"created_at"`
+
+	// PausedFrom First month that isn't billed.
+	PausedFrom string `json:"paused_from"`
+
+	// ResumedFrom First month billed again, absent while the subscription is paused.
+	ResumedFrom *string `json:"resumed_from,omitempty"`
+}
+
+// PriceChange defines model for PriceChange.
+type PriceChange struct {
+	// CreatedAt When the change was recorded.
+	CreatedAt time.Time `json:"created_at"`
+
+	// EffectiveFrom First month the price applies to.
+	EffectiveFrom string `json:"effective_from"`
+
+	// Price Amount charged once per billing period.
+	Price int `json:"price"`
+}
+
+// ReplaceSubscription defines model for ReplaceSubscription.
+type ReplaceSubscription struct {
+	// BillingPeriod How often the price is charged, counting from the start date.
+	BillingPeriod *BillingPeriod `json:"billing_period,omitempty"`
+
+	// Currency ISO-4217 currency code of the price
+	Currency *string `json:"currency,omitempty"`
+
+	// EndDate Last day of the subscription (MM-YYYY or YYYY-MM-DD)
+	EndDate *string `json:"end_date,omitempty"`
+
+	// Price Amount charged once per billing period. When it changes, earlier months keep the price they were billed at.
+	Price int `json:"price"`
 
-	// StartDate Start month and year (MM-YYYY)
-	StartDate string             `json:"start_date"`
-	UserId    openapi_types.UUID `json:"user_id"`
+	// PriceEffectiveFrom First month of a changed price (MM-YYYY), the current month by default.
+	PriceEffectiveFrom *string `json:"price_effective_from,omitempty"`
+	ServiceName        string  `json:"service_name"`
+
+	// StartDate Start date (MM-YYYY or YYYY-MM-DD)
+	StartDate string `json:"start_date"`
+
+	// TrialEnd Last day of the free trial (MM-YYYY or YYYY-MM-DD)
+	TrialEnd *string            `json:"trial_end,omitempty"`
+	UserId   openapi_types.UUID `json:"user_id"`
+}
+
+// ResumePause defines model for ResumePause.
+type ResumePause struct {
+	// ResumedFrom First month billed again (MM-YYYY). Defaults to the current month, or the month
+	// after the pause started if the subscription was paused this month.
+	ResumedFrom *string `json:"resumed_from,omitempty"`
 }
 
 // Subscription defines model for Subscription.
 type Subscription struct {
-	// EndDate Subscription end date (MM-YYYY), optional.
+	// BillingPeriod How often the price is charged, counting from the start date.
+	BillingPeriod BillingPeriod `json:"billing_period"`
+
+	// Currency ISO-4217 currency code of the price.
+	Currency string `json:"currency"`
+
+	// EndDate Last day of the subscription (MM-YYYY or YYYY-MM-DD), optional.
+	EndDate *string `json:"end_date"`
+
+	// Id Unique identifier for the subscription.
+	Id openapi_types.UUID `json:"id"`
+
+	// MonthlyCost Price converted to a monthly amount, rounded. Use price and billing_period instead.
+	// Deprecated: this property has been marked as deprecated upstream, but no `x-deprecated-reason` was set
+	MonthlyCost int `json:"monthly_cost"`
+
+	// Price Amount charged once per billing period, in units of currency.
+	Price int `json:"price"`
+
+	// ServiceName Name of the service.
+	ServiceName string `json:"service_name"`
+
+	// StartDate Subscription start date (MM-YYYY or YYYY-MM-DD).
+	StartDate string `json:"start_date"`
+
+	// TrialEnd Last day of the free trial (MM-YYYY or YYYY-MM-DD), optional. Nothing is charged until then.
+	TrialEnd *string `json:"trial_end"`
+
+	// UserId ID of the user.
+	UserId openapi_types.UUID `json:"user_id"`
+}
+
+// SubscriptionPage defines model for SubscriptionPage.
+type SubscriptionPage struct {
+	Items []Subscription `json:"items"`
+
+	// NextCursor Cursor of the next page, absent on the last one.
+	NextCursor *string `json:"next_cursor,omitempty"`
+
+	// Page Page number, absent on pages after a cursor.
+	Page *int `json:"page,omitempty"`
+
+	// PageSize Maximum number of items per page, after applying the server limit.
+	PageSize int `json:"page_size"`
+
+	// Total Number of subscriptions matching the filters, across all pages.
+	Total int `json:"total"`
+}
+
+// SubscriptionSnapshot defines model for SubscriptionSnapshot.
+type SubscriptionSnapshot struct {
+	// BillingPeriod How often the price is charged, counting from the start date.
+	BillingPeriod BillingPeriod `json:"billing_period"`
+
+	// Currency ISO-4217 currency code of the price.
+	Currency string `json:"currency"`
+
+	// EndDate Last day of the subscription (MM-YYYY or YYYY-MM-DD), optional.
 	EndDate *string `json:"end_date"`
 
 	// Id Unique identifier for the subscription.
 	Id openapi_types.UUID `json:"id"`
 
-	// MonthlyCost Monthly cost in rubles.
+	// MonthlyCost Price converted to a monthly amount, rounded. Use price and billing_period instead.
+	// Deprecated: this property has been marked as deprecated upstream, but no `x-deprecated-reason` was set
 	MonthlyCost int `json:"monthly_cost"`
 
+	// Pauses Pauses of the subscription, only recorded by pause and resume.
+	Pauses *[]Pause `json:"pauses,omitempty"`
+
+	// Price Amount charged once per billing period, in units of currency.
+	Price int `json:"price"`
+
 	// ServiceName Name of the service.
 	ServiceName string `json:"service_name"`
 
-	// StartDate Subscription start date (MM-YYYY).
+	// StartDate Subscription start date (MM-YYYY or YYYY-MM-DD).
 	StartDate string `json:"start_date"`
 
+	// TrialEnd Last day of the free trial (MM-YYYY or YYYY-MM-DD), optional. Nothing is charged until then.
+	TrialEnd *string `json:"trial_end"`
+
 	// UserId ID of the user.
 	UserId openapi_types.UUID `json:"user_id"`
+
+	// Version Version of the subscription, the one its ETag is made of.
+	Version int `json:"version"`
 }
 
 // TotalCost defines model for TotalCost.
 type TotalCost struct {
+	// Currency ISO-4217 currency code of the total cost
+	Currency *string `json:"currency,omitempty"`
+
 	// TotalCost Total cost of subscriptions
 	TotalCost *int `json:"total_cost,omitempty"`
 }
 
-// UpdateSubscription defines model for UpdateSubscription.
-type UpdateSubscription struct {
-	// EndDate New end month and year (MM-YYYY). Use null to remove the end date.
-	EndDate *string `json:"end_date"`
+// ActiveAtFilter defines model for ActiveAtFilter.
+type ActiveAtFilter = string
 
-	// MonthlyCost New monthly cost in rubles.
-	MonthlyCost *int `json:"monthly_cost,omitempty"`
+// EndsBeforeFilter defines model for EndsBeforeFilter.
+type EndsBeforeFilter = string
+
+// HasEndDateFilter defines model for HasEndDateFilter.
+type HasEndDateFilter = bool
+
+// IfMatch defines model for IfMatch.
+type IfMatch = string
+
+// IfNoneMatch defines model for IfNoneMatch.
+type IfNoneMatch = string
+
+// InTrialFilter defines model for InTrialFilter.
+type InTrialFilter = bool
+
+// MaxCostFilter defines model for MaxCostFilter.
+type MaxCostFilter = int
+
+// MinCostFilter defines model for MinCostFilter.
+type MinCostFilter = int
+
+// QueryFilter defines model for QueryFilter.
+type QueryFilter = string
+
+// ServiceNameFilter defines model for ServiceNameFilter.
+type ServiceNameFilter = string
 
-	// ServiceName New name of the service.
-	ServiceName *string `json:"service_name,omitempty"`
+// Sort defines model for Sort.
+type Sort = []string
+
+// StartedAfterFilter defines model for StartedAfterFilter.
+type StartedAfterFilter = string
+
+// StartedBeforeFilter defines model for StartedBeforeFilter.
+type StartedBeforeFilter = string
+
+// UserIdFilter defines model for UserIdFilter.
+type UserIdFilter = openapi_types.UUID
+
+// Forbidden defines model for Forbidden.
+type Forbidden = Error
+
+// PreconditionFailed defines model for PreconditionFailed.
+type PreconditionFailed = Error
+
+// Unauthorized defines model for Unauthorized.
+type Unauthorized = Error
+
+// ListAuditParams defines parameters for ListAudit.
+type ListAuditParams struct {
+	// SubscriptionId Only changes of the subscription
+	SubscriptionId *openapi_types.UUID `form:"subscription_id,omitempty" json:"subscription_id,omitempty"`
+
+	// ActorId Only changes made by the user
+	ActorId *openapi_types.UUID `form:"actor_id,omitempty" json:"actor_id,omitempty"`
+
+	// ApiKeyId Only changes made with the API key
+	ApiKeyId *openapi_types.UUID `form:"api_key_id,omitempty" json:"api_key_id,omitempty"`
+
+	// Operation Only changes of the kind
+	Operation *AuditOperation `form:"operation,omitempty" json:"operation,omitempty"`
+
+	// From Only changes made at or after the time
+	From *time.Time `form:"from,omitempty" json:"from,omitempty"`
+
+	// To Only changes made before the time
+	To *time.Time `form:"to,omitempty" json:"to,omitempty"`
+
+	// Cursor Opaque next_cursor of the previous page
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
+
+	// Limit Number of entries per page
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// SuggestServicesParams defines parameters for SuggestServices.
+type SuggestServicesParams struct {
+	// Q Case-insensitive part of the service name
+	Q string `form:"q" json:"q"`
+
+	// Limit Maximum number of names
+	Limit *int `form:"limit,omitempty" json:"limit,omitempty"`
 }
 
 // ListSubscriptionsParams defines parameters for ListSubscriptions.
 type ListSubscriptionsParams struct {
 	// UserId Filter by user ID
-	UserId *openapi_types.UUID `form:"user_id,omitempty" json:"user_id,omitempty"`
+	UserId *UserIdFilter `form:"user_id,omitempty" json:"user_id,omitempty"`
 
 	// ServiceName Filter by service name
-	ServiceName *string `form:"service_name,omitempty" json:"service_name,omitempty"`
+	ServiceName *ServiceNameFilter `form:"service_name,omitempty" json:"service_name,omitempty"`
+
+	// Q Case-insensitive part of the service name
+	Q *QueryFilter `form:"q,omitempty" json:"q,omitempty"`
+
+	// InTrial Only subscriptions that are currently in their free trial (true) or out of it (false)
+	InTrial *InTrialFilter `form:"in_trial,omitempty" json:"in_trial,omitempty"`
+
+	// ActiveAt Only subscriptions active on at least one day of the month (MM-YYYY)
+	ActiveAt *ActiveAtFilter `form:"active_at,omitempty" json:"active_at,omitempty"`
+
+	// MinCost Lowest monthly_cost, inclusive, in the currency of each subscription
+	MinCost *MinCostFilter `form:"min_cost,omitempty" json:"min_cost,omitempty"`
+
+	// MaxCost Highest monthly_cost, inclusive, in the currency of each subscription
+	MaxCost *MaxCostFilter `form:"max_cost,omitempty" json:"max_cost,omitempty"`
+
+	// StartedAfter Only subscriptions that started after this date (MM-YYYY for after the month, or YYYY-MM-DD)
+	StartedAfter *StartedAfterFilter `form:"started_after,omitempty" json:"started_after,omitempty"`
+
+	// StartedBefore Only subscriptions that started before this date (MM-YYYY for before the month, or YYYY-MM-DD)
+	StartedBefore *StartedBeforeFilter `form:"started_before,omitempty" json:"started_before,omitempty"`
+
+	// EndsBefore Only subscriptions with an end date before this date (MM-YYYY for before the month, or YYYY-MM-DD)
+	EndsBefore *EndsBeforeFilter `form:"ends_before,omitempty" json:"ends_before,omitempty"`
+
+	// HasEndDate Only subscriptions with (true) or without (false) an end date
+	HasEndDate *HasEndDateFilter `form:"has_end_date,omitempty" json:"has_end_date,omitempty"`
+
+	// Sort Comma-separated sort keys, each optionally followed by `:asc` or `:desc`,
+	// e.g. `start_date:desc,service_name`. Defaults to created_at. Keep the sort when following a cursor.
+	Sort *Sort `form:"sort,omitempty" json:"sort,omitempty"`
+
+	// Cursor Opaque next_cursor of the previous page. Cannot be used together with page.
+	Cursor *string `form:"cursor,omitempty" json:"cursor,omitempty"`
 
 	// Page Page number for pagination
 	Page *int `form:"page,omitempty" json:"page,omitempty"`
@@ -84,6 +648,87 @@ type ListSubscriptionsParams struct {
 	PageSize *int `form:"page_size,omitempty" json:"page_size,omitempty"`
 }
 
+// CreateSubscriptionParams defines parameters for CreateSubscription.
+type CreateSubscriptionParams struct {
+	// IdempotencyKey Unique key of the request, up to 255 characters. Retries with the same key
+	// and body get the response of the first request instead of creating another
+	// subscription, until the key expires. Keys are scoped to the user or API key
+	// sending them, other callers using the same key don't share its response.
+	IdempotencyKey *string `json:"Idempotency-Key,omitempty"`
+}
+
+// GetCostBreakdownParams defines parameters for GetCostBreakdown.
+type GetCostBreakdownParams struct {
+	// UserId ID of the user
+	UserId openapi_types.UUID `form:"user_id" json:"user_id"`
+
+	// ServiceName Name of the service
+	ServiceName *string `form:"service_name,omitempty" json:"service_name,omitempty"`
+
+	// Start Start of the period (MM-YYYY)
+	Start string `form:"start" json:"start"`
+
+	// End End of the period (MM-YYYY)
+	End string `form:"end" json:"end"`
+
+	// Currency ISO-4217 code of the currency to convert costs into
+	Currency *string `form:"currency,omitempty" json:"currency,omitempty"`
+
+	// Mode How costs are calculated
+	Mode *CostMode `form:"mode,omitempty" json:"mode,omitempty"`
+}
+
+// ExportSubscriptionsParams defines parameters for ExportSubscriptions.
+type ExportSubscriptionsParams struct {
+	// Format Defaults to ndjson when application/x-ndjson is accepted, to csv otherwise
+	Format *ExportFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// UserId Filter by user ID
+	UserId *UserIdFilter `form:"user_id,omitempty" json:"user_id,omitempty"`
+
+	// ServiceName Filter by service name
+	ServiceName *ServiceNameFilter `form:"service_name,omitempty" json:"service_name,omitempty"`
+
+	// Q Case-insensitive part of the service name
+	Q *QueryFilter `form:"q,omitempty" json:"q,omitempty"`
+
+	// InTrial Only subscriptions that are currently in their free trial (true) or out of it (false)
+	InTrial *InTrialFilter `form:"in_trial,omitempty" json:"in_trial,omitempty"`
+
+	// ActiveAt Only subscriptions active on at least one day of the month (MM-YYYY)
+	ActiveAt *ActiveAtFilter `form:"active_at,omitempty" json:"active_at,omitempty"`
+
+	// MinCost Lowest monthly_cost, inclusive, in the currency of each subscription
+	MinCost *MinCostFilter `form:"min_cost,omitempty" json:"min_cost,omitempty"`
+
+	// MaxCost Highest monthly_cost, inclusive, in the currency of each subscription
+	MaxCost *MaxCostFilter `form:"max_cost,omitempty" json:"max_cost,omitempty"`
+
+	// StartedAfter Only subscriptions that started after this date (MM-YYYY for after the month, or YYYY-MM-DD)
+	StartedAfter *StartedAfterFilter `form:"started_after,omitempty" json:"started_after,omitempty"`
+
+	// StartedBefore Only subscriptions that started before this date (MM-YYYY for before the month, or YYYY-MM-DD)
+	StartedBefore *StartedBeforeFilter `form:"started_before,omitempty" json:"started_before,omitempty"`
+
+	// EndsBefore Only subscriptions with an end date before this date (MM-YYYY for before the month, or YYYY-MM-DD)
+	EndsBefore *EndsBeforeFilter `form:"ends_before,omitempty" json:"ends_before,omitempty"`
+
+	// HasEndDate Only subscriptions with (true) or without (false) an end date
+	HasEndDate *HasEndDateFilter `form:"has_end_date,omitempty" json:"has_end_date,omitempty"`
+
+	// Sort Comma-separated sort keys, each optionally followed by `:asc` or `:desc`,
+	// e.g. `start_date:desc,service_name`. Defaults to created_at. Keep the sort when following a cursor.
+	Sort *Sort `form:"sort,omitempty" json:"sort,omitempty"`
+}
+
+// ImportSubscriptionsParams defines parameters for ImportSubscriptions.
+type ImportSubscriptionsParams struct {
+	Mode *BatchMode `form:"mode,omitempty" json:"mode,omitempty"`
+
+	// DryRun Only validate the rows, nothing is applied
+	DryRun *bool `form:"dry_run,omitempty" json:"dry_run,omitempty"`
+}
+
 // GetTotalCostParams defines parameters for GetTotalCost.
 type GetTotalCostParams struct {
 	// UserId ID of the user
@@ -97,127 +742,1280 @@ type GetTotalCostParams struct {
 
 	// End End of the period (MM-YYYY)
 	End *string `form:"end,omitempty" json:"end,omitempty"`
+
+	// Currency ISO-4217 code of the currency to convert costs into
+	Currency *string `form:"currency,omitempty" json:"currency,omitempty"`
+
+	// Mode How costs are calculated
+	Mode *CostMode `form:"mode,omitempty" json:"mode,omitempty"`
+}
+
+// DeleteSubscriptionParams defines parameters for DeleteSubscription.
+type DeleteSubscriptionParams struct {
+	// IfMatch ETag of the subscription from an earlier response. The request fails with
+	// 412 when the subscription has changed since. Only * or a single tag is accepted.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// GetSubscriptionByIdParams defines parameters for GetSubscriptionById.
+type GetSubscriptionByIdParams struct {
+	// IfNoneMatch ETags of the subscription the client has, 304 is returned when one is current.
+	IfNoneMatch *IfNoneMatch `json:"If-None-Match,omitempty"`
+}
+
+// PatchSubscriptionApplicationJSONPatchPlusJSONBody defines parameters for PatchSubscription.
+type PatchSubscriptionApplicationJSONPatchPlusJSONBody = []JSONPatchOperation
+
+// PatchSubscriptionParams defines parameters for PatchSubscription.
+type PatchSubscriptionParams struct {
+	// IfMatch ETag of the subscription from an earlier response. The request fails with
+	// 412 when the subscription has changed since. Only * or a single tag is accepted.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
 }
 
+// UpdateSubscriptionParams defines parameters for UpdateSubscription.
+type UpdateSubscriptionParams struct {
+	// IfMatch ETag of the subscription from an earlier response. The request fails with
+	// 412 when the subscription has changed since. Only * or a single tag is accepted.
+	IfMatch *IfMatch `json:"If-Match,omitempty"`
+}
+
+// CreateApiKeyJSONRequestBody defines body for CreateApiKey for application/json ContentType.
+type CreateApiKeyJSONRequestBody = NewApiKey
+
+// SetCurrencyRateJSONRequestBody defines body for SetCurrencyRate for application/json ContentType.
+type SetCurrencyRateJSONRequestBody = NewCurrencyRate
+
 // CreateSubscriptionJSONRequestBody defines body for CreateSubscription for application/json ContentType.
 type CreateSubscriptionJSONRequestBody = NewSubscription
 
+// PatchSubscriptionApplicationJSONPatchPlusJSONRequestBody defines body for PatchSubscription for application/json-patch+json ContentType.
+type PatchSubscriptionApplicationJSONPatchPlusJSONRequestBody = PatchSubscriptionApplicationJSONPatchPlusJSONBody
+
+// PatchSubscriptionApplicationMergePatchPlusJSONRequestBody defines body for PatchSubscription for application/merge-patch+json ContentType.
+type PatchSubscriptionApplicationMergePatchPlusJSONRequestBody = ReplaceSubscription
+
 // UpdateSubscriptionJSONRequestBody defines body for UpdateSubscription for application/json ContentType.
-type UpdateSubscriptionJSONRequestBody = UpdateSubscription
+type UpdateSubscriptionJSONRequestBody = ReplaceSubscription
+
+// PauseSubscriptionJSONRequestBody defines body for PauseSubscription for application/json ContentType.
+type PauseSubscriptionJSONRequestBody = NewPause
+
+// ResumeSubscriptionJSONRequestBody defines body for ResumeSubscription for application/json ContentType.
+type ResumeSubscriptionJSONRequestBody = ResumePause
+
+// BatchSubscriptionsJSONRequestBody defines body for BatchSubscriptions for application/json ContentType.
+type BatchSubscriptionsJSONRequestBody = BatchRequest
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// List API keys
+	// (GET /admin/api_keys)
+	ListApiKeys(w http.ResponseWriter, r *http.Request)
+	// Mint an API key
+	// (POST /admin/api_keys)
+	CreateApiKey(w http.ResponseWriter, r *http.Request)
+	// Revoke an API key
+	// (DELETE /admin/api_keys/{id})
+	RevokeApiKey(w http.ResponseWriter, r *http.Request, id openapi_types.UUID)
+	// List the audit log
+	// (GET /admin/audit)
+	ListAudit(w http.ResponseWriter, r *http.Request, params ListAuditParams)
+	// List currency conversion rates
+	// (GET /admin/currency_rates)
+	ListCurrencyRates(w http.ResponseWriter, r *http.Request)
+	// Delete a currency conversion rate
+	// (DELETE /admin/currency_rates/{from}/{to})
+	DeleteCurrencyRate(w http.ResponseWriter, r *http.Request, from string, to string)
+	// Create or replace a currency conversion rate
+	// (PUT /admin/currency_rates/{from}/{to})
+	SetCurrencyRate(w http.ResponseWriter, r *http.Request, from string, to string)
+	// Suggest service names
+	// (GET /services/suggest)
+	SuggestServices(w http.ResponseWriter, r *http.Request, params SuggestServicesParams)
 	// List subscriptions
 	// (GET /subscriptions)
 	ListSubscriptions(w http.ResponseWriter, r *http.Request, params ListSubscriptionsParams)
 	// Create a subscription
 	// (POST /subscriptions)
-	CreateSubscription(w http.ResponseWriter, r *http.Request)
+	CreateSubscription(w http.ResponseWriter, r *http.Request, params CreateSubscriptionParams)
+	// Calculate per-month subscription cost breakdown
+	// (GET /subscriptions/cost_breakdown)
+	GetCostBreakdown(w http.ResponseWriter, r *http.Request, params GetCostBreakdownParams)
+	// Export subscriptions
+	// (GET /subscriptions/export)
+	ExportSubscriptions(w http.ResponseWriter, r *http.Request, params ExportSubscriptionsParams)
+	// Import subscriptions from CSV
+	// (POST /subscriptions/import)
+	ImportSubscriptions(w http.ResponseWriter, r *http.Request, params ImportSubscriptionsParams)
 	// Calculate total subscription cost
 	// (GET /subscriptions/total_cost)
 	GetTotalCost(w http.ResponseWriter, r *http.Request, params GetTotalCostParams)
 	// Delete a subscription
 	// (DELETE /subscriptions/{id})
-	DeleteSubscription(w http.ResponseWriter, r *http.Request, id openapi_types.UUID)
+	DeleteSubscription(w http.ResponseWriter, r *http.Request, id openapi_types.UUID, params DeleteSubscriptionParams)
 	// Get a subscription by ID
 	// (GET /subscriptions/{id})
-	GetSubscriptionById(w http.ResponseWriter, r *http.Request, id openapi_types.UUID)
-	// Update a subscription
+	GetSubscriptionById(w http.ResponseWriter, r *http.Request, id openapi_types.UUID, params GetSubscriptionByIdParams)
+	// Update some fields of a subscription
+	// (PATCH /subscriptions/{id})
+	PatchSubscription(w http.ResponseWriter, r *http.Request, id openapi_types.UUID, params PatchSubscriptionParams)
+	// Replace a subscription
 	// (PUT /subscriptions/{id})
-	UpdateSubscription(w http.ResponseWriter, r *http.Request, id openapi_types.UUID)
+	UpdateSubscription(w http.ResponseWriter, r *http.Request, id openapi_types.UUID, params UpdateSubscriptionParams)
+	// List the changes of a subscription
+	// (GET /subscriptions/{id}/history)
+	ListSubscriptionHistory(w http.ResponseWriter, r *http.Request, id openapi_types.UUID)
+	// Pause a subscription
+	// (POST /subscriptions/{id}/pause)
+	PauseSubscription(w http.ResponseWriter, r *http.Request, id openapi_types.UUID)
+	// List pauses of a subscription
+	// (GET /subscriptions/{id}/pauses)
+	ListSubscriptionPauses(w http.ResponseWriter, r *http.Request, id openapi_types.UUID)
+	// List price changes of a subscription
+	// (GET /subscriptions/{id}/prices)
+	ListSubscriptionPrices(w http.ResponseWriter, r *http.Request, id openapi_types.UUID)
+	// Resume a paused subscription
+	// (POST /subscriptions/{id}/resume)
+	ResumeSubscription(w http.ResponseWriter, r *http.Request, id openapi_types.UUID)
+	// Create, update and delete subscriptions in one request
+	// (POST /subscriptions:batch)
+	BatchSubscriptions(w http.ResponseWriter, r *http.Request)
 }
 
 // Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
 
 type Unimplemented struct{}
 
-// List subscriptions
-// (GET /subscriptions)
-func (_ Unimplemented) ListSubscriptions(w http.ResponseWriter, r *http.Request, params ListSubscriptionsParams) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+// List API keys
+// (GET /admin/api_keys)
+func (_ Unimplemented) ListApiKeys(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Mint an API key
+// (POST /admin/api_keys)
+func (_ Unimplemented) CreateApiKey(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Revoke an API key
+// (DELETE /admin/api_keys/{id})
+func (_ Unimplemented) RevokeApiKey(w http.ResponseWriter, r *http.Request, id openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// List the audit log
+// (GET /admin/audit)
+func (_ Unimplemented) ListAudit(w http.ResponseWriter, r *http.Request, params ListAuditParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// List currency conversion rates
+// (GET /admin/currency_rates)
+func (_ Unimplemented) ListCurrencyRates(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Delete a currency conversion rate
+// (DELETE /admin/currency_rates/{from}/{to})
+func (_ Unimplemented) DeleteCurrencyRate(w http.ResponseWriter, r *http.Request, from string, to string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Create or replace a currency conversion rate
+// (PUT /admin/currency_rates/{from}/{to})
+func (_ Unimplemented) SetCurrencyRate(w http.ResponseWriter, r *http.Request, from string, to string) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Suggest service names
+// (GET /services/suggest)
+func (_ Unimplemented) SuggestServices(w http.ResponseWriter, r *http.Request, params SuggestServicesParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// List subscriptions
+// (GET /subscriptions)
+func (_ Unimplemented) ListSubscriptions(w http.ResponseWriter, r *http.Request, params ListSubscriptionsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Create a subscription
+// (POST /subscriptions)
+func (_ Unimplemented) CreateSubscription(w http.ResponseWriter, r *http.Request, params CreateSubscriptionParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Calculate per-month subscription cost breakdown
+// (GET /subscriptions/cost_breakdown)
+func (_ Unimplemented) GetCostBreakdown(w http.ResponseWriter, r *http.Request, params GetCostBreakdownParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Export subscriptions
+// (GET /subscriptions/export)
+func (_ Unimplemented) ExportSubscriptions(w http.ResponseWriter, r *http.Request, params ExportSubscriptionsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Import subscriptions from CSV
+// (POST /subscriptions/import)
+func (_ Unimplemented) ImportSubscriptions(w http.ResponseWriter, r *http.Request, params ImportSubscriptionsParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Calculate total subscription cost
+// (GET /subscriptions/total_cost)
+func (_ Unimplemented) GetTotalCost(w http.ResponseWriter, r *http.Request, params GetTotalCostParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Delete a subscription
+// (DELETE /subscriptions/{id})
+func (_ Unimplemented) DeleteSubscription(w http.ResponseWriter, r *http.Request, id openapi_types.UUID, params DeleteSubscriptionParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get a subscription by ID
+// (GET /subscriptions/{id})
+func (_ Unimplemented) GetSubscriptionById(w http.ResponseWriter, r *http.Request, id openapi_types.UUID, params GetSubscriptionByIdParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Update some fields of a subscription
+// (PATCH /subscriptions/{id})
+func (_ Unimplemented) PatchSubscription(w http.ResponseWriter, r *http.Request, id openapi_types.UUID, params PatchSubscriptionParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Replace a subscription
+// (PUT /subscriptions/{id})
+func (_ Unimplemented) UpdateSubscription(w http.ResponseWriter, r *http.Request, id openapi_types.UUID, params UpdateSubscriptionParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// List the changes of a subscription
+// (GET /subscriptions/{id}/history)
+func (_ Unimplemented) ListSubscriptionHistory(w http.ResponseWriter, r *http.Request, id openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Pause a subscription
+// (POST /subscriptions/{id}/pause)
+func (_ Unimplemented) PauseSubscription(w http.ResponseWriter, r *http.Request, id openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// List pauses of a subscription
+// (GET /subscriptions/{id}/pauses)
+func (_ Unimplemented) ListSubscriptionPauses(w http.ResponseWriter, r *http.Request, id openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// List price changes of a subscription
+// (GET /subscriptions/{id}/prices)
+func (_ Unimplemented) ListSubscriptionPrices(w http.ResponseWriter, r *http.Request, id openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Resume a paused subscription
+// (POST /subscriptions/{id}/resume)
+func (_ Unimplemented) ResumeSubscription(w http.ResponseWriter, r *http.Request, id openapi_types.UUID) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Create, update and delete subscriptions in one request
+// (POST /subscriptions:batch)
+func (_ Unimplemented) BatchSubscriptions(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// ListApiKeys operation middleware
+func (siw *ServerInterfaceWrapper) ListApiKeys(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListApiKeys(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateApiKey operation middleware
+func (siw *ServerInterfaceWrapper) CreateApiKey(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateApiKey(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RevokeApiKey operation middleware
+func (siw *ServerInterfaceWrapper) RevokeApiKey(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RevokeApiKey(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListAudit operation middleware
+func (siw *ServerInterfaceWrapper) ListAudit(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListAuditParams
+
+	// ------------- Optional query parameter "subscription_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "subscription_id", r.URL.Query(), &params.SubscriptionId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "subscription_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "actor_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "actor_id", r.URL.Query(), &params.ActorId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "actor_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "api_key_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "api_key_id", r.URL.Query(), &params.ApiKeyId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "api_key_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "operation" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "operation", r.URL.Query(), &params.Operation)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "operation", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "from", r.URL.Query(), &params.From)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "to", r.URL.Query(), &params.To)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "to", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListAudit(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListCurrencyRates operation middleware
+func (siw *ServerInterfaceWrapper) ListCurrencyRates(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListCurrencyRates(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteCurrencyRate operation middleware
+func (siw *ServerInterfaceWrapper) DeleteCurrencyRate(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "from" -------------
+	var from string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "from", chi.URLParam(r, "from"), &from, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "to" -------------
+	var to string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "to", chi.URLParam(r, "to"), &to, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "to", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteCurrencyRate(w, r, from, to)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SetCurrencyRate operation middleware
+func (siw *ServerInterfaceWrapper) SetCurrencyRate(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "from" -------------
+	var from string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "from", chi.URLParam(r, "from"), &from, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "from", Err: err})
+		return
+	}
+
+	// ------------- Path parameter "to" -------------
+	var to string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "to", chi.URLParam(r, "to"), &to, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "to", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SetCurrencyRate(w, r, from, to)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SuggestServices operation middleware
+func (siw *ServerInterfaceWrapper) SuggestServices(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params SuggestServicesParams
+
+	// ------------- Required query parameter "q" -------------
+
+	if paramValue := r.URL.Query().Get("q"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "q"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "q", r.URL.Query(), &params.Q)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "q", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SuggestServices(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListSubscriptions operation middleware
+func (siw *ServerInterfaceWrapper) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListSubscriptionsParams
+
+	// ------------- Optional query parameter "user_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "user_id", r.URL.Query(), &params.UserId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "service_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "service_name", r.URL.Query(), &params.ServiceName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "service_name", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "q" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "q", r.URL.Query(), &params.Q)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "q", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "in_trial" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "in_trial", r.URL.Query(), &params.InTrial)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "in_trial", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "active_at" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "active_at", r.URL.Query(), &params.ActiveAt)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "active_at", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "min_cost" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "min_cost", r.URL.Query(), &params.MinCost)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "min_cost", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "max_cost" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "max_cost", r.URL.Query(), &params.MaxCost)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "max_cost", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "started_after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "started_after", r.URL.Query(), &params.StartedAfter)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "started_after", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "started_before" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "started_before", r.URL.Query(), &params.StartedBefore)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "started_before", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "ends_before" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "ends_before", r.URL.Query(), &params.EndsBefore)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "ends_before", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "has_end_date" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "has_end_date", r.URL.Query(), &params.HasEndDate)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "has_end_date", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", false, false, "sort", r.URL.Query(), &params.Sort)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sort", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "cursor", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "page" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page", r.URL.Query(), &params.Page)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "page_size" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_size", r.URL.Query(), &params.PageSize)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page_size", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListSubscriptions(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateSubscription operation middleware
+func (siw *ServerInterfaceWrapper) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CreateSubscriptionParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = &IdempotencyKey
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateSubscription(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetCostBreakdown operation middleware
+func (siw *ServerInterfaceWrapper) GetCostBreakdown(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetCostBreakdownParams
+
+	// ------------- Required query parameter "user_id" -------------
+
+	if paramValue := r.URL.Query().Get("user_id"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "user_id"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "user_id", r.URL.Query(), &params.UserId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "service_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "service_name", r.URL.Query(), &params.ServiceName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "service_name", Err: err})
+		return
+	}
+
+	// ------------- Required query parameter "start" -------------
+
+	if paramValue := r.URL.Query().Get("start"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "start"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "start", r.URL.Query(), &params.Start)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "start", Err: err})
+		return
+	}
+
+	// ------------- Required query parameter "end" -------------
+
+	if paramValue := r.URL.Query().Get("end"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "end"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "end", r.URL.Query(), &params.End)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "end", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "currency" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "currency", r.URL.Query(), &params.Currency)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "currency", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "mode" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "mode", r.URL.Query(), &params.Mode)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mode", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetCostBreakdown(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ExportSubscriptions operation middleware
+func (siw *ServerInterfaceWrapper) ExportSubscriptions(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ExportSubscriptionsParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "user_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "user_id", r.URL.Query(), &params.UserId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "service_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "service_name", r.URL.Query(), &params.ServiceName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "service_name", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "q" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "q", r.URL.Query(), &params.Q)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "q", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "in_trial" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "in_trial", r.URL.Query(), &params.InTrial)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "in_trial", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "active_at" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "active_at", r.URL.Query(), &params.ActiveAt)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "active_at", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "min_cost" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "min_cost", r.URL.Query(), &params.MinCost)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "min_cost", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "max_cost" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "max_cost", r.URL.Query(), &params.MaxCost)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "max_cost", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "started_after" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "started_after", r.URL.Query(), &params.StartedAfter)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "started_after", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "started_before" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "started_before", r.URL.Query(), &params.StartedBefore)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "started_before", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "ends_before" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "ends_before", r.URL.Query(), &params.EndsBefore)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "ends_before", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "has_end_date" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "has_end_date", r.URL.Query(), &params.HasEndDate)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "has_end_date", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	err = runtime.BindQueryParameter("form", false, false, "sort", r.URL.Query(), &params.Sort)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "sort", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ExportSubscriptions(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ImportSubscriptions operation middleware
+func (siw *ServerInterfaceWrapper) ImportSubscriptions(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ImportSubscriptionsParams
+
+	// ------------- Optional query parameter "mode" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "mode", r.URL.Query(), &params.Mode)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mode", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "dry_run" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "dry_run", r.URL.Query(), &params.DryRun)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "dry_run", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ImportSubscriptions(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetTotalCost operation middleware
+func (siw *ServerInterfaceWrapper) GetTotalCost(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTotalCostParams
+
+	// ------------- Required query parameter "user_id" -------------
+
+	if paramValue := r.URL.Query().Get("user_id"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "user_id"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "user_id", r.URL.Query(), &params.UserId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "service_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "service_name", r.URL.Query(), &params.ServiceName)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "service_name", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "start" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "start", r.URL.Query(), &params.Start)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "start", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "end" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "end", r.URL.Query(), &params.End)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "end", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "currency" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "currency", r.URL.Query(), &params.Currency)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "currency", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "mode" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "mode", r.URL.Query(), &params.Mode)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "mode", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetTotalCost(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteSubscription operation middleware
+func (siw *ServerInterfaceWrapper) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteSubscriptionParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
 
-// Create a subscription
-// (POST /subscriptions)
-func (_ Unimplemented) CreateSubscription(w http.ResponseWriter, r *http.Request) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
 
-// Calculate total subscription cost
-// (GET /subscriptions/total_cost)
-func (_ Unimplemented) GetTotalCost(w http.ResponseWriter, r *http.Request, params GetTotalCostParams) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+		params.IfMatch = &IfMatch
 
-// Delete a subscription
-// (DELETE /subscriptions/{id})
-func (_ Unimplemented) DeleteSubscription(w http.ResponseWriter, r *http.Request, id openapi_types.UUID) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	}
 
-// Get a subscription by ID
-// (GET /subscriptions/{id})
-func (_ Unimplemented) GetSubscriptionById(w http.ResponseWriter, r *http.Request, id openapi_types.UUID) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteSubscription(w, r, id, params)
+	}))
 
-// Update a subscription
-// (PUT /subscriptions/{id})
-func (_ Unimplemented) UpdateSubscription(w http.ResponseWriter, r *http.Request, id openapi_types.UUID) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-// ServerInterfaceWrapper converts contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler            ServerInterface
-	HandlerMiddlewares []MiddlewareFunc
-	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+	handler.ServeHTTP(w, r)
 }
 
-type MiddlewareFunc func(http.Handler) http.Handler
-
-// ListSubscriptions operation middleware
-func (siw *ServerInterfaceWrapper) ListSubscriptions(w http.ResponseWriter, r *http.Request) {
+// GetSubscriptionById operation middleware
+func (siw *ServerInterfaceWrapper) GetSubscriptionById(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params ListSubscriptionsParams
-
-	// ------------- Optional query parameter "user_id" -------------
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
 
-	err = runtime.BindQueryParameter("form", true, false, "user_id", r.URL.Query(), &params.UserId)
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
 		return
 	}
 
-	// ------------- Optional query parameter "service_name" -------------
+	ctx := r.Context()
 
-	err = runtime.BindQueryParameter("form", true, false, "service_name", r.URL.Query(), &params.ServiceName)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "service_name", Err: err})
-		return
-	}
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
 
-	// ------------- Optional query parameter "page" -------------
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
 
-	err = runtime.BindQueryParameter("form", true, false, "page", r.URL.Query(), &params.Page)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page", Err: err})
-		return
-	}
+	r = r.WithContext(ctx)
 
-	// ------------- Optional query parameter "page_size" -------------
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetSubscriptionByIdParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-None-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-None-Match")]; found {
+		var IfNoneMatch IfNoneMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-None-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-None-Match", valueList[0], &IfNoneMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-None-Match", Err: err})
+			return
+		}
+
+		params.IfNoneMatch = &IfNoneMatch
 
-	err = runtime.BindQueryParameter("form", true, false, "page_size", r.URL.Query(), &params.PageSize)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page_size", Err: err})
-		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.ListSubscriptions(w, r, params)
+		siw.Handler.GetSubscriptionById(w, r, id, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -227,11 +2025,54 @@ func (siw *ServerInterfaceWrapper) ListSubscriptions(w http.ResponseWriter, r *h
 	handler.ServeHTTP(w, r)
 }
 
-// CreateSubscription operation middleware
-func (siw *ServerInterfaceWrapper) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+// PatchSubscription operation middleware
+func (siw *ServerInterfaceWrapper) PatchSubscription(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchSubscriptionParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.CreateSubscription(w, r)
+		siw.Handler.PatchSubscription(w, r, id, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -241,55 +2082,120 @@ func (siw *ServerInterfaceWrapper) CreateSubscription(w http.ResponseWriter, r *
 	handler.ServeHTTP(w, r)
 }
 
-// GetTotalCost operation middleware
-func (siw *ServerInterfaceWrapper) GetTotalCost(w http.ResponseWriter, r *http.Request) {
+// UpdateSubscription operation middleware
+func (siw *ServerInterfaceWrapper) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
 	// Parameter object where we will unmarshal all parameters from the context
-	var params GetTotalCostParams
+	var params UpdateSubscriptionParams
 
-	// ------------- Required query parameter "user_id" -------------
+	headers := r.Header
 
-	if paramValue := r.URL.Query().Get("user_id"); paramValue != "" {
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch IfMatch
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "If-Match", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "If-Match", valueList[0], &IfMatch, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "If-Match", Err: err})
+			return
+		}
+
+		params.IfMatch = &IfMatch
 
-	} else {
-		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "user_id"})
-		return
 	}
 
-	err = runtime.BindQueryParameter("form", true, true, "user_id", r.URL.Query(), &params.UserId)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "user_id", Err: err})
-		return
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateSubscription(w, r, id, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
 	}
 
-	// ------------- Optional query parameter "service_name" -------------
+	handler.ServeHTTP(w, r)
+}
 
-	err = runtime.BindQueryParameter("form", true, false, "service_name", r.URL.Query(), &params.ServiceName)
+// ListSubscriptionHistory operation middleware
+func (siw *ServerInterfaceWrapper) ListSubscriptionHistory(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "service_name", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
 		return
 	}
 
-	// ------------- Optional query parameter "start" -------------
+	ctx := r.Context()
 
-	err = runtime.BindQueryParameter("form", true, false, "start", r.URL.Query(), &params.Start)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "start", Err: err})
-		return
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListSubscriptionHistory(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
 	}
 
-	// ------------- Optional query parameter "end" -------------
+	handler.ServeHTTP(w, r)
+}
 
-	err = runtime.BindQueryParameter("form", true, false, "end", r.URL.Query(), &params.End)
+// PauseSubscription operation middleware
+func (siw *ServerInterfaceWrapper) PauseSubscription(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "id" -------------
+	var id openapi_types.UUID
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", chi.URLParam(r, "id"), &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "end", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
 		return
 	}
 
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetTotalCost(w, r, params)
+		siw.Handler.PauseSubscription(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -299,8 +2205,8 @@ func (siw *ServerInterfaceWrapper) GetTotalCost(w http.ResponseWriter, r *http.R
 	handler.ServeHTTP(w, r)
 }
 
-// DeleteSubscription operation middleware
-func (siw *ServerInterfaceWrapper) DeleteSubscription(w http.ResponseWriter, r *http.Request) {
+// ListSubscriptionPauses operation middleware
+func (siw *ServerInterfaceWrapper) ListSubscriptionPauses(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
@@ -313,8 +2219,16 @@ func (siw *ServerInterfaceWrapper) DeleteSubscription(w http.ResponseWriter, r *
 		return
 	}
 
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.DeleteSubscription(w, r, id)
+		siw.Handler.ListSubscriptionPauses(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -324,8 +2238,8 @@ func (siw *ServerInterfaceWrapper) DeleteSubscription(w http.ResponseWriter, r *
 	handler.ServeHTTP(w, r)
 }
 
-// GetSubscriptionById operation middleware
-func (siw *ServerInterfaceWrapper) GetSubscriptionById(w http.ResponseWriter, r *http.Request) {
+// ListSubscriptionPrices operation middleware
+func (siw *ServerInterfaceWrapper) ListSubscriptionPrices(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
@@ -338,8 +2252,16 @@ func (siw *ServerInterfaceWrapper) GetSubscriptionById(w http.ResponseWriter, r
 		return
 	}
 
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetSubscriptionById(w, r, id)
+		siw.Handler.ListSubscriptionPrices(w, r, id)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -349,8 +2271,8 @@ func (siw *ServerInterfaceWrapper) GetSubscriptionById(w http.ResponseWriter, r
 	handler.ServeHTTP(w, r)
 }
 
-// UpdateSubscription operation middleware
-func (siw *ServerInterfaceWrapper) UpdateSubscription(w http.ResponseWriter, r *http.Request) {
+// ResumeSubscription operation middleware
+func (siw *ServerInterfaceWrapper) ResumeSubscription(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
@@ -363,8 +2285,38 @@ func (siw *ServerInterfaceWrapper) UpdateSubscription(w http.ResponseWriter, r *
 		return
 	}
 
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ResumeSubscription(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// BatchSubscriptions operation middleware
+func (siw *ServerInterfaceWrapper) BatchSubscriptions(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, ApiKeyAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.UpdateSubscription(w, r, id)
+		siw.Handler.BatchSubscriptions(w, r)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -487,12 +2439,45 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/api_keys", wrapper.ListApiKeys)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/api_keys", wrapper.CreateApiKey)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/admin/api_keys/{id}", wrapper.RevokeApiKey)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/audit", wrapper.ListAudit)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/currency_rates", wrapper.ListCurrencyRates)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/admin/currency_rates/{from}/{to}", wrapper.DeleteCurrencyRate)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/admin/currency_rates/{from}/{to}", wrapper.SetCurrencyRate)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/services/suggest", wrapper.SuggestServices)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/subscriptions", wrapper.ListSubscriptions)
 	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/subscriptions", wrapper.CreateSubscription)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/subscriptions/cost_breakdown", wrapper.GetCostBreakdown)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/subscriptions/export", wrapper.ExportSubscriptions)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/subscriptions/import", wrapper.ImportSubscriptions)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/subscriptions/total_cost", wrapper.GetTotalCost)
 	})
@@ -502,9 +2487,30 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/subscriptions/{id}", wrapper.GetSubscriptionById)
 	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/subscriptions/{id}", wrapper.PatchSubscription)
+	})
 	r.Group(func(r chi.Router) {
 		r.Put(options.BaseURL+"/subscriptions/{id}", wrapper.UpdateSubscription)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/subscriptions/{id}/history", wrapper.ListSubscriptionHistory)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/subscriptions/{id}/pause", wrapper.PauseSubscription)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/subscriptions/{id}/pauses", wrapper.ListSubscriptionPauses)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/subscriptions/{id}/prices", wrapper.ListSubscriptionPrices)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/subscriptions/{id}/resume", wrapper.ResumeSubscription)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/subscriptions:batch", wrapper.BatchSubscriptions)
+	})
 
 	return r
 }
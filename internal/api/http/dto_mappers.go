@@ -1,68 +1,290 @@
 package http
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
 	"github.com/shrtyk/subscriptions-service/internal/core/domain"
 )
 
-func toSubscriptionDTO(sub *domain.Subscription) *dto.Subscription {
+func toSubscriptionDTO(sub *domain.Subscription, layout DateLayout) *dto.Subscription {
 	var endDate *string
 	if sub.EndDate != nil {
-		s := dateLayout.format(*sub.EndDate)
+		s := layout.format(*sub.EndDate)
 		endDate = &s
 	}
 
+	var trialEnd *string
+	if sub.TrialEnd != nil {
+		s := layout.format(*sub.TrialEnd)
+		trialEnd = &s
+	}
+
 	return &dto.Subscription{
-		Id:          sub.ID,
-		ServiceName: sub.ServiceName,
-		MonthlyCost: sub.MonthlyCost,
-		UserId:      sub.UserID,
-		StartDate:   dateLayout.format(sub.StartDate),
-		EndDate:     endDate,
+		Id:            sub.ID,
+		ServiceName:   sub.ServiceName,
+		Price:         sub.Price,
+		BillingPeriod: dto.BillingPeriod(sub.BillingPeriod),
+		MonthlyCost:   sub.MonthlyEquivalent(),
+		Currency:      sub.Currency,
+		UserId:        sub.UserID,
+		StartDate:     layout.format(sub.StartDate),
+		EndDate:       endDate,
+		TrialEnd:      trialEnd,
 	}
 }
 
 func fromNewSubscriptionDTO(d *dto.NewSubscription) (*domain.Subscription, error) {
-	startDate, endDate, err := validateNewSubscription(d)
+	startDate, endDate, trialEnd, err := validateNewSubscription(d)
+	if err != nil {
+		return nil, err
+	}
+
+	currency, err := validateCurrency(d.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	price, period, err := validatePrice(d.Price, d.MonthlyCost, d.BillingPeriod)
 	if err != nil {
 		return nil, err
 	}
+	if price == nil {
+		return nil, &DTOValidationError{ClientMessage: priceRequiredMsg}
+	}
+	billingPeriod := domain.BillingMonthly
+	if period != nil {
+		billingPeriod = *period
+	}
 
 	return &domain.Subscription{
-		ServiceName: d.ServiceName,
-		MonthlyCost: d.MonthlyCost,
-		UserID:      uuid.UUID(d.UserId),
-		StartDate:   startDate,
-		EndDate:     endDate,
+		ServiceName:   d.ServiceName,
+		Price:         *price,
+		BillingPeriod: billingPeriod,
+		Currency:      currency,
+		UserID:        uuid.UUID(d.UserId),
+		StartDate:     startDate,
+		EndDate:       endDate,
+		TrialEnd:      trialEnd,
 	}, nil
 }
 
-func fromUpdateSubscriptionRequest(req *UpdateSubscriptionRequest) (*domain.SubscriptionUpdate, error) {
-	endDate, clearEndDate, err := validateUpdateSubscriptionRequest(req)
+// fromReplaceSubscriptionDTO validates d like a new subscription and returns an
+// update of every field, clearing the optional ones it leaves out.
+func fromReplaceSubscriptionDTO(d *dto.ReplaceSubscription) (*domain.SubscriptionUpdate, error) {
+	sub, err := fromNewSubscriptionDTO(&dto.NewSubscription{
+		ServiceName:   d.ServiceName,
+		Price:         &d.Price,
+		BillingPeriod: d.BillingPeriod,
+		Currency:      d.Currency,
+		UserId:        d.UserId,
+		StartDate:     d.StartDate,
+		EndDate:       d.EndDate,
+		TrialEnd:      d.TrialEnd,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	domainUpdate := &domain.SubscriptionUpdate{
-		ServiceName:  req.ServiceName,
-		MonthlyCost:  req.MonthlyCost,
-		EndDate:      endDate,
-		ClearEndDate: clearEndDate,
+	priceEffectiveFrom, err := validatePriceEffectiveFrom(d.PriceEffectiveFrom, &sub.Price)
+	if err != nil {
+		return nil, err
 	}
 
-	return domainUpdate, nil
+	return replacementOf(sub, priceEffectiveFrom), nil
+}
+
+// replacementOf returns an update of every field to the one of sub.
+func replacementOf(sub *domain.Subscription, priceEffectiveFrom *time.Time) *domain.SubscriptionUpdate {
+	return &domain.SubscriptionUpdate{
+		ServiceName:        &sub.ServiceName,
+		Price:              &sub.Price,
+		PriceEffectiveFrom: priceEffectiveFrom,
+		BillingPeriod:      &sub.BillingPeriod,
+		Currency:           &sub.Currency,
+		UserID:             &sub.UserID,
+		StartDate:          &sub.StartDate,
+		EndDate:            sub.EndDate,
+		ClearEndDate:       sub.EndDate == nil,
+		TrialEnd:           sub.TrialEnd,
+		ClearTrialEnd:      sub.TrialEnd == nil,
+	}
 }
 
-func toListFilter(params dto.ListSubscriptionsParams) domain.SubscriptionFilter {
+// toReplaceSubscriptionDTO returns sub as the document patches apply to. Its
+// dates are in the ISO layout, month-only ones would lose the day.
+func toReplaceSubscriptionDTO(sub *domain.Subscription) *dto.ReplaceSubscription {
+	var endDate *string
+	if sub.EndDate != nil {
+		s := isoDateLayout.format(*sub.EndDate)
+		endDate = &s
+	}
+
+	var trialEnd *string
+	if sub.TrialEnd != nil {
+		s := isoDateLayout.format(*sub.TrialEnd)
+		trialEnd = &s
+	}
+
+	billingPeriod := dto.BillingPeriod(sub.BillingPeriod)
+	currency := sub.Currency
+
+	return &dto.ReplaceSubscription{
+		ServiceName:   sub.ServiceName,
+		Price:         sub.Price,
+		BillingPeriod: &billingPeriod,
+		Currency:      &currency,
+		UserId:        sub.UserID,
+		StartDate:     isoDateLayout.format(sub.StartDate),
+		EndDate:       endDate,
+		TrialEnd:      trialEnd,
+	}
+}
+
+func toListFilter(params dto.ListSubscriptionsParams) (domain.SubscriptionFilter, error) {
+	sort, err := validateSort(params.Sort)
+	if err != nil {
+		return domain.SubscriptionFilter{}, err
+	}
+
+	after, err := validateCursor(params.Cursor, params.Page)
+	if err != nil {
+		return domain.SubscriptionFilter{}, err
+	}
+
+	if err := validateCostRange(params.MinCost, params.MaxCost); err != nil {
+		return domain.SubscriptionFilter{}, err
+	}
+
 	filter := domain.SubscriptionFilter{
 		Page:        params.Page,
 		PageSize:    params.PageSize,
 		ServiceName: params.ServiceName,
+		Query:       params.Q,
+		InTrial:     params.InTrial,
+		MinCost:     params.MinCost,
+		MaxCost:     params.MaxCost,
+		HasEndDate:  params.HasEndDate,
+		Sort:        sort,
+		After:       after,
 	}
 	if params.UserId != nil {
 		uid := uuid.UUID(*params.UserId)
 		filter.UserID = &uid
 	}
-	return filter
+
+	if filter.ActiveAt, err = validateMonth(params.ActiveAt); err != nil {
+		return domain.SubscriptionFilter{}, err
+	}
+	// A month-only date is after the whole month, or before the whole month.
+	if filter.StartedAfter, err = validateDate(params.StartedAfter, parseEndDate); err != nil {
+		return domain.SubscriptionFilter{}, err
+	}
+	if filter.StartedBefore, err = validateDate(params.StartedBefore, parseStartDate); err != nil {
+		return domain.SubscriptionFilter{}, err
+	}
+	if filter.EndsBefore, err = validateDate(params.EndsBefore, parseStartDate); err != nil {
+		return domain.SubscriptionFilter{}, err
+	}
+
+	return filter, nil
+}
+
+// toExportFilter is toListFilter for all pages.
+func toExportFilter(params dto.ExportSubscriptionsParams) (domain.SubscriptionFilter, error) {
+	return toListFilter(dto.ListSubscriptionsParams{
+		UserId:        params.UserId,
+		ServiceName:   params.ServiceName,
+		Q:             params.Q,
+		InTrial:       params.InTrial,
+		ActiveAt:      params.ActiveAt,
+		MinCost:       params.MinCost,
+		MaxCost:       params.MaxCost,
+		StartedAfter:  params.StartedAfter,
+		StartedBefore: params.StartedBefore,
+		EndsBefore:    params.EndsBefore,
+		HasEndDate:    params.HasEndDate,
+		Sort:          params.Sort,
+	})
+}
+
+func toSubscriptionPageDTO(page *domain.SubscriptionPage, layout DateLayout) *dto.SubscriptionPage {
+	d := &dto.SubscriptionPage{
+		Items:    toSubscriptionDTOs(page.Items, layout),
+		Total:    page.Total,
+		PageSize: page.PageSize,
+	}
+	if page.Page > 0 {
+		d.Page = &page.Page
+	}
+	if page.NextCursor != nil {
+		cursor := encodeCursor(*page.NextCursor)
+		d.NextCursor = &cursor
+	}
+	return d
+}
+
+func toSubscriptionDTOs(subs []domain.Subscription, layout DateLayout) []dto.Subscription {
+	dtoSubs := make([]dto.Subscription, 0, len(subs))
+	for _, sub := range subs {
+		dtoSubs = append(dtoSubs, *toSubscriptionDTO(&sub, layout))
+	}
+	return dtoSubs
+}
+
+func toCostBreakdownDTO(months []domain.MonthCost, currency string) *dto.CostBreakdown {
+	breakdown := &dto.CostBreakdown{
+		Currency: currency,
+		Months:   make([]dto.MonthCost, 0, len(months)),
+	}
+
+	for _, month := range months {
+		items := make([]dto.CostLineItem, 0, len(month.Items))
+		for _, item := range month.Items {
+			items = append(items, dto.CostLineItem{
+				SubscriptionId: item.SubscriptionID,
+				ServiceName:    item.ServiceName,
+				Cost:           item.Cost,
+			})
+		}
+
+		breakdown.Months = append(breakdown.Months, dto.MonthCost{
+			Month:     dateLayout.format(month.Month),
+			TotalCost: month.Total,
+			Items:     items,
+		})
+		breakdown.TotalCost += month.Total
+	}
+
+	return breakdown
+}
+
+func toCurrencyRateDTO(rate *domain.CurrencyRate) *dto.CurrencyRate {
+	return &dto.CurrencyRate{
+		From:      rate.From,
+		To:        rate.To,
+		Rate:      rate.Rate,
+		UpdatedAt: rate.UpdatedAt,
+	}
+}
+
+func toPriceChangeDTO(change *domain.PriceChange, layout DateLayout) *dto.PriceChange {
+	return &dto.PriceChange{
+		EffectiveFrom: layout.format(change.EffectiveFrom),
+		Price:         change.Price,
+		CreatedAt:     change.CreatedAt,
+	}
+}
+
+func toPauseDTO(pause *domain.Pause, layout DateLayout) *dto.Pause {
+	d := &dto.Pause{
+		PausedFrom: layout.format(pause.PausedFrom),
+		CreatedAt:  pause.CreatedAt,
+	}
+	if pause.ResumedFrom != nil {
+		resumedFrom := layout.format(*pause.ResumedFrom)
+		d.ResumedFrom = &resumedFrom
+	}
+	return d
 }
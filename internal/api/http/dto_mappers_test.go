@@ -1,7 +1,6 @@
 package http
 
 import (
-	"encoding/json"
 	"testing"
 	"time"
 
@@ -9,8 +8,13 @@ import (
 	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
 	"github.com/shrtyk/subscriptions-service/internal/core/domain"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+func ptr[T any](value T) *T {
+	return &value
+}
+
 func Test_toSubscriptionDTO(t *testing.T) {
 	t.Parallel()
 
@@ -18,46 +22,109 @@ func Test_toSubscriptionDTO(t *testing.T) {
 	endDate := time.Date(2026, time.December, 1, 0, 0, 0, 0, time.UTC)
 
 	tests := []struct {
-		name string
-		sub  *domain.Subscription
-		want *dto.Subscription
+		name   string
+		sub    *domain.Subscription
+		layout DateLayout
+		want   *dto.Subscription
 	}{
 		{
 			name: "Subscription with end date",
 			sub: &domain.Subscription{
-				ID:          uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"),
-				ServiceName: "Netflix",
-				MonthlyCost: 1500,
-				UserID:      uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12"),
-				StartDate:   time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
-				EndDate:     &endDate,
+				ID:            uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"),
+				ServiceName:   "Netflix",
+				Price:         18000,
+				BillingPeriod: domain.BillingYearly,
+				Currency:      "USD",
+				UserID:        uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12"),
+				StartDate:     time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+				EndDate:       &endDate,
 			},
+			layout: dateLayout,
 			want: &dto.Subscription{
-				Id:          uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"),
-				ServiceName: "Netflix",
-				MonthlyCost: 1500,
-				UserId:      uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12"),
-				StartDate:   "01-2025",
-				EndDate:     &endDateStr,
+				Id:            uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11"),
+				ServiceName:   "Netflix",
+				Price:         18000,
+				BillingPeriod: dto.Yearly,
+				MonthlyCost:   1500,
+				Currency:      "USD",
+				UserId:        uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a12"),
+				StartDate:     "01-2025",
+				EndDate:       &endDateStr,
 			},
 		},
 		{
 			name: "Subscription without end date",
 			sub: &domain.Subscription{
-				ID:          uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a13"),
-				ServiceName: "Spotify",
-				MonthlyCost: 500,
-				UserID:      uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a14"),
-				StartDate:   time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
-				EndDate:     nil,
+				ID:            uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a13"),
+				ServiceName:   "Spotify",
+				Price:         500,
+				BillingPeriod: domain.BillingMonthly,
+				Currency:      "RUB",
+				UserID:        uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a14"),
+				StartDate:     time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+				EndDate:       nil,
 			},
+			layout: dateLayout,
 			want: &dto.Subscription{
-				Id:          uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a13"),
-				ServiceName: "Spotify",
-				MonthlyCost: 500,
-				UserId:      uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a14"),
-				StartDate:   "03-2024",
-				EndDate:     nil,
+				Id:            uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a13"),
+				ServiceName:   "Spotify",
+				Price:         500,
+				BillingPeriod: dto.Monthly,
+				MonthlyCost:   500,
+				Currency:      "RUB",
+				UserId:        uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a14"),
+				StartDate:     "03-2024",
+				EndDate:       nil,
+			},
+		},
+		{
+			name: "ISO dates",
+			sub: &domain.Subscription{
+				ID:            uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a15"),
+				ServiceName:   "Kinopoisk",
+				Price:         100,
+				BillingPeriod: domain.BillingWeekly,
+				Currency:      "RUB",
+				UserID:        uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a16"),
+				StartDate:     time.Date(2025, time.January, 28, 0, 0, 0, 0, time.UTC),
+				EndDate:       &endDate,
+			},
+			layout: isoDateLayout,
+			want: &dto.Subscription{
+				Id:            uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a15"),
+				ServiceName:   "Kinopoisk",
+				Price:         100,
+				BillingPeriod: dto.Weekly,
+				MonthlyCost:   433,
+				Currency:      "RUB",
+				UserId:        uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a16"),
+				StartDate:     "2025-01-28",
+				EndDate:       ptr("2026-12-01"),
+			},
+		},
+		{
+			name: "Subscription with a trial",
+			sub: &domain.Subscription{
+				ID:            uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a17"),
+				ServiceName:   "Okko",
+				Price:         300,
+				BillingPeriod: domain.BillingMonthly,
+				Currency:      "RUB",
+				UserID:        uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a18"),
+				StartDate:     time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC),
+				TrialEnd:      ptr(time.Date(2025, time.February, 14, 0, 0, 0, 0, time.UTC)),
+			},
+			layout: isoDateLayout,
+			want: &dto.Subscription{
+				Id:            uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a17"),
+				ServiceName:   "Okko",
+				Price:         300,
+				BillingPeriod: dto.Monthly,
+				MonthlyCost:   300,
+				Currency:      "RUB",
+				UserId:        uuid.MustParse("a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a18"),
+				StartDate:     "2025-01-15",
+				TrialEnd:      ptr("2025-02-14"),
 			},
 		},
 	}
@@ -66,7 +133,7 @@ func Test_toSubscriptionDTO(t *testing.T) {
 		tc := tt
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			got := toSubscriptionDTO(tc.sub)
+			got := toSubscriptionDTO(tc.sub, tc.layout)
 			assert.Equal(t, tc.want, got)
 		})
 	}
@@ -77,7 +144,7 @@ func Test_fromNewSubscriptionDTO(t *testing.T) {
 
 	userID := uuid.New()
 	startDate := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
-	endDate := time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)
 	endDateStr := "12-2025"
 
 	tests := []struct {
@@ -89,18 +156,22 @@ func Test_fromNewSubscriptionDTO(t *testing.T) {
 		{
 			name: "Valid new subscription with end date",
 			dto: &dto.NewSubscription{
-				ServiceName: "New Service",
-				MonthlyCost: 200,
-				UserId:      userID,
-				StartDate:   "01-2025",
-				EndDate:     &endDateStr,
+				ServiceName:   "New Service",
+				Price:         ptr(600),
+				BillingPeriod: ptr(dto.Quarterly),
+				Currency:      func(s string) *string { return &s }("EUR"),
+				UserId:        userID,
+				StartDate:     "01-2025",
+				EndDate:       &endDateStr,
 			},
 			want: &domain.Subscription{
-				ServiceName: "New Service",
-				MonthlyCost: 200,
-				UserID:      userID,
-				StartDate:   startDate,
-				EndDate:     &endDate,
+				ServiceName:   "New Service",
+				Price:         600,
+				BillingPeriod: domain.BillingQuarterly,
+				Currency:      "EUR",
+				UserID:        userID,
+				StartDate:     startDate,
+				EndDate:       &endDate,
 			},
 			wantErr: false,
 		},
@@ -108,17 +179,39 @@ func Test_fromNewSubscriptionDTO(t *testing.T) {
 			name: "Valid new subscription without end date",
 			dto: &dto.NewSubscription{
 				ServiceName: "Another Service",
-				MonthlyCost: 100,
+				Price:       ptr(100),
 				UserId:      userID,
 				StartDate:   "01-2025",
 				EndDate:     nil,
 			},
 			want: &domain.Subscription{
-				ServiceName: "Another Service",
-				MonthlyCost: 100,
-				UserID:      userID,
-				StartDate:   startDate,
-				EndDate:     nil,
+				ServiceName:   "Another Service",
+				Price:         100,
+				BillingPeriod: domain.BillingMonthly,
+				Currency:      domain.DefaultCurrency,
+				UserID:        userID,
+				StartDate:     startDate,
+				EndDate:       nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Valid new subscription with a trial",
+			dto: &dto.NewSubscription{
+				ServiceName: "Trial Service",
+				Price:       ptr(100),
+				UserId:      userID,
+				StartDate:   "2025-01-15",
+				TrialMonths: ptr(2),
+			},
+			want: &domain.Subscription{
+				ServiceName:   "Trial Service",
+				Price:         100,
+				BillingPeriod: domain.BillingMonthly,
+				Currency:      domain.DefaultCurrency,
+				UserID:        userID,
+				StartDate:     time.Date(2025, time.January, 15, 0, 0, 0, 0, time.UTC),
+				TrialEnd:      ptr(time.Date(2025, time.March, 14, 0, 0, 0, 0, time.UTC)),
 			},
 			wantErr: false,
 		},
@@ -126,7 +219,7 @@ func Test_fromNewSubscriptionDTO(t *testing.T) {
 			name: "Invalid start date format",
 			dto: &dto.NewSubscription{
 				ServiceName: "Invalid Date",
-				MonthlyCost: 50,
+				Price:       ptr(50),
 				UserId:      userID,
 				StartDate:   "2025-01",
 				EndDate:     nil,
@@ -138,7 +231,7 @@ func Test_fromNewSubscriptionDTO(t *testing.T) {
 			name: "Invalid end date format",
 			dto: &dto.NewSubscription{
 				ServiceName: "Invalid Date",
-				MonthlyCost: 50,
+				Price:       ptr(50),
 				UserId:      userID,
 				StartDate:   "01-2025",
 				EndDate:     func(s string) *string { return &s }("2025-12"),
@@ -146,11 +239,23 @@ func Test_fromNewSubscriptionDTO(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "Invalid currency",
+			dto: &dto.NewSubscription{
+				ServiceName: "Bad Currency",
+				Price:       ptr(50),
+				Currency:    func(s string) *string { return &s }("rub"),
+				UserId:      userID,
+				StartDate:   "01-2025",
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "Start date after end date",
 			dto: &dto.NewSubscription{
 				ServiceName: "Bad Dates",
-				MonthlyCost: 50,
+				Price:       ptr(50),
 				UserId:      userID,
 				StartDate:   "12-2025",
 				EndDate:     func(s string) *string { return &s }("01-2025"),
@@ -176,111 +281,178 @@ func Test_fromNewSubscriptionDTO(t *testing.T) {
 	}
 }
 
-func Test_fromUpdateSubscriptionRequest(t *testing.T) {
+func Test_fromReplaceSubscriptionDTO(t *testing.T) {
 	t.Parallel()
 
-	serviceName := "Updated Service"
-	monthlyCost := 300
-	endDate := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	userID := uuid.New()
+	startDate := time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC)
+	trialEnd := time.Date(2025, time.April, 14, 0, 0, 0, 0, time.UTC)
+	effectiveFrom := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
 
 	tests := []struct {
 		name    string
-		req     *UpdateSubscriptionRequest
+		d       *dto.ReplaceSubscription
 		want    *domain.SubscriptionUpdate
-		wantErr bool
+		wantMsg string
 	}{
 		{
-			name: "Update with all fields",
-			req: &UpdateSubscriptionRequest{
-				ServiceName: &serviceName,
-				MonthlyCost: &monthlyCost,
-				EndDate:     json.RawMessage(`"01-2026"`),
+			name: "All fields",
+			d: &dto.ReplaceSubscription{
+				ServiceName:        "Updated Service",
+				Price:              3000,
+				PriceEffectiveFrom: ptr("06-2025"),
+				BillingPeriod:      ptr(dto.Yearly),
+				Currency:           ptr("USD"),
+				UserId:             userID,
+				StartDate:          "2025-03-15",
+				EndDate:            ptr("01-2026"),
+				TrialEnd:           ptr("2025-04-14"),
 			},
 			want: &domain.SubscriptionUpdate{
-				ServiceName: &serviceName,
-				MonthlyCost: &monthlyCost,
-				EndDate:     &endDate,
+				ServiceName:        ptr("Updated Service"),
+				Price:              ptr(3000),
+				PriceEffectiveFrom: &effectiveFrom,
+				BillingPeriod:      ptr(domain.BillingYearly),
+				Currency:           ptr("USD"),
+				UserID:             &userID,
+				StartDate:          &startDate,
+				EndDate:            &endDate,
+				TrialEnd:           &trialEnd,
 			},
-			wantErr: false,
 		},
 		{
-			name: "Clear end date with null",
-			req: &UpdateSubscriptionRequest{
-				EndDate: json.RawMessage(`null`),
+			name: "Left out fields are reset",
+			d: &dto.ReplaceSubscription{
+				ServiceName: "Updated Service",
+				Price:       300,
+				UserId:      userID,
+				StartDate:   "03-2025",
 			},
 			want: &domain.SubscriptionUpdate{
-				ClearEndDate: true,
+				ServiceName:   ptr("Updated Service"),
+				Price:         ptr(300),
+				BillingPeriod: ptr(domain.BillingMonthly),
+				Currency:      ptr(domain.DefaultCurrency),
+				UserID:        &userID,
+				StartDate:     ptr(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)),
+				ClearEndDate:  true,
+				ClearTrialEnd: true,
 			},
-			wantErr: false,
 		},
 		{
-			name: "Absent end date",
-			req: &UpdateSubscriptionRequest{
-				ServiceName: &serviceName,
-			},
-			want: &domain.SubscriptionUpdate{
-				ServiceName: &serviceName,
-			},
-			wantErr: false,
+			name:    "Invalid billing period",
+			d:       &dto.ReplaceSubscription{Price: 300, StartDate: "03-2025", BillingPeriod: ptr(dto.BillingPeriod("daily"))},
+			wantMsg: invalidBillingPeriodMsg,
 		},
 		{
-			name: "Invalid end date format",
-			req: &UpdateSubscriptionRequest{
-				EndDate: json.RawMessage(`"2026-01"`),
-			},
-			want:    nil,
-			wantErr: true,
+			name:    "Invalid currency",
+			d:       &dto.ReplaceSubscription{Price: 300, StartDate: "03-2025", Currency: ptr("US")},
+			wantMsg: invalidCurrencyMsg,
 		},
 		{
-			name: "Invalid end date json",
-			req: &UpdateSubscriptionRequest{
-				EndDate: json.RawMessage(`not-a-string`),
-			},
-			want:    nil,
-			wantErr: true,
+			name:    "Missing start date",
+			d:       &dto.ReplaceSubscription{Price: 300},
+			wantMsg: invalidSubDateMsg,
+		},
+		{
+			name:    "Trial end before start date",
+			d:       &dto.ReplaceSubscription{Price: 300, StartDate: "03-2025", TrialEnd: ptr("2025-02-01")},
+			wantMsg: trialEndBeforeStartMsg,
+		},
+		{
+			name:    "Invalid effective month",
+			d:       &dto.ReplaceSubscription{Price: 300, StartDate: "03-2025", PriceEffectiveFrom: ptr("2025-06")},
+			wantMsg: invalidDateMsg,
 		},
 	}
 
 	for _, tt := range tests {
-		tc := tt
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
-			got, err := fromUpdateSubscriptionRequest(tc.req)
-			if tc.wantErr {
-				assert.Error(t, err)
+
+			got, err := fromReplaceSubscriptionDTO(tt.d)
+			if tt.wantMsg != "" {
+				var dtoErr *DTOValidationError
+				require.ErrorAs(t, err, &dtoErr)
+				assert.Equal(t, tt.wantMsg, dtoErr.ClientMessage)
 				assert.Nil(t, got)
-			} else {
-				assert.NoError(t, err)
-				assert.Equal(t, tc.want, got)
+				return
 			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
 		})
 	}
 }
 
+func Test_toReplaceSubscriptionDTO(t *testing.T) {
+	t.Parallel()
+
+	endDate := time.Date(2026, time.January, 31, 0, 0, 0, 0, time.UTC)
+	sub := &domain.Subscription{
+		ServiceName:   "Okko",
+		Price:         400,
+		BillingPeriod: domain.BillingQuarterly,
+		Currency:      "RUB",
+		UserID:        uuid.New(),
+		StartDate:     time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC),
+		EndDate:       &endDate,
+	}
+
+	d := toReplaceSubscriptionDTO(sub)
+	assert.Equal(t, &dto.ReplaceSubscription{
+		ServiceName:   "Okko",
+		Price:         400,
+		BillingPeriod: ptr(dto.Quarterly),
+		Currency:      ptr("RUB"),
+		UserId:        sub.UserID,
+		StartDate:     "2025-03-15",
+		EndDate:       ptr("2026-01-31"),
+	}, d)
+
+	// Replacing a subscription with its own document changes nothing.
+	update, err := fromReplaceSubscriptionDTO(d)
+	require.NoError(t, err)
+	assert.Equal(t, sub.StartDate, *update.StartDate)
+	assert.Equal(t, sub.EndDate, update.EndDate)
+	assert.True(t, update.ClearTrialEnd)
+}
+
 func Test_toListFilter(t *testing.T) {
 	t.Parallel()
 
 	userID := uuid.New()
 	serviceName := "Test Service"
+	query := "serv"
 	page := 1
 	pageSize := 10
+	inTrial := true
+	cursor := domain.Cursor{CreatedAt: time.Date(2025, time.March, 1, 12, 0, 0, 0, time.UTC), BillingPeriod: domain.BillingMonthly, ID: uuid.New()}
+	encodedCursor := encodeCursor(cursor)
 
 	tests := []struct {
-		name   string
-		params dto.ListSubscriptionsParams
-		want   domain.SubscriptionFilter
+		name    string
+		params  dto.ListSubscriptionsParams
+		want    domain.SubscriptionFilter
+		wantMsg string
 	}{
 		{
 			name: "All parameters provided",
 			params: dto.ListSubscriptionsParams{
 				UserId:      &userID,
 				ServiceName: &serviceName,
+				Q:           &query,
+				InTrial:     &inTrial,
+				Sort:        &[]string{"service_name:desc"},
 				Page:        &page,
 				PageSize:    &pageSize,
 			},
 			want: domain.SubscriptionFilter{
 				UserID:      &userID,
 				ServiceName: &serviceName,
+				Query:       &query,
+				InTrial:     &inTrial,
+				Sort:        []domain.SortKey{{Field: domain.SortServiceName, Desc: true}},
 				Page:        &page,
 				PageSize:    &pageSize,
 			},
@@ -303,6 +475,71 @@ func Test_toListFilter(t *testing.T) {
 				ServiceName: &serviceName,
 			},
 		},
+		{
+			name: "Cursor",
+			params: dto.ListSubscriptionsParams{
+				Cursor:   &encodedCursor,
+				PageSize: &pageSize,
+			},
+			want: domain.SubscriptionFilter{
+				After:    &cursor,
+				PageSize: &pageSize,
+			},
+		},
+		{
+			name: "Date and cost filters",
+			params: dto.ListSubscriptionsParams{
+				ActiveAt:      ptr("03-2025"),
+				MinCost:       ptr(100),
+				MaxCost:       ptr(100),
+				StartedAfter:  ptr("01-2025"),
+				StartedBefore: ptr("2025-03-15"),
+				EndsBefore:    ptr("06-2025"),
+				HasEndDate:    ptr(true),
+			},
+			want: domain.SubscriptionFilter{
+				ActiveAt:      ptr(time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)),
+				MinCost:       ptr(100),
+				MaxCost:       ptr(100),
+				StartedAfter:  ptr(time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC)),
+				StartedBefore: ptr(time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC)),
+				EndsBefore:    ptr(time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)),
+				HasEndDate:    ptr(true),
+			},
+		},
+		{
+			name:    "Min cost above max cost",
+			params:  dto.ListSubscriptionsParams{MinCost: ptr(500), MaxCost: ptr(100)},
+			wantMsg: invalidCostRangeMsg,
+		},
+		{
+			name:    "Negative cost",
+			params:  dto.ListSubscriptionsParams{MaxCost: ptr(-1)},
+			wantMsg: negativeCostMsg,
+		},
+		{
+			name:    "Malformed active month",
+			params:  dto.ListSubscriptionsParams{ActiveAt: ptr("2025-03")},
+			wantMsg: invalidDateMsg,
+		},
+		{
+			name:    "Malformed start bound",
+			params:  dto.ListSubscriptionsParams{StartedAfter: ptr("yesterday")},
+			wantMsg: invalidSubDateMsg,
+		},
+		{
+			name: "Cursor with page",
+			params: dto.ListSubscriptionsParams{
+				Cursor: &encodedCursor,
+				Page:   &page,
+			},
+			wantMsg: cursorConflictMsg,
+		},
+		{
+			name:    "Malformed cursor",
+			params:  dto.ListSubscriptionsParams{Cursor: ptr("not a cursor")},
+			wantMsg: invalidCursorMsg,
+		},
 		{
 			name:   "No parameters",
 			params: dto.ListSubscriptionsParams{},
@@ -314,8 +551,65 @@ func Test_toListFilter(t *testing.T) {
 		tc := tt
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			got := toListFilter(tc.params)
+			got, err := toListFilter(tc.params)
+			if tc.wantMsg != "" {
+				var validationError *DTOValidationError
+				require.ErrorAs(t, err, &validationError)
+				assert.Equal(t, tc.wantMsg, validationError.ClientMessage)
+				return
+			}
+			require.NoError(t, err)
 			assert.Equal(t, tc.want, got)
 		})
 	}
 }
+
+func Test_toCostBreakdownDTO(t *testing.T) {
+	t.Parallel()
+
+	subID := uuid.New()
+	jan := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2025, time.February, 1, 0, 0, 0, 0, time.UTC)
+
+	months := []domain.MonthCost{
+		{
+			Month: jan,
+			Total: 100,
+			Items: []domain.CostItem{{SubscriptionID: subID, ServiceName: "Test", Cost: 100}},
+		},
+		{
+			Month: feb,
+			Total: 0,
+			Items: []domain.CostItem{},
+		},
+	}
+
+	want := &dto.CostBreakdown{
+		TotalCost: 100,
+		Currency:  "RUB",
+		Months: []dto.MonthCost{
+			{
+				Month:     "01-2025",
+				TotalCost: 100,
+				Items:     []dto.CostLineItem{{SubscriptionId: subID, ServiceName: "Test", Cost: 100}},
+			},
+			{
+				Month:     "02-2025",
+				TotalCost: 0,
+				Items:     []dto.CostLineItem{},
+			},
+		},
+	}
+
+	assert.Equal(t, want, toCostBreakdownDTO(months, "RUB"))
+}
+
+func Test_toCurrencyRateDTO(t *testing.T) {
+	t.Parallel()
+
+	updatedAt := time.Date(2025, time.November, 15, 10, 0, 0, 0, time.UTC)
+	rate := &domain.CurrencyRate{From: "USD", To: "RUB", Rate: 81.5, UpdatedAt: updatedAt}
+
+	want := &dto.CurrencyRate{From: "USD", To: "RUB", Rate: 81.5, UpdatedAt: updatedAt}
+	assert.Equal(t, want, toCurrencyRateDTO(rate))
+}
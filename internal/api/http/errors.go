@@ -59,7 +59,11 @@ func InternalError(err error) *HttpError {
 func processAppError(err error) *HttpError {
 	var dtoErr *DTOValidationError
 	if errors.As(err, &dtoErr) {
-		return NewHTTPError(http.StatusUnprocessableEntity, dtoErr.ClientMessage, dtoErr)
+		code := http.StatusUnprocessableEntity
+		if dtoErr.StatusCode != 0 {
+			code = dtoErr.StatusCode
+		}
+		return NewHTTPError(code, dtoErr.ClientMessage, dtoErr)
 	}
 
 	var serviceErr *errkit.BaseErr[subservice.ServiceKind]
@@ -67,6 +71,18 @@ func processAppError(err error) *HttpError {
 		switch serviceErr.Kind {
 		case subservice.KindNotFound:
 			return NewHTTPError(http.StatusNotFound, "The requested resource was not found", serviceErr)
+		case subservice.KindForbidden:
+			return NewHTTPError(http.StatusForbidden, forbiddenMsg, serviceErr)
+		case subservice.KindConflict:
+			return NewHTTPError(http.StatusPreconditionFailed, preconditionFailedMsg, serviceErr)
+		case subservice.KindDuplicate:
+			var overlapErr *subservice.OverlapError
+			if errors.As(serviceErr, &overlapErr) {
+				httpErr := NewHTTPError(http.StatusConflict, overlapMsg, serviceErr)
+				httpErr.DTOErr.ConflictingId = &overlapErr.ConflictingID
+				return httpErr
+			}
+			return NewHTTPError(http.StatusConflict, "The request conflicts with an earlier one", serviceErr)
 		case subservice.KindBusinessLogic:
 			return NewHTTPError(http.StatusUnprocessableEntity, "The operation cannot be completed due to a business rule violation", serviceErr)
 		default:
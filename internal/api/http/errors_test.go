@@ -109,11 +109,21 @@ func Test_processAppError(t *testing.T) {
 			err:  subservice.NewErr("test", subservice.KindNotFound),
 			want: NewHTTPError(http.StatusNotFound, "The requested resource was not found", subservice.NewErr("test", subservice.KindNotFound)),
 		},
+		{
+			name: "Service Error - KindForbidden",
+			err:  subservice.NewErr("test", subservice.KindForbidden),
+			want: NewHTTPError(http.StatusForbidden, forbiddenMsg, subservice.NewErr("test", subservice.KindForbidden)),
+		},
 		{
 			name: "Service Error - KindBusinessLogic",
 			err:  subservice.NewErr("test", subservice.KindBusinessLogic),
 			want: NewHTTPError(http.StatusUnprocessableEntity, "The operation cannot be completed due to a business rule violation", subservice.NewErr("test", subservice.KindBusinessLogic)),
 		},
+		{
+			name: "Service Error - KindDuplicate",
+			err:  subservice.NewErr("test", subservice.KindDuplicate),
+			want: NewHTTPError(http.StatusConflict, "The request conflicts with an earlier one", subservice.NewErr("test", subservice.KindDuplicate)),
+		},
 		{
 			name: "Service Error - KindUnknown",
 			err:  subservice.NewErr("test", subservice.KindUnknown),
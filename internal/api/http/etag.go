@@ -0,0 +1,75 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+)
+
+// isoETagSuffix marks the tags of subscriptions with ISO 8601 dates.
+const isoETagSuffix = "-iso"
+
+// etagOf returns the strong entity tag of the current version of sub with its
+// dates in layout. Both representations share the version, but they aren't
+// byte for byte the same, so their tags differ.
+func etagOf(sub *domain.Subscription, layout DateLayout) string {
+	tag := strconv.Itoa(sub.Version)
+	if layout == isoDateLayout {
+		tag += isoETagSuffix
+	}
+	return strconv.Quote(tag)
+}
+
+// etagHeader returns the response headers with the ETag of sub in layout. The
+// layout can come from Accept, so caches have to tell them apart.
+func etagHeader(sub *domain.Subscription, layout DateLayout) http.Header {
+	h := http.Header{}
+	h.Set("ETag", etagOf(sub, layout))
+	h.Set("Vary", "Accept")
+	return h
+}
+
+// ifMatchVersion returns the subscription version an If-Match header value
+// requires, nil for an absent header or *. Only one entity tag is supported,
+// tags that can't be ours never match. Tags of any representation are taken,
+// updates replace all of them.
+func ifMatchVersion(ifMatch *string) (*int, error) {
+	if ifMatch == nil {
+		return nil, nil
+	}
+	header := strings.TrimSpace(*ifMatch)
+	if header == "" || header == "*" {
+		return nil, nil
+	}
+	if strings.Contains(header, ",") {
+		return nil, &DTOValidationError{ClientMessage: invalidIfMatchMsg, StatusCode: http.StatusBadRequest}
+	}
+
+	tag, weak := strings.CutPrefix(header, "W/")
+	if len(tag) < 2 || tag[0] != '"' || tag[len(tag)-1] != '"' {
+		return nil, &DTOValidationError{ClientMessage: invalidIfMatchMsg, StatusCode: http.StatusBadRequest}
+	}
+	// If-Match uses the strong comparison, weak tags match nothing.
+	version, err := strconv.Atoi(strings.TrimSuffix(tag[1:len(tag)-1], isoETagSuffix))
+	if weak || err != nil {
+		return nil, &DTOValidationError{ClientMessage: preconditionFailedMsg, StatusCode: http.StatusPreconditionFailed}
+	}
+	return &version, nil
+}
+
+// listsETag reports whether an If-None-Match header value is * or lists etag,
+// using the weak comparison.
+func listsETag(ifNoneMatch *string, etag string) bool {
+	if ifNoneMatch == nil {
+		return false
+	}
+	for tag := range strings.SplitSeq(*ifNoneMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
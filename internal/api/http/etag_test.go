@@ -0,0 +1,69 @@
+package http
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ifMatchVersion(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		ifMatch    *string
+		want       *int
+		wantStatus int
+	}{
+		{name: "Absent", ifMatch: nil},
+		{name: "Any version", ifMatch: ptr(" * ")},
+		{name: "Strong tag", ifMatch: ptr(`"12"`), want: ptr(12)},
+		{name: "Tag of ISO dates", ifMatch: ptr(`"12-iso"`), want: ptr(12)},
+		{name: "Weak tag never matches", ifMatch: ptr(`W/"12"`), wantStatus: http.StatusPreconditionFailed},
+		{name: "Foreign tag never matches", ifMatch: ptr(`"abc"`), wantStatus: http.StatusPreconditionFailed},
+		{name: "Unquoted tag", ifMatch: ptr("12"), wantStatus: http.StatusBadRequest},
+		{name: "Several tags", ifMatch: ptr(`"1", "2"`), wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := ifMatchVersion(tt.ifMatch)
+			if tt.wantStatus != 0 {
+				var valErr *DTOValidationError
+				require.ErrorAs(t, err, &valErr)
+				assert.Equal(t, tt.wantStatus, valErr.StatusCode)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_listsETag(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		ifNoneMatch *string
+		want        bool
+	}{
+		{name: "Absent", ifNoneMatch: nil, want: false},
+		{name: "Any", ifNoneMatch: ptr("*"), want: true},
+		{name: "Same tag", ifNoneMatch: ptr(`"3"`), want: true},
+		{name: "Weak tag in a list", ifNoneMatch: ptr(`"1", W/"3"`), want: true},
+		{name: "Other tags", ifNoneMatch: ptr(`"1", "2"`), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, listsETag(tt.ifNoneMatch, `"3"`))
+		})
+	}
+}
@@ -1,44 +1,87 @@
 package http
 
 import (
+	"maps"
 	"net/http"
+	"strconv"
 
 	"github.com/google/uuid"
 	"github.com/oapi-codegen/runtime/types"
 	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
+	"github.com/shrtyk/subscriptions-service/internal/config"
 	"github.com/shrtyk/subscriptions-service/internal/core/domain"
 	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
 )
 
 type handler struct {
 	service subservice.SubscriptionsService
+	cfg     *config.HttpCfg
 }
 
-func NewHandler(service subservice.SubscriptionsService) *handler {
-	return &handler{service: service}
+func NewHandler(service subservice.SubscriptionsService, cfg *config.HttpCfg) *handler {
+	return &handler{
+		service: service,
+		cfg:     cfg,
+	}
 }
 
 func (h *handler) ListSubscriptions(w http.ResponseWriter, r *http.Request, params dto.ListSubscriptionsParams) {
-	filter := toListFilter(params)
+	filter, err := toListFilter(params)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
 
-	subs, err := h.service.List(r.Context(), filter)
+	page, err := h.service.List(r.Context(), filter)
 	if err != nil {
 		WriteHTTPError(w, r, processAppError(err))
 		return
 	}
 
-	dtoSubs := make([]dto.Subscription, 0, len(subs))
-	for _, sub := range subs {
-		dtoSubs = append(dtoSubs, *toSubscriptionDTO(&sub))
+	// The page envelope is opt-in, existing clients keep getting a plain array.
+	layout := dateLayoutFromCtx(r.Context())
+	headers := http.Header{
+		"Vary":          {"Accept"},
+		"X-Total-Count": {strconv.Itoa(page.Total)},
+		"Link":          {pageLinks(r.URL, page)},
+	}
+	accept := r.Header.Values("Accept")
+	if acceptsMediaType(accept, ndjsonMediaType) {
+		maps.Copy(w.Header(), headers)
+		streamSubscriptions(w, r, subsSeq(page.Items), &ndjsonRowWriter{layout: layout}, h.cfg.WriteTimeout)
+		return
 	}
+	if acceptsMediaType(accept, subscriptionPageMediaType) {
+		headers.Set("Content-Type", subscriptionPageMediaType)
+		err = WriteJSON(w, toSubscriptionPageDTO(page, layout), http.StatusOK, headers)
+	} else {
+		err = WriteJSON(w, toSubscriptionDTOs(page.Items, layout), http.StatusOK, headers)
+	}
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+	}
+}
 
-	err = WriteJSON(w, dtoSubs, http.StatusOK, nil)
+func (h *handler) SuggestServices(w http.ResponseWriter, r *http.Request, params dto.SuggestServicesParams) {
+	query, err := validateSuggestQuery(params.Q)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	names, err := h.service.SuggestServiceNames(r.Context(), query, params.Limit)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	err = WriteJSON(w, names, http.StatusOK, nil)
 	if err != nil {
 		WriteHTTPError(w, r, processAppError(err))
 	}
 }
 
-func (h *handler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
+func (h *handler) CreateSubscription(w http.ResponseWriter, r *http.Request, params dto.CreateSubscriptionParams) {
 	var body dto.NewSubscription
 	if err := ReadJSON(w, r, &body); err != nil {
 		WriteHTTPError(w, r, BadRequestError(err))
@@ -51,13 +94,29 @@ func (h *handler) CreateSubscription(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	createdSub, err := h.service.Create(r.Context(), *domainSub)
+	var createdSub *domain.Subscription
+	var replayed bool
+	if params.IdempotencyKey != nil {
+		key, keyErr := toIdempotencyKey(*params.IdempotencyKey, &body)
+		if keyErr != nil {
+			WriteHTTPError(w, r, processAppError(keyErr))
+			return
+		}
+		createdSub, replayed, err = h.service.CreateIdempotent(r.Context(), *domainSub, key)
+	} else {
+		createdSub, err = h.service.Create(r.Context(), *domainSub)
+	}
 	if err != nil {
 		WriteHTTPError(w, r, processAppError(err))
 		return
 	}
 
-	err = WriteJSON(w, toSubscriptionDTO(createdSub), http.StatusCreated, nil)
+	layout := dateLayoutFromCtx(r.Context())
+	headers := etagHeader(createdSub, layout)
+	if replayed {
+		headers.Set("Idempotent-Replayed", "true")
+	}
+	err = WriteJSON(w, toSubscriptionDTO(createdSub, layout), http.StatusCreated, headers)
 	if err != nil {
 		WriteHTTPError(w, r, processAppError(err))
 	}
@@ -76,20 +135,75 @@ func (h *handler) GetTotalCost(w http.ResponseWriter, r *http.Request, params dt
 		return
 	}
 
-	total, err := h.service.TotalCost(r.Context(), filter, start, end)
+	currency, valErr := validateCurrency(params.Currency)
+	if valErr != nil {
+		WriteHTTPError(w, r, processAppError(valErr))
+		return
+	}
+
+	mode, valErr := validateCostMode(params.Mode)
+	if valErr != nil {
+		WriteHTTPError(w, r, processAppError(valErr))
+		return
+	}
+
+	total, err := h.service.TotalCost(r.Context(), filter, start, end, currency, mode)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	err = WriteJSON(w, dto.TotalCost{TotalCost: &total, Currency: &currency}, http.StatusOK, nil)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+	}
+}
+
+func (h *handler) GetCostBreakdown(w http.ResponseWriter, r *http.Request, params dto.GetCostBreakdownParams) {
+	uid := uuid.UUID(params.UserId)
+	filter := domain.SubscriptionFilter{
+		UserID:      &uid,
+		ServiceName: params.ServiceName,
+	}
+
+	start, end, valErr := validateGetCostBreakdownParams(params)
+	if valErr != nil {
+		WriteHTTPError(w, r, processAppError(valErr))
+		return
+	}
+
+	currency, valErr := validateCurrency(params.Currency)
+	if valErr != nil {
+		WriteHTTPError(w, r, processAppError(valErr))
+		return
+	}
+
+	mode, valErr := validateCostMode(params.Mode)
+	if valErr != nil {
+		WriteHTTPError(w, r, processAppError(valErr))
+		return
+	}
+
+	months, err := h.service.CostBreakdown(r.Context(), filter, start, end, currency, mode)
 	if err != nil {
 		WriteHTTPError(w, r, processAppError(err))
 		return
 	}
 
-	err = WriteJSON(w, dto.TotalCost{TotalCost: &total}, http.StatusOK, nil)
+	err = WriteJSON(w, toCostBreakdownDTO(months, currency), http.StatusOK, nil)
 	if err != nil {
 		WriteHTTPError(w, r, processAppError(err))
 	}
 }
 
-func (h *handler) DeleteSubscription(w http.ResponseWriter, r *http.Request, id types.UUID) {
-	if err := h.service.Delete(r.Context(), uuid.UUID(id)); err != nil {
+func (h *handler) DeleteSubscription(w http.ResponseWriter, r *http.Request, id types.UUID, params dto.DeleteSubscriptionParams) {
+	version, err := ifMatchVersion(params.IfMatch)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), uuid.UUID(id), version); err != nil {
 		WriteHTTPError(w, r, processAppError(err))
 		return
 	}
@@ -97,40 +211,237 @@ func (h *handler) DeleteSubscription(w http.ResponseWriter, r *http.Request, id
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *handler) GetSubscriptionById(w http.ResponseWriter, r *http.Request, id types.UUID) {
+func (h *handler) GetSubscriptionById(w http.ResponseWriter, r *http.Request, id types.UUID, params dto.GetSubscriptionByIdParams) {
 	sub, err := h.service.GetByID(r.Context(), uuid.UUID(id))
 	if err != nil {
 		WriteHTTPError(w, r, processAppError(err))
 		return
 	}
 
-	err = WriteJSON(w, toSubscriptionDTO(sub), http.StatusOK, nil)
+	layout := dateLayoutFromCtx(r.Context())
+	headers := etagHeader(sub, layout)
+	if listsETag(params.IfNoneMatch, etagOf(sub, layout)) {
+		maps.Copy(w.Header(), headers)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	err = WriteJSON(w, toSubscriptionDTO(sub, layout), http.StatusOK, headers)
 	if err != nil {
 		WriteHTTPError(w, r, processAppError(err))
 	}
 }
 
-func (h *handler) UpdateSubscription(w http.ResponseWriter, r *http.Request, id types.UUID) {
-	var req UpdateSubscriptionRequest
-	if err := ReadJSON(w, r, &req); err != nil {
+func (h *handler) UpdateSubscription(w http.ResponseWriter, r *http.Request, id types.UUID, params dto.UpdateSubscriptionParams) {
+	version, err := ifMatchVersion(params.IfMatch)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	var body dto.ReplaceSubscription
+	if err := ReadJSON(w, r, &body); err != nil {
 		WriteHTTPError(w, r, BadRequestError(err))
 		return
 	}
 
-	domainUpdate, err := fromUpdateSubscriptionRequest(&req)
+	domainUpdate, err := fromReplaceSubscriptionDTO(&body)
 	if err != nil {
 		WriteHTTPError(w, r, processAppError(err))
 		return
 	}
 
-	updatedSub, err := h.service.Update(r.Context(), uuid.UUID(id), *domainUpdate)
+	updatedSub, err := h.service.Update(r.Context(), uuid.UUID(id), version, *domainUpdate)
 	if err != nil {
 		WriteHTTPError(w, r, processAppError(err))
 		return
 	}
 
-	err = WriteJSON(w, toSubscriptionDTO(updatedSub), http.StatusOK, nil)
+	layout := dateLayoutFromCtx(r.Context())
+	err = WriteJSON(w, toSubscriptionDTO(updatedSub, layout), http.StatusOK, etagHeader(updatedSub, layout))
 	if err != nil {
 		WriteHTTPError(w, r, processAppError(err))
 	}
 }
+
+func (h *handler) PatchSubscription(w http.ResponseWriter, r *http.Request, id types.UUID, params dto.PatchSubscriptionParams) {
+	version, err := ifMatchVersion(params.IfMatch)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	apply, err := patchFuncOf(r.Header.Get("Content-Type"))
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	patch, err := ReadBody(w, r)
+	if err != nil {
+		WriteHTTPError(w, r, BadRequestError(err))
+		return
+	}
+
+	updatedSub, err := h.service.Patch(r.Context(), uuid.UUID(id), version, func(current domain.Subscription) (domain.SubscriptionUpdate, error) {
+		return patchSubscription(current, patch, apply)
+	})
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	layout := dateLayoutFromCtx(r.Context())
+	err = WriteJSON(w, toSubscriptionDTO(updatedSub, layout), http.StatusOK, etagHeader(updatedSub, layout))
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+	}
+}
+
+func (h *handler) ListSubscriptionPrices(w http.ResponseWriter, r *http.Request, id types.UUID) {
+	changes, err := h.service.ListPriceChanges(r.Context(), uuid.UUID(id))
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	layout := dateLayoutFromCtx(r.Context())
+	dtoChanges := make([]dto.PriceChange, 0, len(changes))
+	for _, change := range changes {
+		dtoChanges = append(dtoChanges, *toPriceChangeDTO(&change, layout))
+	}
+
+	err = WriteJSON(w, dtoChanges, http.StatusOK, nil)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+	}
+}
+
+func (h *handler) PauseSubscription(w http.ResponseWriter, r *http.Request, id types.UUID) {
+	var body dto.NewPause
+	if err := ReadOptionalJSON(w, r, &body); err != nil {
+		WriteHTTPError(w, r, BadRequestError(err))
+		return
+	}
+
+	pausedFrom, resumedFrom, err := validateNewPause(&body)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	pause, err := h.service.Pause(r.Context(), uuid.UUID(id), pausedFrom, resumedFrom)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	err = WriteJSON(w, toPauseDTO(pause, dateLayoutFromCtx(r.Context())), http.StatusCreated, nil)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+	}
+}
+
+func (h *handler) ResumeSubscription(w http.ResponseWriter, r *http.Request, id types.UUID) {
+	var body dto.ResumePause
+	if err := ReadOptionalJSON(w, r, &body); err != nil {
+		WriteHTTPError(w, r, BadRequestError(err))
+		return
+	}
+
+	resumedFrom, err := validateMonth(body.ResumedFrom)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	pause, err := h.service.Resume(r.Context(), uuid.UUID(id), resumedFrom)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	err = WriteJSON(w, toPauseDTO(pause, dateLayoutFromCtx(r.Context())), http.StatusOK, nil)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+	}
+}
+
+func (h *handler) ListSubscriptionPauses(w http.ResponseWriter, r *http.Request, id types.UUID) {
+	pauses, err := h.service.ListPauses(r.Context(), uuid.UUID(id))
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	layout := dateLayoutFromCtx(r.Context())
+	dtoPauses := make([]dto.Pause, 0, len(pauses))
+	for _, pause := range pauses {
+		dtoPauses = append(dtoPauses, *toPauseDTO(&pause, layout))
+	}
+
+	err = WriteJSON(w, dtoPauses, http.StatusOK, nil)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+	}
+}
+
+func (h *handler) ListCurrencyRates(w http.ResponseWriter, r *http.Request) {
+	rates, err := h.service.ListCurrencyRates(r.Context())
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	dtoRates := make([]dto.CurrencyRate, 0, len(rates))
+	for _, rate := range rates {
+		dtoRates = append(dtoRates, *toCurrencyRateDTO(&rate))
+	}
+
+	err = WriteJSON(w, dtoRates, http.StatusOK, nil)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+	}
+}
+
+func (h *handler) SetCurrencyRate(w http.ResponseWriter, r *http.Request, from string, to string) {
+	var body dto.NewCurrencyRate
+	if err := ReadJSON(w, r, &body); err != nil {
+		WriteHTTPError(w, r, BadRequestError(err))
+		return
+	}
+
+	if err := validateCurrencyPair(from, to); err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+	if err := validateNewCurrencyRate(&body); err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	rate, err := h.service.SetCurrencyRate(r.Context(), domain.CurrencyRate{From: from, To: to, Rate: body.Rate})
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	err = WriteJSON(w, toCurrencyRateDTO(rate), http.StatusOK, nil)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+	}
+}
+
+func (h *handler) DeleteCurrencyRate(w http.ResponseWriter, r *http.Request, from string, to string) {
+	if err := validateCurrencyPair(from, to); err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	if err := h.service.DeleteCurrencyRate(r.Context(), from, to); err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
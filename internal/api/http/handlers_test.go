@@ -15,6 +15,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
 	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
+	"github.com/shrtyk/subscriptions-service/internal/config"
 	"github.com/shrtyk/subscriptions-service/internal/core/domain"
 	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
 	ssmocks "github.com/shrtyk/subscriptions-service/internal/core/ports/subservice/mocks"
@@ -31,13 +32,18 @@ type testHarness struct {
 func setup(t *testing.T) testHarness {
 	t.Helper()
 	service := ssmocks.NewMockSubscriptionsService(t)
-	h := NewHandler(service)
+	h := NewHandler(service, &config.HttpCfg{MaxBatchSize: 3, MaxImportRows: 3})
 	return testHarness{
 		h:       h,
 		service: service,
 	}
 }
 
+// adminCtx is the context of requests by an admin, who passes every ownership check.
+func adminCtx() context.Context {
+	return domain.PrincipalToCtx(context.Background(), &domain.Principal{UserID: uuid.New(), Role: domain.RoleAdmin})
+}
+
 func mustMarshal(t *testing.T, v any) io.Reader {
 	t.Helper()
 	b, err := json.Marshal(v)
@@ -61,14 +67,16 @@ func newRequestWithChiCtx(t *testing.T, method, path string, body io.Reader, pat
 func TestHandler_GetSubscriptionById(t *testing.T) {
 	t.Parallel()
 
-	ctx := context.Background()
+	ctx := adminCtx()
 	subID := uuid.New()
 	expectedSub := &domain.Subscription{
-		ID:          subID,
-		ServiceName: "Test",
-		MonthlyCost: 100,
-		UserID:      uuid.New(),
-		StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		ID:            subID,
+		ServiceName:   "Test",
+		Price:         100,
+		BillingPeriod: domain.BillingMonthly,
+		UserID:        uuid.New(),
+		StartDate:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		Version:       4,
 	}
 	serviceErrNotFound := subservice.NewErr("subservice.GetByID", subservice.KindNotFound)
 	genericErr := errors.New("generic error")
@@ -77,6 +85,8 @@ func TestHandler_GetSubscriptionById(t *testing.T) {
 	testCases := []struct {
 		name       string
 		subID      string
+		params     dto.GetSubscriptionByIdParams
+		layout     DateLayout
 		setupMocks func(th testHarness)
 		assertFunc func(t *testing.T, rr *httptest.ResponseRecorder)
 	}{
@@ -91,7 +101,63 @@ func TestHandler_GetSubscriptionById(t *testing.T) {
 				err := json.NewDecoder(rr.Body).Decode(&respBody)
 				require.NoError(t, err)
 				assert.Equal(t, rr.Code, http.StatusOK)
-				assert.Equal(t, toSubscriptionDTO(expectedSub), &respBody)
+				assert.Equal(t, `"4"`, rr.Header().Get("ETag"))
+				assert.Equal(t, "Accept", rr.Header().Get("Vary"))
+				assert.Equal(t, toSubscriptionDTO(expectedSub, dateLayout), &respBody)
+			},
+		},
+		{
+			name:   "ISO Dates",
+			subID:  subID.String(),
+			layout: isoDateLayout,
+			setupMocks: func(th testHarness) {
+				th.service.On("GetByID", mock.Anything, subID).Return(expectedSub, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var respBody dto.Subscription
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&respBody))
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, `"4-iso"`, rr.Header().Get("ETag"))
+				assert.Equal(t, "Accept", rr.Header().Get("Vary"))
+				assert.Equal(t, toSubscriptionDTO(expectedSub, isoDateLayout), &respBody)
+			},
+		},
+		{
+			name:   "ISO Dates Modified Since Default Tag",
+			subID:  subID.String(),
+			params: dto.GetSubscriptionByIdParams{IfNoneMatch: ptr(`"4"`)},
+			layout: isoDateLayout,
+			setupMocks: func(th testHarness) {
+				th.service.On("GetByID", mock.Anything, subID).Return(expectedSub, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, `"4-iso"`, rr.Header().Get("ETag"))
+			},
+		},
+		{
+			name:   "Not Modified",
+			subID:  subID.String(),
+			params: dto.GetSubscriptionByIdParams{IfNoneMatch: ptr(`"3", W/"4"`)},
+			setupMocks: func(th testHarness) {
+				th.service.On("GetByID", ctx, subID).Return(expectedSub, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNotModified, rr.Code)
+				assert.Equal(t, `"4"`, rr.Header().Get("ETag"))
+				assert.Empty(t, rr.Body.String())
+			},
+		},
+		{
+			name:   "Modified Since If-None-Match",
+			subID:  subID.String(),
+			params: dto.GetSubscriptionByIdParams{IfNoneMatch: ptr(`"3"`)},
+			setupMocks: func(th testHarness) {
+				th.service.On("GetByID", ctx, subID).Return(expectedSub, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, `"4"`, rr.Header().Get("ETag"))
 			},
 		},
 		{
@@ -138,7 +204,11 @@ func TestHandler_GetSubscriptionById(t *testing.T) {
 
 			id, err := uuid.Parse(tc.subID)
 			require.NoError(t, err)
-			th.h.GetSubscriptionById(rr, req.WithContext(ctx), id)
+			reqCtx := ctx
+			if tc.layout != "" {
+				reqCtx = dateLayoutToCtx(reqCtx, tc.layout)
+			}
+			th.h.GetSubscriptionById(rr, req.WithContext(reqCtx), id, tc.params)
 			tc.assertFunc(t, rr)
 		})
 	}
@@ -147,11 +217,11 @@ func TestHandler_GetSubscriptionById(t *testing.T) {
 func TestHandler_CreateSubscription(t *testing.T) {
 	t.Parallel()
 
-	ctx := context.Background()
+	ctx := adminCtx()
 	userID := uuid.New()
 	newSubDTO := dto.NewSubscription{
 		ServiceName: "Test Service",
-		MonthlyCost: 100,
+		Price:       ptr(100),
 		UserId:      userID,
 		StartDate:   "01-2025",
 	}
@@ -159,20 +229,30 @@ func TestHandler_CreateSubscription(t *testing.T) {
 	require.NoError(t, err)
 
 	createdSub := &domain.Subscription{
-		ID:          uuid.New(),
-		ServiceName: newSubDTO.ServiceName,
-		MonthlyCost: newSubDTO.MonthlyCost,
-		UserID:      userID,
-		StartDate:   domainSub.StartDate,
+		ID:            uuid.New(),
+		ServiceName:   newSubDTO.ServiceName,
+		Price:         *newSubDTO.Price,
+		BillingPeriod: domainSub.BillingPeriod,
+		UserID:        userID,
+		StartDate:     domainSub.StartDate,
 	}
 
 	serviceErrBizLogic := subservice.NewErr("subservice.Create", subservice.KindBusinessLogic)
 	genericErr := errors.New("generic error")
 	serviceErrGeneric := subservice.WrapErr("subservice.Create", subservice.KindUnknown, genericErr)
+	serviceErrDuplicate := subservice.NewErr("subservice.CreateIdempotent", subservice.KindDuplicate)
+	overlappedID := uuid.New()
+	serviceErrOverlap := subservice.WrapErr(
+		"subservice.Create", subservice.KindDuplicate,
+		&subservice.OverlapError{ConflictingID: overlappedID, Err: genericErr},
+	)
+	idempotencyKey, err := toIdempotencyKey("key-1", &newSubDTO)
+	require.NoError(t, err)
 
 	testCases := []struct {
 		name       string
 		body       io.Reader
+		params     dto.CreateSubscriptionParams
 		setupMocks func(th testHarness)
 		assertFunc func(t *testing.T, rr *httptest.ResponseRecorder)
 	}{
@@ -187,7 +267,86 @@ func TestHandler_CreateSubscription(t *testing.T) {
 				err := json.NewDecoder(rr.Body).Decode(&respBody)
 				require.NoError(t, err)
 				assert.Equal(t, rr.Code, http.StatusCreated)
-				assert.Equal(t, toSubscriptionDTO(createdSub), &respBody)
+				assert.Equal(t, etagOf(createdSub, dateLayout), rr.Header().Get("ETag"))
+				assert.Equal(t, toSubscriptionDTO(createdSub, dateLayout), &respBody)
+			},
+		},
+		{
+			name:   "Success - Idempotency-Key",
+			body:   mustMarshal(t, newSubDTO),
+			params: dto.CreateSubscriptionParams{IdempotencyKey: ptr("key-1")},
+			setupMocks: func(th testHarness) {
+				th.service.On("CreateIdempotent", ctx, *domainSub, idempotencyKey).Return(createdSub, false, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, rr.Code)
+				assert.Empty(t, rr.Header().Get("Idempotent-Replayed"))
+			},
+		},
+		{
+			name:   "Success - Replayed",
+			body:   mustMarshal(t, newSubDTO),
+			params: dto.CreateSubscriptionParams{IdempotencyKey: ptr("key-1")},
+			setupMocks: func(th testHarness) {
+				th.service.On("CreateIdempotent", ctx, *domainSub, idempotencyKey).Return(createdSub, true, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var respBody dto.Subscription
+				err := json.NewDecoder(rr.Body).Decode(&respBody)
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusCreated, rr.Code)
+				assert.Equal(t, "true", rr.Header().Get("Idempotent-Replayed"))
+				assert.Equal(t, toSubscriptionDTO(createdSub, dateLayout), &respBody)
+			},
+		},
+		{
+			name:   "Conflict - Key Reused",
+			body:   mustMarshal(t, newSubDTO),
+			params: dto.CreateSubscriptionParams{IdempotencyKey: ptr("key-1")},
+			setupMocks: func(th testHarness) {
+				th.service.On("CreateIdempotent", ctx, *domainSub, idempotencyKey).Return(nil, false, serviceErrDuplicate).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var errBody dto.Error
+				err := json.NewDecoder(rr.Body).Decode(&errBody)
+				require.NoError(t, err)
+				assert.Equal(t, int32(http.StatusConflict), errBody.Code)
+			},
+		},
+		{
+			name: "Conflict - Overlapping Subscription",
+			body: mustMarshal(t, newSubDTO),
+			setupMocks: func(th testHarness) {
+				th.service.On("Create", ctx, *domainSub).Return(nil, serviceErrOverlap).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var errBody dto.Error
+				err := json.NewDecoder(rr.Body).Decode(&errBody)
+				require.NoError(t, err)
+				assert.Equal(t, int32(http.StatusConflict), errBody.Code)
+				assert.Equal(t, overlapMsg, errBody.Message)
+				require.NotNil(t, errBody.ConflictingId)
+				assert.Equal(t, overlappedID, *errBody.ConflictingId)
+			},
+		},
+		{
+			name:   "Bad Request - Empty Idempotency-Key",
+			body:   mustMarshal(t, newSubDTO),
+			params: dto.CreateSubscriptionParams{IdempotencyKey: ptr("")},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var errBody dto.Error
+				err := json.NewDecoder(rr.Body).Decode(&errBody)
+				require.NoError(t, err)
+				assert.Equal(t, int32(http.StatusBadRequest), errBody.Code)
+				assert.Equal(t, invalidIdempotencyKeyMsg, errBody.Message)
+			},
+		},
+		{
+			name:   "Bad Request - Long Idempotency-Key",
+			body:   mustMarshal(t, newSubDTO),
+			params: dto.CreateSubscriptionParams{IdempotencyKey: ptr(strings.Repeat("k", maxIdempotencyKeyLen+1))},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rr.Code)
 			},
 		},
 		{
@@ -205,7 +364,7 @@ func TestHandler_CreateSubscription(t *testing.T) {
 			name: "Validation Error - Invalid Date",
 			body: mustMarshal(t, dto.NewSubscription{
 				ServiceName: "Test",
-				MonthlyCost: 100,
+				Price:       ptr(100),
 				UserId:      userID,
 				StartDate:   "bad-date",
 			}),
@@ -258,7 +417,7 @@ func TestHandler_CreateSubscription(t *testing.T) {
 			req := httptest.NewRequest(http.MethodPost, "/subscriptions", tc.body)
 			rr := httptest.NewRecorder()
 
-			th.h.CreateSubscription(rr, req.WithContext(ctx))
+			th.h.CreateSubscription(rr, req.WithContext(ctx), tc.params)
 
 			tc.assertFunc(t, rr)
 		})
@@ -268,32 +427,61 @@ func TestHandler_CreateSubscription(t *testing.T) {
 func TestHandler_UpdateSubscription(t *testing.T) {
 	t.Parallel()
 
-	ctx := context.Background()
+	ctx := adminCtx()
 	subID := uuid.New()
+	userID := uuid.New()
 
 	serviceErrNotFound := subservice.NewErr("subservice.Update", subservice.KindNotFound)
 	serviceErrBizLogic := subservice.NewErr("subservice.Update", subservice.KindBusinessLogic)
 	genericErr := errors.New("generic error")
 	serviceErrGeneric := subservice.WrapErr("subservice.Update", subservice.KindUnknown, genericErr)
+	serviceErrConflict := subservice.NewErr("subservice.Update", subservice.KindConflict)
+
+	replacement := map[string]any{
+		"service_name": "New Name",
+		"price":        400,
+		"user_id":      userID,
+		"start_date":   "03-2025",
+	}
+	with := func(fields map[string]any) map[string]any {
+		body := make(map[string]any, len(replacement)+len(fields))
+		for k, v := range replacement {
+			body[k] = v
+		}
+		for k, v := range fields {
+			body[k] = v
+		}
+		return body
+	}
+	march := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	replaceUpdate := domain.SubscriptionUpdate{
+		ServiceName:   ptr("New Name"),
+		Price:         ptr(400),
+		BillingPeriod: ptr(domain.BillingMonthly),
+		Currency:      ptr(domain.DefaultCurrency),
+		UserID:        &userID,
+		StartDate:     &march,
+		ClearEndDate:  true,
+		ClearTrialEnd: true,
+	}
 
 	testCases := []struct {
 		name                 string
 		subID                string
+		params               dto.UpdateSubscriptionParams
 		body                 io.Reader
 		expectedDomainUpdate domain.SubscriptionUpdate
 		setupMocks           func(th testHarness, expectedUpdate domain.SubscriptionUpdate)
 		assertFunc           func(t *testing.T, rr *httptest.ResponseRecorder)
 	}{
 		{
-			name:  "Success - Update ServiceName",
-			subID: subID.String(),
-			body:  mustMarshal(t, map[string]string{"service_name": "New Name"}),
-			expectedDomainUpdate: domain.SubscriptionUpdate{
-				ServiceName: func(s string) *string { return &s }("New Name"),
-			},
+			name:                 "Success - Replace",
+			subID:                subID.String(),
+			body:                 mustMarshal(t, replacement),
+			expectedDomainUpdate: replaceUpdate,
 			setupMocks: func(th testHarness, expectedUpdate domain.SubscriptionUpdate) {
-				updatedSub := &domain.Subscription{ID: subID, ServiceName: "New Name"}
-				th.service.On("Update", ctx, subID, expectedUpdate).Return(updatedSub, nil).Once()
+				updatedSub := &domain.Subscription{ID: subID, ServiceName: "New Name", UserID: userID, StartDate: march}
+				th.service.On("Update", ctx, subID, (*int)(nil), expectedUpdate).Return(updatedSub, nil).Once()
 			},
 			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusOK, rr.Code)
@@ -301,43 +489,120 @@ func TestHandler_UpdateSubscription(t *testing.T) {
 				err := json.NewDecoder(rr.Body).Decode(&respBody)
 				require.NoError(t, err)
 				assert.Equal(t, "New Name", respBody.ServiceName)
+				assert.Equal(t, userID, respBody.UserId)
+				assert.Nil(t, respBody.EndDate)
+				assert.Equal(t, `"0"`, rr.Header().Get("ETag"))
 			},
 		},
 		{
-			name:                 "Success - Clear EndDate",
+			name:                 "Success - If-Match",
 			subID:                subID.String(),
-			body:                 mustMarshal(t, map[string]any{"end_date": nil}),
-			expectedDomainUpdate: domain.SubscriptionUpdate{ClearEndDate: true},
+			params:               dto.UpdateSubscriptionParams{IfMatch: ptr(`"3"`)},
+			body:                 mustMarshal(t, replacement),
+			expectedDomainUpdate: replaceUpdate,
 			setupMocks: func(th testHarness, expectedUpdate domain.SubscriptionUpdate) {
-				updatedSub := &domain.Subscription{ID: subID, EndDate: nil}
-				th.service.On("Update", ctx, subID, expectedUpdate).Return(updatedSub, nil).Once()
+				updatedSub := &domain.Subscription{ID: subID, Version: 4}
+				th.service.On("Update", ctx, subID, ptr(3), expectedUpdate).Return(updatedSub, nil).Once()
 			},
 			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusOK, rr.Code)
-				var respBody dto.Subscription
-				err := json.NewDecoder(rr.Body).Decode(&respBody)
-				require.NoError(t, err)
-				assert.Nil(t, respBody.EndDate)
+				assert.Equal(t, `"4"`, rr.Header().Get("ETag"))
+			},
+		},
+		{
+			name:                 "Precondition Failed - Stale ETag",
+			subID:                subID.String(),
+			params:               dto.UpdateSubscriptionParams{IfMatch: ptr(`"3"`)},
+			body:                 mustMarshal(t, replacement),
+			expectedDomainUpdate: replaceUpdate,
+			setupMocks: func(th testHarness, expectedUpdate domain.SubscriptionUpdate) {
+				th.service.On("Update", ctx, subID, ptr(3), expectedUpdate).Return(nil, serviceErrConflict).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+			},
+		},
+		{
+			name:   "Precondition Failed - Weak ETag",
+			subID:  subID.String(),
+			params: dto.UpdateSubscriptionParams{IfMatch: ptr(`W/"3"`)},
+			body:   mustMarshal(t, replacement),
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+			},
+		},
+		{
+			name:   "Bad Request - Invalid If-Match",
+			subID:  subID.String(),
+			params: dto.UpdateSubscriptionParams{IfMatch: ptr("3")},
+			body:   mustMarshal(t, replacement),
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rr.Code)
 			},
 		},
 		{
 			name:  "Success - Update EndDate",
 			subID: subID.String(),
-			body:  mustMarshal(t, map[string]string{"end_date": "12-2025"}),
-			expectedDomainUpdate: domain.SubscriptionUpdate{
-				EndDate: func() *time.Time {
-					t, _ := time.Parse("01-2006", "12-2025")
-					return &t
-				}(),
-			},
+			body:  mustMarshal(t, with(map[string]any{"end_date": "12-2025"})),
+			expectedDomainUpdate: func() domain.SubscriptionUpdate {
+				update := replaceUpdate
+				update.EndDate = ptr(time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC))
+				update.ClearEndDate = false
+				return update
+			}(),
 			setupMocks: func(th testHarness, expectedUpdate domain.SubscriptionUpdate) {
 				updatedSub := &domain.Subscription{ID: subID, EndDate: expectedUpdate.EndDate}
-				th.service.On("Update", ctx, subID, expectedUpdate).Return(updatedSub, nil).Once()
+				th.service.On("Update", ctx, subID, (*int)(nil), expectedUpdate).Return(updatedSub, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rr.Code)
+			},
+		},
+		{
+			name:  "Success - Backdated Price Change",
+			subID: subID.String(),
+			body:  mustMarshal(t, with(map[string]any{"price": 500, "price_effective_from": "03-2025"})),
+			expectedDomainUpdate: func() domain.SubscriptionUpdate {
+				update := replaceUpdate
+				update.Price = ptr(500)
+				update.PriceEffectiveFrom = &march
+				return update
+			}(),
+			setupMocks: func(th testHarness, expectedUpdate domain.SubscriptionUpdate) {
+				updatedSub := &domain.Subscription{ID: subID, Price: 500}
+				th.service.On("Update", ctx, subID, (*int)(nil), expectedUpdate).Return(updatedSub, nil).Once()
 			},
 			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusOK, rr.Code)
 			},
 		},
+		{
+			name:       "Validation Error - Partial Body",
+			subID:      subID.String(),
+			body:       mustMarshal(t, map[string]string{"service_name": "New Name"}),
+			setupMocks: nil,
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+			},
+		},
+		{
+			name:       "Bad Request - Pauses Can't Be Edited",
+			subID:      subID.String(),
+			body:       mustMarshal(t, with(map[string]any{"pauses": []map[string]string{{"paused_from": "03-2025"}}})),
+			setupMocks: nil,
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rr.Code)
+			},
+		},
+		{
+			name:       "Bad Request - Deprecated Monthly Cost",
+			subID:      subID.String(),
+			body:       mustMarshal(t, with(map[string]any{"monthly_cost": 400})),
+			setupMocks: nil,
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rr.Code)
+			},
+		},
 		{
 			name:       "Bad Request - Invalid JSON",
 			subID:      subID.String(),
@@ -350,49 +615,43 @@ func TestHandler_UpdateSubscription(t *testing.T) {
 		{
 			name:       "Validation Error - Invalid Date",
 			subID:      subID.String(),
-			body:       mustMarshal(t, map[string]string{"end_date": "bad-date"}),
+			body:       mustMarshal(t, with(map[string]any{"end_date": "bad-date"})),
 			setupMocks: nil,
 			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
 			},
 		},
 		{
-			name:  "Service Error - Not Found",
-			subID: subID.String(),
-			body:  mustMarshal(t, map[string]string{"service_name": "New Name"}),
-			expectedDomainUpdate: domain.SubscriptionUpdate{
-				ServiceName: func(s string) *string { return &s }("New Name"),
-			},
+			name:                 "Service Error - Not Found",
+			subID:                subID.String(),
+			body:                 mustMarshal(t, replacement),
+			expectedDomainUpdate: replaceUpdate,
 			setupMocks: func(th testHarness, expectedUpdate domain.SubscriptionUpdate) {
-				th.service.On("Update", ctx, subID, expectedUpdate).Return(nil, serviceErrNotFound).Once()
+				th.service.On("Update", ctx, subID, (*int)(nil), expectedUpdate).Return(nil, serviceErrNotFound).Once()
 			},
 			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusNotFound, rr.Code)
 			},
 		},
 		{
-			name:  "Service Error - Business Logic",
-			subID: subID.String(),
-			body:  mustMarshal(t, map[string]string{"service_name": "New Name"}),
-			expectedDomainUpdate: domain.SubscriptionUpdate{
-				ServiceName: func(s string) *string { return &s }("New Name"),
-			},
+			name:                 "Service Error - Business Logic",
+			subID:                subID.String(),
+			body:                 mustMarshal(t, replacement),
+			expectedDomainUpdate: replaceUpdate,
 			setupMocks: func(th testHarness, expectedUpdate domain.SubscriptionUpdate) {
-				th.service.On("Update", ctx, subID, expectedUpdate).Return(nil, serviceErrBizLogic).Once()
+				th.service.On("Update", ctx, subID, (*int)(nil), expectedUpdate).Return(nil, serviceErrBizLogic).Once()
 			},
 			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
 			},
 		},
 		{
-			name:  "Service Error - Generic",
-			subID: subID.String(),
-			body:  mustMarshal(t, map[string]string{"service_name": "New Name"}),
-			expectedDomainUpdate: domain.SubscriptionUpdate{
-				ServiceName: func(s string) *string { return &s }("New Name"),
-			},
+			name:                 "Service Error - Generic",
+			subID:                subID.String(),
+			body:                 mustMarshal(t, replacement),
+			expectedDomainUpdate: replaceUpdate,
 			setupMocks: func(th testHarness, expectedUpdate domain.SubscriptionUpdate) {
-				th.service.On("Update", ctx, subID, expectedUpdate).Return(nil, serviceErrGeneric).Once()
+				th.service.On("Update", ctx, subID, (*int)(nil), expectedUpdate).Return(nil, serviceErrGeneric).Once()
 			},
 			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
 				assert.Equal(t, http.StatusInternalServerError, rr.Code)
@@ -415,62 +674,169 @@ func TestHandler_UpdateSubscription(t *testing.T) {
 			id, err := uuid.Parse(tc.subID)
 			require.NoError(t, err)
 
-			th.h.UpdateSubscription(rr, req.WithContext(ctx), id)
+			th.h.UpdateSubscription(rr, req.WithContext(ctx), id, tc.params)
 			tc.assertFunc(t, rr)
 		})
 	}
 }
 
-func TestHandler_DeleteSubscription(t *testing.T) {
+func TestHandler_PatchSubscription(t *testing.T) {
 	t.Parallel()
 
-	ctx := context.Background()
+	ctx := adminCtx()
 	subID := uuid.New()
-	serviceErrNotFound := subservice.NewErr("subservice.Delete", subservice.KindNotFound)
-	genericErr := errors.New("generic error")
-	serviceErrGeneric := subservice.WrapErr("subservice.Delete", subservice.KindUnknown, genericErr)
+	current := domain.Subscription{
+		ID:            subID,
+		ServiceName:   "Okko",
+		Price:         400,
+		BillingPeriod: domain.BillingMonthly,
+		Currency:      "RUB",
+		UserID:        uuid.New(),
+		StartDate:     time.Date(2025, time.March, 15, 0, 0, 0, 0, time.UTC),
+		Version:       2,
+	}
+	serviceErrNotFound := subservice.NewErr("subservice.Patch", subservice.KindNotFound)
+	serviceErrConflict := subservice.NewErr("subservice.Patch", subservice.KindConflict)
+
+	// patchCurrent runs the patch of the handler against current, like the service does.
+	patchCurrent := func(update *domain.SubscriptionUpdate) func(context.Context, uuid.UUID, *int, func(domain.Subscription) (domain.SubscriptionUpdate, error)) (*domain.Subscription, error) {
+		return func(_ context.Context, _ uuid.UUID, _ *int, patch func(domain.Subscription) (domain.SubscriptionUpdate, error)) (*domain.Subscription, error) {
+			u, err := patch(current)
+			if err != nil {
+				return nil, err
+			}
+			*update = u
+			updated := current
+			updated.ServiceName = *u.ServiceName
+			updated.Version++
+			return &updated, nil
+		}
+	}
 
 	testCases := []struct {
-		name       string
-		subID      string
-		setupMocks func(th testHarness)
-		assertFunc func(t *testing.T, rr *httptest.ResponseRecorder)
+		name        string
+		contentType string
+		params      dto.PatchSubscriptionParams
+		body        string
+		setupMocks  func(th testHarness, update *domain.SubscriptionUpdate)
+		assertFunc  func(t *testing.T, rr *httptest.ResponseRecorder, update domain.SubscriptionUpdate)
 	}{
 		{
-			name:  "Success",
-			subID: subID.String(),
-			setupMocks: func(th testHarness) {
-				th.service.On("Delete", ctx, subID).Return(nil).Once()
+			name:        "Success - Merge Patch",
+			contentType: "application/merge-patch+json; charset=utf-8",
+			body:        `{"service_name":"Okko Premium","end_date":"12-2025"}`,
+			setupMocks: func(th testHarness, update *domain.SubscriptionUpdate) {
+				th.service.On("Patch", ctx, subID, (*int)(nil), mock.Anything).Return(patchCurrent(update)).Once()
 			},
-			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
-				assert.Equal(t, rr.Code, http.StatusNoContent)
-				assert.Equal(t, 0, rr.Body.Len())
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder, update domain.SubscriptionUpdate) {
+				assert.Equal(t, http.StatusOK, rr.Code)
+				var respBody dto.Subscription
+				require.NoError(t, json.NewDecoder(rr.Body).Decode(&respBody))
+				assert.Equal(t, "Okko Premium", respBody.ServiceName)
+				assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
+				assert.Equal(t, current.StartDate, *update.StartDate)
+				assert.Equal(t, ptr(time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)), update.EndDate)
 			},
 		},
 		{
-			name:  "Not Found",
-			subID: subID.String(),
-			setupMocks: func(th testHarness) {
-				th.service.On("Delete", ctx, subID).Return(serviceErrNotFound).Once()
+			name:        "Success - JSON Patch",
+			contentType: "application/json-patch+json",
+			body:        `[{"op":"test","path":"/price","value":400},{"op":"replace","path":"/price","value":500},{"op":"add","path":"/price_effective_from","value":"04-2025"}]`,
+			setupMocks: func(th testHarness, update *domain.SubscriptionUpdate) {
+				th.service.On("Patch", ctx, subID, (*int)(nil), mock.Anything).Return(patchCurrent(update)).Once()
 			},
-			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
-				var errBody dto.Error
-				err := json.NewDecoder(rr.Body).Decode(&errBody)
-				require.NoError(t, err)
-				assert.Equal(t, int32(http.StatusNotFound), errBody.Code)
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder, update domain.SubscriptionUpdate) {
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, ptr(500), update.Price)
+				assert.Equal(t, ptr(time.Date(2025, time.April, 1, 0, 0, 0, 0, time.UTC)), update.PriceEffectiveFrom)
 			},
 		},
 		{
-			name:  "Generic Service Error",
-			subID: subID.String(),
-			setupMocks: func(th testHarness) {
-				th.service.On("Delete", ctx, subID).Return(serviceErrGeneric).Once()
+			name:        "Conflict - Failed Test",
+			contentType: "application/json-patch+json",
+			body:        `[{"op":"test","path":"/price","value":300},{"op":"replace","path":"/price","value":500}]`,
+			setupMocks: func(th testHarness, update *domain.SubscriptionUpdate) {
+				th.service.On("Patch", ctx, subID, (*int)(nil), mock.Anything).Return(patchCurrent(update)).Once()
 			},
-			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
-				var errBody dto.Error
-				err := json.NewDecoder(rr.Body).Decode(&errBody)
-				require.NoError(t, err)
-				assert.Equal(t, int32(http.StatusInternalServerError), errBody.Code)
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder, _ domain.SubscriptionUpdate) {
+				assert.Equal(t, http.StatusConflict, rr.Code)
+			},
+		},
+		{
+			name:        "Bad Request - Malformed Patch",
+			contentType: "application/json-patch+json",
+			body:        `{"op":"replace"}`,
+			setupMocks: func(th testHarness, update *domain.SubscriptionUpdate) {
+				th.service.On("Patch", ctx, subID, (*int)(nil), mock.Anything).Return(patchCurrent(update)).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder, _ domain.SubscriptionUpdate) {
+				assert.Equal(t, http.StatusBadRequest, rr.Code)
+			},
+		},
+		{
+			name:        "Validation Error - Removed Required Field",
+			contentType: "application/merge-patch+json",
+			body:        `{"start_date":null}`,
+			setupMocks: func(th testHarness, update *domain.SubscriptionUpdate) {
+				th.service.On("Patch", ctx, subID, (*int)(nil), mock.Anything).Return(patchCurrent(update)).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder, _ domain.SubscriptionUpdate) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+			},
+		},
+		{
+			name:        "Validation Error - Unknown Field",
+			contentType: "application/merge-patch+json",
+			body:        `{"pauses":[]}`,
+			setupMocks: func(th testHarness, update *domain.SubscriptionUpdate) {
+				th.service.On("Patch", ctx, subID, (*int)(nil), mock.Anything).Return(patchCurrent(update)).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder, _ domain.SubscriptionUpdate) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+			},
+		},
+		{
+			name:        "Unsupported Media Type",
+			contentType: "application/json",
+			body:        `{"service_name":"Okko Premium"}`,
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder, _ domain.SubscriptionUpdate) {
+				assert.Equal(t, http.StatusUnsupportedMediaType, rr.Code)
+			},
+		},
+		{
+			name:        "Success - If-Match",
+			contentType: "application/merge-patch+json",
+			params:      dto.PatchSubscriptionParams{IfMatch: ptr(`"2"`)},
+			body:        `{"service_name":"Okko Premium"}`,
+			setupMocks: func(th testHarness, update *domain.SubscriptionUpdate) {
+				th.service.On("Patch", ctx, subID, ptr(2), mock.Anything).Return(patchCurrent(update)).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder, _ domain.SubscriptionUpdate) {
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, `"3"`, rr.Header().Get("ETag"))
+			},
+		},
+		{
+			name:        "Precondition Failed - Stale ETag",
+			contentType: "application/merge-patch+json",
+			params:      dto.PatchSubscriptionParams{IfMatch: ptr(`"1"`)},
+			body:        `{"service_name":"Okko Premium"}`,
+			setupMocks: func(th testHarness, _ *domain.SubscriptionUpdate) {
+				th.service.On("Patch", ctx, subID, ptr(1), mock.Anything).Return(nil, serviceErrConflict).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder, _ domain.SubscriptionUpdate) {
+				assert.Equal(t, http.StatusPreconditionFailed, rr.Code)
+			},
+		},
+		{
+			name:        "Service Error - Not Found",
+			contentType: "application/merge-patch+json",
+			body:        `{"service_name":"Okko Premium"}`,
+			setupMocks: func(th testHarness, _ *domain.SubscriptionUpdate) {
+				th.service.On("Patch", ctx, subID, (*int)(nil), mock.Anything).Return(nil, serviceErrNotFound).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder, _ domain.SubscriptionUpdate) {
+				assert.Equal(t, http.StatusNotFound, rr.Code)
 			},
 		},
 	}
@@ -480,65 +846,61 @@ func TestHandler_DeleteSubscription(t *testing.T) {
 			t.Parallel()
 
 			th := setup(t)
+			var update domain.SubscriptionUpdate
 			if tc.setupMocks != nil {
-				tc.setupMocks(th)
+				tc.setupMocks(th, &update)
 			}
 
-			req := newRequestWithChiCtx(t, http.MethodDelete, "/subscriptions/"+tc.subID, nil, map[string]string{"id": tc.subID})
+			req := newRequestWithChiCtx(t, http.MethodPatch, "/subscriptions/"+subID.String(), strings.NewReader(tc.body), map[string]string{"id": subID.String()})
+			req.Header.Set("Content-Type", tc.contentType)
 			rr := httptest.NewRecorder()
 
-			id, err := uuid.Parse(tc.subID)
-			require.NoError(t, err)
-
-			th.h.DeleteSubscription(rr, req.WithContext(ctx), id)
-			tc.assertFunc(t, rr)
+			th.h.PatchSubscription(rr, req.WithContext(ctx), subID, tc.params)
+			tc.assertFunc(t, rr, update)
 		})
 	}
 }
 
-func TestHandler_ListSubscriptions(t *testing.T) {
+func TestHandler_ListSubscriptionPrices(t *testing.T) {
 	t.Parallel()
 
-	ctx := context.Background()
-	expectedSubs := []domain.Subscription{
-		{ID: uuid.New(), ServiceName: "Test 1"},
-		{ID: uuid.New(), ServiceName: "Test 2"},
+	ctx := adminCtx()
+	subID := uuid.New()
+	changes := []domain.PriceChange{
+		{EffectiveFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Price: 100, CreatedAt: time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{EffectiveFrom: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), Price: 150, CreatedAt: time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)},
 	}
-	genericErr := errors.New("generic error")
-	serviceErrGeneric := subservice.WrapErr("subservice.List", subservice.KindUnknown, genericErr)
+	serviceErrNotFound := subservice.NewErr("subservice.ListPriceChanges", subservice.KindNotFound)
 
 	testCases := []struct {
 		name       string
-		params     dto.ListSubscriptionsParams
 		setupMocks func(th testHarness)
 		assertFunc func(t *testing.T, rr *httptest.ResponseRecorder)
 	}{
 		{
-			name:   "Success - No Filter",
-			params: dto.ListSubscriptionsParams{},
+			name: "Success",
 			setupMocks: func(th testHarness) {
-				th.service.On("List", ctx, mock.Anything).Return(expectedSubs, nil).Once()
+				th.service.On("ListPriceChanges", ctx, subID).Return(changes, nil).Once()
 			},
 			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
-				var respBody []dto.Subscription
+				assert.Equal(t, http.StatusOK, rr.Code)
+				var respBody []dto.PriceChange
 				err := json.NewDecoder(rr.Body).Decode(&respBody)
 				require.NoError(t, err)
 				require.Len(t, respBody, 2)
-				assert.Equal(t, rr.Code, http.StatusOK)
-				assert.Equal(t, expectedSubs[0].ID, respBody[0].Id)
+				assert.Equal(t, "01-2025", respBody[0].EffectiveFrom)
+				assert.Equal(t, 100, respBody[0].Price)
+				assert.Equal(t, "06-2025", respBody[1].EffectiveFrom)
+				assert.Equal(t, 150, respBody[1].Price)
 			},
 		},
 		{
-			name:   "Service Error",
-			params: dto.ListSubscriptionsParams{},
+			name: "Not Found",
 			setupMocks: func(th testHarness) {
-				th.service.On("List", ctx, mock.Anything).Return(nil, serviceErrGeneric).Once()
+				th.service.On("ListPriceChanges", ctx, subID).Return(nil, serviceErrNotFound).Once()
 			},
 			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
-				var errBody dto.Error
-				err := json.NewDecoder(rr.Body).Decode(&errBody)
-				require.NoError(t, err)
-				assert.Equal(t, int32(http.StatusInternalServerError), errBody.Code)
+				assert.Equal(t, http.StatusNotFound, rr.Code)
 			},
 		},
 	}
@@ -548,23 +910,531 @@ func TestHandler_ListSubscriptions(t *testing.T) {
 			t.Parallel()
 
 			th := setup(t)
-			if tc.setupMocks != nil {
-				tc.setupMocks(th)
-			}
+			tc.setupMocks(th)
 
-			req := httptest.NewRequest(http.MethodGet, "/subscriptions", nil)
+			req := newRequestWithChiCtx(t, http.MethodGet, "/subscriptions/"+subID.String()+"/prices", nil, map[string]string{"id": subID.String()})
 			rr := httptest.NewRecorder()
 
-			th.h.ListSubscriptions(rr, req.WithContext(ctx), tc.params)
+			th.h.ListSubscriptionPrices(rr, req.WithContext(ctx), subID)
 			tc.assertFunc(t, rr)
 		})
 	}
 }
 
-func TestHandler_GetTotalCost(t *testing.T) {
+func TestHandler_PauseSubscription(t *testing.T) {
 	t.Parallel()
 
-	ctx := context.Background()
+	ctx := adminCtx()
+	subID := uuid.New()
+	march := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	june := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+	serviceErrBizLogic := subservice.NewErr("subservice.Pause", subservice.KindBusinessLogic)
+
+	testCases := []struct {
+		name       string
+		body       io.Reader
+		setupMocks func(th testHarness)
+		assertFunc func(t *testing.T, rr *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Success - Without Body",
+			body: nil,
+			setupMocks: func(th testHarness) {
+				th.service.On("Pause", ctx, subID, (*time.Time)(nil), (*time.Time)(nil)).
+					Return(&domain.Pause{PausedFrom: march}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, rr.Code)
+				var respBody dto.Pause
+				err := json.NewDecoder(rr.Body).Decode(&respBody)
+				require.NoError(t, err)
+				assert.Equal(t, "03-2025", respBody.PausedFrom)
+				assert.Nil(t, respBody.ResumedFrom)
+			},
+		},
+		{
+			name: "Success - With Months",
+			body: mustMarshal(t, map[string]string{"paused_from": "03-2025", "resumed_from": "06-2025"}),
+			setupMocks: func(th testHarness) {
+				th.service.On("Pause", ctx, subID, &march, &june).
+					Return(&domain.Pause{PausedFrom: march, ResumedFrom: &june}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusCreated, rr.Code)
+				var respBody dto.Pause
+				err := json.NewDecoder(rr.Body).Decode(&respBody)
+				require.NoError(t, err)
+				require.NotNil(t, respBody.ResumedFrom)
+				assert.Equal(t, "06-2025", *respBody.ResumedFrom)
+			},
+		},
+		{
+			name:       "Validation Error - Invalid Month",
+			body:       mustMarshal(t, map[string]string{"paused_from": "2025-03"}),
+			setupMocks: func(th testHarness) {},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+			},
+		},
+		{
+			name: "Service Error - Overlapping Pause",
+			body: mustMarshal(t, map[string]string{"paused_from": "03-2025"}),
+			setupMocks: func(th testHarness) {
+				th.service.On("Pause", ctx, subID, &march, (*time.Time)(nil)).Return(nil, serviceErrBizLogic).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			th := setup(t)
+			tc.setupMocks(th)
+
+			req := newRequestWithChiCtx(t, http.MethodPost, "/subscriptions/"+subID.String()+"/pause", tc.body, map[string]string{"id": subID.String()})
+			rr := httptest.NewRecorder()
+
+			th.h.PauseSubscription(rr, req.WithContext(ctx), subID)
+			tc.assertFunc(t, rr)
+		})
+	}
+}
+
+func TestHandler_ResumeSubscription(t *testing.T) {
+	t.Parallel()
+
+	ctx := adminCtx()
+	subID := uuid.New()
+	march := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	june := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+	serviceErrBizLogic := subservice.NewErr("subservice.Resume", subservice.KindBusinessLogic)
+
+	testCases := []struct {
+		name       string
+		body       io.Reader
+		setupMocks func(th testHarness)
+		assertFunc func(t *testing.T, rr *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Success",
+			body: mustMarshal(t, map[string]string{"resumed_from": "06-2025"}),
+			setupMocks: func(th testHarness) {
+				th.service.On("Resume", ctx, subID, &june).
+					Return(&domain.Pause{PausedFrom: march, ResumedFrom: &june}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rr.Code)
+				var respBody dto.Pause
+				err := json.NewDecoder(rr.Body).Decode(&respBody)
+				require.NoError(t, err)
+				require.NotNil(t, respBody.ResumedFrom)
+				assert.Equal(t, "06-2025", *respBody.ResumedFrom)
+			},
+		},
+		{
+			name: "Service Error - Not Paused",
+			body: nil,
+			setupMocks: func(th testHarness) {
+				th.service.On("Resume", ctx, subID, (*time.Time)(nil)).Return(nil, serviceErrBizLogic).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			th := setup(t)
+			tc.setupMocks(th)
+
+			req := newRequestWithChiCtx(t, http.MethodPost, "/subscriptions/"+subID.String()+"/resume", tc.body, map[string]string{"id": subID.String()})
+			rr := httptest.NewRecorder()
+
+			th.h.ResumeSubscription(rr, req.WithContext(ctx), subID)
+			tc.assertFunc(t, rr)
+		})
+	}
+}
+
+func TestHandler_ListSubscriptionPauses(t *testing.T) {
+	t.Parallel()
+
+	ctx := adminCtx()
+	subID := uuid.New()
+	june := time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC)
+	th := setup(t)
+	th.service.On("ListPauses", ctx, subID).Return([]domain.Pause{
+		{PausedFrom: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC), ResumedFrom: &june},
+		{PausedFrom: time.Date(2025, time.September, 1, 0, 0, 0, 0, time.UTC)},
+	}, nil).Once()
+
+	req := newRequestWithChiCtx(t, http.MethodGet, "/subscriptions/"+subID.String()+"/pauses", nil, map[string]string{"id": subID.String()})
+	rr := httptest.NewRecorder()
+
+	th.h.ListSubscriptionPauses(rr, req.WithContext(ctx), subID)
+
+	var respBody []dto.Pause
+	err := json.NewDecoder(rr.Body).Decode(&respBody)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, respBody, 2)
+	assert.Equal(t, "03-2025", respBody[0].PausedFrom)
+	assert.Equal(t, "09-2025", respBody[1].PausedFrom)
+	assert.Nil(t, respBody[1].ResumedFrom)
+}
+
+func TestHandler_DeleteSubscription(t *testing.T) {
+	t.Parallel()
+
+	ctx := adminCtx()
+	subID := uuid.New()
+	serviceErrNotFound := subservice.NewErr("subservice.Delete", subservice.KindNotFound)
+	genericErr := errors.New("generic error")
+	serviceErrGeneric := subservice.WrapErr("subservice.Delete", subservice.KindUnknown, genericErr)
+	serviceErrConflict := subservice.NewErr("subservice.Delete", subservice.KindConflict)
+
+	testCases := []struct {
+		name       string
+		subID      string
+		params     dto.DeleteSubscriptionParams
+		setupMocks func(th testHarness)
+		assertFunc func(t *testing.T, rr *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "Success",
+			subID: subID.String(),
+			setupMocks: func(th testHarness) {
+				th.service.On("Delete", ctx, subID, (*int)(nil)).Return(nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, rr.Code, http.StatusNoContent)
+				assert.Equal(t, 0, rr.Body.Len())
+			},
+		},
+		{
+			name:  "Not Found",
+			subID: subID.String(),
+			setupMocks: func(th testHarness) {
+				th.service.On("Delete", ctx, subID, (*int)(nil)).Return(serviceErrNotFound).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var errBody dto.Error
+				err := json.NewDecoder(rr.Body).Decode(&errBody)
+				require.NoError(t, err)
+				assert.Equal(t, int32(http.StatusNotFound), errBody.Code)
+			},
+		},
+		{
+			name:   "Any Version",
+			subID:  subID.String(),
+			params: dto.DeleteSubscriptionParams{IfMatch: ptr("*")},
+			setupMocks: func(th testHarness) {
+				th.service.On("Delete", ctx, subID, (*int)(nil)).Return(nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusNoContent, rr.Code)
+			},
+		},
+		{
+			name:   "Precondition Failed",
+			subID:  subID.String(),
+			params: dto.DeleteSubscriptionParams{IfMatch: ptr(`"5"`)},
+			setupMocks: func(th testHarness) {
+				th.service.On("Delete", ctx, subID, ptr(5)).Return(serviceErrConflict).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var errBody dto.Error
+				err := json.NewDecoder(rr.Body).Decode(&errBody)
+				require.NoError(t, err)
+				assert.Equal(t, int32(http.StatusPreconditionFailed), errBody.Code)
+				assert.Equal(t, preconditionFailedMsg, errBody.Message)
+			},
+		},
+		{
+			name:   "Bad Request - Several ETags",
+			subID:  subID.String(),
+			params: dto.DeleteSubscriptionParams{IfMatch: ptr(`"4", "5"`)},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rr.Code)
+			},
+		},
+		{
+			name:  "Generic Service Error",
+			subID: subID.String(),
+			setupMocks: func(th testHarness) {
+				th.service.On("Delete", ctx, subID, (*int)(nil)).Return(serviceErrGeneric).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var errBody dto.Error
+				err := json.NewDecoder(rr.Body).Decode(&errBody)
+				require.NoError(t, err)
+				assert.Equal(t, int32(http.StatusInternalServerError), errBody.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			th := setup(t)
+			if tc.setupMocks != nil {
+				tc.setupMocks(th)
+			}
+
+			req := newRequestWithChiCtx(t, http.MethodDelete, "/subscriptions/"+tc.subID, nil, map[string]string{"id": tc.subID})
+			rr := httptest.NewRecorder()
+
+			id, err := uuid.Parse(tc.subID)
+			require.NoError(t, err)
+
+			th.h.DeleteSubscription(rr, req.WithContext(ctx), id, tc.params)
+			tc.assertFunc(t, rr)
+		})
+	}
+}
+
+func TestHandler_ListSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	ctx := adminCtx()
+	expectedSubs := []domain.Subscription{
+		{ID: uuid.New(), ServiceName: "Test 1"},
+		{ID: uuid.New(), ServiceName: "Test 2"},
+	}
+	nextCursor := domain.Cursor{CreatedAt: time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC), BillingPeriod: domain.BillingMonthly, ID: expectedSubs[1].ID}
+	genericErr := errors.New("generic error")
+	serviceErrGeneric := subservice.WrapErr("subservice.List", subservice.KindUnknown, genericErr)
+
+	testCases := []struct {
+		name       string
+		params     dto.ListSubscriptionsParams
+		accept     string
+		setupMocks func(th testHarness)
+		assertFunc func(t *testing.T, rr *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Success - No Filter",
+			params: dto.ListSubscriptionsParams{},
+			setupMocks: func(th testHarness) {
+				th.service.On("List", ctx, mock.Anything).
+					Return(&domain.SubscriptionPage{Items: expectedSubs, NextCursor: &nextCursor, Total: 3, Page: 1, PageSize: 2}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var respBody []dto.Subscription
+				err := json.NewDecoder(rr.Body).Decode(&respBody)
+				require.NoError(t, err)
+				require.Len(t, respBody, 2)
+				assert.Equal(t, rr.Code, http.StatusOK)
+				assert.Equal(t, expectedSubs[0].ID, respBody[0].Id)
+				assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+				assert.Equal(t, "3", rr.Header().Get("X-Total-Count"))
+				assert.Equal(t,
+					`</subscriptions?page=1>; rel="first", </subscriptions?page=2>; rel="next", </subscriptions?page=2>; rel="last"`,
+					rr.Header().Get("Link"))
+			},
+		},
+		{
+			name:   "Success - Page envelope",
+			params: dto.ListSubscriptionsParams{},
+			accept: "application/vnd.subscriptions.v2+json; date-format=iso-8601",
+			setupMocks: func(th testHarness) {
+				th.service.On("List", ctx, mock.Anything).
+					Return(&domain.SubscriptionPage{Items: expectedSubs, NextCursor: &nextCursor, Total: 3, Page: 1, PageSize: 2}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var respBody dto.SubscriptionPage
+				err := json.NewDecoder(rr.Body).Decode(&respBody)
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, subscriptionPageMediaType, rr.Header().Get("Content-Type"))
+				require.Len(t, respBody.Items, 2)
+				require.NotNil(t, respBody.NextCursor)
+				assert.Equal(t, encodeCursor(nextCursor), *respBody.NextCursor)
+				assert.Equal(t, 3, respBody.Total)
+				assert.Equal(t, ptr(1), respBody.Page)
+				assert.Equal(t, 2, respBody.PageSize)
+			},
+		},
+		{
+			name:   "Success - NDJSON",
+			params: dto.ListSubscriptionsParams{},
+			accept: ndjsonMediaType,
+			setupMocks: func(th testHarness) {
+				th.service.On("List", ctx, mock.Anything).
+					Return(&domain.SubscriptionPage{Items: expectedSubs, NextCursor: &nextCursor, Total: 3, Page: 1, PageSize: 2}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, ndjsonMediaType, rr.Header().Get("Content-Type"))
+				assert.Equal(t, "3", rr.Header().Get("X-Total-Count"))
+				assert.Contains(t, rr.Header().Get("Link"), `rel="next"`)
+				dec := json.NewDecoder(rr.Body)
+				for _, want := range expectedSubs {
+					var sub dto.Subscription
+					require.NoError(t, dec.Decode(&sub))
+					assert.Equal(t, want.ID, sub.Id)
+				}
+				assert.False(t, dec.More())
+			},
+		},
+		{
+			name:   "Success - Last page",
+			params: dto.ListSubscriptionsParams{Cursor: ptr(encodeCursor(nextCursor))},
+			accept: subscriptionPageMediaType,
+			setupMocks: func(th testHarness) {
+				th.service.On("List", ctx, domain.SubscriptionFilter{After: &nextCursor}).
+					Return(&domain.SubscriptionPage{Items: []domain.Subscription{}, Total: 3, PageSize: 2}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var respBody dto.SubscriptionPage
+				err := json.NewDecoder(rr.Body).Decode(&respBody)
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Empty(t, respBody.Items)
+				assert.Nil(t, respBody.NextCursor)
+				assert.Nil(t, respBody.Page)
+				assert.Equal(t, `</subscriptions>; rel="first"`, rr.Header().Get("Link"))
+			},
+		},
+		{
+			name:   "Unknown Sort Key",
+			params: dto.ListSubscriptionsParams{Sort: &[]string{"user_id"}},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var errBody dto.Error
+				err := json.NewDecoder(rr.Body).Decode(&errBody)
+				require.NoError(t, err)
+				assert.Equal(t, int32(http.StatusBadRequest), errBody.Code)
+				assert.Equal(t, invalidSortMsg, errBody.Message)
+			},
+		},
+		{
+			name:   "Invalid Cursor",
+			params: dto.ListSubscriptionsParams{Cursor: ptr("garbage")},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var errBody dto.Error
+				err := json.NewDecoder(rr.Body).Decode(&errBody)
+				require.NoError(t, err)
+				assert.Equal(t, int32(http.StatusUnprocessableEntity), errBody.Code)
+			},
+		},
+		{
+			name:   "Service Error",
+			params: dto.ListSubscriptionsParams{},
+			setupMocks: func(th testHarness) {
+				th.service.On("List", ctx, mock.Anything).Return(nil, serviceErrGeneric).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var errBody dto.Error
+				err := json.NewDecoder(rr.Body).Decode(&errBody)
+				require.NoError(t, err)
+				assert.Equal(t, int32(http.StatusInternalServerError), errBody.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			th := setup(t)
+			if tc.setupMocks != nil {
+				tc.setupMocks(th)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/subscriptions", nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			rr := httptest.NewRecorder()
+
+			th.h.ListSubscriptions(rr, req.WithContext(ctx), tc.params)
+			tc.assertFunc(t, rr)
+		})
+	}
+}
+
+func TestHandler_SuggestServices(t *testing.T) {
+	t.Parallel()
+
+	ctx := adminCtx()
+	serviceErrGeneric := subservice.WrapErr("subservice.SuggestServiceNames", subservice.KindUnknown, errors.New("generic error"))
+
+	testCases := []struct {
+		name       string
+		params     dto.SuggestServicesParams
+		setupMocks func(th testHarness)
+		assertFunc func(t *testing.T, rr *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "Success",
+			params: dto.SuggestServicesParams{Q: " yan ", Limit: ptr(5)},
+			setupMocks: func(th testHarness) {
+				th.service.On("SuggestServiceNames", ctx, "yan", ptr(5)).Return([]string{"Yandex Plus"}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var respBody []string
+				err := json.NewDecoder(rr.Body).Decode(&respBody)
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, []string{"Yandex Plus"}, respBody)
+			},
+		},
+		{
+			name:   "Blank Query",
+			params: dto.SuggestServicesParams{Q: "   "},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var errBody dto.Error
+				err := json.NewDecoder(rr.Body).Decode(&errBody)
+				require.NoError(t, err)
+				assert.Equal(t, int32(http.StatusUnprocessableEntity), errBody.Code)
+				assert.Equal(t, blankQueryMsg, errBody.Message)
+			},
+		},
+		{
+			name:   "Service Error",
+			params: dto.SuggestServicesParams{Q: "yan"},
+			setupMocks: func(th testHarness) {
+				th.service.On("SuggestServiceNames", ctx, "yan", (*int)(nil)).Return(nil, serviceErrGeneric).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var errBody dto.Error
+				err := json.NewDecoder(rr.Body).Decode(&errBody)
+				require.NoError(t, err)
+				assert.Equal(t, int32(http.StatusInternalServerError), errBody.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			th := setup(t)
+			if tc.setupMocks != nil {
+				tc.setupMocks(th)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/services/suggest", nil)
+			rr := httptest.NewRecorder()
+
+			th.h.SuggestServices(rr, req.WithContext(ctx), tc.params)
+			tc.assertFunc(t, rr)
+		})
+	}
+}
+
+func TestHandler_GetTotalCost(t *testing.T) {
+	t.Parallel()
+
+	ctx := adminCtx()
 	userID := uuid.New()
 	genericErr := errors.New("generic error")
 	serviceErrGeneric := subservice.WrapErr("subservice.TotalCost", subservice.KindUnknown, genericErr)
@@ -581,7 +1451,7 @@ func TestHandler_GetTotalCost(t *testing.T) {
 				UserId: userID,
 			},
 			setupMocks: func(th testHarness) {
-				th.service.On("TotalCost", ctx, mock.Anything, mock.Anything, mock.Anything).Return(12345, nil).Once()
+				th.service.On("TotalCost", ctx, mock.Anything, mock.Anything, mock.Anything, "RUB", domain.CostModeBilled).Return(12345, nil).Once()
 			},
 			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
 				var respBody dto.TotalCost
@@ -590,6 +1460,73 @@ func TestHandler_GetTotalCost(t *testing.T) {
 				require.NotNil(t, respBody.TotalCost)
 				assert.Equal(t, rr.Code, http.StatusOK)
 				assert.Equal(t, 12345, *respBody.TotalCost)
+				require.NotNil(t, respBody.Currency)
+				assert.Equal(t, "RUB", *respBody.Currency)
+			},
+		},
+		{
+			name: "Success - Target Currency",
+			params: dto.GetTotalCostParams{
+				UserId:   userID,
+				Currency: func(s string) *string { return &s }("USD"),
+			},
+			setupMocks: func(th testHarness) {
+				th.service.On("TotalCost", ctx, mock.Anything, mock.Anything, mock.Anything, "USD", domain.CostModeBilled).Return(150, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var respBody dto.TotalCost
+				err := json.NewDecoder(rr.Body).Decode(&respBody)
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, 150, *respBody.TotalCost)
+				assert.Equal(t, "USD", *respBody.Currency)
+			},
+		},
+		{
+			name: "Validation Error - Invalid Currency",
+			params: dto.GetTotalCostParams{
+				UserId:   userID,
+				Currency: func(s string) *string { return &s }("dollars"),
+			},
+			setupMocks: nil,
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var errBody dto.Error
+				err := json.NewDecoder(rr.Body).Decode(&errBody)
+				require.NoError(t, err)
+				assert.Equal(t, int32(http.StatusUnprocessableEntity), errBody.Code)
+				assert.Equal(t, invalidCurrencyMsg, errBody.Message)
+			},
+		},
+		{
+			name: "Success - Prorated",
+			params: dto.GetTotalCostParams{
+				UserId: userID,
+				Mode:   ptr(dto.Prorated),
+			},
+			setupMocks: func(th testHarness) {
+				th.service.On("TotalCost", ctx, mock.Anything, mock.Anything, mock.Anything, "RUB", domain.CostModeProrated).Return(99, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var respBody dto.TotalCost
+				err := json.NewDecoder(rr.Body).Decode(&respBody)
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, 99, *respBody.TotalCost)
+			},
+		},
+		{
+			name: "Validation Error - Invalid Mode",
+			params: dto.GetTotalCostParams{
+				UserId: userID,
+				Mode:   ptr(dto.CostMode("estimated")),
+			},
+			setupMocks: nil,
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var errBody dto.Error
+				err := json.NewDecoder(rr.Body).Decode(&errBody)
+				require.NoError(t, err)
+				assert.Equal(t, int32(http.StatusUnprocessableEntity), errBody.Code)
+				assert.Equal(t, invalidCostModeMsg, errBody.Message)
 			},
 		},
 		{
@@ -613,7 +1550,7 @@ func TestHandler_GetTotalCost(t *testing.T) {
 				UserId: userID,
 			},
 			setupMocks: func(th testHarness) {
-				th.service.On("TotalCost", ctx, mock.Anything, mock.Anything, mock.Anything).Return(0, serviceErrGeneric).Once()
+				th.service.On("TotalCost", ctx, mock.Anything, mock.Anything, mock.Anything, "RUB", domain.CostModeBilled).Return(0, serviceErrGeneric).Once()
 			},
 			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
 				var errBody dto.Error
@@ -641,3 +1578,247 @@ func TestHandler_GetTotalCost(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_GetCostBreakdown(t *testing.T) {
+	t.Parallel()
+
+	ctx := adminCtx()
+	userID := uuid.New()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	months := []domain.MonthCost{
+		{Month: start, Total: 100, Items: []domain.CostItem{{SubscriptionID: uuid.New(), ServiceName: "Test", Cost: 100}}},
+		{Month: end, Total: 0, Items: []domain.CostItem{}},
+	}
+	genericErr := errors.New("generic error")
+	serviceErrGeneric := subservice.WrapErr("subservice.CostBreakdown", subservice.KindUnknown, genericErr)
+
+	testCases := []struct {
+		name       string
+		params     dto.GetCostBreakdownParams
+		setupMocks func(th testHarness)
+		assertFunc func(t *testing.T, rr *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Success",
+			params: dto.GetCostBreakdownParams{
+				UserId: userID,
+				Start:  "01-2025",
+				End:    "02-2025",
+			},
+			setupMocks: func(th testHarness) {
+				th.service.On("CostBreakdown", ctx, mock.Anything, start, end, "RUB", domain.CostModeBilled).Return(months, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var respBody dto.CostBreakdown
+				err := json.NewDecoder(rr.Body).Decode(&respBody)
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, 100, respBody.TotalCost)
+				assert.Equal(t, "RUB", respBody.Currency)
+				require.Len(t, respBody.Months, 2)
+				assert.Equal(t, "01-2025", respBody.Months[0].Month)
+				assert.Len(t, respBody.Months[0].Items, 1)
+			},
+		},
+		{
+			name: "Validation Error - Start After End",
+			params: dto.GetCostBreakdownParams{
+				UserId: userID,
+				Start:  "02-2025",
+				End:    "01-2025",
+			},
+			setupMocks: nil,
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var errBody dto.Error
+				err := json.NewDecoder(rr.Body).Decode(&errBody)
+				require.NoError(t, err)
+				assert.Equal(t, int32(http.StatusUnprocessableEntity), errBody.Code)
+				assert.Equal(t, startAfterEndMsg, errBody.Message)
+			},
+		},
+		{
+			name: "Service Error",
+			params: dto.GetCostBreakdownParams{
+				UserId: userID,
+				Start:  "01-2025",
+				End:    "02-2025",
+			},
+			setupMocks: func(th testHarness) {
+				th.service.On("CostBreakdown", ctx, mock.Anything, start, end, "RUB", domain.CostModeBilled).Return(nil, serviceErrGeneric).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var errBody dto.Error
+				err := json.NewDecoder(rr.Body).Decode(&errBody)
+				require.NoError(t, err)
+				assert.Equal(t, int32(http.StatusInternalServerError), errBody.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			th := setup(t)
+			if tc.setupMocks != nil {
+				tc.setupMocks(th)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/subscriptions/cost_breakdown", nil)
+			rr := httptest.NewRecorder()
+
+			th.h.GetCostBreakdown(rr, req.WithContext(ctx), tc.params)
+			tc.assertFunc(t, rr)
+		})
+	}
+}
+
+func TestHandler_SetCurrencyRate(t *testing.T) {
+	t.Parallel()
+
+	ctx := adminCtx()
+	updatedAt := time.Date(2025, 11, 15, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name       string
+		from, to   string
+		body       io.Reader
+		setupMocks func(th testHarness)
+		assertFunc func(t *testing.T, rr *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Success",
+			from: "USD",
+			to:   "RUB",
+			body: strings.NewReader(`{"rate": 81.5}`),
+			setupMocks: func(th testHarness) {
+				th.service.On("SetCurrencyRate", ctx, domain.CurrencyRate{From: "USD", To: "RUB", Rate: 81.5}).
+					Return(&domain.CurrencyRate{From: "USD", To: "RUB", Rate: 81.5, UpdatedAt: updatedAt}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var respBody dto.CurrencyRate
+				err := json.NewDecoder(rr.Body).Decode(&respBody)
+				require.NoError(t, err)
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, dto.CurrencyRate{From: "USD", To: "RUB", Rate: 81.5, UpdatedAt: updatedAt}, respBody)
+			},
+		},
+		{
+			name: "Malformed JSON",
+			from: "USD",
+			to:   "RUB",
+			body: strings.NewReader(`{"rate":`),
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusBadRequest, rr.Code)
+			},
+		},
+		{
+			name: "Invalid currency code",
+			from: "usd",
+			to:   "RUB",
+			body: strings.NewReader(`{"rate": 81.5}`),
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var errBody dto.Error
+				err := json.NewDecoder(rr.Body).Decode(&errBody)
+				require.NoError(t, err)
+				assert.Equal(t, int32(http.StatusUnprocessableEntity), errBody.Code)
+				assert.Equal(t, invalidCurrencyMsg, errBody.Message)
+			},
+		},
+		{
+			name: "Non-positive rate",
+			from: "USD",
+			to:   "RUB",
+			body: strings.NewReader(`{"rate": -1}`),
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				var errBody dto.Error
+				err := json.NewDecoder(rr.Body).Decode(&errBody)
+				require.NoError(t, err)
+				assert.Equal(t, int32(http.StatusUnprocessableEntity), errBody.Code)
+				assert.Equal(t, invalidRateMsg, errBody.Message)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			th := setup(t)
+			if tc.setupMocks != nil {
+				tc.setupMocks(th)
+			}
+
+			req := httptest.NewRequest(http.MethodPut, "/admin/currency_rates/"+tc.from+"/"+tc.to, tc.body)
+			rr := httptest.NewRecorder()
+
+			th.h.SetCurrencyRate(rr, req.WithContext(ctx), tc.from, tc.to)
+			tc.assertFunc(t, rr)
+		})
+	}
+}
+
+func TestHandler_DeleteCurrencyRate(t *testing.T) {
+	t.Parallel()
+
+	ctx := adminCtx()
+	serviceErrNotFound := subservice.NewErr("subservice.DeleteCurrencyRate", subservice.KindNotFound)
+
+	testCases := []struct {
+		name         string
+		setupMocks   func(th testHarness)
+		expectedCode int
+	}{
+		{
+			name: "Success",
+			setupMocks: func(th testHarness) {
+				th.service.On("DeleteCurrencyRate", ctx, "USD", "RUB").Return(nil).Once()
+			},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name: "Not Found",
+			setupMocks: func(th testHarness) {
+				th.service.On("DeleteCurrencyRate", ctx, "USD", "RUB").Return(serviceErrNotFound).Once()
+			},
+			expectedCode: http.StatusNotFound,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			th := setup(t)
+			tc.setupMocks(th)
+
+			req := httptest.NewRequest(http.MethodDelete, "/admin/currency_rates/USD/RUB", nil)
+			rr := httptest.NewRecorder()
+
+			th.h.DeleteCurrencyRate(rr, req.WithContext(ctx), "USD", "RUB")
+			assert.Equal(t, tc.expectedCode, rr.Code)
+		})
+	}
+}
+
+func TestHandler_ListCurrencyRates(t *testing.T) {
+	t.Parallel()
+
+	ctx := adminCtx()
+	th := setup(t)
+	th.service.On("ListCurrencyRates", ctx).
+		Return([]domain.CurrencyRate{{From: "USD", To: "RUB", Rate: 81.5}}, nil).Once()
+
+	req := httptest.NewRequest(http.MethodGet, "/admin/currency_rates", nil)
+	rr := httptest.NewRecorder()
+
+	th.h.ListCurrencyRates(rr, req.WithContext(ctx))
+
+	var respBody []dto.CurrencyRate
+	err := json.NewDecoder(rr.Body).Decode(&respBody)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rr.Code)
+	require.Len(t, respBody, 1)
+	assert.Equal(t, "USD", respBody[0].From)
+}
@@ -0,0 +1,29 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+)
+
+const maxIdempotencyKeyLen = 255
+
+// toIdempotencyKey pairs key with a hash of the decoded body, so retries may
+// format the same request differently.
+func toIdempotencyKey(key string, body *dto.NewSubscription) (domain.IdempotencyKey, error) {
+	if key == "" || len(key) > maxIdempotencyKeyLen {
+		return domain.IdempotencyKey{}, &DTOValidationError{ClientMessage: invalidIdempotencyKeyMsg, StatusCode: http.StatusBadRequest}
+	}
+
+	b, err := json.Marshal(body)
+	if err != nil {
+		return domain.IdempotencyKey{}, err
+	}
+	hash := sha256.Sum256(b)
+
+	return domain.IdempotencyKey{Key: key, RequestHash: hex.EncodeToString(hash[:])}, nil
+}
@@ -3,20 +3,39 @@ package http
 import (
 	"fmt"
 	"log/slog"
+	"mime"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/pkg/jwt"
 	"github.com/shrtyk/subscriptions-service/pkg/log"
 )
 
+const (
+	dateFormatQueryParam  = "date_format"
+	dateFormatAcceptParam = "date-format"
+	monthDateFormat       = "month"
+	isoDateFormat         = "iso-8601"
+
+	subscriptionPageMediaType = "application/vnd.subscriptions.v2+json"
+)
+
 type middlewares struct {
-	log *slog.Logger
+	log     *slog.Logger
+	tokens  *jwt.Verifier
+	keys    apiKeyAuthenticator
+	tenants TenantResolver
 }
 
-func NewMiddlewaresProvider(log *slog.Logger) *middlewares {
+func NewMiddlewaresProvider(log *slog.Logger, tokens *jwt.Verifier, keys apiKeyAuthenticator, tenants TenantResolver) *middlewares {
 	return &middlewares{
-		log: log,
+		log:     log,
+		tokens:  tokens,
+		keys:    keys,
+		tenants: tenants,
 	}
 }
 
@@ -24,6 +43,10 @@ func (m middlewares) PanicRecoveryMW(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		defer func() {
 			if err := recover(); err != nil {
+				// Handlers abort responses they have started this way, there is nothing to write.
+				if err == http.ErrAbortHandler {
+					panic(err)
+				}
 				m.log.Error(
 					"Error occurred",
 					log.WithErr(fmt.Errorf("%v", err)),
@@ -54,7 +77,7 @@ func (m middlewares) LoggingMW(next http.Handler) http.Handler {
 		)
 
 		l.Debug("New HTTP request")
-		newCtx := log.ToCtx(r.Context(), l)
+		newCtx := domain.RequestIDToCtx(log.ToCtx(r.Context(), l), reqID)
 		newReq := r.WithContext(newCtx)
 		custWriter := &customResponseWriter{
 			ResponseWriter: w,
@@ -73,3 +96,59 @@ func (m middlewares) LoggingMW(next http.Handler) http.Handler {
 		)
 	})
 }
+
+// DateFormatMW picks the layout of subscription dates in responses. The date_format
+// query flag takes precedence over the date-format parameter of the Accept header,
+// e.g. "Accept: application/json; date-format=iso-8601".
+func (m middlewares) DateFormatMW(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		format := r.URL.Query().Get(dateFormatQueryParam)
+		if format == "" {
+			format = acceptedDateFormat(r.Header.Values("Accept"))
+		}
+
+		layout := dateLayout
+		switch format {
+		case "", monthDateFormat:
+		case isoDateFormat:
+			layout = isoDateLayout
+		default:
+			WriteHTTPError(w, r, NewHTTPError(
+				http.StatusBadRequest,
+				fmt.Sprintf("unsupported date format %q, expected %s or %s", format, monthDateFormat, isoDateFormat),
+				nil))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(dateLayoutToCtx(r.Context(), layout)))
+	})
+}
+
+func acceptedDateFormat(accept []string) string {
+	for _, header := range accept {
+		for mediaRange := range strings.SplitSeq(header, ",") {
+			_, params, err := mime.ParseMediaType(mediaRange)
+			if err != nil {
+				continue
+			}
+			if format, ok := params[dateFormatAcceptParam]; ok {
+				return format
+			}
+		}
+	}
+	return ""
+}
+
+// acceptsMediaType reports whether mediaType is listed in the Accept header,
+// wildcards don't count.
+func acceptsMediaType(accept []string, mediaType string) bool {
+	for _, header := range accept {
+		for mediaRange := range strings.SplitSeq(header, ",") {
+			mt, _, err := mime.ParseMediaType(mediaRange)
+			if err == nil && mt == mediaType {
+				return true
+			}
+		}
+	}
+	return false
+}
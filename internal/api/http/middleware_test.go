@@ -0,0 +1,102 @@
+package http
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddlewares_DateFormatMW(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name       string
+		target     string
+		accept     string
+		wantCode   int
+		wantLayout DateLayout
+	}{
+		{
+			name:       "Default",
+			target:     "/subscriptions",
+			wantCode:   http.StatusOK,
+			wantLayout: dateLayout,
+		},
+		{
+			name:       "Query flag",
+			target:     "/subscriptions?date_format=iso-8601",
+			wantCode:   http.StatusOK,
+			wantLayout: isoDateLayout,
+		},
+		{
+			name:       "Accept header",
+			target:     "/subscriptions",
+			accept:     "text/html, application/json; date-format=iso-8601",
+			wantCode:   http.StatusOK,
+			wantLayout: isoDateLayout,
+		},
+		{
+			name:       "Query flag wins over Accept header",
+			target:     "/subscriptions?date_format=month",
+			accept:     "application/json; date-format=iso-8601",
+			wantCode:   http.StatusOK,
+			wantLayout: dateLayout,
+		},
+		{
+			name:     "Unknown format",
+			target:   "/subscriptions?date_format=unix",
+			wantCode: http.StatusBadRequest,
+		},
+	}
+
+	mws := NewMiddlewaresProvider(slog.New(slog.DiscardHandler), nil, nil, TenantResolver{})
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var gotLayout DateLayout
+			next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotLayout = dateLayoutFromCtx(r.Context())
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tc.target, nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			rr := httptest.NewRecorder()
+
+			mws.DateFormatMW(next).ServeHTTP(rr, req)
+
+			assert.Equal(t, tc.wantCode, rr.Code)
+			assert.Equal(t, tc.wantLayout, gotLayout)
+		})
+	}
+}
+
+func Test_acceptsMediaType(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		accept []string
+		want   bool
+	}{
+		{name: "No header", want: false},
+		{name: "Listed with parameters", accept: []string{"application/json, application/vnd.subscriptions.v2+json; date-format=iso-8601"}, want: true},
+		{name: "Second header", accept: []string{"application/json", "application/vnd.subscriptions.v2+json"}, want: true},
+		{name: "Wildcard", accept: []string{"*/*"}, want: false},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.want, acceptsMediaType(tc.accept, subscriptionPageMediaType))
+		})
+	}
+}
@@ -0,0 +1,47 @@
+package http
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+)
+
+// pageLinks returns the RFC 8288 Link header value for page of the list at u.
+// Pages after a cursor can't be counted back, so they only link forward.
+func pageLinks(u *url.URL, page *domain.SubscriptionPage) string {
+	var links []string
+	link := func(rel string, set func(q url.Values)) {
+		q := u.Query()
+		q.Del("cursor")
+		q.Del("page")
+		set(q)
+		target := url.URL{Path: u.Path, RawQuery: q.Encode()}
+		links = append(links, fmt.Sprintf("<%s>; rel=%q", target.String(), rel))
+	}
+	toPage := func(n int) func(q url.Values) {
+		return func(q url.Values) { q.Set("page", strconv.Itoa(n)) }
+	}
+
+	if page.Page == 0 {
+		link("first", func(url.Values) {})
+		if page.NextCursor != nil {
+			link("next", func(q url.Values) { q.Set("cursor", encodeCursor(*page.NextCursor)) })
+		}
+		return strings.Join(links, ", ")
+	}
+
+	last := max(1, (page.Total+page.PageSize-1)/page.PageSize)
+	link("first", toPage(1))
+	if page.Page > 1 {
+		link("prev", toPage(min(page.Page-1, last)))
+	}
+	if page.Page < last {
+		link("next", toPage(page.Page+1))
+	}
+	link("last", toPage(last))
+
+	return strings.Join(links, ", ")
+}
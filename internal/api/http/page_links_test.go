@@ -0,0 +1,76 @@
+package http
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_pageLinks(t *testing.T) {
+	t.Parallel()
+
+	cursor := domain.Cursor{BillingPeriod: domain.BillingMonthly, ID: uuid.New()}
+
+	tests := []struct {
+		name  string
+		url   string
+		page  domain.SubscriptionPage
+		links string
+	}{
+		{
+			name: "Middle page keeps the filters",
+			url:  "/api/v1/subscriptions?page=2&page_size=10&service_name=Okko",
+			page: domain.SubscriptionPage{Total: 45, Page: 2, PageSize: 10, NextCursor: &cursor},
+			links: `</api/v1/subscriptions?page=1&page_size=10&service_name=Okko>; rel="first", ` +
+				`</api/v1/subscriptions?page=1&page_size=10&service_name=Okko>; rel="prev", ` +
+				`</api/v1/subscriptions?page=3&page_size=10&service_name=Okko>; rel="next", ` +
+				`</api/v1/subscriptions?page=5&page_size=10&service_name=Okko>; rel="last"`,
+		},
+		{
+			name:  "First page by default",
+			url:   "/api/v1/subscriptions",
+			page:  domain.SubscriptionPage{Total: 10, Page: 1, PageSize: 10},
+			links: `</api/v1/subscriptions?page=1>; rel="first", </api/v1/subscriptions?page=1>; rel="last"`,
+		},
+		{
+			name:  "Empty list",
+			url:   "/api/v1/subscriptions",
+			page:  domain.SubscriptionPage{Page: 1, PageSize: 10},
+			links: `</api/v1/subscriptions?page=1>; rel="first", </api/v1/subscriptions?page=1>; rel="last"`,
+		},
+		{
+			name: "Past the last page",
+			url:  "/api/v1/subscriptions?page=9",
+			page: domain.SubscriptionPage{Total: 15, Page: 9, PageSize: 10},
+			links: `</api/v1/subscriptions?page=1>; rel="first", </api/v1/subscriptions?page=2>; rel="prev", ` +
+				`</api/v1/subscriptions?page=2>; rel="last"`,
+		},
+		{
+			name: "After a cursor",
+			url:  "/api/v1/subscriptions?cursor=abc&sort=service_name",
+			page: domain.SubscriptionPage{Total: 45, PageSize: 10, NextCursor: &cursor},
+			links: `</api/v1/subscriptions?sort=service_name>; rel="first", ` +
+				`</api/v1/subscriptions?cursor=` + encodeCursor(cursor) + `&sort=service_name>; rel="next"`,
+		},
+		{
+			name:  "Last page after a cursor",
+			url:   "/api/v1/subscriptions?cursor=abc",
+			page:  domain.SubscriptionPage{Total: 45, PageSize: 10},
+			links: `</api/v1/subscriptions>; rel="first"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			u, err := url.Parse(tt.url)
+			require.NoError(t, err)
+			assert.Equal(t, tt.links, pageLinks(u, &tt.page))
+		})
+	}
+}
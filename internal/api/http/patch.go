@@ -0,0 +1,65 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+
+	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/pkg/jsonpatch"
+)
+
+const (
+	mergePatchMediaType = "application/merge-patch+json"
+	jsonPatchMediaType  = "application/json-patch+json"
+)
+
+type patchFunc func(doc, patch []byte) ([]byte, error)
+
+// patchFuncOf returns the patch format of a request body with contentType.
+func patchFuncOf(contentType string) (patchFunc, error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case mergePatchMediaType:
+		return jsonpatch.MergePatch, nil
+	case jsonPatchMediaType:
+		return jsonpatch.Apply, nil
+	default:
+		return nil, &DTOValidationError{ClientMessage: unsupportedPatchMsg, StatusCode: http.StatusUnsupportedMediaType}
+	}
+}
+
+// patchSubscription applies patch to current as a ReplaceSubscription document
+// and validates the result like a PUT body.
+func patchSubscription(current domain.Subscription, patch []byte, apply patchFunc) (domain.SubscriptionUpdate, error) {
+	doc, err := json.Marshal(toReplaceSubscriptionDTO(&current))
+	if err != nil {
+		return domain.SubscriptionUpdate{}, err
+	}
+
+	patched, err := apply(doc, patch)
+	if err != nil {
+		switch {
+		case errors.Is(err, jsonpatch.ErrInvalidPatch):
+			return domain.SubscriptionUpdate{}, &DTOValidationError{ClientMessage: invalidPatchMsg, InternalError: err, StatusCode: http.StatusBadRequest}
+		case errors.Is(err, jsonpatch.ErrTestFailed):
+			return domain.SubscriptionUpdate{}, &DTOValidationError{ClientMessage: err.Error(), StatusCode: http.StatusConflict}
+		default:
+			return domain.SubscriptionUpdate{}, &DTOValidationError{ClientMessage: err.Error()}
+		}
+	}
+
+	var d dto.ReplaceSubscription
+	if err := decodeJSON(bytes.NewReader(patched), &d); err != nil {
+		return domain.SubscriptionUpdate{}, &DTOValidationError{ClientMessage: err.Error()}
+	}
+
+	update, err := fromReplaceSubscriptionDTO(&d)
+	if err != nil {
+		return domain.SubscriptionUpdate{}, err
+	}
+	return *update, nil
+}
@@ -17,8 +17,28 @@ const maxRequestBodyBytes = 1_048_576
 
 func ReadJSON[T any](w http.ResponseWriter, r *http.Request, dst T) error {
 	r.Body = http.MaxBytesReader(w, r.Body, int64(maxRequestBodyBytes))
+	return decodeJSON(r.Body, dst)
+}
+
+// ReadBody reads the whole request body, up to the same limit as ReadJSON.
+func ReadBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, int64(maxRequestBodyBytes)))
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			return nil, fmt.Errorf("body must not be larger than %d bytes", maxBytesError.Limit)
+		}
+		return nil, err
+	}
+	if len(body) == 0 {
+		return nil, errors.New("body must not be empty")
+	}
+	return body, nil
+}
 
-	dec := json.NewDecoder(r.Body)
+// decodeJSON decodes the single JSON value of r into dst, rejecting unknown fields.
+func decodeJSON[T any](r io.Reader, dst T) error {
+	dec := json.NewDecoder(r)
 	dec.DisallowUnknownFields()
 
 	if err := dec.Decode(dst); err != nil {
@@ -61,6 +81,14 @@ func ReadJSON[T any](w http.ResponseWriter, r *http.Request, dst T) error {
 	return nil
 }
 
+// ReadOptionalJSON is ReadJSON for bodies that can be left out, dst is kept as is then.
+func ReadOptionalJSON[T any](w http.ResponseWriter, r *http.Request, dst T) error {
+	if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 {
+		return nil
+	}
+	return ReadJSON(w, r, dst)
+}
+
 func SubIdParam(r *http.Request) (*uuid.UUID, error) {
 	strId := chi.URLParam(r, "id")
 	if err := uuid.Validate(strId); err != nil {
@@ -32,6 +32,11 @@ func (w *customResponseWriter) Write(b []byte) (int, error) {
 	return w.ResponseWriter.Write(b)
 }
 
+// Unwrap lets http.ResponseController reach the Flusher of the server.
+func (w *customResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
 func WriteHTTPError(w http.ResponseWriter, r *http.Request, e *HttpError) {
 	l := log.FromCtx(r.Context())
 	if e.DTOErr.Code >= 500 {
@@ -69,7 +74,9 @@ func WriteJSON[T any](w http.ResponseWriter, data T, status int, headers http.He
 	//
 
 	maps.Copy(w.Header(), headers)
-	w.Header().Set("Content-Type", "application/json")
+	if w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "application/json")
+	}
 	w.WriteHeader(status)
 
 	if _, err = w.Write(b); err != nil {
@@ -0,0 +1,215 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+)
+
+// operationScopes is the scope API keys need for each operationId. Keys are
+// refused operations missing here.
+var operationScopes = map[string]domain.Scope{
+	"listSubscriptions":       domain.ScopeRead,
+	"getSubscriptionById":     domain.ScopeRead,
+	"exportSubscriptions":     domain.ScopeRead,
+	"getTotalCost":            domain.ScopeRead,
+	"getCostBreakdown":        domain.ScopeRead,
+	"suggestServices":         domain.ScopeRead,
+	"listSubscriptionPrices":  domain.ScopeRead,
+	"listSubscriptionPauses":  domain.ScopeRead,
+	"listSubscriptionHistory": domain.ScopeRead,
+
+	"createSubscription":  domain.ScopeWrite,
+	"updateSubscription":  domain.ScopeWrite,
+	"patchSubscription":   domain.ScopeWrite,
+	"deleteSubscription":  domain.ScopeWrite,
+	"batchSubscriptions":  domain.ScopeWrite,
+	"importSubscriptions": domain.ScopeWrite,
+	"pauseSubscription":   domain.ScopeWrite,
+	"resumeSubscription":  domain.ScopeWrite,
+
+	"listCurrencyRates":  domain.ScopeAdmin,
+	"setCurrencyRate":    domain.ScopeAdmin,
+	"deleteCurrencyRate": domain.ScopeAdmin,
+	"listApiKeys":        domain.ScopeAdmin,
+	"createApiKey":       domain.ScopeAdmin,
+	"revokeApiKey":       domain.ScopeAdmin,
+	"listAudit":          domain.ScopeAdmin,
+}
+
+// scopedServer refuses operations to API keys without their scope. It doesn't
+// embed the server, so new operations don't compile until they get a scope.
+type scopedServer struct {
+	next dto.ServerInterface
+}
+
+// WithScopes enforces operationScopes on the operations of next.
+func WithScopes(next dto.ServerInterface) dto.ServerInterface {
+	return &scopedServer{next: next}
+}
+
+// allow writes the error and returns false unless the caller may call
+// operationID.
+func (s *scopedServer) allow(w http.ResponseWriter, r *http.Request, operationID string) bool {
+	p, err := principalFromCtx(r.Context())
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return false
+	}
+	scope := operationScopes[operationID]
+	if !p.Allows(scope) {
+		WriteHTTPError(w, r, processAppError(&DTOValidationError{
+			ClientMessage: fmt.Sprintf(missingScopeMsg, scope),
+			StatusCode:    http.StatusForbidden,
+		}))
+		return false
+	}
+	return true
+}
+
+func (s *scopedServer) ListApiKeys(w http.ResponseWriter, r *http.Request) {
+	if s.allow(w, r, "listApiKeys") {
+		s.next.ListApiKeys(w, r)
+	}
+}
+
+func (s *scopedServer) CreateApiKey(w http.ResponseWriter, r *http.Request) {
+	if s.allow(w, r, "createApiKey") {
+		s.next.CreateApiKey(w, r)
+	}
+}
+
+func (s *scopedServer) RevokeApiKey(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	if s.allow(w, r, "revokeApiKey") {
+		s.next.RevokeApiKey(w, r, id)
+	}
+}
+
+func (s *scopedServer) ListAudit(w http.ResponseWriter, r *http.Request, params dto.ListAuditParams) {
+	if s.allow(w, r, "listAudit") {
+		s.next.ListAudit(w, r, params)
+	}
+}
+
+func (s *scopedServer) ListCurrencyRates(w http.ResponseWriter, r *http.Request) {
+	if s.allow(w, r, "listCurrencyRates") {
+		s.next.ListCurrencyRates(w, r)
+	}
+}
+
+func (s *scopedServer) DeleteCurrencyRate(w http.ResponseWriter, r *http.Request, from string, to string) {
+	if s.allow(w, r, "deleteCurrencyRate") {
+		s.next.DeleteCurrencyRate(w, r, from, to)
+	}
+}
+
+func (s *scopedServer) SetCurrencyRate(w http.ResponseWriter, r *http.Request, from string, to string) {
+	if s.allow(w, r, "setCurrencyRate") {
+		s.next.SetCurrencyRate(w, r, from, to)
+	}
+}
+
+func (s *scopedServer) SuggestServices(w http.ResponseWriter, r *http.Request, params dto.SuggestServicesParams) {
+	if s.allow(w, r, "suggestServices") {
+		s.next.SuggestServices(w, r, params)
+	}
+}
+
+func (s *scopedServer) ListSubscriptions(w http.ResponseWriter, r *http.Request, params dto.ListSubscriptionsParams) {
+	if s.allow(w, r, "listSubscriptions") {
+		s.next.ListSubscriptions(w, r, params)
+	}
+}
+
+func (s *scopedServer) CreateSubscription(w http.ResponseWriter, r *http.Request, params dto.CreateSubscriptionParams) {
+	if s.allow(w, r, "createSubscription") {
+		s.next.CreateSubscription(w, r, params)
+	}
+}
+
+func (s *scopedServer) GetCostBreakdown(w http.ResponseWriter, r *http.Request, params dto.GetCostBreakdownParams) {
+	if s.allow(w, r, "getCostBreakdown") {
+		s.next.GetCostBreakdown(w, r, params)
+	}
+}
+
+func (s *scopedServer) ExportSubscriptions(w http.ResponseWriter, r *http.Request, params dto.ExportSubscriptionsParams) {
+	if s.allow(w, r, "exportSubscriptions") {
+		s.next.ExportSubscriptions(w, r, params)
+	}
+}
+
+func (s *scopedServer) ImportSubscriptions(w http.ResponseWriter, r *http.Request, params dto.ImportSubscriptionsParams) {
+	if s.allow(w, r, "importSubscriptions") {
+		s.next.ImportSubscriptions(w, r, params)
+	}
+}
+
+func (s *scopedServer) GetTotalCost(w http.ResponseWriter, r *http.Request, params dto.GetTotalCostParams) {
+	if s.allow(w, r, "getTotalCost") {
+		s.next.GetTotalCost(w, r, params)
+	}
+}
+
+func (s *scopedServer) DeleteSubscription(w http.ResponseWriter, r *http.Request, id uuid.UUID, params dto.DeleteSubscriptionParams) {
+	if s.allow(w, r, "deleteSubscription") {
+		s.next.DeleteSubscription(w, r, id, params)
+	}
+}
+
+func (s *scopedServer) GetSubscriptionById(w http.ResponseWriter, r *http.Request, id uuid.UUID, params dto.GetSubscriptionByIdParams) {
+	if s.allow(w, r, "getSubscriptionById") {
+		s.next.GetSubscriptionById(w, r, id, params)
+	}
+}
+
+func (s *scopedServer) PatchSubscription(w http.ResponseWriter, r *http.Request, id uuid.UUID, params dto.PatchSubscriptionParams) {
+	if s.allow(w, r, "patchSubscription") {
+		s.next.PatchSubscription(w, r, id, params)
+	}
+}
+
+func (s *scopedServer) UpdateSubscription(w http.ResponseWriter, r *http.Request, id uuid.UUID, params dto.UpdateSubscriptionParams) {
+	if s.allow(w, r, "updateSubscription") {
+		s.next.UpdateSubscription(w, r, id, params)
+	}
+}
+
+func (s *scopedServer) PauseSubscription(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	if s.allow(w, r, "pauseSubscription") {
+		s.next.PauseSubscription(w, r, id)
+	}
+}
+
+func (s *scopedServer) ListSubscriptionPauses(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	if s.allow(w, r, "listSubscriptionPauses") {
+		s.next.ListSubscriptionPauses(w, r, id)
+	}
+}
+
+func (s *scopedServer) ListSubscriptionHistory(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	if s.allow(w, r, "listSubscriptionHistory") {
+		s.next.ListSubscriptionHistory(w, r, id)
+	}
+}
+
+func (s *scopedServer) ListSubscriptionPrices(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	if s.allow(w, r, "listSubscriptionPrices") {
+		s.next.ListSubscriptionPrices(w, r, id)
+	}
+}
+
+func (s *scopedServer) ResumeSubscription(w http.ResponseWriter, r *http.Request, id uuid.UUID) {
+	if s.allow(w, r, "resumeSubscription") {
+		s.next.ResumeSubscription(w, r, id)
+	}
+}
+
+func (s *scopedServer) BatchSubscriptions(w http.ResponseWriter, r *http.Request) {
+	if s.allow(w, r, "batchSubscriptions") {
+		s.next.BatchSubscriptions(w, r)
+	}
+}
@@ -0,0 +1,165 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestOperationScopes(t *testing.T) {
+	t.Parallel()
+
+	server := reflect.TypeFor[dto.ServerInterface]()
+	operations := make(map[string]bool, server.NumMethod())
+	for i := range server.NumMethod() {
+		name := server.Method(i).Name
+		r, size := utf8.DecodeRuneInString(name)
+		operationID := string(unicode.ToLower(r)) + name[size:]
+		operations[operationID] = true
+
+		scope, ok := operationScopes[operationID]
+		if assert.True(t, ok, "operation %s has no scope", operationID) {
+			assert.True(t, scope.IsValid(), "operation %s has invalid scope %q", operationID, scope)
+		}
+	}
+	for operationID := range operationScopes {
+		assert.True(t, operations[operationID], "scope of unknown operation %s", operationID)
+	}
+}
+
+func TestWithScopes(t *testing.T) {
+	t.Parallel()
+
+	keyCtx := func(scopes ...domain.Scope) context.Context {
+		id := uuid.New()
+		return domain.PrincipalToCtx(context.Background(), &domain.Principal{Role: domain.RoleService, APIKeyID: &id, Scopes: scopes})
+	}
+	subID := uuid.New()
+	sub := &domain.Subscription{
+		ID: subID, ServiceName: "Okko", Price: 300, BillingPeriod: domain.BillingMonthly, Currency: "RUB",
+		UserID: uuid.New(), Version: 1,
+	}
+
+	testCases := []struct {
+		name       string
+		ctx        context.Context
+		method     string
+		url        string
+		setupMocks func(th testHarness)
+		wantCode   int
+		wantMsg    string
+	}{
+		{
+			name:   "Read scope reads subscriptions of any user",
+			ctx:    keyCtx(domain.ScopeRead),
+			method: http.MethodGet,
+			url:    "/subscriptions/" + subID.String(),
+			setupMocks: func(th testHarness) {
+				th.service.On("GetByID", mock.Anything, subID).Return(sub, nil).Once()
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Read scope cannot delete",
+			ctx:      keyCtx(domain.ScopeRead),
+			method:   http.MethodDelete,
+			url:      "/subscriptions/" + subID.String(),
+			wantCode: http.StatusForbidden,
+			wantMsg:  "API key lacks the write scope",
+		},
+		{
+			name:   "Write scope deletes without a lookup",
+			ctx:    keyCtx(domain.ScopeWrite),
+			method: http.MethodDelete,
+			url:    "/subscriptions/" + subID.String(),
+			setupMocks: func(th testHarness) {
+				th.service.On("Delete", mock.Anything, subID, (*int)(nil)).Return(nil).Once()
+			},
+			wantCode: http.StatusNoContent,
+		},
+		{
+			name:     "Write scope cannot read",
+			ctx:      keyCtx(domain.ScopeWrite),
+			method:   http.MethodGet,
+			url:      "/subscriptions",
+			wantCode: http.StatusForbidden,
+			wantMsg:  "API key lacks the read scope",
+		},
+		{
+			name:     "Admin scope alone cannot read",
+			ctx:      keyCtx(domain.ScopeAdmin),
+			method:   http.MethodGet,
+			url:      "/subscriptions",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:   "Admin scope manages currency rates",
+			ctx:    keyCtx(domain.ScopeAdmin),
+			method: http.MethodGet,
+			url:    "/admin/currency_rates",
+			setupMocks: func(th testHarness) {
+				th.service.On("ListCurrencyRates", mock.Anything).Return([]domain.CurrencyRate{}, nil).Once()
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Read scope cannot manage API keys",
+			ctx:      keyCtx(domain.ScopeRead, domain.ScopeWrite),
+			method:   http.MethodGet,
+			url:      "/admin/api_keys",
+			wantCode: http.StatusForbidden,
+			wantMsg:  "API key lacks the admin scope",
+		},
+		{
+			name:   "Users are not limited by scopes",
+			ctx:    domain.PrincipalToCtx(context.Background(), &domain.Principal{UserID: sub.UserID, Role: domain.RoleEditor}),
+			method: http.MethodGet,
+			url:    "/subscriptions/" + subID.String(),
+			setupMocks: func(th testHarness) {
+				th.service.On("GetByID", mock.Anything, subID).Return(sub, nil).Once()
+			},
+			wantCode: http.StatusOK,
+		},
+		{
+			name:     "Unauthenticated",
+			ctx:      context.Background(),
+			method:   http.MethodGet,
+			url:      "/subscriptions",
+			wantCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			th := setup(t)
+			if tc.setupMocks != nil {
+				tc.setupMocks(th)
+			}
+
+			req := httptest.NewRequest(tc.method, tc.url, strings.NewReader(""))
+			rr := httptest.NewRecorder()
+
+			router := dto.HandlerFromMux(WithScopes(th.h), chi.NewRouter())
+			router.ServeHTTP(rr, req.WithContext(tc.ctx))
+
+			assert.Equal(t, tc.wantCode, rr.Code, rr.Body.String())
+			if tc.wantMsg != "" {
+				assert.Contains(t, rr.Body.String(), tc.wantMsg)
+			}
+		})
+	}
+}
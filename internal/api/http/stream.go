@@ -0,0 +1,184 @@
+package http
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"iter"
+	"net/http"
+	"time"
+
+	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/pkg/log"
+)
+
+const ndjsonMediaType = "application/x-ndjson"
+
+func (h *handler) ExportSubscriptions(w http.ResponseWriter, r *http.Request, params dto.ExportSubscriptionsParams) {
+	format, err := validateExportFormat(params.Format, r.Header.Values("Accept"))
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	filter, err := toExportFilter(params)
+	if err != nil {
+		WriteHTTPError(w, r, processAppError(err))
+		return
+	}
+
+	w.Header().Set("Vary", "Accept")
+	var rw rowWriter = &csvRowWriter{}
+	if format == dto.Ndjson {
+		rw = &ndjsonRowWriter{layout: dateLayoutFromCtx(r.Context())}
+	}
+	streamSubscriptions(w, r, h.service.Export(r.Context(), filter), rw, h.cfg.WriteTimeout)
+}
+
+// validateExportFormat returns format, or the one accept asks for when it is unset.
+func validateExportFormat(format *dto.ExportFormat, accept []string) (dto.ExportFormat, error) {
+	if format == nil {
+		if acceptsMediaType(accept, ndjsonMediaType) {
+			return dto.Ndjson, nil
+		}
+		return dto.Csv, nil
+	}
+	switch *format {
+	case dto.Csv, dto.Ndjson:
+		return *format, nil
+	default:
+		return "", &DTOValidationError{ClientMessage: invalidExportFormatMsg}
+	}
+}
+
+// rowWriter writes subscriptions one at a time in a streamed format.
+type rowWriter interface {
+	// start sets the headers and writes what comes before the first row.
+	start(w http.ResponseWriter) error
+	write(sub *domain.Subscription) error
+	// flush sends the rows written so far.
+	flush() error
+}
+
+// streamSubscriptions writes subs with rw as they are yielded. The response
+// starts with the first one, so errors before it still get an error response.
+// Each row gets writeTimeout of its own, streams may outlast the one of the server.
+func streamSubscriptions(
+	w http.ResponseWriter,
+	r *http.Request,
+	subs iter.Seq2[domain.Subscription, error],
+	rw rowWriter,
+	writeTimeout time.Duration,
+) {
+	rc := http.NewResponseController(w)
+	started := false
+	for sub, err := range subs {
+		if err != nil {
+			if !started {
+				WriteHTTPError(w, r, processAppError(err))
+				return
+			}
+			// The status is sent already, aborting the response is the only way to
+			// tell the client it is incomplete.
+			log.FromCtx(r.Context()).Error("streaming subscriptions failed", log.WithErr(err))
+			panic(http.ErrAbortHandler)
+		}
+		if !started {
+			started = true
+			if err := rw.start(w); err != nil {
+				log.FromCtx(r.Context()).Info("failed to stream subscriptions", log.WithErr(err))
+				return
+			}
+		}
+		if err := extendWriteDeadline(rc, writeTimeout); err != nil {
+			log.FromCtx(r.Context()).Info("failed to stream subscriptions", log.WithErr(err))
+			return
+		}
+		if err := rw.write(&sub); err != nil {
+			log.FromCtx(r.Context()).Info("failed to stream subscriptions", log.WithErr(err))
+			return
+		}
+	}
+
+	if !started {
+		if err := rw.start(w); err != nil {
+			log.FromCtx(r.Context()).Info("failed to stream subscriptions", log.WithErr(err))
+			return
+		}
+	}
+	if err := rw.flush(); err != nil {
+		log.FromCtx(r.Context()).Info("failed to stream subscriptions", log.WithErr(err))
+	}
+}
+
+// extendWriteDeadline gives the writes from now on timeout, unless it is unset or
+// the writer has no deadlines.
+func extendWriteDeadline(rc *http.ResponseController, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+	err := rc.SetWriteDeadline(time.Now().Add(timeout))
+	if errors.Is(err, http.ErrNotSupported) {
+		return nil
+	}
+	return err
+}
+
+// csvRowWriter writes a header row and then a row of csvColumns per subscription.
+type csvRowWriter struct {
+	cw *csv.Writer
+}
+
+func (c *csvRowWriter) start(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", csvMediaType+"; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="subscriptions.csv"`)
+	c.cw = csv.NewWriter(w)
+	return c.cw.Write(csvColumns)
+}
+
+func (c *csvRowWriter) write(sub *domain.Subscription) error {
+	return c.cw.Write(toCSVRecord(sub))
+}
+
+func (c *csvRowWriter) flush() error {
+	c.cw.Flush()
+	return c.cw.Error()
+}
+
+// ndjsonRowWriter writes a JSON object per line and flushes each one, so clients
+// get subscriptions as they are read.
+type ndjsonRowWriter struct {
+	layout DateLayout
+	enc    *json.Encoder
+	rc     *http.ResponseController
+}
+
+func (n *ndjsonRowWriter) start(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", ndjsonMediaType)
+	n.enc = json.NewEncoder(w)
+	n.rc = http.NewResponseController(w)
+	return nil
+}
+
+func (n *ndjsonRowWriter) write(sub *domain.Subscription) error {
+	if err := n.enc.Encode(toSubscriptionDTO(sub, n.layout)); err != nil {
+		return err
+	}
+	return n.flush()
+}
+
+func (n *ndjsonRowWriter) flush() error {
+	return n.rc.Flush()
+}
+
+// subsSeq yields subs, which are read already, without errors.
+func subsSeq(subs []domain.Subscription) iter.Seq2[domain.Subscription, error] {
+	return func(yield func(domain.Subscription, error) bool) {
+		for _, sub := range subs {
+			if !yield(sub, nil) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,261 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"iter"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// streamOf yields subs and then err, if set.
+func streamOf(subs []domain.Subscription, err error) iter.Seq2[domain.Subscription, error] {
+	return func(yield func(domain.Subscription, error) bool) {
+		for _, sub := range subs {
+			if !yield(sub, nil) {
+				return
+			}
+		}
+		if err != nil {
+			yield(domain.Subscription{}, err)
+		}
+	}
+}
+
+func TestHandler_ExportSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	ctx := adminCtx()
+	subID := uuid.MustParse("0199a2c4-7f1e-7c3a-9d2b-5e8f4a6b1c2d")
+	userID := uuid.MustParse("3fa85f64-5717-4562-b3fc-2c963f66afa6")
+	endDate := time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC)
+	subs := []domain.Subscription{
+		{
+			ID: subID, ServiceName: "Yandex Plus", Price: 1200, BillingPeriod: domain.BillingQuarterly, Currency: "RUB",
+			UserID: userID, StartDate: time.Date(2025, 11, 15, 0, 0, 0, 0, time.UTC), EndDate: &endDate, Version: 3,
+		},
+		{
+			ID: subID, ServiceName: `Okko, "Premium"`, Price: 300, BillingPeriod: domain.BillingMonthly, Currency: "RUB",
+			UserID: userID, StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Version: 1,
+		},
+	}
+	serviceErr := subservice.WrapErr("subservice.Export", subservice.KindUnknown, errors.New("db is down"))
+
+	testCases := []struct {
+		name       string
+		url        string
+		accept     string
+		setupMocks func(th testHarness)
+		assertFunc func(t *testing.T, rr *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Success",
+			url:  "/subscriptions/export?user_id=" + userID.String() + "&sort=start_date:desc",
+			setupMocks: func(th testHarness) {
+				th.service.On("Export", mock.Anything, domain.SubscriptionFilter{
+					UserID: &userID,
+					Sort:   []domain.SortKey{{Field: domain.SortStartDate, Desc: true}},
+				}).Return(streamOf(subs, nil)).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+				assert.Equal(t, `attachment; filename="subscriptions.csv"`, rr.Header().Get("Content-Disposition"))
+				assert.Equal(t,
+					"id,service_name,price,billing_period,monthly_cost,currency,user_id,start_date,end_date,trial_end,version\n"+
+						"0199a2c4-7f1e-7c3a-9d2b-5e8f4a6b1c2d,Yandex Plus,1200,quarterly,400,RUB,3fa85f64-5717-4562-b3fc-2c963f66afa6,2025-11-15,2026-10-31,,3\n"+
+						`0199a2c4-7f1e-7c3a-9d2b-5e8f4a6b1c2d,"Okko, ""Premium""",300,monthly,300,RUB,3fa85f64-5717-4562-b3fc-2c963f66afa6,2025-01-01,,,1`+"\n",
+					rr.Body.String())
+			},
+		},
+		{
+			name: "Formulas",
+			url:  "/subscriptions/export",
+			setupMocks: func(th testHarness) {
+				formulas := make([]domain.Subscription, 0, 6)
+				for _, name := range []string{"=1+1", "+7 TV", "-HYPERLINK()", "@SUM(A1)", "\tTab", "\rReturn"} {
+					sub := subs[1]
+					sub.ServiceName = name
+					formulas = append(formulas, sub)
+				}
+				th.service.On("Export", mock.Anything, domain.SubscriptionFilter{}).Return(streamOf(formulas, nil)).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rr.Code)
+				var names []string
+				for _, line := range strings.Split(strings.TrimSuffix(rr.Body.String(), "\n"), "\n")[1:] {
+					names = append(names, strings.Split(line, ",")[1])
+				}
+				assert.Equal(t, []string{`'=1+1`, `'+7 TV`, `'-HYPERLINK()`, `'@SUM(A1)`, "'\tTab", "\"'\rReturn\""}, names)
+			},
+		},
+		{
+			name: "NDJSON",
+			url:  "/subscriptions/export?format=ndjson",
+			setupMocks: func(th testHarness) {
+				th.service.On("Export", mock.Anything, domain.SubscriptionFilter{}).Return(streamOf(subs, nil)).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, ndjsonMediaType, rr.Header().Get("Content-Type"))
+				assert.Empty(t, rr.Header().Get("Content-Disposition"))
+				lines := strings.Split(strings.TrimSuffix(rr.Body.String(), "\n"), "\n")
+				require.Len(t, lines, 2)
+				var first dto.Subscription
+				require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+				assert.Equal(t, subID, first.Id)
+				assert.Equal(t, "11-2025", first.StartDate)
+				assert.Equal(t, 400, first.MonthlyCost)
+			},
+		},
+		{
+			name:   "NDJSON From Accept",
+			url:    "/subscriptions/export",
+			accept: "application/x-ndjson, text/csv;q=0.5",
+			setupMocks: func(th testHarness) {
+				th.service.On("Export", mock.Anything, domain.SubscriptionFilter{}).Return(streamOf(subs[1:], nil)).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, ndjsonMediaType, rr.Header().Get("Content-Type"))
+				assert.Equal(t, "Accept", rr.Header().Get("Vary"))
+				var sub dto.Subscription
+				require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &sub))
+				assert.Equal(t, `Okko, "Premium"`, sub.ServiceName)
+			},
+		},
+		{
+			name:   "Format Overrides Accept",
+			url:    "/subscriptions/export?format=csv",
+			accept: ndjsonMediaType,
+			setupMocks: func(th testHarness) {
+				th.service.On("Export", mock.Anything, domain.SubscriptionFilter{}).Return(streamOf(nil, nil)).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, "text/csv; charset=utf-8", rr.Header().Get("Content-Type"))
+			},
+		},
+		{
+			name: "No Subscriptions",
+			url:  "/subscriptions/export?format=csv",
+			setupMocks: func(th testHarness) {
+				th.service.On("Export", mock.Anything, domain.SubscriptionFilter{}).Return(streamOf(nil, nil)).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusOK, rr.Code)
+				assert.Equal(t, strings.Join(csvColumns, ",")+"\n", rr.Body.String())
+			},
+		},
+		{
+			name: "Service Error",
+			url:  "/subscriptions/export",
+			setupMocks: func(th testHarness) {
+				th.service.On("Export", mock.Anything, domain.SubscriptionFilter{}).Return(streamOf(nil, serviceErr)).Once()
+			},
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusInternalServerError, rr.Code)
+				assert.Equal(t, "application/json", rr.Header().Get("Content-Type"))
+			},
+		},
+		{
+			name: "Invalid Format",
+			url:  "/subscriptions/export?format=xlsx",
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+				assert.Contains(t, rr.Body.String(), invalidExportFormatMsg)
+			},
+		},
+		{
+			name: "Invalid Filter",
+			url:  "/subscriptions/export?min_cost=10&max_cost=5",
+			assertFunc: func(t *testing.T, rr *httptest.ResponseRecorder) {
+				assert.Equal(t, http.StatusUnprocessableEntity, rr.Code)
+				assert.Contains(t, rr.Body.String(), invalidCostRangeMsg)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			th := setup(t)
+			if tc.setupMocks != nil {
+				tc.setupMocks(th)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, tc.url, nil)
+			if tc.accept != "" {
+				req.Header.Set("Accept", tc.accept)
+			}
+			rr := httptest.NewRecorder()
+
+			router := dto.HandlerFromMux(th.h, chi.NewRouter())
+			router.ServeHTTP(rr, req.WithContext(ctx))
+
+			tc.assertFunc(t, rr)
+		})
+	}
+
+	t.Run("Service Error After First Row", func(t *testing.T) {
+		t.Parallel()
+
+		th := setup(t)
+		th.service.On("Export", mock.Anything, domain.SubscriptionFilter{}).Return(streamOf(subs[:1], serviceErr)).Once()
+
+		req := httptest.NewRequest(http.MethodGet, "/subscriptions/export", nil)
+		rr := httptest.NewRecorder()
+
+		assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+			th.h.ExportSubscriptions(rr, req.WithContext(ctx), dto.ExportSubscriptionsParams{})
+		})
+	})
+}
+
+func TestStreamSubscriptions_OutlastsWriteTimeout(t *testing.T) {
+	t.Parallel()
+
+	const rows = 5
+	writeTimeout := 100 * time.Millisecond
+	slow := func(yield func(domain.Subscription, error) bool) {
+		for range rows {
+			time.Sleep(writeTimeout / 2)
+			if !yield(domain.Subscription{ID: uuid.New()}, nil) {
+				return
+			}
+		}
+	}
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		streamSubscriptions(w, r, slow, &ndjsonRowWriter{layout: dateLayout}, writeTimeout)
+	}))
+	srv.Config.WriteTimeout = writeTimeout
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	resp, err := srv.Client().Get(srv.URL)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	dec := json.NewDecoder(resp.Body)
+	received := 0
+	for dec.More() {
+		var sub dto.Subscription
+		require.NoError(t, dec.Decode(&sub))
+		received++
+	}
+	assert.Equal(t, rows, received)
+}
@@ -1,28 +1,85 @@
 package http
 
 import (
-	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
 )
 
 var (
 	beginningOfTime = time.Time{}
 	endOfTime       = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+	currencyCodeRe = regexp.MustCompile(`^[A-Z]{3}$`)
 )
 
+// maxAPIKeyNameLen is the size of the name column.
+const maxAPIKeyNameLen = 255
+
 const (
 	startDateAfterEndDateMsg = "start_date cannot be after end_date"
 	invalidDateMsg           = "invalid date format, expected MM-YYYY"
+	invalidSubDateMsg        = "invalid date format, expected MM-YYYY or YYYY-MM-DD"
+	startAfterEndMsg         = "start cannot be after end"
+	invalidCurrencyMsg       = "invalid currency, expected ISO-4217 code like RUB"
+	invalidRateMsg           = "rate must be positive"
+	priceRequiredMsg         = "price is required"
+	priceConflictMsg         = "price and monthly_cost cannot be used together"
+	monthlyCostPeriodMsg     = "monthly_cost can only be used with monthly billing_period"
+	invalidBillingPeriodMsg  = "invalid billing_period, expected one of weekly, monthly, quarterly, yearly"
+	invalidCostModeMsg       = "invalid mode, expected billed or prorated"
+	effectiveFromNoPriceMsg  = "price_effective_from can only be used with price"
+	trialConflictMsg         = "trial_months and trial_end cannot be used together"
+	invalidTrialMonthsMsg    = "trial_months must be positive"
+	trialEndBeforeStartMsg   = "trial_end cannot be before start_date"
+	invalidCursorMsg         = "invalid cursor"
+	cursorConflictMsg        = "cursor and page cannot be used together"
+	invalidSortMsg           = "invalid sort, expected created_at, start_date, monthly_cost or service_name with optional :asc or :desc"
+	duplicateSortMsg         = "sort keys cannot repeat"
+	negativeCostMsg          = "min_cost and max_cost cannot be negative"
+	invalidCostRangeMsg      = "min_cost cannot be greater than max_cost"
+	blankQueryMsg            = "q cannot be blank"
+	unsupportedPatchMsg      = "unsupported Content-Type, expected application/merge-patch+json or application/json-patch+json"
+	invalidPatchMsg          = "invalid patch document"
+	invalidIfMatchMsg        = "invalid If-Match, expected * or a single entity tag"
+	preconditionFailedMsg    = "subscription has changed, fetch it again for its current ETag"
+	invalidIdempotencyKeyMsg = "Idempotency-Key must be 1 to 255 characters long"
+	overlapMsg               = "subscription overlaps another subscription of the user to the service"
+	emptyBatchMsg            = "operations cannot be empty"
+	batchTooLargeMsg         = "batch cannot have more than %d operations"
+	invalidBatchModeMsg      = "invalid mode, expected atomic or best_effort"
+	invalidBatchOpMsg        = "invalid op, expected create, update or delete"
+	batchCreateMsg           = "create takes subscription"
+	batchUpdateMsg           = "update takes id and replacement"
+	batchDeleteMsg           = "delete takes id"
+	invalidExportFormatMsg   = "invalid format, expected csv or ndjson"
+	invalidAuditOpMsg        = "invalid operation, expected one of create, update, delete, pause, resume"
+	fromAfterToMsg           = "from cannot be after to"
+	unsupportedImportMsg     = "unsupported Content-Type, expected text/csv"
+	importTooLargeMsg        = "import cannot have more than %d rows"
+	unknownColumnMsg         = "unknown column %q"
+	repeatedColumnMsg        = "column %q is repeated"
+	missingColumnMsg         = "column %q is required"
+	csvIntegerMsg            = "%s must be an integer"
+	csvUUIDMsg               = "%s must be a UUID"
+	missingTokenMsg          = "authentication required, send a bearer token or an API key in the Authorization header"
+	invalidTokenMsg          = "invalid or expired bearer token"
+	forbiddenMsg             = "your role does not allow this operation"
+	invalidAPIKeyMsg         = "invalid or expired API key"
+	missingTenantMsg         = "tenant required, the bearer token names none"
+	invalidTenantMsg         = "invalid tenant, expected 1 to 63 lowercase letters, digits, - or _"
+	missingScopeMsg          = "API key lacks the %s scope"
+	apiKeyNameMsg            = "name must be 1 to 255 characters long"
+	apiKeyScopesMsg          = "scopes cannot be empty"
+	invalidScopeMsg          = "invalid scope, expected read, write or admin"
+	pastExpiryMsg            = "expires_at must be in the future"
 )
 
-type UpdateSubscriptionRequest struct {
-	ServiceName *string         `json:"service_name"`
-	MonthlyCost *int            `json:"monthly_cost"`
-	EndDate     json.RawMessage `json:"end_date"`
-}
-
 func validateGetTotalCostParams(params dto.GetTotalCostParams) (time.Time, time.Time, error) {
 	var start, end time.Time
 	var err error
@@ -61,21 +118,37 @@ func validateGetTotalCostParams(params dto.GetTotalCostParams) (time.Time, time.
 	return start, end, nil
 }
 
-func validateNewSubscription(d *dto.NewSubscription) (time.Time, *time.Time, error) {
-	startDate, err := dateLayout.parse(d.StartDate)
+func validateGetCostBreakdownParams(params dto.GetCostBreakdownParams) (time.Time, time.Time, error) {
+	start, err := dateLayout.parse(params.Start)
+	if err != nil {
+		return time.Time{}, time.Time{}, &DTOValidationError{ClientMessage: invalidDateMsg, InternalError: err}
+	}
+	end, err := dateLayout.parse(params.End)
 	if err != nil {
-		return time.Time{}, nil, &DTOValidationError{
-			ClientMessage: invalidDateMsg,
+		return time.Time{}, time.Time{}, &DTOValidationError{ClientMessage: invalidDateMsg, InternalError: err}
+	}
+
+	if start.After(end) {
+		return time.Time{}, time.Time{}, &DTOValidationError{ClientMessage: startAfterEndMsg}
+	}
+
+	return start, end, nil
+}
+
+func validateNewSubscription(d *dto.NewSubscription) (startDate time.Time, endDate, trialEnd *time.Time, err error) {
+	startDate, err = parseStartDate(d.StartDate)
+	if err != nil {
+		return time.Time{}, nil, nil, &DTOValidationError{
+			ClientMessage: invalidSubDateMsg,
 			InternalError: err,
 		}
 	}
 
-	var endDate *time.Time
 	if d.EndDate != nil && *d.EndDate != "" {
-		t, err := dateLayout.parse(*d.EndDate)
+		t, err := parseEndDate(*d.EndDate)
 		if err != nil {
-			return time.Time{}, nil, &DTOValidationError{
-				ClientMessage: invalidDateMsg,
+			return time.Time{}, nil, nil, &DTOValidationError{
+				ClientMessage: invalidSubDateMsg,
 				InternalError: err,
 			}
 		}
@@ -83,29 +156,244 @@ func validateNewSubscription(d *dto.NewSubscription) (time.Time, *time.Time, err
 	}
 
 	if endDate != nil && startDate.After(*endDate) {
-		return time.Time{}, nil, &DTOValidationError{ClientMessage: startDateAfterEndDateMsg}
+		return time.Time{}, nil, nil, &DTOValidationError{ClientMessage: startDateAfterEndDateMsg}
+	}
+
+	trialEnd, err = validateTrial(d.TrialMonths, d.TrialEnd, startDate)
+	if err != nil {
+		return time.Time{}, nil, nil, err
 	}
 
-	return startDate, endDate, nil
+	return startDate, endDate, trialEnd, nil
 }
 
-func validateUpdateSubscriptionRequest(req *UpdateSubscriptionRequest) (*time.Time, bool, error) {
-	if req.EndDate == nil {
-		return nil, false, nil
+// validateTrial resolves trial_months into the last day of the trial, the day
+// before the first billing date after it.
+func validateTrial(months *int, end *string, startDate time.Time) (*time.Time, error) {
+	if months != nil && end != nil {
+		return nil, &DTOValidationError{ClientMessage: trialConflictMsg}
 	}
 
-	if string(req.EndDate) == "null" {
-		return nil, true, nil
+	if months != nil {
+		if *months <= 0 {
+			return nil, &DTOValidationError{ClientMessage: invalidTrialMonthsMsg}
+		}
+		t := addBillingMonths(startDate, *months).AddDate(0, 0, -1)
+		return &t, nil
+	}
+
+	if end == nil {
+		return nil, nil
+	}
+
+	t, err := parseEndDate(*end)
+	if err != nil {
+		return nil, &DTOValidationError{ClientMessage: invalidSubDateMsg, InternalError: err}
 	}
+	if t.Before(startDate) {
+		return nil, &DTOValidationError{ClientMessage: trialEndBeforeStartMsg}
+	}
+	return &t, nil
+}
+
+// addBillingMonths moves date by months keeping its day, clamped to the length
+// of shorter months like monthly billing dates are.
+func addBillingMonths(date time.Time, months int) time.Time {
+	month := time.Date(date.Year(), date.Month()+time.Month(months), 1, 0, 0, 0, 0, time.UTC)
+	lastDay := month.AddDate(0, 1, -1).Day()
+	return month.AddDate(0, 0, min(date.Day(), lastDay)-1)
+}
+
+// validatePrice resolves the deprecated monthly_cost into a price with a monthly
+// billing period. Both results are nil when none of the fields are set.
+func validatePrice(price, monthlyCost *int, period *dto.BillingPeriod) (*int, *domain.BillingPeriod, error) {
+	var billingPeriod *domain.BillingPeriod
+	if period != nil {
+		bp := domain.BillingPeriod(*period)
+		if !bp.IsValid() {
+			return nil, nil, &DTOValidationError{ClientMessage: invalidBillingPeriodMsg}
+		}
+		billingPeriod = &bp
+	}
+
+	if monthlyCost == nil {
+		return price, billingPeriod, nil
+	}
+
+	if price != nil {
+		return nil, nil, &DTOValidationError{ClientMessage: priceConflictMsg}
+	}
+	if billingPeriod != nil && *billingPeriod != domain.BillingMonthly {
+		return nil, nil, &DTOValidationError{ClientMessage: monthlyCostPeriodMsg}
+	}
+
+	monthly := domain.BillingMonthly
+	return monthlyCost, &monthly, nil
+}
+
+// validatePriceEffectiveFrom parses the month a price change takes effect, price is
+// the already resolved new price.
+func validatePriceEffectiveFrom(month *string, price *int) (*time.Time, error) {
+	if month == nil {
+		return nil, nil
+	}
+	if price == nil {
+		return nil, &DTOValidationError{ClientMessage: effectiveFromNoPriceMsg}
+	}
+	return validateMonth(month)
+}
+
+// validateNewPause parses the optional months of d, the service checks their order.
+func validateNewPause(d *dto.NewPause) (pausedFrom, resumedFrom *time.Time, err error) {
+	pausedFrom, err = validateMonth(d.PausedFrom)
+	if err != nil {
+		return nil, nil, err
+	}
+	resumedFrom, err = validateMonth(d.ResumedFrom)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pausedFrom, resumedFrom, nil
+}
 
-	var endDateStr string
-	if err := json.Unmarshal(req.EndDate, &endDateStr); err != nil {
-		return nil, false, &DTOValidationError{ClientMessage: "invalid end_date format", InternalError: err}
+// validateMonth parses an optional MM-YYYY month.
+func validateMonth(month *string) (*time.Time, error) {
+	if month == nil {
+		return nil, nil
 	}
 
-	t, err := dateLayout.parse(endDateStr)
+	t, err := dateLayout.parse(*month)
 	if err != nil {
-		return nil, false, &DTOValidationError{ClientMessage: invalidDateMsg, InternalError: err}
+		return nil, &DTOValidationError{ClientMessage: invalidDateMsg, InternalError: err}
+	}
+	return &t, nil
+}
+
+// validateCursor decodes the optional cursor, which replaces page.
+func validateCursor(cursor *string, page *int) (*domain.Cursor, error) {
+	if cursor == nil {
+		return nil, nil
+	}
+	if page != nil {
+		return nil, &DTOValidationError{ClientMessage: cursorConflictMsg}
+	}
+
+	c, err := decodeCursor(*cursor)
+	if err != nil {
+		return nil, &DTOValidationError{ClientMessage: invalidCursorMsg, InternalError: err}
+	}
+	return &c, nil
+}
+
+// validateCostRange checks the optional monthly cost bounds of a list filter.
+func validateCostRange(minCost, maxCost *int) error {
+	if (minCost != nil && *minCost < 0) || (maxCost != nil && *maxCost < 0) {
+		return &DTOValidationError{ClientMessage: negativeCostMsg}
+	}
+	if minCost != nil && maxCost != nil && *minCost > *maxCost {
+		return &DTOValidationError{ClientMessage: invalidCostRangeMsg}
+	}
+	return nil
+}
+
+func validateSuggestQuery(q string) (string, error) {
+	q = strings.TrimSpace(q)
+	if q == "" {
+		return "", &DTOValidationError{ClientMessage: blankQueryMsg}
+	}
+	return q, nil
+}
+
+// validateDate parses an optional subscription date, parse decides which day
+// of the month a month-only date means.
+func validateDate(date *string, parse func(string) (time.Time, error)) (*time.Time, error) {
+	if date == nil {
+		return nil, nil
+	}
+
+	t, err := parse(*date)
+	if err != nil {
+		return nil, &DTOValidationError{ClientMessage: invalidSubDateMsg, InternalError: err}
+	}
+	return &t, nil
+}
+
+// validateSort parses "field[:asc|:desc]" keys, unknown ones are a bad request.
+func validateSort(keys *[]string) ([]domain.SortKey, error) {
+	if keys == nil {
+		return nil, nil
+	}
+
+	sort := make([]domain.SortKey, 0, len(*keys))
+	seen := make(map[domain.SortField]bool, len(*keys))
+	for _, k := range *keys {
+		field, dir, _ := strings.Cut(k, ":")
+		key := domain.SortKey{Field: domain.SortField(field), Desc: dir == "desc"}
+		if !key.Field.IsValid() || (dir != "" && dir != "asc" && dir != "desc") {
+			return nil, &DTOValidationError{ClientMessage: invalidSortMsg, StatusCode: http.StatusBadRequest}
+		}
+		if seen[key.Field] {
+			return nil, &DTOValidationError{ClientMessage: duplicateSortMsg, StatusCode: http.StatusBadRequest}
+		}
+		seen[key.Field] = true
+		sort = append(sort, key)
+	}
+	return sort, nil
+}
+
+// validateCostMode returns the mode or domain.CostModeBilled when it is omitted.
+func validateCostMode(mode *dto.CostMode) (domain.CostMode, error) {
+	if mode == nil {
+		return domain.CostModeBilled, nil
+	}
+	m := domain.CostMode(*mode)
+	if !m.IsValid() {
+		return "", &DTOValidationError{ClientMessage: invalidCostModeMsg}
+	}
+	return m, nil
+}
+
+// validateCurrency returns the ISO-4217 code or domain.DefaultCurrency when it is omitted.
+func validateCurrency(code *string) (string, error) {
+	if code == nil {
+		return domain.DefaultCurrency, nil
+	}
+	if !currencyCodeRe.MatchString(*code) {
+		return "", &DTOValidationError{ClientMessage: invalidCurrencyMsg}
+	}
+	return *code, nil
+}
+
+func validateCurrencyPair(from, to string) error {
+	if !currencyCodeRe.MatchString(from) || !currencyCodeRe.MatchString(to) {
+		return &DTOValidationError{ClientMessage: invalidCurrencyMsg}
+	}
+	return nil
+}
+
+func validateNewCurrencyRate(d *dto.NewCurrencyRate) error {
+	if d.Rate <= 0 {
+		return &DTOValidationError{ClientMessage: invalidRateMsg}
+	}
+	return nil
+}
+
+func validateNewAPIKey(d *dto.NewApiKey) ([]domain.Scope, error) {
+	if name := strings.TrimSpace(d.Name); name == "" || utf8.RuneCountInString(name) > maxAPIKeyNameLen {
+		return nil, &DTOValidationError{ClientMessage: apiKeyNameMsg}
+	}
+	if len(d.Scopes) == 0 {
+		return nil, &DTOValidationError{ClientMessage: apiKeyScopesMsg}
+	}
+	scopes := make([]domain.Scope, len(d.Scopes))
+	for i, scope := range d.Scopes {
+		scopes[i] = domain.Scope(scope)
+		if !scopes[i].IsValid() {
+			return nil, &DTOValidationError{ClientMessage: invalidScopeMsg}
+		}
+	}
+	if d.ExpiresAt != nil && !d.ExpiresAt.After(time.Now()) {
+		return nil, &DTOValidationError{ClientMessage: pastExpiryMsg}
 	}
-	return &t, false, nil
+	return scopes, nil
 }
@@ -5,6 +5,7 @@ import "fmt"
 type DTOValidationError struct {
 	ClientMessage string
 	InternalError error
+	StatusCode    int // 422 Unprocessable Entity when zero
 }
 
 func (e *DTOValidationError) Error() string {
@@ -1,83 +1,16 @@
 package http
 
 import (
-	"encoding/json"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/shrtyk/subscriptions-service/internal/api/http/dto"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func Test_validateUpdateSubscriptionRequest(t *testing.T) {
-	t.Parallel()
-
-	endDate := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
-
-	tests := []struct {
-		name             string
-		req              *UpdateSubscriptionRequest
-		wantEndDate      *time.Time
-		wantClearEndDate bool
-		wantErr          bool
-	}{
-		{
-			name:             "Absent end date",
-			req:              &UpdateSubscriptionRequest{},
-			wantEndDate:      nil,
-			wantClearEndDate: false,
-			wantErr:          false,
-		},
-		{
-			name: "Clear end date with null",
-			req: &UpdateSubscriptionRequest{
-				EndDate: json.RawMessage(`null`),
-			},
-			wantEndDate:      nil,
-			wantClearEndDate: true,
-			wantErr:          false,
-		},
-		{
-			name: "Valid end date",
-			req: &UpdateSubscriptionRequest{
-				EndDate: json.RawMessage(`"01-2026"`),
-			},
-			wantEndDate:      &endDate,
-			wantClearEndDate: false,
-			wantErr:          false,
-		},
-		{
-			name: "Invalid end date format",
-			req: &UpdateSubscriptionRequest{
-				EndDate: json.RawMessage(`"2026-01"`),
-			},
-			wantErr: true,
-		},
-		{
-			name: "Invalid end date json",
-			req: &UpdateSubscriptionRequest{
-				EndDate: json.RawMessage(`not-a-string`),
-			},
-			wantErr: true,
-		},
-	}
-
-	for _, tt := range tests {
-		tc := tt
-		t.Run(tt.name, func(t *testing.T) {
-			t.Parallel()
-			gotDate, gotClear, err := validateUpdateSubscriptionRequest(tc.req)
-			if tc.wantErr {
-				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
-				assert.Equal(t, tc.wantEndDate, gotDate)
-				assert.Equal(t, tc.wantClearEndDate, gotClear)
-			}
-		})
-	}
-}
-
 func Test_validateGetTotalCostParams(t *testing.T) {
 	t.Parallel()
 
@@ -168,7 +101,7 @@ func Test_validateNewSubscription(t *testing.T) {
 	t.Parallel()
 
 	startDate := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
-	endDate := time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, time.December, 31, 0, 0, 0, 0, time.UTC)
 	endDateStr := "12-2025"
 
 	tests := []struct {
@@ -176,6 +109,7 @@ func Test_validateNewSubscription(t *testing.T) {
 		dto     *dto.NewSubscription
 		wantS   time.Time
 		wantE   *time.Time
+		wantT   *time.Time
 		wantErr bool
 		errMsg  string
 	}{
@@ -199,6 +133,64 @@ func Test_validateNewSubscription(t *testing.T) {
 			wantE:   nil,
 			wantErr: false,
 		},
+		{
+			name: "Valid subscription with ISO dates",
+			dto: &dto.NewSubscription{
+				StartDate: "2025-01-28",
+				EndDate:   func(s string) *string { return &s }("2025-03-27"),
+			},
+			wantS:   time.Date(2025, time.January, 28, 0, 0, 0, 0, time.UTC),
+			wantE:   func(t time.Time) *time.Time { return &t }(time.Date(2025, time.March, 27, 0, 0, 0, 0, time.UTC)),
+			wantErr: false,
+		},
+		{
+			name: "Trial months end the day before the first paid billing date",
+			dto: &dto.NewSubscription{
+				StartDate:   "2025-01-31",
+				TrialMonths: func(i int) *int { return &i }(1),
+			},
+			wantS:   time.Date(2025, time.January, 31, 0, 0, 0, 0, time.UTC),
+			wantT:   func(t time.Time) *time.Time { return &t }(time.Date(2025, time.February, 27, 0, 0, 0, 0, time.UTC)),
+			wantErr: false,
+		},
+		{
+			name: "Month-only trial end",
+			dto: &dto.NewSubscription{
+				StartDate: "01-2025",
+				TrialEnd:  func(s string) *string { return &s }("02-2025"),
+			},
+			wantS:   startDate,
+			wantT:   func(t time.Time) *time.Time { return &t }(time.Date(2025, time.February, 28, 0, 0, 0, 0, time.UTC)),
+			wantErr: false,
+		},
+		{
+			name: "Trial months and trial end together",
+			dto: &dto.NewSubscription{
+				StartDate:   "01-2025",
+				TrialMonths: func(i int) *int { return &i }(1),
+				TrialEnd:    func(s string) *string { return &s }("02-2025"),
+			},
+			wantErr: true,
+			errMsg:  trialConflictMsg,
+		},
+		{
+			name: "Zero trial months",
+			dto: &dto.NewSubscription{
+				StartDate:   "01-2025",
+				TrialMonths: func(i int) *int { return &i }(0),
+			},
+			wantErr: true,
+			errMsg:  invalidTrialMonthsMsg,
+		},
+		{
+			name: "Trial end before start date",
+			dto: &dto.NewSubscription{
+				StartDate: "2025-01-15",
+				TrialEnd:  func(s string) *string { return &s }("2025-01-14"),
+			},
+			wantErr: true,
+			errMsg:  trialEndBeforeStartMsg,
+		},
 		{
 			name: "Invalid start date format",
 			dto: &dto.NewSubscription{
@@ -208,7 +200,7 @@ func Test_validateNewSubscription(t *testing.T) {
 			wantS:   time.Time{},
 			wantE:   nil,
 			wantErr: true,
-			errMsg:  invalidDateMsg,
+			errMsg:  invalidSubDateMsg,
 		},
 		{
 			name: "Invalid end date format",
@@ -219,7 +211,7 @@ func Test_validateNewSubscription(t *testing.T) {
 			wantS:   time.Time{},
 			wantE:   nil,
 			wantErr: true,
-			errMsg:  invalidDateMsg,
+			errMsg:  invalidSubDateMsg,
 		},
 		{
 			name: "Start date after end date",
@@ -238,7 +230,7 @@ func Test_validateNewSubscription(t *testing.T) {
 		tc := tt
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
-			gotS, gotE, err := validateNewSubscription(tc.dto)
+			gotS, gotE, gotT, err := validateNewSubscription(tc.dto)
 			if tc.wantErr {
 				assert.Error(t, err)
 				var validationError *DTOValidationError
@@ -248,6 +240,219 @@ func Test_validateNewSubscription(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Equal(t, tc.wantS, gotS)
 				assert.Equal(t, tc.wantE, gotE)
+				assert.Equal(t, tc.wantT, gotT)
+			}
+		})
+	}
+}
+
+func Test_validateGetCostBreakdownParams(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		params  dto.GetCostBreakdownParams
+		wantS   time.Time
+		wantE   time.Time
+		wantMsg string
+	}{
+		{
+			name:   "Valid period",
+			params: dto.GetCostBreakdownParams{Start: "01-2025", End: "12-2025"},
+			wantS:  time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC),
+			wantE:  time.Date(2025, time.December, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "Single month period",
+			params: dto.GetCostBreakdownParams{Start: "03-2025", End: "03-2025"},
+			wantS:  time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+			wantE:  time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "Invalid start date format",
+			params:  dto.GetCostBreakdownParams{Start: "2025-01", End: "12-2025"},
+			wantMsg: invalidDateMsg,
+		},
+		{
+			name:    "Invalid end date format",
+			params:  dto.GetCostBreakdownParams{Start: "01-2025", End: "2025-12"},
+			wantMsg: invalidDateMsg,
+		},
+		{
+			name:    "Start after end",
+			params:  dto.GetCostBreakdownParams{Start: "12-2025", End: "01-2025"},
+			wantMsg: startAfterEndMsg,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			gotS, gotE, err := validateGetCostBreakdownParams(tc.params)
+			if tc.wantMsg != "" {
+				var validationError *DTOValidationError
+				require.ErrorAs(t, err, &validationError)
+				assert.Equal(t, tc.wantMsg, validationError.ClientMessage)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.wantS, gotS)
+				assert.Equal(t, tc.wantE, gotE)
+			}
+		})
+	}
+}
+
+func Test_validatePrice(t *testing.T) {
+	t.Parallel()
+
+	monthly := domain.BillingMonthly
+	weekly := domain.BillingWeekly
+
+	tests := []struct {
+		name        string
+		price       *int
+		monthlyCost *int
+		period      *dto.BillingPeriod
+		wantPrice   *int
+		wantPeriod  *domain.BillingPeriod
+		wantMsg     string
+	}{
+		{
+			name: "Nothing set",
+		},
+		{
+			name:       "Price with period",
+			price:      ptr(100),
+			period:     ptr(dto.Weekly),
+			wantPrice:  ptr(100),
+			wantPeriod: &weekly,
+		},
+		{
+			name:      "Price without period",
+			price:     ptr(100),
+			wantPrice: ptr(100),
+		},
+		{
+			name:        "Monthly cost becomes monthly price",
+			monthlyCost: ptr(100),
+			wantPrice:   ptr(100),
+			wantPeriod:  &monthly,
+		},
+		{
+			name:        "Monthly cost with monthly period",
+			monthlyCost: ptr(100),
+			period:      ptr(dto.Monthly),
+			wantPrice:   ptr(100),
+			wantPeriod:  &monthly,
+		},
+		{
+			name:        "Price and monthly cost",
+			price:       ptr(100),
+			monthlyCost: ptr(100),
+			wantMsg:     priceConflictMsg,
+		},
+		{
+			name:        "Monthly cost with quarterly period",
+			monthlyCost: ptr(100),
+			period:      ptr(dto.Quarterly),
+			wantMsg:     monthlyCostPeriodMsg,
+		},
+		{
+			name:    "Unknown period",
+			price:   ptr(100),
+			period:  ptr(dto.BillingPeriod("daily")),
+			wantMsg: invalidBillingPeriodMsg,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			gotPrice, gotPeriod, err := validatePrice(tc.price, tc.monthlyCost, tc.period)
+			if tc.wantMsg != "" {
+				var validationError *DTOValidationError
+				require.ErrorAs(t, err, &validationError)
+				assert.Equal(t, tc.wantMsg, validationError.ClientMessage)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.wantPrice, gotPrice)
+				assert.Equal(t, tc.wantPeriod, gotPeriod)
+			}
+		})
+	}
+}
+
+func Test_validateSort(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		sort    *[]string
+		want    []domain.SortKey
+		wantMsg string
+	}{
+		{name: "Omitted", sort: nil, want: nil},
+		{
+			name: "Several keys",
+			sort: &[]string{"start_date:desc", "service_name", "monthly_cost:asc"},
+			want: []domain.SortKey{
+				{Field: domain.SortStartDate, Desc: true},
+				{Field: domain.SortServiceName},
+				{Field: domain.SortMonthlyCost},
+			},
+		},
+		{name: "Unknown field", sort: &[]string{"price"}, wantMsg: invalidSortMsg},
+		{name: "Unknown direction", sort: &[]string{"created_at:up"}, wantMsg: invalidSortMsg},
+		{name: "Repeated field", sort: &[]string{"created_at", "created_at:desc"}, wantMsg: duplicateSortMsg},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := validateSort(tc.sort)
+			if tc.wantMsg != "" {
+				var validationError *DTOValidationError
+				require.ErrorAs(t, err, &validationError)
+				assert.Equal(t, tc.wantMsg, validationError.ClientMessage)
+				assert.Equal(t, http.StatusBadRequest, validationError.StatusCode)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
+func Test_validateCostMode(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		mode    *dto.CostMode
+		want    domain.CostMode
+		wantErr bool
+	}{
+		{name: "Omitted", mode: nil, want: domain.CostModeBilled},
+		{name: "Billed", mode: ptr(dto.Billed), want: domain.CostModeBilled},
+		{name: "Prorated", mode: ptr(dto.Prorated), want: domain.CostModeProrated},
+		{name: "Unknown", mode: ptr(dto.CostMode("estimated")), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := validateCostMode(tc.mode)
+			if tc.wantErr {
+				var validationError *DTOValidationError
+				require.ErrorAs(t, err, &validationError)
+				assert.Equal(t, invalidCostModeMsg, validationError.ClientMessage)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.want, got)
 			}
 		})
 	}
@@ -19,26 +19,55 @@ func init() {
 type Config struct {
 	AppCfg      AppCfg      `yaml:"app"`
 	HttpCfg     HttpCfg     `yaml:"http_server"`
+	AuthCfg     AuthCfg     `yaml:"auth"`
+	PolicyCfg   PolicyCfg   `yaml:"-"` // Read from AuthCfg.PolicyPath
 	PostgresCfg PostgresCfg `yaml:"postgres"`
 	RepoCfg     RepoConfig  `yaml:"repository"`
 }
 
 type AppCfg struct {
-	Env             string        `yaml:"env" env:"APP_ENV" env-default:"dev"` // One of: "dev", "staging", "prod"
-	Timeout         time.Duration `yaml:"timeout" env:"APP_TIMEOUT" env-default:"5s"`
-	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"APP_SHUTDOWN_TIMEOUT" env-default:"10s"`
+	Env                      string        `yaml:"env" env:"APP_ENV" env-default:"dev"` // One of: "dev", "staging", "prod"
+	Timeout                  time.Duration `yaml:"timeout" env:"APP_TIMEOUT" env-default:"5s"`
+	ShutdownTimeout          time.Duration `yaml:"shutdown_timeout" env:"APP_SHUTDOWN_TIMEOUT" env-default:"10s"`
+	IdempotencyPurgeInterval time.Duration `yaml:"idempotency_purge_interval" env:"APP_IDEMPOTENCY_PURGE_INTERVAL" env-default:"1h"` // How often expired idempotency keys are deleted
 }
 
 type HttpCfg struct {
-	Port         string        `yaml:"port" env:"HTTP_SERVER_PORT" env-default:"8080"`
-	IdleTimeout  time.Duration `yaml:"idle_timeout" env:"HTTP_SERVER_IDLE_TIMEOUT" env-default:"5s"`
-	WriteTimeout time.Duration `yaml:"write_timeout" env:"HTTP_SERVER_WRITE_TIMEOUT" env-default:"10s"`
-	ReadTimeout  time.Duration `yaml:"read_timeout" env:"HTTP_SERVER_READ_TIMEOUT" env-default:"10s"`
+	Port          string        `yaml:"port" env:"HTTP_SERVER_PORT" env-default:"8080"`
+	IdleTimeout   time.Duration `yaml:"idle_timeout" env:"HTTP_SERVER_IDLE_TIMEOUT" env-default:"5s"`
+	WriteTimeout  time.Duration `yaml:"write_timeout" env:"HTTP_SERVER_WRITE_TIMEOUT" env-default:"10s"`
+	ReadTimeout   time.Duration `yaml:"read_timeout" env:"HTTP_SERVER_READ_TIMEOUT" env-default:"10s"`
+	MaxBatchSize  int           `yaml:"max_batch_size" env:"HTTP_SERVER_MAX_BATCH_SIZE" env-default:"500"`    // Most operations a batch request may have
+	MaxImportRows int           `yaml:"max_import_rows" env:"HTTP_SERVER_MAX_IMPORT_ROWS" env-default:"5000"` // Most rows a CSV import may have
+}
+
+type AuthCfg struct {
+	Issuer         string        `yaml:"issuer" env:"AUTH_ISSUER"`                                               // Required iss claim of tokens, not checked when empty
+	Audience       string        `yaml:"audience" env:"AUTH_AUDIENCE"`                                           // Required aud claim of tokens, not checked when empty
+	HMACSecretPath string        `yaml:"hmac_secret_path" env:"AUTH_HMAC_SECRET_PATH"`                           // File with the secret of HS256 tokens
+	JWKSPath       string        `yaml:"jwks_path" env:"AUTH_JWKS_PATH"`                                         // JWK Set file with the public keys of RS256 tokens
+	Leeway         time.Duration `yaml:"leeway" env:"AUTH_LEEWAY" env-default:"30s"`                             // Clock skew allowed when checking exp and nbf
+	PolicyPath     string        `yaml:"policy_path" env:"AUTH_POLICY_PATH" env-default:"./configs/policy.yaml"` // Policy file granting actions to roles
+	TenantHeader   string        `yaml:"tenant_header" env:"AUTH_TENANT_HEADER"`                                 // Header naming the tenant of tokens without a tenant_id claim, only taken from TrustedProxies
+	TrustedProxies []string      `yaml:"trusted_proxies" env:"AUTH_TRUSTED_PROXIES" env-separator:","`           // Addresses or CIDRs of the proxies setting TenantHeader, required with it
+	DefaultTenant  string        `yaml:"default_tenant" env:"AUTH_DEFAULT_TENANT" env-default:"default"`         // Tenant of users named neither by their token nor by the header, such requests are refused when empty
+}
+
+// PolicyCfg grants actions, like "subscriptions:read", to the roles of callers.
+type PolicyCfg struct {
+	Roles map[string]RoleGrants `yaml:"roles"`
+}
+
+type RoleGrants struct {
+	Own []string `yaml:"own"` // Actions on the subscriptions of the caller
+	Any []string `yaml:"any"` // Actions on the subscriptions of all users and on what no user owns
 }
 
 type RepoConfig struct {
-	DefaultPageSize int `yaml:"default_page_size" env:"REPO_DEFAULT_PAGE_SIZE" env-default:"10"`
-	MaxPageSize     int `yaml:"max_page_size" env:"REPO_MAX_PAGE_SIZE" env-default:"100"`
+	DefaultPageSize        int           `yaml:"default_page_size" env:"REPO_DEFAULT_PAGE_SIZE" env-default:"10"`
+	MaxPageSize            int           `yaml:"max_page_size" env:"REPO_MAX_PAGE_SIZE" env-default:"100"`
+	IdempotencyKeyTTL      time.Duration `yaml:"idempotency_key_ttl" env:"REPO_IDEMPOTENCY_KEY_TTL" env-default:"24h"`          // How long retries with an Idempotency-Key replay the first response
+	ExclusiveSubscriptions bool          `yaml:"exclusive_subscriptions" env:"REPO_EXCLUSIVE_SUBSCRIPTIONS" env-default:"true"` // Reject new subscriptions overlapping another of the same user and service
 }
 
 type PostgresCfg struct {
@@ -72,6 +101,10 @@ func MustInitConfig() *Config {
 
 	validateCfg(cfg)
 
+	if err := cleanenv.ReadConfig(cfg.AuthCfg.PolicyPath, &cfg.PolicyCfg); err != nil {
+		panic(fmt.Sprintf("failed to read authorization policy: %s", err))
+	}
+
 	return cfg
 }
 
@@ -92,4 +125,8 @@ func validateCfg(cfg *Config) {
 	if !slices.Contains(allowedEnvs, cfg.AppCfg.Env) {
 		panic(fmt.Sprintf("wrong environment: environment should be one of: %v", allowedEnvs))
 	}
+
+	if cfg.AuthCfg.HMACSecretPath == "" && cfg.AuthCfg.JWKSPath == "" {
+		panic("no token keys: at least one of AUTH_HMAC_SECRET_PATH and AUTH_JWKS_PATH should be set")
+	}
 }
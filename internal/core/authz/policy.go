@@ -0,0 +1,87 @@
+package authz
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/config"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/authz"
+)
+
+// grants are the actions of a role on the subscriptions of the caller (own)
+// and on those of all users (any).
+type grants struct {
+	own []authz.Action
+	any []authz.Action
+}
+
+// policy authorizes by the role of the caller. Roles it doesn't know may do
+// nothing.
+type policy struct {
+	roles map[domain.Role]grants
+}
+
+// MustCreatePolicy creates the authorizer of cfg.
+func MustCreatePolicy(cfg *config.PolicyCfg) authz.Authorizer {
+	p, err := NewPolicy(cfg)
+	if err != nil {
+		panic(fmt.Sprintf("invalid authorization policy: %s", err))
+	}
+	return p
+}
+
+func NewPolicy(cfg *config.PolicyCfg) (*policy, error) {
+	p := &policy{roles: make(map[domain.Role]grants, len(cfg.Roles))}
+	for role, g := range cfg.Roles {
+		own, err := toActions(g.Own)
+		if err != nil {
+			return nil, fmt.Errorf("role %q: %w", role, err)
+		}
+		all, err := toActions(g.Any)
+		if err != nil {
+			return nil, fmt.Errorf("role %q: %w", role, err)
+		}
+		p.roles[domain.Role(role)] = grants{own: own, any: all}
+	}
+	return p, nil
+}
+
+func toActions(names []string) ([]authz.Action, error) {
+	actions := make([]authz.Action, len(names))
+	for i, name := range names {
+		actions[i] = authz.Action(name)
+		if !actions[i].IsValid() {
+			return nil, fmt.Errorf("unknown action %q", name)
+		}
+	}
+	return actions, nil
+}
+
+func (p *policy) Authorize(ctx context.Context, action authz.Action, userID uuid.UUID) error {
+	caller, ok := domain.PrincipalFromCtx(ctx)
+	if !ok {
+		return fmt.Errorf("%w: no caller", authz.ErrDenied)
+	}
+	g := p.roles[caller.Role]
+	if slices.Contains(g.any, action) {
+		return nil
+	}
+	if caller.UserID != uuid.Nil && caller.UserID == userID && slices.Contains(g.own, action) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s to role %q for user %s", authz.ErrDenied, action, caller.Role, userID)
+}
+
+func (p *policy) AuthorizeAll(ctx context.Context, action authz.Action) error {
+	caller, ok := domain.PrincipalFromCtx(ctx)
+	if !ok {
+		return fmt.Errorf("%w: no caller", authz.ErrDenied)
+	}
+	if slices.Contains(p.roles[caller.Role].any, action) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s to role %q for all users", authz.ErrDenied, action, caller.Role)
+}
@@ -0,0 +1,86 @@
+package authz
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/shrtyk/subscriptions-service/internal/config"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/authz"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The policy shipped with the service.
+const policyPath = "../../../configs/policy.yaml"
+
+func TestPolicy(t *testing.T) {
+	t.Parallel()
+
+	var cfg config.PolicyCfg
+	require.NoError(t, cleanenv.ReadConfig(policyPath, &cfg))
+	p, err := NewPolicy(&cfg)
+	require.NoError(t, err)
+
+	callerID := uuid.New()
+	otherID := uuid.New()
+	as := func(role domain.Role) context.Context {
+		return domain.PrincipalToCtx(context.Background(), &domain.Principal{UserID: callerID, Role: role})
+	}
+	apiKeyID := uuid.New()
+	apiKeyCtx := domain.PrincipalToCtx(context.Background(), &domain.Principal{
+		Role: domain.RoleService, APIKeyID: &apiKeyID, Scopes: []domain.Scope{domain.ScopeRead},
+	})
+
+	testCases := []struct {
+		name    string
+		ctx     context.Context
+		action  authz.Action
+		userID  *uuid.UUID // AuthorizeAll if nil
+		allowed bool
+	}{
+		{name: "Viewer reads own subscriptions", ctx: as(domain.RoleViewer), action: authz.ReadSubscriptions, userID: &callerID, allowed: true},
+		{name: "Viewer cannot write own subscriptions", ctx: as(domain.RoleViewer), action: authz.WriteSubscriptions, userID: &callerID},
+		{name: "Editor writes own subscriptions", ctx: as(domain.RoleEditor), action: authz.WriteSubscriptions, userID: &callerID, allowed: true},
+		{name: "Editor cannot read other users", ctx: as(domain.RoleEditor), action: authz.ReadSubscriptions, userID: &otherID},
+		{name: "Editor cannot list all users", ctx: as(domain.RoleEditor), action: authz.ReadSubscriptions},
+		{name: "Finance reads costs of other users", ctx: as(domain.RoleFinance), action: authz.ReadCosts, userID: &otherID, allowed: true},
+		{name: "Finance reads costs of all users", ctx: as(domain.RoleFinance), action: authz.ReadCosts, allowed: true},
+		{name: "Finance cannot read subscriptions of other users", ctx: as(domain.RoleFinance), action: authz.ReadSubscriptions, userID: &otherID},
+		{name: "Finance cannot set currency rates", ctx: as(domain.RoleFinance), action: authz.WriteRates},
+		{name: "Admin manages API keys", ctx: as(domain.RoleAdmin), action: authz.ManageAPIKeys, allowed: true},
+		{name: "Admin writes subscriptions of other users", ctx: as(domain.RoleAdmin), action: authz.WriteSubscriptions, userID: &otherID, allowed: true},
+		{name: "API key reads all users", ctx: apiKeyCtx, action: authz.ReadSubscriptions, allowed: true},
+		{name: "Unknown role", ctx: as("root"), action: authz.ReadSubscriptions, userID: &callerID},
+		{name: "No caller", ctx: context.Background(), action: authz.ReadSubscriptions, userID: &callerID},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var err error
+			if tc.userID != nil {
+				err = p.Authorize(tc.ctx, tc.action, *tc.userID)
+			} else {
+				err = p.AuthorizeAll(tc.ctx, tc.action)
+			}
+			if tc.allowed {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, authz.ErrDenied)
+			}
+		})
+	}
+}
+
+func TestNewPolicy(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewPolicy(&config.PolicyCfg{Roles: map[string]config.RoleGrants{
+		"viewer": {Own: []string{"subscriptions:read", "subscriptions:delete"}},
+	}})
+	require.EqualError(t, err, `role "viewer": unknown action "subscriptions:delete"`)
+}
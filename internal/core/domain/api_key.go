@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Scope string
+
+const (
+	ScopeRead  Scope = "read"  // Read subscriptions and their costs
+	ScopeWrite Scope = "write" // Create, change and delete subscriptions
+	ScopeAdmin Scope = "admin" // Manage currency rates and API keys
+)
+
+func (s Scope) IsValid() bool {
+	switch s {
+	case ScopeRead, ScopeWrite, ScopeAdmin:
+		return true
+	default:
+		return false
+	}
+}
+
+// APIKey authenticates a service caller. Only the hash of the key is stored, the
+// key itself is shown once when it is minted.
+type APIKey struct {
+	ID         uuid.UUID
+	TenantID   TenantID // Tenant the key acts in, the one of the admin who minted it
+	Name       string
+	Prefix     string // Start of the key, to tell keys apart
+	Hash       string // Hex SHA-256 of the key
+	Scopes     []Scope
+	ExpiresAt  *time.Time // Never expires when nil
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditOp is the kind of change an audit entry records.
+type AuditOp string
+
+const (
+	AuditCreate AuditOp = "create"
+	AuditUpdate AuditOp = "update"
+	AuditDelete AuditOp = "delete"
+	AuditPause  AuditOp = "pause"
+	AuditResume AuditOp = "resume"
+)
+
+func (op AuditOp) IsValid() bool {
+	switch op {
+	case AuditCreate, AuditUpdate, AuditDelete, AuditPause, AuditResume:
+		return true
+	default:
+		return false
+	}
+}
+
+// AuditEntry records one change of a subscription. Entries are never changed
+// once recorded.
+type AuditEntry struct {
+	ID             int64 // Increases with every entry of the tenant
+	SubscriptionID uuid.UUID
+	Op             AuditOp
+	ActorID        uuid.UUID     // User who made the change, nil for API keys
+	ActorRole      Role          // Role of the actor at the time
+	APIKeyID       *uuid.UUID    // Key the change was made with
+	RequestID      string        // Request the change was made in, empty outside of requests
+	Before         *Subscription // Nil for AuditCreate
+	After          *Subscription // Nil for AuditDelete
+	CreatedAt      time.Time
+}
+
+// AuditFilter selects audit entries, newest first.
+type AuditFilter struct {
+	SubscriptionID *uuid.UUID
+	ActorID        *uuid.UUID
+	APIKeyID       *uuid.UUID
+	Op             *AuditOp
+	From           *time.Time // Inclusive
+	To             *time.Time // Exclusive
+	Before         *int64     // ID of the last entry of the previous page
+	Limit          *int
+}
+
+type AuditPage struct {
+	Items []AuditEntry
+	Next  *int64 // Before of the next page, nil on the last one
+}
+
+type requestIDCtxKey struct{}
+
+func RequestIDToCtx(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDCtxKey{}, id)
+}
+
+// RequestIDFromCtx returns the id of the request ctx belongs to, empty outside
+// of requests.
+func RequestIDFromCtx(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDCtxKey{}).(string)
+	return id
+}
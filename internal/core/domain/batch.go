@@ -0,0 +1,26 @@
+package domain
+
+import "github.com/google/uuid"
+
+type BatchOpKind string
+
+const (
+	BatchCreate BatchOpKind = "create"
+	BatchUpdate BatchOpKind = "update"
+	BatchDelete BatchOpKind = "delete"
+)
+
+// BatchOp is one operation of a batch request.
+type BatchOp struct {
+	Kind    BatchOpKind
+	ID      uuid.UUID          // Subscription to update or delete
+	Version *int               // Version the updated or deleted subscription must be at, any when nil
+	Sub     Subscription       // Subscription to create
+	Update  SubscriptionUpdate // Replacement of the updated subscription
+}
+
+// BatchResult is the outcome of a BatchOp.
+type BatchResult struct {
+	Sub *Subscription // Created or updated subscription
+	Err error
+}
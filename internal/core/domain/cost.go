@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type CostMode string
+
+const (
+	// CostModeBilled charges the full price on every billing date.
+	CostModeBilled CostMode = "billed"
+	// CostModeProrated spreads the price over the days the subscription is active.
+	CostModeProrated CostMode = "prorated"
+)
+
+func (m CostMode) IsValid() bool {
+	return m == CostModeBilled || m == CostModeProrated
+}
+
+type MonthCost struct {
+	Month time.Time
+	Total int
+	Items []CostItem
+}
+
+type CostItem struct {
+	SubscriptionID uuid.UUID
+	ServiceName    string
+	Cost           int
+}
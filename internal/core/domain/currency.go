@@ -0,0 +1,14 @@
+package domain
+
+import "time"
+
+// DefaultCurrency is used for subscriptions created without an explicit currency.
+const DefaultCurrency = "RUB"
+
+// CurrencyRate converts amounts in From currency to To currency: to = from * Rate.
+type CurrencyRate struct {
+	From      string
+	To        string
+	Rate      float64
+	UpdatedAt time.Time
+}
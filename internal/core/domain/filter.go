@@ -1,10 +1,80 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type SubscriptionFilter struct {
-	UserID      *uuid.UUID
-	ServiceName *string
-	Page        *int
-	PageSize    *int
+	UserID        *uuid.UUID
+	ServiceName   *string
+	Query         *string    // Case-insensitive part of the service name
+	InTrial       *bool      // Started subscriptions with a trial that hasn't ended yet, or all others
+	ActiveAt      *time.Time // Active on at least one day of this month
+	MinCost       *int       // Inclusive bounds of the rounded monthly rate
+	MaxCost       *int
+	StartedAfter  *time.Time // Exclusive bounds of the start date
+	StartedBefore *time.Time
+	EndsBefore    *time.Time // Exclusive, subscriptions without an end date never match
+	HasEndDate    *bool
+	Sort          []SortKey // By created_at when empty, ties are broken by id
+	After         *Cursor   // Keyset pagination in Sort order, replaces Page
+	Page          *int
+	PageSize      *int
+}
+
+type SortField string
+
+const (
+	SortCreatedAt   SortField = "created_at"
+	SortStartDate   SortField = "start_date"
+	SortMonthlyCost SortField = "monthly_cost"
+	SortServiceName SortField = "service_name"
+)
+
+func (f SortField) IsValid() bool {
+	switch f {
+	case SortCreatedAt, SortStartDate, SortMonthlyCost, SortServiceName:
+		return true
+	default:
+		return false
+	}
+}
+
+type SortKey struct {
+	Field SortField
+	Desc  bool
+}
+
+// Cursor is the position of a subscription in a sorted list: the values of the
+// fields it can be sorted by, monthly cost included, and its id.
+type Cursor struct {
+	CreatedAt     time.Time
+	StartDate     time.Time
+	ServiceName   string
+	Price         int
+	BillingPeriod BillingPeriod
+	ID            uuid.UUID
+}
+
+// CursorOf returns the position of sub.
+func CursorOf(sub Subscription) Cursor {
+	return Cursor{
+		CreatedAt:     sub.CreatedAt,
+		StartDate:     sub.StartDate,
+		ServiceName:   sub.ServiceName,
+		Price:         sub.Price,
+		BillingPeriod: sub.BillingPeriod,
+		ID:            sub.ID,
+	}
+}
+
+// SubscriptionPage is a page of a subscription list, NextCursor is nil on the last one.
+type SubscriptionPage struct {
+	Items      []Subscription
+	NextCursor *Cursor
+	Total      int // Subscriptions matching the filter across all pages
+	Page       int // Zero on pages after a cursor
+	PageSize   int // Page size after applying the repository limit
 }
@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+// IdempotencyKey lets a client retry a request without repeating its effect.
+type IdempotencyKey struct {
+	Key         string
+	Caller      string // Principal the key belongs to, other callers never see its record
+	RequestHash string // Fingerprint of the request, retries must send the same request
+}
+
+// IdempotencyRecord is the stored outcome of the first request with a key.
+type IdempotencyRecord struct {
+	IdempotencyKey
+	Response  *Subscription // Subscription created by the request
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"context"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// Role names the grants of a principal in the authorization policy.
+type Role string
+
+const (
+	RoleViewer  Role = "viewer"  // Reads own subscriptions and costs
+	RoleEditor  Role = "editor"  // Manages own subscriptions, the default of users
+	RoleFinance Role = "finance" // Reads the costs of all users
+	RoleAdmin   Role = "admin"   // Manages everything
+	RoleService Role = "service" // API keys, limited by their scopes
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	TenantID TenantID
+	UserID   uuid.UUID // Nil for API keys
+	Role     Role
+	APIKeyID *uuid.UUID // Key the caller authenticated with
+	Scopes   []Scope    // Operations an API key may call
+}
+
+// Allows reports whether p may call operations that need scope. Only API keys
+// are limited by scopes, users by their role.
+func (p *Principal) Allows(scope Scope) bool {
+	return p.Role != RoleService || slices.Contains(p.Scopes, scope)
+}
+
+// CallerID tells p apart from the other callers of its tenant, by the API key
+// or the user it authenticated as.
+func (p *Principal) CallerID() string {
+	if p.APIKeyID != nil {
+		return "api_key:" + p.APIKeyID.String()
+	}
+	return "user:" + p.UserID.String()
+}
+
+type principalCtxKey struct{}
+
+func PrincipalToCtx(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalCtxKey{}, p)
+}
+
+// PrincipalFromCtx returns the caller of the request ctx belongs to, if it is
+// authenticated.
+func PrincipalFromCtx(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalCtxKey{}).(*Principal)
+	return p, ok
+}
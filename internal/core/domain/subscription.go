@@ -1,25 +1,99 @@
 package domain
 
 import (
+	"math"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type BillingPeriod string
+
+const (
+	BillingWeekly    BillingPeriod = "weekly"
+	BillingMonthly   BillingPeriod = "monthly"
+	BillingQuarterly BillingPeriod = "quarterly"
+	BillingYearly    BillingPeriod = "yearly"
+)
+
+func (p BillingPeriod) IsValid() bool {
+	switch p {
+	case BillingWeekly, BillingMonthly, BillingQuarterly, BillingYearly:
+		return true
+	default:
+		return false
+	}
+}
+
+// Months returns the length of the period in months, or 0 for weekly billing.
+// An unset period is treated as monthly.
+func (p BillingPeriod) Months() int {
+	switch p {
+	case BillingWeekly:
+		return 0
+	case BillingQuarterly:
+		return 3
+	case BillingYearly:
+		return 12
+	default:
+		return 1
+	}
+}
+
 type Subscription struct {
-	ID          uuid.UUID
-	ServiceName string
-	MonthlyCost int
-	UserID      uuid.UUID
-	StartDate   time.Time
-	EndDate     *time.Time
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID            uuid.UUID
+	ServiceName   string
+	Price         int // Charged once per BillingPeriod, the latest entry of PriceHistory
+	BillingPeriod BillingPeriod
+	Currency      string
+	UserID        uuid.UUID
+	StartDate     time.Time
+	EndDate       *time.Time // Last day of the subscription, inclusive
+	TrialEnd      *time.Time // Last day of the free trial, inclusive. Nothing is charged until then
+	Version       int        // Incremented by every update, guards against lost updates
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	PriceHistory  []PriceChange // Ordered by EffectiveFrom, only loaded for cost calculations
+	Pauses        []Pause       // Ordered by PausedFrom, only loaded for cost calculations
+}
+
+// MonthlyRate returns the price normalized to one month.
+func (s Subscription) MonthlyRate() float64 {
+	if s.BillingPeriod == BillingWeekly {
+		return float64(s.Price) * 52 / 12
+	}
+	return float64(s.Price) / float64(s.BillingPeriod.Months())
+}
+
+// MonthlyEquivalent returns MonthlyRate rounded to the nearest unit.
+func (s Subscription) MonthlyEquivalent() int {
+	return int(math.Round(s.MonthlyRate()))
 }
 
 type SubscriptionUpdate struct {
-	ServiceName  *string
-	MonthlyCost  *int
-	EndDate      *time.Time
-	ClearEndDate bool // Flag to determine meaning of EndDate nil value (could mean 'delete' or 'do not update')
+	ServiceName        *string
+	Price              *int
+	PriceEffectiveFrom *time.Time // First month of the new Price, the current month when nil
+	BillingPeriod      *BillingPeriod
+	Currency           *string
+	UserID             *uuid.UUID
+	StartDate          *time.Time
+	EndDate            *time.Time
+	ClearEndDate       bool // Flag to determine meaning of EndDate nil value (could mean 'delete' or 'do not update')
+	TrialEnd           *time.Time
+	ClearTrialEnd      bool // Same as ClearEndDate for TrialEnd
+}
+
+// PriceChange sets the price of a subscription from the EffectiveFrom month on.
+type PriceChange struct {
+	EffectiveFrom time.Time
+	Price         int
+	CreatedAt     time.Time
+}
+
+// Pause stops billing of a subscription for the months from PausedFrom until ResumedFrom.
+type Pause struct {
+	PausedFrom  time.Time
+	ResumedFrom *time.Time // First billed month after the pause, nil while paused
+	CreatedAt   time.Time
 }
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"context"
+	"regexp"
+)
+
+// TenantID names the organisation data belongs to. Tenants never see each
+// other's data.
+type TenantID string
+
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+func (t TenantID) IsValid() bool {
+	return tenantIDPattern.MatchString(string(t))
+}
+
+// TenantFromCtx returns the tenant of the caller of the request ctx belongs to.
+func TenantFromCtx(ctx context.Context) (TenantID, bool) {
+	p, ok := PrincipalFromCtx(ctx)
+	if !ok || p.TenantID == "" {
+		return "", false
+	}
+	return p.TenantID, true
+}
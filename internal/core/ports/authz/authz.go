@@ -0,0 +1,45 @@
+package authz
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrDenied is the error of actions the caller may not perform.
+var ErrDenied = errors.New("permission denied")
+
+// Action is what callers do to subscriptions and the settings around them.
+type Action string
+
+const (
+	ReadSubscriptions  Action = "subscriptions:read"
+	WriteSubscriptions Action = "subscriptions:write" // Create, change, pause and delete
+	ReadCosts          Action = "costs:read"
+	ReadRates          Action = "currency_rates:read"
+	WriteRates         Action = "currency_rates:write"
+	ManageAPIKeys      Action = "api_keys:manage"
+	ReadAudit          Action = "audit:read" // The audit log of all subscriptions, see ReadSubscriptions for one
+)
+
+func (a Action) IsValid() bool {
+	switch a {
+	case ReadSubscriptions, WriteSubscriptions, ReadCosts, ReadRates, WriteRates, ManageAPIKeys, ReadAudit:
+		return true
+	default:
+		return false
+	}
+}
+
+//go:generate mockery
+type Authorizer interface {
+	// The principal of ctx is the caller. Both methods fail with ErrDenied when
+	// it may not perform action.
+
+	// Authorize checks action on the subscriptions of userID.
+	Authorize(ctx context.Context, action Action, userID uuid.UUID) error
+	// AuthorizeAll checks action on the subscriptions of all users. Actions on
+	// what no user owns, like currency rates, need it too.
+	AuthorizeAll(ctx context.Context, action Action) error
+}
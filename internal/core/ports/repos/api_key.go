@@ -0,0 +1,20 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+)
+
+//go:generate mockery
+type APIKeyRepository interface {
+	// Create stores key and sets its ID and CreatedAt.
+	Create(ctx context.Context, key *domain.APIKey) error
+	List(ctx context.Context) ([]domain.APIKey, error)
+	// Use returns the live key with hash and records it was used now. Unknown,
+	// expired and revoked keys fail with KindNotFound.
+	Use(ctx context.Context, hash string) (*domain.APIKey, error)
+	// Revoke fails with KindNotFound for unknown and already revoked keys.
+	Revoke(ctx context.Context, id uuid.UUID) error
+}
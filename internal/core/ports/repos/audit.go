@@ -0,0 +1,17 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+)
+
+//go:generate mockery
+type AuditRepository interface {
+	// Record appends entry and sets its ID and CreatedAt.
+	Record(ctx context.Context, entry *domain.AuditEntry) error
+	// ListBySubscription returns the entries of the subscription, oldest first.
+	ListBySubscription(ctx context.Context, subID uuid.UUID) ([]domain.AuditEntry, error)
+	List(ctx context.Context, filter domain.AuditFilter) (*domain.AuditPage, error)
+}
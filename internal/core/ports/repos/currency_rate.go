@@ -0,0 +1,15 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+)
+
+//go:generate mockery
+type CurrencyRateRepository interface {
+	Upsert(ctx context.Context, rate *domain.CurrencyRate) error
+	Get(ctx context.Context, from, to string) (*domain.CurrencyRate, error)
+	List(ctx context.Context) ([]domain.CurrencyRate, error)
+	Delete(ctx context.Context, from, to string) error
+}
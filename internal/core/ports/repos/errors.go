@@ -9,6 +9,7 @@ const (
 	KindNotFound
 	KindDuplicate
 	KindInvalidArgument
+	KindConflict
 )
 
 func (k RepoKind) String() string {
@@ -19,6 +20,8 @@ func (k RepoKind) String() string {
 		return "Duplicate"
 	case KindInvalidArgument:
 		return "InvalidArgument"
+	case KindConflict:
+		return "Conflict"
 	default:
 		return "Unknown"
 	}
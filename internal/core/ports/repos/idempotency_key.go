@@ -0,0 +1,18 @@
+package repos
+
+import (
+	"context"
+
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+)
+
+//go:generate mockery
+type IdempotencyKeyRepository interface {
+	// Reserve stores key until it expires, unless a live record with the same
+	// Key and Caller exists, which is returned instead. A reserved key is nil.
+	Reserve(ctx context.Context, key domain.IdempotencyKey) (*domain.IdempotencyRecord, error)
+	// Complete stores the response to the request that reserved key.
+	Complete(ctx context.Context, key domain.IdempotencyKey, response *domain.Subscription) error
+	// PurgeExpired deletes the expired records and returns their number.
+	PurgeExpired(ctx context.Context) (int64, error)
+}
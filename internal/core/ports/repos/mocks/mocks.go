@@ -6,19 +6,21 @@ package reposmocks
 
 import (
 	"context"
+	"iter"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shrtyk/subscriptions-service/internal/core/domain"
 	mock "github.com/stretchr/testify/mock"
 )
 
-// NewMockSubscriptionRepository creates a new instance of MockSubscriptionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// NewMockAPIKeyRepository creates a new instance of MockAPIKeyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
-func NewMockSubscriptionRepository(t interface {
+func NewMockAPIKeyRepository(t interface {
 	mock.TestingT
 	Cleanup(func())
-}) *MockSubscriptionRepository {
-	mock := &MockSubscriptionRepository{}
+}) *MockAPIKeyRepository {
+	mock := &MockAPIKeyRepository{}
 	mock.Mock.Test(t)
 
 	t.Cleanup(func() { mock.AssertExpectations(t) })
@@ -26,57 +28,57 @@ func NewMockSubscriptionRepository(t interface {
 	return mock
 }
 
-// MockSubscriptionRepository is an autogenerated mock type for the SubscriptionRepository type
-type MockSubscriptionRepository struct {
+// MockAPIKeyRepository is an autogenerated mock type for the APIKeyRepository type
+type MockAPIKeyRepository struct {
 	mock.Mock
 }
 
-type MockSubscriptionRepository_Expecter struct {
+type MockAPIKeyRepository_Expecter struct {
 	mock *mock.Mock
 }
 
-func (_m *MockSubscriptionRepository) EXPECT() *MockSubscriptionRepository_Expecter {
-	return &MockSubscriptionRepository_Expecter{mock: &_m.Mock}
+func (_m *MockAPIKeyRepository) EXPECT() *MockAPIKeyRepository_Expecter {
+	return &MockAPIKeyRepository_Expecter{mock: &_m.Mock}
 }
 
-// Create provides a mock function for the type MockSubscriptionRepository
-func (_mock *MockSubscriptionRepository) Create(ctx context.Context, sub *domain.Subscription) error {
-	ret := _mock.Called(ctx, sub)
+// Create provides a mock function for the type MockAPIKeyRepository
+func (_mock *MockAPIKeyRepository) Create(ctx context.Context, key *domain.APIKey) error {
+	ret := _mock.Called(ctx, key)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Create")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.Subscription) error); ok {
-		r0 = returnFunc(ctx, sub)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.APIKey) error); ok {
+		r0 = returnFunc(ctx, key)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockSubscriptionRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
-type MockSubscriptionRepository_Create_Call struct {
+// MockAPIKeyRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockAPIKeyRepository_Create_Call struct {
 	*mock.Call
 }
 
 // Create is a helper method to define mock.On call
 //   - ctx context.Context
-//   - sub *domain.Subscription
-func (_e *MockSubscriptionRepository_Expecter) Create(ctx interface{}, sub interface{}) *MockSubscriptionRepository_Create_Call {
-	return &MockSubscriptionRepository_Create_Call{Call: _e.mock.On("Create", ctx, sub)}
+//   - key *domain.APIKey
+func (_e *MockAPIKeyRepository_Expecter) Create(ctx interface{}, key interface{}) *MockAPIKeyRepository_Create_Call {
+	return &MockAPIKeyRepository_Create_Call{Call: _e.mock.On("Create", ctx, key)}
 }
 
-func (_c *MockSubscriptionRepository_Create_Call) Run(run func(ctx context.Context, sub *domain.Subscription)) *MockSubscriptionRepository_Create_Call {
+func (_c *MockAPIKeyRepository_Create_Call) Run(run func(ctx context.Context, key *domain.APIKey)) *MockAPIKeyRepository_Create_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *domain.Subscription
+		var arg1 *domain.APIKey
 		if args[1] != nil {
-			arg1 = args[1].(*domain.Subscription)
+			arg1 = args[1].(*domain.APIKey)
 		}
 		run(
 			arg0,
@@ -86,22 +88,84 @@ func (_c *MockSubscriptionRepository_Create_Call) Run(run func(ctx context.Conte
 	return _c
 }
 
-func (_c *MockSubscriptionRepository_Create_Call) Return(err error) *MockSubscriptionRepository_Create_Call {
+func (_c *MockAPIKeyRepository_Create_Call) Return(err error) *MockAPIKeyRepository_Create_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockSubscriptionRepository_Create_Call) RunAndReturn(run func(ctx context.Context, sub *domain.Subscription) error) *MockSubscriptionRepository_Create_Call {
+func (_c *MockAPIKeyRepository_Create_Call) RunAndReturn(run func(ctx context.Context, key *domain.APIKey) error) *MockAPIKeyRepository_Create_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Delete provides a mock function for the type MockSubscriptionRepository
-func (_mock *MockSubscriptionRepository) Delete(ctx context.Context, id uuid.UUID) error {
+// List provides a mock function for the type MockAPIKeyRepository
+func (_mock *MockAPIKeyRepository) List(ctx context.Context) ([]domain.APIKey, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []domain.APIKey
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]domain.APIKey, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []domain.APIKey); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.APIKey)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockAPIKeyRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockAPIKeyRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockAPIKeyRepository_Expecter) List(ctx interface{}) *MockAPIKeyRepository_List_Call {
+	return &MockAPIKeyRepository_List_Call{Call: _e.mock.On("List", ctx)}
+}
+
+func (_c *MockAPIKeyRepository_List_Call) Run(run func(ctx context.Context)) *MockAPIKeyRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAPIKeyRepository_List_Call) Return(aPIKeys []domain.APIKey, err error) *MockAPIKeyRepository_List_Call {
+	_c.Call.Return(aPIKeys, err)
+	return _c
+}
+
+func (_c *MockAPIKeyRepository_List_Call) RunAndReturn(run func(ctx context.Context) ([]domain.APIKey, error)) *MockAPIKeyRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Revoke provides a mock function for the type MockAPIKeyRepository
+func (_mock *MockAPIKeyRepository) Revoke(ctx context.Context, id uuid.UUID) error {
 	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Delete")
+		panic("no return value specified for Revoke")
 	}
 
 	var r0 error
@@ -113,19 +177,19 @@ func (_mock *MockSubscriptionRepository) Delete(ctx context.Context, id uuid.UUI
 	return r0
 }
 
-// MockSubscriptionRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
-type MockSubscriptionRepository_Delete_Call struct {
+// MockAPIKeyRepository_Revoke_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Revoke'
+type MockAPIKeyRepository_Revoke_Call struct {
 	*mock.Call
 }
 
-// Delete is a helper method to define mock.On call
+// Revoke is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id uuid.UUID
-func (_e *MockSubscriptionRepository_Expecter) Delete(ctx interface{}, id interface{}) *MockSubscriptionRepository_Delete_Call {
-	return &MockSubscriptionRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, id)}
+func (_e *MockAPIKeyRepository_Expecter) Revoke(ctx interface{}, id interface{}) *MockAPIKeyRepository_Revoke_Call {
+	return &MockAPIKeyRepository_Revoke_Call{Call: _e.mock.On("Revoke", ctx, id)}
 }
 
-func (_c *MockSubscriptionRepository_Delete_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockSubscriptionRepository_Delete_Call {
+func (_c *MockAPIKeyRepository_Revoke_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockAPIKeyRepository_Revoke_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -143,65 +207,65 @@ func (_c *MockSubscriptionRepository_Delete_Call) Run(run func(ctx context.Conte
 	return _c
 }
 
-func (_c *MockSubscriptionRepository_Delete_Call) Return(err error) *MockSubscriptionRepository_Delete_Call {
+func (_c *MockAPIKeyRepository_Revoke_Call) Return(err error) *MockAPIKeyRepository_Revoke_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockSubscriptionRepository_Delete_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) error) *MockSubscriptionRepository_Delete_Call {
+func (_c *MockAPIKeyRepository_Revoke_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) error) *MockAPIKeyRepository_Revoke_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetByID provides a mock function for the type MockSubscriptionRepository
-func (_mock *MockSubscriptionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Subscription, error) {
-	ret := _mock.Called(ctx, id)
+// Use provides a mock function for the type MockAPIKeyRepository
+func (_mock *MockAPIKeyRepository) Use(ctx context.Context, hash string) (*domain.APIKey, error) {
+	ret := _mock.Called(ctx, hash)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetByID")
+		panic("no return value specified for Use")
 	}
 
-	var r0 *domain.Subscription
+	var r0 *domain.APIKey
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*domain.Subscription, error)); ok {
-		return returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*domain.APIKey, error)); ok {
+		return returnFunc(ctx, hash)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) *domain.Subscription); ok {
-		r0 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *domain.APIKey); ok {
+		r0 = returnFunc(ctx, hash)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.Subscription)
+			r0 = ret.Get(0).(*domain.APIKey)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, hash)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockSubscriptionRepository_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
-type MockSubscriptionRepository_GetByID_Call struct {
+// MockAPIKeyRepository_Use_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Use'
+type MockAPIKeyRepository_Use_Call struct {
 	*mock.Call
 }
 
-// GetByID is a helper method to define mock.On call
+// Use is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id uuid.UUID
-func (_e *MockSubscriptionRepository_Expecter) GetByID(ctx interface{}, id interface{}) *MockSubscriptionRepository_GetByID_Call {
-	return &MockSubscriptionRepository_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
+//   - hash string
+func (_e *MockAPIKeyRepository_Expecter) Use(ctx interface{}, hash interface{}) *MockAPIKeyRepository_Use_Call {
+	return &MockAPIKeyRepository_Use_Call{Call: _e.mock.On("Use", ctx, hash)}
 }
 
-func (_c *MockSubscriptionRepository_GetByID_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockSubscriptionRepository_GetByID_Call {
+func (_c *MockAPIKeyRepository_Use_Call) Run(run func(ctx context.Context, hash string)) *MockAPIKeyRepository_Use_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 uuid.UUID
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(uuid.UUID)
+			arg1 = args[1].(string)
 		}
 		run(
 			arg0,
@@ -211,37 +275,64 @@ func (_c *MockSubscriptionRepository_GetByID_Call) Run(run func(ctx context.Cont
 	return _c
 }
 
-func (_c *MockSubscriptionRepository_GetByID_Call) Return(subscription *domain.Subscription, err error) *MockSubscriptionRepository_GetByID_Call {
-	_c.Call.Return(subscription, err)
+func (_c *MockAPIKeyRepository_Use_Call) Return(aPIKey *domain.APIKey, err error) *MockAPIKeyRepository_Use_Call {
+	_c.Call.Return(aPIKey, err)
 	return _c
 }
 
-func (_c *MockSubscriptionRepository_GetByID_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) (*domain.Subscription, error)) *MockSubscriptionRepository_GetByID_Call {
+func (_c *MockAPIKeyRepository_Use_Call) RunAndReturn(run func(ctx context.Context, hash string) (*domain.APIKey, error)) *MockAPIKeyRepository_Use_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// List provides a mock function for the type MockSubscriptionRepository
-func (_mock *MockSubscriptionRepository) List(ctx context.Context, filter domain.SubscriptionFilter) ([]domain.Subscription, error) {
+// NewMockAuditRepository creates a new instance of MockAuditRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockAuditRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockAuditRepository {
+	mock := &MockAuditRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockAuditRepository is an autogenerated mock type for the AuditRepository type
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+type MockAuditRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockAuditRepository) EXPECT() *MockAuditRepository_Expecter {
+	return &MockAuditRepository_Expecter{mock: &_m.Mock}
+}
+
+// List provides a mock function for the type MockAuditRepository
+func (_mock *MockAuditRepository) List(ctx context.Context, filter domain.AuditFilter) (*domain.AuditPage, error) {
 	ret := _mock.Called(ctx, filter)
 
 	if len(ret) == 0 {
 		panic("no return value specified for List")
 	}
 
-	var r0 []domain.Subscription
+	var r0 *domain.AuditPage
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter) ([]domain.Subscription, error)); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.AuditFilter) (*domain.AuditPage, error)); ok {
 		return returnFunc(ctx, filter)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter) []domain.Subscription); ok {
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.AuditFilter) *domain.AuditPage); ok {
 		r0 = returnFunc(ctx, filter)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]domain.Subscription)
+			r0 = ret.Get(0).(*domain.AuditPage)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.SubscriptionFilter) error); ok {
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.AuditFilter) error); ok {
 		r1 = returnFunc(ctx, filter)
 	} else {
 		r1 = ret.Error(1)
@@ -249,27 +340,27 @@ func (_mock *MockSubscriptionRepository) List(ctx context.Context, filter domain
 	return r0, r1
 }
 
-// MockSubscriptionRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
-type MockSubscriptionRepository_List_Call struct {
+// MockAuditRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockAuditRepository_List_Call struct {
 	*mock.Call
 }
 
 // List is a helper method to define mock.On call
 //   - ctx context.Context
-//   - filter domain.SubscriptionFilter
-func (_e *MockSubscriptionRepository_Expecter) List(ctx interface{}, filter interface{}) *MockSubscriptionRepository_List_Call {
-	return &MockSubscriptionRepository_List_Call{Call: _e.mock.On("List", ctx, filter)}
+//   - filter domain.AuditFilter
+func (_e *MockAuditRepository_Expecter) List(ctx interface{}, filter interface{}) *MockAuditRepository_List_Call {
+	return &MockAuditRepository_List_Call{Call: _e.mock.On("List", ctx, filter)}
 }
 
-func (_c *MockSubscriptionRepository_List_Call) Run(run func(ctx context.Context, filter domain.SubscriptionFilter)) *MockSubscriptionRepository_List_Call {
+func (_c *MockAuditRepository_List_Call) Run(run func(ctx context.Context, filter domain.AuditFilter)) *MockAuditRepository_List_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 domain.SubscriptionFilter
+		var arg1 domain.AuditFilter
 		if args[1] != nil {
-			arg1 = args[1].(domain.SubscriptionFilter)
+			arg1 = args[1].(domain.AuditFilter)
 		}
 		run(
 			arg0,
@@ -279,65 +370,65 @@ func (_c *MockSubscriptionRepository_List_Call) Run(run func(ctx context.Context
 	return _c
 }
 
-func (_c *MockSubscriptionRepository_List_Call) Return(subscriptions []domain.Subscription, err error) *MockSubscriptionRepository_List_Call {
-	_c.Call.Return(subscriptions, err)
+func (_c *MockAuditRepository_List_Call) Return(auditPage *domain.AuditPage, err error) *MockAuditRepository_List_Call {
+	_c.Call.Return(auditPage, err)
 	return _c
 }
 
-func (_c *MockSubscriptionRepository_List_Call) RunAndReturn(run func(ctx context.Context, filter domain.SubscriptionFilter) ([]domain.Subscription, error)) *MockSubscriptionRepository_List_Call {
+func (_c *MockAuditRepository_List_Call) RunAndReturn(run func(ctx context.Context, filter domain.AuditFilter) (*domain.AuditPage, error)) *MockAuditRepository_List_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// ListAll provides a mock function for the type MockSubscriptionRepository
-func (_mock *MockSubscriptionRepository) ListAll(ctx context.Context, filter domain.SubscriptionFilter) ([]domain.Subscription, error) {
-	ret := _mock.Called(ctx, filter)
+// ListBySubscription provides a mock function for the type MockAuditRepository
+func (_mock *MockAuditRepository) ListBySubscription(ctx context.Context, subID uuid.UUID) ([]domain.AuditEntry, error) {
+	ret := _mock.Called(ctx, subID)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListAll")
+		panic("no return value specified for ListBySubscription")
 	}
 
-	var r0 []domain.Subscription
+	var r0 []domain.AuditEntry
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter) ([]domain.Subscription, error)); ok {
-		return returnFunc(ctx, filter)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.AuditEntry, error)); ok {
+		return returnFunc(ctx, subID)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter) []domain.Subscription); ok {
-		r0 = returnFunc(ctx, filter)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) []domain.AuditEntry); ok {
+		r0 = returnFunc(ctx, subID)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]domain.Subscription)
+			r0 = ret.Get(0).([]domain.AuditEntry)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.SubscriptionFilter) error); ok {
-		r1 = returnFunc(ctx, filter)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, subID)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockSubscriptionRepository_ListAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAll'
-type MockSubscriptionRepository_ListAll_Call struct {
+// MockAuditRepository_ListBySubscription_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListBySubscription'
+type MockAuditRepository_ListBySubscription_Call struct {
 	*mock.Call
 }
 
-// ListAll is a helper method to define mock.On call
+// ListBySubscription is a helper method to define mock.On call
 //   - ctx context.Context
-//   - filter domain.SubscriptionFilter
-func (_e *MockSubscriptionRepository_Expecter) ListAll(ctx interface{}, filter interface{}) *MockSubscriptionRepository_ListAll_Call {
-	return &MockSubscriptionRepository_ListAll_Call{Call: _e.mock.On("ListAll", ctx, filter)}
+//   - subID uuid.UUID
+func (_e *MockAuditRepository_Expecter) ListBySubscription(ctx interface{}, subID interface{}) *MockAuditRepository_ListBySubscription_Call {
+	return &MockAuditRepository_ListBySubscription_Call{Call: _e.mock.On("ListBySubscription", ctx, subID)}
 }
 
-func (_c *MockSubscriptionRepository_ListAll_Call) Run(run func(ctx context.Context, filter domain.SubscriptionFilter)) *MockSubscriptionRepository_ListAll_Call {
+func (_c *MockAuditRepository_ListBySubscription_Call) Run(run func(ctx context.Context, subID uuid.UUID)) *MockAuditRepository_ListBySubscription_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 domain.SubscriptionFilter
+		var arg1 uuid.UUID
 		if args[1] != nil {
-			arg1 = args[1].(domain.SubscriptionFilter)
+			arg1 = args[1].(uuid.UUID)
 		}
 		run(
 			arg0,
@@ -347,12 +438,1798 @@ func (_c *MockSubscriptionRepository_ListAll_Call) Run(run func(ctx context.Cont
 	return _c
 }
 
-func (_c *MockSubscriptionRepository_ListAll_Call) Return(subscriptions []domain.Subscription, err error) *MockSubscriptionRepository_ListAll_Call {
-	_c.Call.Return(subscriptions, err)
+func (_c *MockAuditRepository_ListBySubscription_Call) Return(auditEntrys []domain.AuditEntry, err error) *MockAuditRepository_ListBySubscription_Call {
+	_c.Call.Return(auditEntrys, err)
 	return _c
 }
 
-func (_c *MockSubscriptionRepository_ListAll_Call) RunAndReturn(run func(ctx context.Context, filter domain.SubscriptionFilter) ([]domain.Subscription, error)) *MockSubscriptionRepository_ListAll_Call {
+func (_c *MockAuditRepository_ListBySubscription_Call) RunAndReturn(run func(ctx context.Context, subID uuid.UUID) ([]domain.AuditEntry, error)) *MockAuditRepository_ListBySubscription_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Record provides a mock function for the type MockAuditRepository
+func (_mock *MockAuditRepository) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	ret := _mock.Called(ctx, entry)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Record")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.AuditEntry) error); ok {
+		r0 = returnFunc(ctx, entry)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockAuditRepository_Record_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Record'
+type MockAuditRepository_Record_Call struct {
+	*mock.Call
+}
+
+// Record is a helper method to define mock.On call
+//   - ctx context.Context
+//   - entry *domain.AuditEntry
+func (_e *MockAuditRepository_Expecter) Record(ctx interface{}, entry interface{}) *MockAuditRepository_Record_Call {
+	return &MockAuditRepository_Record_Call{Call: _e.mock.On("Record", ctx, entry)}
+}
+
+func (_c *MockAuditRepository_Record_Call) Run(run func(ctx context.Context, entry *domain.AuditEntry)) *MockAuditRepository_Record_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.AuditEntry
+		if args[1] != nil {
+			arg1 = args[1].(*domain.AuditEntry)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockAuditRepository_Record_Call) Return(err error) *MockAuditRepository_Record_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockAuditRepository_Record_Call) RunAndReturn(run func(ctx context.Context, entry *domain.AuditEntry) error) *MockAuditRepository_Record_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockCurrencyRateRepository creates a new instance of MockCurrencyRateRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockCurrencyRateRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockCurrencyRateRepository {
+	mock := &MockCurrencyRateRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockCurrencyRateRepository is an autogenerated mock type for the CurrencyRateRepository type
+type MockCurrencyRateRepository struct {
+	mock.Mock
+}
+
+type MockCurrencyRateRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockCurrencyRateRepository) EXPECT() *MockCurrencyRateRepository_Expecter {
+	return &MockCurrencyRateRepository_Expecter{mock: &_m.Mock}
+}
+
+// Delete provides a mock function for the type MockCurrencyRateRepository
+func (_mock *MockCurrencyRateRepository) Delete(ctx context.Context, from string, to string) error {
+	ret := _mock.Called(ctx, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, from, to)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCurrencyRateRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockCurrencyRateRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - from string
+//   - to string
+func (_e *MockCurrencyRateRepository_Expecter) Delete(ctx interface{}, from interface{}, to interface{}) *MockCurrencyRateRepository_Delete_Call {
+	return &MockCurrencyRateRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, from, to)}
+}
+
+func (_c *MockCurrencyRateRepository_Delete_Call) Run(run func(ctx context.Context, from string, to string)) *MockCurrencyRateRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCurrencyRateRepository_Delete_Call) Return(err error) *MockCurrencyRateRepository_Delete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCurrencyRateRepository_Delete_Call) RunAndReturn(run func(ctx context.Context, from string, to string) error) *MockCurrencyRateRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Get provides a mock function for the type MockCurrencyRateRepository
+func (_mock *MockCurrencyRateRepository) Get(ctx context.Context, from string, to string) (*domain.CurrencyRate, error) {
+	ret := _mock.Called(ctx, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Get")
+	}
+
+	var r0 *domain.CurrencyRate
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) (*domain.CurrencyRate, error)); ok {
+		return returnFunc(ctx, from, to)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) *domain.CurrencyRate); ok {
+		r0 = returnFunc(ctx, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.CurrencyRate)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = returnFunc(ctx, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCurrencyRateRepository_Get_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Get'
+type MockCurrencyRateRepository_Get_Call struct {
+	*mock.Call
+}
+
+// Get is a helper method to define mock.On call
+//   - ctx context.Context
+//   - from string
+//   - to string
+func (_e *MockCurrencyRateRepository_Expecter) Get(ctx interface{}, from interface{}, to interface{}) *MockCurrencyRateRepository_Get_Call {
+	return &MockCurrencyRateRepository_Get_Call{Call: _e.mock.On("Get", ctx, from, to)}
+}
+
+func (_c *MockCurrencyRateRepository_Get_Call) Run(run func(ctx context.Context, from string, to string)) *MockCurrencyRateRepository_Get_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCurrencyRateRepository_Get_Call) Return(currencyRate *domain.CurrencyRate, err error) *MockCurrencyRateRepository_Get_Call {
+	_c.Call.Return(currencyRate, err)
+	return _c
+}
+
+func (_c *MockCurrencyRateRepository_Get_Call) RunAndReturn(run func(ctx context.Context, from string, to string) (*domain.CurrencyRate, error)) *MockCurrencyRateRepository_Get_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function for the type MockCurrencyRateRepository
+func (_mock *MockCurrencyRateRepository) List(ctx context.Context) ([]domain.CurrencyRate, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 []domain.CurrencyRate
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]domain.CurrencyRate, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []domain.CurrencyRate); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.CurrencyRate)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockCurrencyRateRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockCurrencyRateRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockCurrencyRateRepository_Expecter) List(ctx interface{}) *MockCurrencyRateRepository_List_Call {
+	return &MockCurrencyRateRepository_List_Call{Call: _e.mock.On("List", ctx)}
+}
+
+func (_c *MockCurrencyRateRepository_List_Call) Run(run func(ctx context.Context)) *MockCurrencyRateRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCurrencyRateRepository_List_Call) Return(currencyRates []domain.CurrencyRate, err error) *MockCurrencyRateRepository_List_Call {
+	_c.Call.Return(currencyRates, err)
+	return _c
+}
+
+func (_c *MockCurrencyRateRepository_List_Call) RunAndReturn(run func(ctx context.Context) ([]domain.CurrencyRate, error)) *MockCurrencyRateRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Upsert provides a mock function for the type MockCurrencyRateRepository
+func (_mock *MockCurrencyRateRepository) Upsert(ctx context.Context, rate *domain.CurrencyRate) error {
+	ret := _mock.Called(ctx, rate)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Upsert")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.CurrencyRate) error); ok {
+		r0 = returnFunc(ctx, rate)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockCurrencyRateRepository_Upsert_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Upsert'
+type MockCurrencyRateRepository_Upsert_Call struct {
+	*mock.Call
+}
+
+// Upsert is a helper method to define mock.On call
+//   - ctx context.Context
+//   - rate *domain.CurrencyRate
+func (_e *MockCurrencyRateRepository_Expecter) Upsert(ctx interface{}, rate interface{}) *MockCurrencyRateRepository_Upsert_Call {
+	return &MockCurrencyRateRepository_Upsert_Call{Call: _e.mock.On("Upsert", ctx, rate)}
+}
+
+func (_c *MockCurrencyRateRepository_Upsert_Call) Run(run func(ctx context.Context, rate *domain.CurrencyRate)) *MockCurrencyRateRepository_Upsert_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.CurrencyRate
+		if args[1] != nil {
+			arg1 = args[1].(*domain.CurrencyRate)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockCurrencyRateRepository_Upsert_Call) Return(err error) *MockCurrencyRateRepository_Upsert_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockCurrencyRateRepository_Upsert_Call) RunAndReturn(run func(ctx context.Context, rate *domain.CurrencyRate) error) *MockCurrencyRateRepository_Upsert_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockIdempotencyKeyRepository creates a new instance of MockIdempotencyKeyRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockIdempotencyKeyRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockIdempotencyKeyRepository {
+	mock := &MockIdempotencyKeyRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockIdempotencyKeyRepository is an autogenerated mock type for the IdempotencyKeyRepository type
+type MockIdempotencyKeyRepository struct {
+	mock.Mock
+}
+
+type MockIdempotencyKeyRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockIdempotencyKeyRepository) EXPECT() *MockIdempotencyKeyRepository_Expecter {
+	return &MockIdempotencyKeyRepository_Expecter{mock: &_m.Mock}
+}
+
+// Complete provides a mock function for the type MockIdempotencyKeyRepository
+func (_mock *MockIdempotencyKeyRepository) Complete(ctx context.Context, key domain.IdempotencyKey, response *domain.Subscription) error {
+	ret := _mock.Called(ctx, key, response)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Complete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.IdempotencyKey, *domain.Subscription) error); ok {
+		r0 = returnFunc(ctx, key, response)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockIdempotencyKeyRepository_Complete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Complete'
+type MockIdempotencyKeyRepository_Complete_Call struct {
+	*mock.Call
+}
+
+// Complete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key domain.IdempotencyKey
+//   - response *domain.Subscription
+func (_e *MockIdempotencyKeyRepository_Expecter) Complete(ctx interface{}, key interface{}, response interface{}) *MockIdempotencyKeyRepository_Complete_Call {
+	return &MockIdempotencyKeyRepository_Complete_Call{Call: _e.mock.On("Complete", ctx, key, response)}
+}
+
+func (_c *MockIdempotencyKeyRepository_Complete_Call) Run(run func(ctx context.Context, key domain.IdempotencyKey, response *domain.Subscription)) *MockIdempotencyKeyRepository_Complete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 domain.IdempotencyKey
+		if args[1] != nil {
+			arg1 = args[1].(domain.IdempotencyKey)
+		}
+		var arg2 *domain.Subscription
+		if args[2] != nil {
+			arg2 = args[2].(*domain.Subscription)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIdempotencyKeyRepository_Complete_Call) Return(err error) *MockIdempotencyKeyRepository_Complete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockIdempotencyKeyRepository_Complete_Call) RunAndReturn(run func(ctx context.Context, key domain.IdempotencyKey, response *domain.Subscription) error) *MockIdempotencyKeyRepository_Complete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// PurgeExpired provides a mock function for the type MockIdempotencyKeyRepository
+func (_mock *MockIdempotencyKeyRepository) PurgeExpired(ctx context.Context) (int64, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for PurgeExpired")
+	}
+
+	var r0 int64
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) (int64, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) int64); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIdempotencyKeyRepository_PurgeExpired_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'PurgeExpired'
+type MockIdempotencyKeyRepository_PurgeExpired_Call struct {
+	*mock.Call
+}
+
+// PurgeExpired is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockIdempotencyKeyRepository_Expecter) PurgeExpired(ctx interface{}) *MockIdempotencyKeyRepository_PurgeExpired_Call {
+	return &MockIdempotencyKeyRepository_PurgeExpired_Call{Call: _e.mock.On("PurgeExpired", ctx)}
+}
+
+func (_c *MockIdempotencyKeyRepository_PurgeExpired_Call) Run(run func(ctx context.Context)) *MockIdempotencyKeyRepository_PurgeExpired_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIdempotencyKeyRepository_PurgeExpired_Call) Return(n int64, err error) *MockIdempotencyKeyRepository_PurgeExpired_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockIdempotencyKeyRepository_PurgeExpired_Call) RunAndReturn(run func(ctx context.Context) (int64, error)) *MockIdempotencyKeyRepository_PurgeExpired_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Reserve provides a mock function for the type MockIdempotencyKeyRepository
+func (_mock *MockIdempotencyKeyRepository) Reserve(ctx context.Context, key domain.IdempotencyKey) (*domain.IdempotencyRecord, error) {
+	ret := _mock.Called(ctx, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reserve")
+	}
+
+	var r0 *domain.IdempotencyRecord
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.IdempotencyKey) (*domain.IdempotencyRecord, error)); ok {
+		return returnFunc(ctx, key)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.IdempotencyKey) *domain.IdempotencyRecord); ok {
+		r0 = returnFunc(ctx, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.IdempotencyRecord)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.IdempotencyKey) error); ok {
+		r1 = returnFunc(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockIdempotencyKeyRepository_Reserve_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reserve'
+type MockIdempotencyKeyRepository_Reserve_Call struct {
+	*mock.Call
+}
+
+// Reserve is a helper method to define mock.On call
+//   - ctx context.Context
+//   - key domain.IdempotencyKey
+func (_e *MockIdempotencyKeyRepository_Expecter) Reserve(ctx interface{}, key interface{}) *MockIdempotencyKeyRepository_Reserve_Call {
+	return &MockIdempotencyKeyRepository_Reserve_Call{Call: _e.mock.On("Reserve", ctx, key)}
+}
+
+func (_c *MockIdempotencyKeyRepository_Reserve_Call) Run(run func(ctx context.Context, key domain.IdempotencyKey)) *MockIdempotencyKeyRepository_Reserve_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 domain.IdempotencyKey
+		if args[1] != nil {
+			arg1 = args[1].(domain.IdempotencyKey)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockIdempotencyKeyRepository_Reserve_Call) Return(idempotencyRecord *domain.IdempotencyRecord, err error) *MockIdempotencyKeyRepository_Reserve_Call {
+	_c.Call.Return(idempotencyRecord, err)
+	return _c
+}
+
+func (_c *MockIdempotencyKeyRepository_Reserve_Call) RunAndReturn(run func(ctx context.Context, key domain.IdempotencyKey) (*domain.IdempotencyRecord, error)) *MockIdempotencyKeyRepository_Reserve_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// NewMockSubscriptionRepository creates a new instance of MockSubscriptionRepository. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
+// The first argument is typically a *testing.T value.
+func NewMockSubscriptionRepository(t interface {
+	mock.TestingT
+	Cleanup(func())
+}) *MockSubscriptionRepository {
+	mock := &MockSubscriptionRepository{}
+	mock.Mock.Test(t)
+
+	t.Cleanup(func() { mock.AssertExpectations(t) })
+
+	return mock
+}
+
+// MockSubscriptionRepository is an autogenerated mock type for the SubscriptionRepository type
+type MockSubscriptionRepository struct {
+	mock.Mock
+}
+
+type MockSubscriptionRepository_Expecter struct {
+	mock *mock.Mock
+}
+
+func (_m *MockSubscriptionRepository) EXPECT() *MockSubscriptionRepository_Expecter {
+	return &MockSubscriptionRepository_Expecter{mock: &_m.Mock}
+}
+
+// AddPause provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) AddPause(ctx context.Context, subID uuid.UUID, pause *domain.Pause) error {
+	ret := _mock.Called(ctx, subID, pause)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddPause")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *domain.Pause) error); ok {
+		r0 = returnFunc(ctx, subID, pause)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSubscriptionRepository_AddPause_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddPause'
+type MockSubscriptionRepository_AddPause_Call struct {
+	*mock.Call
+}
+
+// AddPause is a helper method to define mock.On call
+//   - ctx context.Context
+//   - subID uuid.UUID
+//   - pause *domain.Pause
+func (_e *MockSubscriptionRepository_Expecter) AddPause(ctx interface{}, subID interface{}, pause interface{}) *MockSubscriptionRepository_AddPause_Call {
+	return &MockSubscriptionRepository_AddPause_Call{Call: _e.mock.On("AddPause", ctx, subID, pause)}
+}
+
+func (_c *MockSubscriptionRepository_AddPause_Call) Run(run func(ctx context.Context, subID uuid.UUID, pause *domain.Pause)) *MockSubscriptionRepository_AddPause_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 *domain.Pause
+		if args[2] != nil {
+			arg2 = args[2].(*domain.Pause)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_AddPause_Call) Return(err error) *MockSubscriptionRepository_AddPause_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_AddPause_Call) RunAndReturn(run func(ctx context.Context, subID uuid.UUID, pause *domain.Pause) error) *MockSubscriptionRepository_AddPause_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// AddPriceChange provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) AddPriceChange(ctx context.Context, subID uuid.UUID, change *domain.PriceChange) error {
+	ret := _mock.Called(ctx, subID, change)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddPriceChange")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *domain.PriceChange) error); ok {
+		r0 = returnFunc(ctx, subID, change)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSubscriptionRepository_AddPriceChange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AddPriceChange'
+type MockSubscriptionRepository_AddPriceChange_Call struct {
+	*mock.Call
+}
+
+// AddPriceChange is a helper method to define mock.On call
+//   - ctx context.Context
+//   - subID uuid.UUID
+//   - change *domain.PriceChange
+func (_e *MockSubscriptionRepository_Expecter) AddPriceChange(ctx interface{}, subID interface{}, change interface{}) *MockSubscriptionRepository_AddPriceChange_Call {
+	return &MockSubscriptionRepository_AddPriceChange_Call{Call: _e.mock.On("AddPriceChange", ctx, subID, change)}
+}
+
+func (_c *MockSubscriptionRepository_AddPriceChange_Call) Run(run func(ctx context.Context, subID uuid.UUID, change *domain.PriceChange)) *MockSubscriptionRepository_AddPriceChange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 *domain.PriceChange
+		if args[2] != nil {
+			arg2 = args[2].(*domain.PriceChange)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_AddPriceChange_Call) Return(err error) *MockSubscriptionRepository_AddPriceChange_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_AddPriceChange_Call) RunAndReturn(run func(ctx context.Context, subID uuid.UUID, change *domain.PriceChange) error) *MockSubscriptionRepository_AddPriceChange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Count provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) Count(ctx context.Context, filter domain.SubscriptionFilter) (int, error) {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Count")
+	}
+
+	var r0 int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter) (int, error)); ok {
+		return returnFunc(ctx, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter) int); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		r0 = ret.Get(0).(int)
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.SubscriptionFilter) error); ok {
+		r1 = returnFunc(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionRepository_Count_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Count'
+type MockSubscriptionRepository_Count_Call struct {
+	*mock.Call
+}
+
+// Count is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter domain.SubscriptionFilter
+func (_e *MockSubscriptionRepository_Expecter) Count(ctx interface{}, filter interface{}) *MockSubscriptionRepository_Count_Call {
+	return &MockSubscriptionRepository_Count_Call{Call: _e.mock.On("Count", ctx, filter)}
+}
+
+func (_c *MockSubscriptionRepository_Count_Call) Run(run func(ctx context.Context, filter domain.SubscriptionFilter)) *MockSubscriptionRepository_Count_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 domain.SubscriptionFilter
+		if args[1] != nil {
+			arg1 = args[1].(domain.SubscriptionFilter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_Count_Call) Return(n int, err error) *MockSubscriptionRepository_Count_Call {
+	_c.Call.Return(n, err)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_Count_Call) RunAndReturn(run func(ctx context.Context, filter domain.SubscriptionFilter) (int, error)) *MockSubscriptionRepository_Count_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Create provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) Create(ctx context.Context, sub *domain.Subscription) error {
+	ret := _mock.Called(ctx, sub)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Create")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.Subscription) error); ok {
+		r0 = returnFunc(ctx, sub)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSubscriptionRepository_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockSubscriptionRepository_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sub *domain.Subscription
+func (_e *MockSubscriptionRepository_Expecter) Create(ctx interface{}, sub interface{}) *MockSubscriptionRepository_Create_Call {
+	return &MockSubscriptionRepository_Create_Call{Call: _e.mock.On("Create", ctx, sub)}
+}
+
+func (_c *MockSubscriptionRepository_Create_Call) Run(run func(ctx context.Context, sub *domain.Subscription)) *MockSubscriptionRepository_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.Subscription
+		if args[1] != nil {
+			arg1 = args[1].(*domain.Subscription)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_Create_Call) Return(err error) *MockSubscriptionRepository_Create_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_Create_Call) RunAndReturn(run func(ctx context.Context, sub *domain.Subscription) error) *MockSubscriptionRepository_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateBatch provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) CreateBatch(ctx context.Context, subs []*domain.Subscription) error {
+	ret := _mock.Called(ctx, subs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBatch")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []*domain.Subscription) error); ok {
+		r0 = returnFunc(ctx, subs)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSubscriptionRepository_CreateBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateBatch'
+type MockSubscriptionRepository_CreateBatch_Call struct {
+	*mock.Call
+}
+
+// CreateBatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - subs []*domain.Subscription
+func (_e *MockSubscriptionRepository_Expecter) CreateBatch(ctx interface{}, subs interface{}) *MockSubscriptionRepository_CreateBatch_Call {
+	return &MockSubscriptionRepository_CreateBatch_Call{Call: _e.mock.On("CreateBatch", ctx, subs)}
+}
+
+func (_c *MockSubscriptionRepository_CreateBatch_Call) Run(run func(ctx context.Context, subs []*domain.Subscription)) *MockSubscriptionRepository_CreateBatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []*domain.Subscription
+		if args[1] != nil {
+			arg1 = args[1].([]*domain.Subscription)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_CreateBatch_Call) Return(err error) *MockSubscriptionRepository_CreateBatch_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_CreateBatch_Call) RunAndReturn(run func(ctx context.Context, subs []*domain.Subscription) error) *MockSubscriptionRepository_CreateBatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) Delete(ctx context.Context, id uuid.UUID, version *int) (*domain.Subscription, error) {
+	ret := _mock.Called(ctx, id, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 *domain.Subscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *int) (*domain.Subscription, error)); ok {
+		return returnFunc(ctx, id, version)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *int) *domain.Subscription); ok {
+		r0 = returnFunc(ctx, id, version)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Subscription)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, *int) error); ok {
+		r1 = returnFunc(ctx, id, version)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionRepository_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockSubscriptionRepository_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - version *int
+func (_e *MockSubscriptionRepository_Expecter) Delete(ctx interface{}, id interface{}, version interface{}) *MockSubscriptionRepository_Delete_Call {
+	return &MockSubscriptionRepository_Delete_Call{Call: _e.mock.On("Delete", ctx, id, version)}
+}
+
+func (_c *MockSubscriptionRepository_Delete_Call) Run(run func(ctx context.Context, id uuid.UUID, version *int)) *MockSubscriptionRepository_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 *int
+		if args[2] != nil {
+			arg2 = args[2].(*int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_Delete_Call) Return(subscription *domain.Subscription, err error) *MockSubscriptionRepository_Delete_Call {
+	_c.Call.Return(subscription, err)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_Delete_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, version *int) (*domain.Subscription, error)) *MockSubscriptionRepository_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteBatch provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) DeleteBatch(ctx context.Context, ids []uuid.UUID, versions []int) ([]domain.Subscription, error) {
+	ret := _mock.Called(ctx, ids, versions)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBatch")
+	}
+
+	var r0 []domain.Subscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []uuid.UUID, []int) ([]domain.Subscription, error)); ok {
+		return returnFunc(ctx, ids, versions)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []uuid.UUID, []int) []domain.Subscription); ok {
+		r0 = returnFunc(ctx, ids, versions)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Subscription)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []uuid.UUID, []int) error); ok {
+		r1 = returnFunc(ctx, ids, versions)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionRepository_DeleteBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteBatch'
+type MockSubscriptionRepository_DeleteBatch_Call struct {
+	*mock.Call
+}
+
+// DeleteBatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []uuid.UUID
+//   - versions []int
+func (_e *MockSubscriptionRepository_Expecter) DeleteBatch(ctx interface{}, ids interface{}, versions interface{}) *MockSubscriptionRepository_DeleteBatch_Call {
+	return &MockSubscriptionRepository_DeleteBatch_Call{Call: _e.mock.On("DeleteBatch", ctx, ids, versions)}
+}
+
+func (_c *MockSubscriptionRepository_DeleteBatch_Call) Run(run func(ctx context.Context, ids []uuid.UUID, versions []int)) *MockSubscriptionRepository_DeleteBatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].([]uuid.UUID)
+		}
+		var arg2 []int
+		if args[2] != nil {
+			arg2 = args[2].([]int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_DeleteBatch_Call) Return(subscriptions []domain.Subscription, err error) *MockSubscriptionRepository_DeleteBatch_Call {
+	_c.Call.Return(subscriptions, err)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_DeleteBatch_Call) RunAndReturn(run func(ctx context.Context, ids []uuid.UUID, versions []int) ([]domain.Subscription, error)) *MockSubscriptionRepository_DeleteBatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// FindOverlapping provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) FindOverlapping(ctx context.Context, sub *domain.Subscription) (uuid.UUID, error) {
+	ret := _mock.Called(ctx, sub)
+
+	if len(ret) == 0 {
+		panic("no return value specified for FindOverlapping")
+	}
+
+	var r0 uuid.UUID
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.Subscription) (uuid.UUID, error)); ok {
+		return returnFunc(ctx, sub)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *domain.Subscription) uuid.UUID); ok {
+		r0 = returnFunc(ctx, sub)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(uuid.UUID)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, *domain.Subscription) error); ok {
+		r1 = returnFunc(ctx, sub)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionRepository_FindOverlapping_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'FindOverlapping'
+type MockSubscriptionRepository_FindOverlapping_Call struct {
+	*mock.Call
+}
+
+// FindOverlapping is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sub *domain.Subscription
+func (_e *MockSubscriptionRepository_Expecter) FindOverlapping(ctx interface{}, sub interface{}) *MockSubscriptionRepository_FindOverlapping_Call {
+	return &MockSubscriptionRepository_FindOverlapping_Call{Call: _e.mock.On("FindOverlapping", ctx, sub)}
+}
+
+func (_c *MockSubscriptionRepository_FindOverlapping_Call) Run(run func(ctx context.Context, sub *domain.Subscription)) *MockSubscriptionRepository_FindOverlapping_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 *domain.Subscription
+		if args[1] != nil {
+			arg1 = args[1].(*domain.Subscription)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_FindOverlapping_Call) Return(uUID uuid.UUID, err error) *MockSubscriptionRepository_FindOverlapping_Call {
+	_c.Call.Return(uUID, err)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_FindOverlapping_Call) RunAndReturn(run func(ctx context.Context, sub *domain.Subscription) (uuid.UUID, error)) *MockSubscriptionRepository_FindOverlapping_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.Subscription, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *domain.Subscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*domain.Subscription, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) *domain.Subscription); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Subscription)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionRepository_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type MockSubscriptionRepository_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockSubscriptionRepository_Expecter) GetByID(ctx interface{}, id interface{}) *MockSubscriptionRepository_GetByID_Call {
+	return &MockSubscriptionRepository_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
+}
+
+func (_c *MockSubscriptionRepository_GetByID_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockSubscriptionRepository_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_GetByID_Call) Return(subscription *domain.Subscription, err error) *MockSubscriptionRepository_GetByID_Call {
+	_c.Call.Return(subscription, err)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_GetByID_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) (*domain.Subscription, error)) *MockSubscriptionRepository_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) List(ctx context.Context, filter domain.SubscriptionFilter) (*domain.SubscriptionPage, error) {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 *domain.SubscriptionPage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter) (*domain.SubscriptionPage, error)); ok {
+		return returnFunc(ctx, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter) *domain.SubscriptionPage); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.SubscriptionPage)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.SubscriptionFilter) error); ok {
+		r1 = returnFunc(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionRepository_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockSubscriptionRepository_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter domain.SubscriptionFilter
+func (_e *MockSubscriptionRepository_Expecter) List(ctx interface{}, filter interface{}) *MockSubscriptionRepository_List_Call {
+	return &MockSubscriptionRepository_List_Call{Call: _e.mock.On("List", ctx, filter)}
+}
+
+func (_c *MockSubscriptionRepository_List_Call) Run(run func(ctx context.Context, filter domain.SubscriptionFilter)) *MockSubscriptionRepository_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 domain.SubscriptionFilter
+		if args[1] != nil {
+			arg1 = args[1].(domain.SubscriptionFilter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_List_Call) Return(subscriptionPage *domain.SubscriptionPage, err error) *MockSubscriptionRepository_List_Call {
+	_c.Call.Return(subscriptionPage, err)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_List_Call) RunAndReturn(run func(ctx context.Context, filter domain.SubscriptionFilter) (*domain.SubscriptionPage, error)) *MockSubscriptionRepository_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAll provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) ListAll(ctx context.Context, filter domain.SubscriptionFilter) ([]domain.Subscription, error) {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAll")
+	}
+
+	var r0 []domain.Subscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter) ([]domain.Subscription, error)); ok {
+		return returnFunc(ctx, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter) []domain.Subscription); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Subscription)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.SubscriptionFilter) error); ok {
+		r1 = returnFunc(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionRepository_ListAll_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAll'
+type MockSubscriptionRepository_ListAll_Call struct {
+	*mock.Call
+}
+
+// ListAll is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter domain.SubscriptionFilter
+func (_e *MockSubscriptionRepository_Expecter) ListAll(ctx interface{}, filter interface{}) *MockSubscriptionRepository_ListAll_Call {
+	return &MockSubscriptionRepository_ListAll_Call{Call: _e.mock.On("ListAll", ctx, filter)}
+}
+
+func (_c *MockSubscriptionRepository_ListAll_Call) Run(run func(ctx context.Context, filter domain.SubscriptionFilter)) *MockSubscriptionRepository_ListAll_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 domain.SubscriptionFilter
+		if args[1] != nil {
+			arg1 = args[1].(domain.SubscriptionFilter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_ListAll_Call) Return(subscriptions []domain.Subscription, err error) *MockSubscriptionRepository_ListAll_Call {
+	_c.Call.Return(subscriptions, err)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_ListAll_Call) RunAndReturn(run func(ctx context.Context, filter domain.SubscriptionFilter) ([]domain.Subscription, error)) *MockSubscriptionRepository_ListAll_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPauses provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) ListPauses(ctx context.Context, subID uuid.UUID) ([]domain.Pause, error) {
+	ret := _mock.Called(ctx, subID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPauses")
+	}
+
+	var r0 []domain.Pause
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.Pause, error)); ok {
+		return returnFunc(ctx, subID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) []domain.Pause); ok {
+		r0 = returnFunc(ctx, subID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Pause)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, subID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionRepository_ListPauses_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPauses'
+type MockSubscriptionRepository_ListPauses_Call struct {
+	*mock.Call
+}
+
+// ListPauses is a helper method to define mock.On call
+//   - ctx context.Context
+//   - subID uuid.UUID
+func (_e *MockSubscriptionRepository_Expecter) ListPauses(ctx interface{}, subID interface{}) *MockSubscriptionRepository_ListPauses_Call {
+	return &MockSubscriptionRepository_ListPauses_Call{Call: _e.mock.On("ListPauses", ctx, subID)}
+}
+
+func (_c *MockSubscriptionRepository_ListPauses_Call) Run(run func(ctx context.Context, subID uuid.UUID)) *MockSubscriptionRepository_ListPauses_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_ListPauses_Call) Return(pauses []domain.Pause, err error) *MockSubscriptionRepository_ListPauses_Call {
+	_c.Call.Return(pauses, err)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_ListPauses_Call) RunAndReturn(run func(ctx context.Context, subID uuid.UUID) ([]domain.Pause, error)) *MockSubscriptionRepository_ListPauses_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPriceChanges provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) ListPriceChanges(ctx context.Context, subID uuid.UUID) ([]domain.PriceChange, error) {
+	ret := _mock.Called(ctx, subID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPriceChanges")
+	}
+
+	var r0 []domain.PriceChange
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.PriceChange, error)); ok {
+		return returnFunc(ctx, subID)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) []domain.PriceChange); ok {
+		r0 = returnFunc(ctx, subID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.PriceChange)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, subID)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionRepository_ListPriceChanges_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPriceChanges'
+type MockSubscriptionRepository_ListPriceChanges_Call struct {
+	*mock.Call
+}
+
+// ListPriceChanges is a helper method to define mock.On call
+//   - ctx context.Context
+//   - subID uuid.UUID
+func (_e *MockSubscriptionRepository_Expecter) ListPriceChanges(ctx interface{}, subID interface{}) *MockSubscriptionRepository_ListPriceChanges_Call {
+	return &MockSubscriptionRepository_ListPriceChanges_Call{Call: _e.mock.On("ListPriceChanges", ctx, subID)}
+}
+
+func (_c *MockSubscriptionRepository_ListPriceChanges_Call) Run(run func(ctx context.Context, subID uuid.UUID)) *MockSubscriptionRepository_ListPriceChanges_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_ListPriceChanges_Call) Return(priceChanges []domain.PriceChange, err error) *MockSubscriptionRepository_ListPriceChanges_Call {
+	_c.Call.Return(priceChanges, err)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_ListPriceChanges_Call) RunAndReturn(run func(ctx context.Context, subID uuid.UUID) ([]domain.PriceChange, error)) *MockSubscriptionRepository_ListPriceChanges_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RebasePriceHistory provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) RebasePriceHistory(ctx context.Context, subID uuid.UUID, startMonth time.Time) error {
+	ret := _mock.Called(ctx, subID, startMonth)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RebasePriceHistory")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, time.Time) error); ok {
+		r0 = returnFunc(ctx, subID, startMonth)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSubscriptionRepository_RebasePriceHistory_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RebasePriceHistory'
+type MockSubscriptionRepository_RebasePriceHistory_Call struct {
+	*mock.Call
+}
+
+// RebasePriceHistory is a helper method to define mock.On call
+//   - ctx context.Context
+//   - subID uuid.UUID
+//   - startMonth time.Time
+func (_e *MockSubscriptionRepository_Expecter) RebasePriceHistory(ctx interface{}, subID interface{}, startMonth interface{}) *MockSubscriptionRepository_RebasePriceHistory_Call {
+	return &MockSubscriptionRepository_RebasePriceHistory_Call{Call: _e.mock.On("RebasePriceHistory", ctx, subID, startMonth)}
+}
+
+func (_c *MockSubscriptionRepository_RebasePriceHistory_Call) Run(run func(ctx context.Context, subID uuid.UUID, startMonth time.Time)) *MockSubscriptionRepository_RebasePriceHistory_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_RebasePriceHistory_Call) Return(err error) *MockSubscriptionRepository_RebasePriceHistory_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_RebasePriceHistory_Call) RunAndReturn(run func(ctx context.Context, subID uuid.UUID, startMonth time.Time) error) *MockSubscriptionRepository_RebasePriceHistory_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ResumePause provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) ResumePause(ctx context.Context, subID uuid.UUID, pause *domain.Pause) error {
+	ret := _mock.Called(ctx, subID, pause)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ResumePause")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *domain.Pause) error); ok {
+		r0 = returnFunc(ctx, subID, pause)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSubscriptionRepository_ResumePause_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ResumePause'
+type MockSubscriptionRepository_ResumePause_Call struct {
+	*mock.Call
+}
+
+// ResumePause is a helper method to define mock.On call
+//   - ctx context.Context
+//   - subID uuid.UUID
+//   - pause *domain.Pause
+func (_e *MockSubscriptionRepository_Expecter) ResumePause(ctx interface{}, subID interface{}, pause interface{}) *MockSubscriptionRepository_ResumePause_Call {
+	return &MockSubscriptionRepository_ResumePause_Call{Call: _e.mock.On("ResumePause", ctx, subID, pause)}
+}
+
+func (_c *MockSubscriptionRepository_ResumePause_Call) Run(run func(ctx context.Context, subID uuid.UUID, pause *domain.Pause)) *MockSubscriptionRepository_ResumePause_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 *domain.Pause
+		if args[2] != nil {
+			arg2 = args[2].(*domain.Pause)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_ResumePause_Call) Return(err error) *MockSubscriptionRepository_ResumePause_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_ResumePause_Call) RunAndReturn(run func(ctx context.Context, subID uuid.UUID, pause *domain.Pause) error) *MockSubscriptionRepository_ResumePause_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Stream provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) Stream(ctx context.Context, filter domain.SubscriptionFilter) iter.Seq2[domain.Subscription, error] {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Stream")
+	}
+
+	var r0 iter.Seq2[domain.Subscription, error]
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter) iter.Seq2[domain.Subscription, error]); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(iter.Seq2[domain.Subscription, error])
+		}
+	}
+	return r0
+}
+
+// MockSubscriptionRepository_Stream_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Stream'
+type MockSubscriptionRepository_Stream_Call struct {
+	*mock.Call
+}
+
+// Stream is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter domain.SubscriptionFilter
+func (_e *MockSubscriptionRepository_Expecter) Stream(ctx interface{}, filter interface{}) *MockSubscriptionRepository_Stream_Call {
+	return &MockSubscriptionRepository_Stream_Call{Call: _e.mock.On("Stream", ctx, filter)}
+}
+
+func (_c *MockSubscriptionRepository_Stream_Call) Run(run func(ctx context.Context, filter domain.SubscriptionFilter)) *MockSubscriptionRepository_Stream_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 domain.SubscriptionFilter
+		if args[1] != nil {
+			arg1 = args[1].(domain.SubscriptionFilter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_Stream_Call) Return(seq2 iter.Seq2[domain.Subscription, error]) *MockSubscriptionRepository_Stream_Call {
+	_c.Call.Return(seq2)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_Stream_Call) RunAndReturn(run func(ctx context.Context, filter domain.SubscriptionFilter) iter.Seq2[domain.Subscription, error]) *MockSubscriptionRepository_Stream_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// SuggestServiceNames provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) SuggestServiceNames(ctx context.Context, query string, limit *int) ([]string, error) {
+	ret := _mock.Called(ctx, query, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SuggestServiceNames")
+	}
+
+	var r0 []string
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *int) ([]string, error)); ok {
+		return returnFunc(ctx, query, limit)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *int) []string); ok {
+		r0 = returnFunc(ctx, query, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *int) error); ok {
+		r1 = returnFunc(ctx, query, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionRepository_SuggestServiceNames_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SuggestServiceNames'
+type MockSubscriptionRepository_SuggestServiceNames_Call struct {
+	*mock.Call
+}
+
+// SuggestServiceNames is a helper method to define mock.On call
+//   - ctx context.Context
+//   - query string
+//   - limit *int
+func (_e *MockSubscriptionRepository_Expecter) SuggestServiceNames(ctx interface{}, query interface{}, limit interface{}) *MockSubscriptionRepository_SuggestServiceNames_Call {
+	return &MockSubscriptionRepository_SuggestServiceNames_Call{Call: _e.mock.On("SuggestServiceNames", ctx, query, limit)}
+}
+
+func (_c *MockSubscriptionRepository_SuggestServiceNames_Call) Run(run func(ctx context.Context, query string, limit *int)) *MockSubscriptionRepository_SuggestServiceNames_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 *int
+		if args[2] != nil {
+			arg2 = args[2].(*int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_SuggestServiceNames_Call) Return(strings []string, err error) *MockSubscriptionRepository_SuggestServiceNames_Call {
+	_c.Call.Return(strings, err)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_SuggestServiceNames_Call) RunAndReturn(run func(ctx context.Context, query string, limit *int) ([]string, error)) *MockSubscriptionRepository_SuggestServiceNames_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// TotalCostByCurrency provides a mock function for the type MockSubscriptionRepository
+func (_mock *MockSubscriptionRepository) TotalCostByCurrency(ctx context.Context, filter domain.SubscriptionFilter, start time.Time, end time.Time) (map[string]int, error) {
+	ret := _mock.Called(ctx, filter, start, end)
+
+	if len(ret) == 0 {
+		panic("no return value specified for TotalCostByCurrency")
+	}
+
+	var r0 map[string]int
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter, time.Time, time.Time) (map[string]int, error)); ok {
+		return returnFunc(ctx, filter, start, end)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter, time.Time, time.Time) map[string]int); ok {
+		r0 = returnFunc(ctx, filter, start, end)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.SubscriptionFilter, time.Time, time.Time) error); ok {
+		r1 = returnFunc(ctx, filter, start, end)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionRepository_TotalCostByCurrency_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'TotalCostByCurrency'
+type MockSubscriptionRepository_TotalCostByCurrency_Call struct {
+	*mock.Call
+}
+
+// TotalCostByCurrency is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter domain.SubscriptionFilter
+//   - start time.Time
+//   - end time.Time
+func (_e *MockSubscriptionRepository_Expecter) TotalCostByCurrency(ctx interface{}, filter interface{}, start interface{}, end interface{}) *MockSubscriptionRepository_TotalCostByCurrency_Call {
+	return &MockSubscriptionRepository_TotalCostByCurrency_Call{Call: _e.mock.On("TotalCostByCurrency", ctx, filter, start, end)}
+}
+
+func (_c *MockSubscriptionRepository_TotalCostByCurrency_Call) Run(run func(ctx context.Context, filter domain.SubscriptionFilter, start time.Time, end time.Time)) *MockSubscriptionRepository_TotalCostByCurrency_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 domain.SubscriptionFilter
+		if args[1] != nil {
+			arg1 = args[1].(domain.SubscriptionFilter)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_TotalCostByCurrency_Call) Return(stringToInt map[string]int, err error) *MockSubscriptionRepository_TotalCostByCurrency_Call {
+	_c.Call.Return(stringToInt, err)
+	return _c
+}
+
+func (_c *MockSubscriptionRepository_TotalCostByCurrency_Call) RunAndReturn(run func(ctx context.Context, filter domain.SubscriptionFilter, start time.Time, end time.Time) (map[string]int, error)) *MockSubscriptionRepository_TotalCostByCurrency_Call {
 	_c.Call.Return(run)
 	return _c
 }
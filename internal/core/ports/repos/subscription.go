@@ -2,6 +2,8 @@ package repos
 
 import (
 	"context"
+	"iter"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shrtyk/subscriptions-service/internal/core/domain"
@@ -10,9 +12,38 @@ import (
 //go:generate mockery
 type SubscriptionRepository interface {
 	Create(ctx context.Context, sub *domain.Subscription) error
+	// CreateBatch creates subs with multi-row inserts in one transaction, so either all of them or none.
+	CreateBatch(ctx context.Context, subs []*domain.Subscription) error
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Subscription, error)
+	// Update stores sub if it is still at sub.Version and increments the version.
 	Update(ctx context.Context, sub *domain.Subscription) error
-	Delete(ctx context.Context, id uuid.UUID) error
-	List(ctx context.Context, filter domain.SubscriptionFilter) ([]domain.Subscription, error)
+	// FindOverlapping returns the id of another exclusive subscription of the same
+	// user and service whose dates overlap the ones of sub.
+	FindOverlapping(ctx context.Context, sub *domain.Subscription) (uuid.UUID, error)
+	// Delete deletes the subscription if it is at version, any version when nil,
+	// and returns it as it was.
+	Delete(ctx context.Context, id uuid.UUID, version *int) (*domain.Subscription, error)
+	// DeleteBatch deletes the subscriptions with ids at the versions of the same
+	// index and returns the ones that were at them.
+	DeleteBatch(ctx context.Context, ids []uuid.UUID, versions []int) ([]domain.Subscription, error)
+	List(ctx context.Context, filter domain.SubscriptionFilter) (*domain.SubscriptionPage, error)
 	ListAll(ctx context.Context, filter domain.SubscriptionFilter) ([]domain.Subscription, error)
+	// Stream yields the subscriptions matching filter in its sort order, ignoring its
+	// pagination, as they are scanned. Iteration ends after the first error.
+	Stream(ctx context.Context, filter domain.SubscriptionFilter) iter.Seq2[domain.Subscription, error]
+	// Count returns the number of subscriptions matching filter, ignoring its sort and pagination.
+	Count(ctx context.Context, filter domain.SubscriptionFilter) (int, error)
+	// SuggestServiceNames returns up to limit distinct service names containing query, ignoring case.
+	SuggestServiceNames(ctx context.Context, query string, limit *int) ([]string, error)
+	TotalCostByCurrency(ctx context.Context, filter domain.SubscriptionFilter, start, end time.Time) (map[string]int, error)
+	// AddPriceChange replaces the change with the same EffectiveFrom month if there is one.
+	AddPriceChange(ctx context.Context, subID uuid.UUID, change *domain.PriceChange) error
+	ListPriceChanges(ctx context.Context, subID uuid.UUID) ([]domain.PriceChange, error)
+	// RebasePriceHistory makes startMonth the first month of the price history: the
+	// price in effect on it becomes its first change and earlier changes are dropped.
+	RebasePriceHistory(ctx context.Context, subID uuid.UUID, startMonth time.Time) error
+	AddPause(ctx context.Context, subID uuid.UUID, pause *domain.Pause) error
+	// ResumePause sets ResumedFrom of the open pause that started at pause.PausedFrom.
+	ResumePause(ctx context.Context, subID uuid.UUID, pause *domain.Pause) error
+	ListPauses(ctx context.Context, subID uuid.UUID) ([]domain.Pause, error)
 }
@@ -1,6 +1,16 @@
 package subservice
 
-import "github.com/shrtyk/subscriptions-service/pkg/errkit"
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/pkg/errkit"
+)
+
+// ErrNotApplied is the error of the ops of an atomic batch that were rolled back
+// or skipped because another op failed.
+var ErrNotApplied = errors.New("not applied, another operation of the batch failed")
 
 type ServiceKind int
 
@@ -8,6 +18,9 @@ const (
 	KindUnknown ServiceKind = iota
 	KindBusinessLogic
 	KindNotFound
+	KindConflict
+	KindDuplicate
+	KindForbidden
 )
 
 func (k ServiceKind) String() string {
@@ -16,6 +29,12 @@ func (k ServiceKind) String() string {
 		return "BusinessLogic"
 	case KindNotFound:
 		return "NotFound"
+	case KindConflict:
+		return "Conflict"
+	case KindDuplicate:
+		return "Duplicate"
+	case KindForbidden:
+		return "Forbidden"
 	default:
 		return "Unknown"
 	}
@@ -28,3 +47,18 @@ func NewErr(op string, kind ServiceKind) error {
 func WrapErr(op string, kind ServiceKind, cause error) error {
 	return errkit.WrapErr(op, kind, cause)
 }
+
+// OverlapError is the cause of KindDuplicate errors for subscriptions whose dates
+// overlap another subscription of the same user and service.
+type OverlapError struct {
+	ConflictingID uuid.UUID
+	Err           error
+}
+
+func (e *OverlapError) Error() string {
+	return fmt.Sprintf("overlaps subscription %s: %v", e.ConflictingID, e.Err)
+}
+
+func (e *OverlapError) Unwrap() error {
+	return e.Err
+}
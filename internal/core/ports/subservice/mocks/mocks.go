@@ -6,6 +6,7 @@ package subservicemocks
 
 import (
 	"context"
+	"iter"
 	"time"
 
 	"github.com/google/uuid"
@@ -40,80 +41,1431 @@ func (_m *MockSubscriptionsService) EXPECT() *MockSubscriptionsService_Expecter
 	return &MockSubscriptionsService_Expecter{mock: &_m.Mock}
 }
 
+// AuthenticateAPIKey provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) AuthenticateAPIKey(ctx context.Context, secret string) (*domain.APIKey, error) {
+	ret := _mock.Called(ctx, secret)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AuthenticateAPIKey")
+	}
+
+	var r0 *domain.APIKey
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) (*domain.APIKey, error)); ok {
+		return returnFunc(ctx, secret)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string) *domain.APIKey); ok {
+		r0 = returnFunc(ctx, secret)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.APIKey)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = returnFunc(ctx, secret)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsService_AuthenticateAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'AuthenticateAPIKey'
+type MockSubscriptionsService_AuthenticateAPIKey_Call struct {
+	*mock.Call
+}
+
+// AuthenticateAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - secret string
+func (_e *MockSubscriptionsService_Expecter) AuthenticateAPIKey(ctx interface{}, secret interface{}) *MockSubscriptionsService_AuthenticateAPIKey_Call {
+	return &MockSubscriptionsService_AuthenticateAPIKey_Call{Call: _e.mock.On("AuthenticateAPIKey", ctx, secret)}
+}
+
+func (_c *MockSubscriptionsService_AuthenticateAPIKey_Call) Run(run func(ctx context.Context, secret string)) *MockSubscriptionsService_AuthenticateAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsService_AuthenticateAPIKey_Call) Return(aPIKey *domain.APIKey, err error) *MockSubscriptionsService_AuthenticateAPIKey_Call {
+	_c.Call.Return(aPIKey, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsService_AuthenticateAPIKey_Call) RunAndReturn(run func(ctx context.Context, secret string) (*domain.APIKey, error)) *MockSubscriptionsService_AuthenticateAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Batch provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) Batch(ctx context.Context, ops []domain.BatchOp, atomic bool) ([]domain.BatchResult, error) {
+	ret := _mock.Called(ctx, ops, atomic)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Batch")
+	}
+
+	var r0 []domain.BatchResult
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []domain.BatchOp, bool) ([]domain.BatchResult, error)); ok {
+		return returnFunc(ctx, ops, atomic)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []domain.BatchOp, bool) []domain.BatchResult); ok {
+		r0 = returnFunc(ctx, ops, atomic)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.BatchResult)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, []domain.BatchOp, bool) error); ok {
+		r1 = returnFunc(ctx, ops, atomic)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsService_Batch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Batch'
+type MockSubscriptionsService_Batch_Call struct {
+	*mock.Call
+}
+
+// Batch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ops []domain.BatchOp
+//   - atomic bool
+func (_e *MockSubscriptionsService_Expecter) Batch(ctx interface{}, ops interface{}, atomic interface{}) *MockSubscriptionsService_Batch_Call {
+	return &MockSubscriptionsService_Batch_Call{Call: _e.mock.On("Batch", ctx, ops, atomic)}
+}
+
+func (_c *MockSubscriptionsService_Batch_Call) Run(run func(ctx context.Context, ops []domain.BatchOp, atomic bool)) *MockSubscriptionsService_Batch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []domain.BatchOp
+		if args[1] != nil {
+			arg1 = args[1].([]domain.BatchOp)
+		}
+		var arg2 bool
+		if args[2] != nil {
+			arg2 = args[2].(bool)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsService_Batch_Call) Return(batchResults []domain.BatchResult, err error) *MockSubscriptionsService_Batch_Call {
+	_c.Call.Return(batchResults, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsService_Batch_Call) RunAndReturn(run func(ctx context.Context, ops []domain.BatchOp, atomic bool) ([]domain.BatchResult, error)) *MockSubscriptionsService_Batch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CostBreakdown provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) CostBreakdown(ctx context.Context, filter domain.SubscriptionFilter, start time.Time, end time.Time, currency string, mode domain.CostMode) ([]domain.MonthCost, error) {
+	ret := _mock.Called(ctx, filter, start, end, currency, mode)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CostBreakdown")
+	}
+
+	var r0 []domain.MonthCost
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter, time.Time, time.Time, string, domain.CostMode) ([]domain.MonthCost, error)); ok {
+		return returnFunc(ctx, filter, start, end, currency, mode)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter, time.Time, time.Time, string, domain.CostMode) []domain.MonthCost); ok {
+		r0 = returnFunc(ctx, filter, start, end, currency, mode)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.MonthCost)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.SubscriptionFilter, time.Time, time.Time, string, domain.CostMode) error); ok {
+		r1 = returnFunc(ctx, filter, start, end, currency, mode)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsService_CostBreakdown_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CostBreakdown'
+type MockSubscriptionsService_CostBreakdown_Call struct {
+	*mock.Call
+}
+
+// CostBreakdown is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter domain.SubscriptionFilter
+//   - start time.Time
+//   - end time.Time
+//   - currency string
+//   - mode domain.CostMode
+func (_e *MockSubscriptionsService_Expecter) CostBreakdown(ctx interface{}, filter interface{}, start interface{}, end interface{}, currency interface{}, mode interface{}) *MockSubscriptionsService_CostBreakdown_Call {
+	return &MockSubscriptionsService_CostBreakdown_Call{Call: _e.mock.On("CostBreakdown", ctx, filter, start, end, currency, mode)}
+}
+
+func (_c *MockSubscriptionsService_CostBreakdown_Call) Run(run func(ctx context.Context, filter domain.SubscriptionFilter, start time.Time, end time.Time, currency string, mode domain.CostMode)) *MockSubscriptionsService_CostBreakdown_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 domain.SubscriptionFilter
+		if args[1] != nil {
+			arg1 = args[1].(domain.SubscriptionFilter)
+		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
+		var arg3 time.Time
+		if args[3] != nil {
+			arg3 = args[3].(time.Time)
+		}
+		var arg4 string
+		if args[4] != nil {
+			arg4 = args[4].(string)
+		}
+		var arg5 domain.CostMode
+		if args[5] != nil {
+			arg5 = args[5].(domain.CostMode)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+			arg4,
+			arg5,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsService_CostBreakdown_Call) Return(monthCosts []domain.MonthCost, err error) *MockSubscriptionsService_CostBreakdown_Call {
+	_c.Call.Return(monthCosts, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsService_CostBreakdown_Call) RunAndReturn(run func(ctx context.Context, filter domain.SubscriptionFilter, start time.Time, end time.Time, currency string, mode domain.CostMode) ([]domain.MonthCost, error)) *MockSubscriptionsService_CostBreakdown_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Create provides a mock function for the type MockSubscriptionsService
 func (_mock *MockSubscriptionsService) Create(ctx context.Context, sub domain.Subscription) (*domain.Subscription, error) {
 	ret := _mock.Called(ctx, sub)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Create")
+		panic("no return value specified for Create")
+	}
+
+	var r0 *domain.Subscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.Subscription) (*domain.Subscription, error)); ok {
+		return returnFunc(ctx, sub)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.Subscription) *domain.Subscription); ok {
+		r0 = returnFunc(ctx, sub)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Subscription)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.Subscription) error); ok {
+		r1 = returnFunc(ctx, sub)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsService_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
+type MockSubscriptionsService_Create_Call struct {
+	*mock.Call
+}
+
+// Create is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sub domain.Subscription
+func (_e *MockSubscriptionsService_Expecter) Create(ctx interface{}, sub interface{}) *MockSubscriptionsService_Create_Call {
+	return &MockSubscriptionsService_Create_Call{Call: _e.mock.On("Create", ctx, sub)}
+}
+
+func (_c *MockSubscriptionsService_Create_Call) Run(run func(ctx context.Context, sub domain.Subscription)) *MockSubscriptionsService_Create_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 domain.Subscription
+		if args[1] != nil {
+			arg1 = args[1].(domain.Subscription)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsService_Create_Call) Return(subscription *domain.Subscription, err error) *MockSubscriptionsService_Create_Call {
+	_c.Call.Return(subscription, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsService_Create_Call) RunAndReturn(run func(ctx context.Context, sub domain.Subscription) (*domain.Subscription, error)) *MockSubscriptionsService_Create_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// CreateIdempotent provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) CreateIdempotent(ctx context.Context, sub domain.Subscription, key domain.IdempotencyKey) (*domain.Subscription, bool, error) {
+	ret := _mock.Called(ctx, sub, key)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateIdempotent")
+	}
+
+	var r0 *domain.Subscription
+	var r1 bool
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.Subscription, domain.IdempotencyKey) (*domain.Subscription, bool, error)); ok {
+		return returnFunc(ctx, sub, key)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.Subscription, domain.IdempotencyKey) *domain.Subscription); ok {
+		r0 = returnFunc(ctx, sub, key)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Subscription)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.Subscription, domain.IdempotencyKey) bool); ok {
+		r1 = returnFunc(ctx, sub, key)
+	} else {
+		r1 = ret.Get(1).(bool)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, domain.Subscription, domain.IdempotencyKey) error); ok {
+		r2 = returnFunc(ctx, sub, key)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// MockSubscriptionsService_CreateIdempotent_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateIdempotent'
+type MockSubscriptionsService_CreateIdempotent_Call struct {
+	*mock.Call
+}
+
+// CreateIdempotent is a helper method to define mock.On call
+//   - ctx context.Context
+//   - sub domain.Subscription
+//   - key domain.IdempotencyKey
+func (_e *MockSubscriptionsService_Expecter) CreateIdempotent(ctx interface{}, sub interface{}, key interface{}) *MockSubscriptionsService_CreateIdempotent_Call {
+	return &MockSubscriptionsService_CreateIdempotent_Call{Call: _e.mock.On("CreateIdempotent", ctx, sub, key)}
+}
+
+func (_c *MockSubscriptionsService_CreateIdempotent_Call) Run(run func(ctx context.Context, sub domain.Subscription, key domain.IdempotencyKey)) *MockSubscriptionsService_CreateIdempotent_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 domain.Subscription
+		if args[1] != nil {
+			arg1 = args[1].(domain.Subscription)
+		}
+		var arg2 domain.IdempotencyKey
+		if args[2] != nil {
+			arg2 = args[2].(domain.IdempotencyKey)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsService_CreateIdempotent_Call) Return(created *domain.Subscription, replayed bool, err error) *MockSubscriptionsService_CreateIdempotent_Call {
+	_c.Call.Return(created, replayed, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsService_CreateIdempotent_Call) RunAndReturn(run func(ctx context.Context, sub domain.Subscription, key domain.IdempotencyKey) (*domain.Subscription, bool, error)) *MockSubscriptionsService_CreateIdempotent_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Delete provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) Delete(ctx context.Context, id uuid.UUID, version *int) error {
+	ret := _mock.Called(ctx, id, version)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Delete")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *int) error); ok {
+		r0 = returnFunc(ctx, id, version)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSubscriptionsService_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
+type MockSubscriptionsService_Delete_Call struct {
+	*mock.Call
+}
+
+// Delete is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - version *int
+func (_e *MockSubscriptionsService_Expecter) Delete(ctx interface{}, id interface{}, version interface{}) *MockSubscriptionsService_Delete_Call {
+	return &MockSubscriptionsService_Delete_Call{Call: _e.mock.On("Delete", ctx, id, version)}
+}
+
+func (_c *MockSubscriptionsService_Delete_Call) Run(run func(ctx context.Context, id uuid.UUID, version *int)) *MockSubscriptionsService_Delete_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 *int
+		if args[2] != nil {
+			arg2 = args[2].(*int)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsService_Delete_Call) Return(err error) *MockSubscriptionsService_Delete_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSubscriptionsService_Delete_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, version *int) error) *MockSubscriptionsService_Delete_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteCurrencyRate provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) DeleteCurrencyRate(ctx context.Context, from string, to string) error {
+	ret := _mock.Called(ctx, from, to)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteCurrencyRate")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = returnFunc(ctx, from, to)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockSubscriptionsService_DeleteCurrencyRate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteCurrencyRate'
+type MockSubscriptionsService_DeleteCurrencyRate_Call struct {
+	*mock.Call
+}
+
+// DeleteCurrencyRate is a helper method to define mock.On call
+//   - ctx context.Context
+//   - from string
+//   - to string
+func (_e *MockSubscriptionsService_Expecter) DeleteCurrencyRate(ctx interface{}, from interface{}, to interface{}) *MockSubscriptionsService_DeleteCurrencyRate_Call {
+	return &MockSubscriptionsService_DeleteCurrencyRate_Call{Call: _e.mock.On("DeleteCurrencyRate", ctx, from, to)}
+}
+
+func (_c *MockSubscriptionsService_DeleteCurrencyRate_Call) Run(run func(ctx context.Context, from string, to string)) *MockSubscriptionsService_DeleteCurrencyRate_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 string
+		if args[2] != nil {
+			arg2 = args[2].(string)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsService_DeleteCurrencyRate_Call) Return(err error) *MockSubscriptionsService_DeleteCurrencyRate_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockSubscriptionsService_DeleteCurrencyRate_Call) RunAndReturn(run func(ctx context.Context, from string, to string) error) *MockSubscriptionsService_DeleteCurrencyRate_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Export provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) Export(ctx context.Context, filter domain.SubscriptionFilter) iter.Seq2[domain.Subscription, error] {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Export")
+	}
+
+	var r0 iter.Seq2[domain.Subscription, error]
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter) iter.Seq2[domain.Subscription, error]); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(iter.Seq2[domain.Subscription, error])
+		}
+	}
+	return r0
+}
+
+// MockSubscriptionsService_Export_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Export'
+type MockSubscriptionsService_Export_Call struct {
+	*mock.Call
+}
+
+// Export is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter domain.SubscriptionFilter
+func (_e *MockSubscriptionsService_Expecter) Export(ctx interface{}, filter interface{}) *MockSubscriptionsService_Export_Call {
+	return &MockSubscriptionsService_Export_Call{Call: _e.mock.On("Export", ctx, filter)}
+}
+
+func (_c *MockSubscriptionsService_Export_Call) Run(run func(ctx context.Context, filter domain.SubscriptionFilter)) *MockSubscriptionsService_Export_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 domain.SubscriptionFilter
+		if args[1] != nil {
+			arg1 = args[1].(domain.SubscriptionFilter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsService_Export_Call) Return(seq2 iter.Seq2[domain.Subscription, error]) *MockSubscriptionsService_Export_Call {
+	_c.Call.Return(seq2)
+	return _c
+}
+
+func (_c *MockSubscriptionsService_Export_Call) RunAndReturn(run func(ctx context.Context, filter domain.SubscriptionFilter) iter.Seq2[domain.Subscription, error]) *MockSubscriptionsService_Export_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// GetByID provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) GetByID(ctx context.Context, id uuid.UUID) (*domain.Subscription, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetByID")
+	}
+
+	var r0 *domain.Subscription
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*domain.Subscription, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) *domain.Subscription); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Subscription)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsService_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
+type MockSubscriptionsService_GetByID_Call struct {
+	*mock.Call
+}
+
+// GetByID is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockSubscriptionsService_Expecter) GetByID(ctx interface{}, id interface{}) *MockSubscriptionsService_GetByID_Call {
+	return &MockSubscriptionsService_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
+}
+
+func (_c *MockSubscriptionsService_GetByID_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockSubscriptionsService_GetByID_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsService_GetByID_Call) Return(subscription *domain.Subscription, err error) *MockSubscriptionsService_GetByID_Call {
+	_c.Call.Return(subscription, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsService_GetByID_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) (*domain.Subscription, error)) *MockSubscriptionsService_GetByID_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// History provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) History(ctx context.Context, id uuid.UUID) ([]domain.AuditEntry, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for History")
+	}
+
+	var r0 []domain.AuditEntry
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.AuditEntry, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) []domain.AuditEntry); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AuditEntry)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsService_History_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'History'
+type MockSubscriptionsService_History_Call struct {
+	*mock.Call
+}
+
+// History is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockSubscriptionsService_Expecter) History(ctx interface{}, id interface{}) *MockSubscriptionsService_History_Call {
+	return &MockSubscriptionsService_History_Call{Call: _e.mock.On("History", ctx, id)}
+}
+
+func (_c *MockSubscriptionsService_History_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockSubscriptionsService_History_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsService_History_Call) Return(auditEntrys []domain.AuditEntry, err error) *MockSubscriptionsService_History_Call {
+	_c.Call.Return(auditEntrys, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsService_History_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) ([]domain.AuditEntry, error)) *MockSubscriptionsService_History_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// List provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) List(ctx context.Context, filter domain.SubscriptionFilter) (*domain.SubscriptionPage, error) {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for List")
+	}
+
+	var r0 *domain.SubscriptionPage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter) (*domain.SubscriptionPage, error)); ok {
+		return returnFunc(ctx, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter) *domain.SubscriptionPage); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.SubscriptionPage)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.SubscriptionFilter) error); ok {
+		r1 = returnFunc(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsService_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
+type MockSubscriptionsService_List_Call struct {
+	*mock.Call
+}
+
+// List is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter domain.SubscriptionFilter
+func (_e *MockSubscriptionsService_Expecter) List(ctx interface{}, filter interface{}) *MockSubscriptionsService_List_Call {
+	return &MockSubscriptionsService_List_Call{Call: _e.mock.On("List", ctx, filter)}
+}
+
+func (_c *MockSubscriptionsService_List_Call) Run(run func(ctx context.Context, filter domain.SubscriptionFilter)) *MockSubscriptionsService_List_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 domain.SubscriptionFilter
+		if args[1] != nil {
+			arg1 = args[1].(domain.SubscriptionFilter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsService_List_Call) Return(subscriptionPage *domain.SubscriptionPage, err error) *MockSubscriptionsService_List_Call {
+	_c.Call.Return(subscriptionPage, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsService_List_Call) RunAndReturn(run func(ctx context.Context, filter domain.SubscriptionFilter) (*domain.SubscriptionPage, error)) *MockSubscriptionsService_List_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAPIKeys provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) ListAPIKeys(ctx context.Context) ([]domain.APIKey, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAPIKeys")
+	}
+
+	var r0 []domain.APIKey
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]domain.APIKey, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []domain.APIKey); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.APIKey)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsService_ListAPIKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAPIKeys'
+type MockSubscriptionsService_ListAPIKeys_Call struct {
+	*mock.Call
+}
+
+// ListAPIKeys is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockSubscriptionsService_Expecter) ListAPIKeys(ctx interface{}) *MockSubscriptionsService_ListAPIKeys_Call {
+	return &MockSubscriptionsService_ListAPIKeys_Call{Call: _e.mock.On("ListAPIKeys", ctx)}
+}
+
+func (_c *MockSubscriptionsService_ListAPIKeys_Call) Run(run func(ctx context.Context)) *MockSubscriptionsService_ListAPIKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsService_ListAPIKeys_Call) Return(aPIKeys []domain.APIKey, err error) *MockSubscriptionsService_ListAPIKeys_Call {
+	_c.Call.Return(aPIKeys, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsService_ListAPIKeys_Call) RunAndReturn(run func(ctx context.Context) ([]domain.APIKey, error)) *MockSubscriptionsService_ListAPIKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListAudit provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) ListAudit(ctx context.Context, filter domain.AuditFilter) (*domain.AuditPage, error) {
+	ret := _mock.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAudit")
+	}
+
+	var r0 *domain.AuditPage
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.AuditFilter) (*domain.AuditPage, error)); ok {
+		return returnFunc(ctx, filter)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.AuditFilter) *domain.AuditPage); ok {
+		r0 = returnFunc(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.AuditPage)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.AuditFilter) error); ok {
+		r1 = returnFunc(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsService_ListAudit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListAudit'
+type MockSubscriptionsService_ListAudit_Call struct {
+	*mock.Call
+}
+
+// ListAudit is a helper method to define mock.On call
+//   - ctx context.Context
+//   - filter domain.AuditFilter
+func (_e *MockSubscriptionsService_Expecter) ListAudit(ctx interface{}, filter interface{}) *MockSubscriptionsService_ListAudit_Call {
+	return &MockSubscriptionsService_ListAudit_Call{Call: _e.mock.On("ListAudit", ctx, filter)}
+}
+
+func (_c *MockSubscriptionsService_ListAudit_Call) Run(run func(ctx context.Context, filter domain.AuditFilter)) *MockSubscriptionsService_ListAudit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 domain.AuditFilter
+		if args[1] != nil {
+			arg1 = args[1].(domain.AuditFilter)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsService_ListAudit_Call) Return(auditPage *domain.AuditPage, err error) *MockSubscriptionsService_ListAudit_Call {
+	_c.Call.Return(auditPage, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsService_ListAudit_Call) RunAndReturn(run func(ctx context.Context, filter domain.AuditFilter) (*domain.AuditPage, error)) *MockSubscriptionsService_ListAudit_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListCurrencyRates provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) ListCurrencyRates(ctx context.Context) ([]domain.CurrencyRate, error) {
+	ret := _mock.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListCurrencyRates")
+	}
+
+	var r0 []domain.CurrencyRate
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]domain.CurrencyRate, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []domain.CurrencyRate); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.CurrencyRate)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsService_ListCurrencyRates_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListCurrencyRates'
+type MockSubscriptionsService_ListCurrencyRates_Call struct {
+	*mock.Call
+}
+
+// ListCurrencyRates is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockSubscriptionsService_Expecter) ListCurrencyRates(ctx interface{}) *MockSubscriptionsService_ListCurrencyRates_Call {
+	return &MockSubscriptionsService_ListCurrencyRates_Call{Call: _e.mock.On("ListCurrencyRates", ctx)}
+}
+
+func (_c *MockSubscriptionsService_ListCurrencyRates_Call) Run(run func(ctx context.Context)) *MockSubscriptionsService_ListCurrencyRates_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsService_ListCurrencyRates_Call) Return(currencyRates []domain.CurrencyRate, err error) *MockSubscriptionsService_ListCurrencyRates_Call {
+	_c.Call.Return(currencyRates, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsService_ListCurrencyRates_Call) RunAndReturn(run func(ctx context.Context) ([]domain.CurrencyRate, error)) *MockSubscriptionsService_ListCurrencyRates_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPauses provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) ListPauses(ctx context.Context, id uuid.UUID) ([]domain.Pause, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPauses")
+	}
+
+	var r0 []domain.Pause
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.Pause, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) []domain.Pause); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Pause)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsService_ListPauses_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPauses'
+type MockSubscriptionsService_ListPauses_Call struct {
+	*mock.Call
+}
+
+// ListPauses is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockSubscriptionsService_Expecter) ListPauses(ctx interface{}, id interface{}) *MockSubscriptionsService_ListPauses_Call {
+	return &MockSubscriptionsService_ListPauses_Call{Call: _e.mock.On("ListPauses", ctx, id)}
+}
+
+func (_c *MockSubscriptionsService_ListPauses_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockSubscriptionsService_ListPauses_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsService_ListPauses_Call) Return(pauses []domain.Pause, err error) *MockSubscriptionsService_ListPauses_Call {
+	_c.Call.Return(pauses, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsService_ListPauses_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) ([]domain.Pause, error)) *MockSubscriptionsService_ListPauses_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ListPriceChanges provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) ListPriceChanges(ctx context.Context, id uuid.UUID) ([]domain.PriceChange, error) {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPriceChanges")
+	}
+
+	var r0 []domain.PriceChange
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) ([]domain.PriceChange, error)); ok {
+		return returnFunc(ctx, id)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) []domain.PriceChange); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.PriceChange)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = returnFunc(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsService_ListPriceChanges_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ListPriceChanges'
+type MockSubscriptionsService_ListPriceChanges_Call struct {
+	*mock.Call
+}
+
+// ListPriceChanges is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+func (_e *MockSubscriptionsService_Expecter) ListPriceChanges(ctx interface{}, id interface{}) *MockSubscriptionsService_ListPriceChanges_Call {
+	return &MockSubscriptionsService_ListPriceChanges_Call{Call: _e.mock.On("ListPriceChanges", ctx, id)}
+}
+
+func (_c *MockSubscriptionsService_ListPriceChanges_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockSubscriptionsService_ListPriceChanges_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsService_ListPriceChanges_Call) Return(priceChanges []domain.PriceChange, err error) *MockSubscriptionsService_ListPriceChanges_Call {
+	_c.Call.Return(priceChanges, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsService_ListPriceChanges_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) ([]domain.PriceChange, error)) *MockSubscriptionsService_ListPriceChanges_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// MintAPIKey provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) MintAPIKey(ctx context.Context, name string, scopes []domain.Scope, expiresAt *time.Time) (*domain.APIKey, string, error) {
+	ret := _mock.Called(ctx, name, scopes, expiresAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MintAPIKey")
+	}
+
+	var r0 *domain.APIKey
+	var r1 string
+	var r2 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []domain.Scope, *time.Time) (*domain.APIKey, string, error)); ok {
+		return returnFunc(ctx, name, scopes, expiresAt)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, []domain.Scope, *time.Time) *domain.APIKey); ok {
+		r0 = returnFunc(ctx, name, scopes, expiresAt)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.APIKey)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, []domain.Scope, *time.Time) string); ok {
+		r1 = returnFunc(ctx, name, scopes, expiresAt)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+	if returnFunc, ok := ret.Get(2).(func(context.Context, string, []domain.Scope, *time.Time) error); ok {
+		r2 = returnFunc(ctx, name, scopes, expiresAt)
+	} else {
+		r2 = ret.Error(2)
+	}
+	return r0, r1, r2
+}
+
+// MockSubscriptionsService_MintAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'MintAPIKey'
+type MockSubscriptionsService_MintAPIKey_Call struct {
+	*mock.Call
+}
+
+// MintAPIKey is a helper method to define mock.On call
+//   - ctx context.Context
+//   - name string
+//   - scopes []domain.Scope
+//   - expiresAt *time.Time
+func (_e *MockSubscriptionsService_Expecter) MintAPIKey(ctx interface{}, name interface{}, scopes interface{}, expiresAt interface{}) *MockSubscriptionsService_MintAPIKey_Call {
+	return &MockSubscriptionsService_MintAPIKey_Call{Call: _e.mock.On("MintAPIKey", ctx, name, scopes, expiresAt)}
+}
+
+func (_c *MockSubscriptionsService_MintAPIKey_Call) Run(run func(ctx context.Context, name string, scopes []domain.Scope, expiresAt *time.Time)) *MockSubscriptionsService_MintAPIKey_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 string
+		if args[1] != nil {
+			arg1 = args[1].(string)
+		}
+		var arg2 []domain.Scope
+		if args[2] != nil {
+			arg2 = args[2].([]domain.Scope)
+		}
+		var arg3 *time.Time
+		if args[3] != nil {
+			arg3 = args[3].(*time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsService_MintAPIKey_Call) Return(key *domain.APIKey, secret string, err error) *MockSubscriptionsService_MintAPIKey_Call {
+	_c.Call.Return(key, secret, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsService_MintAPIKey_Call) RunAndReturn(run func(ctx context.Context, name string, scopes []domain.Scope, expiresAt *time.Time) (*domain.APIKey, string, error)) *MockSubscriptionsService_MintAPIKey_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Patch provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) Patch(ctx context.Context, id uuid.UUID, version *int, patch func(current domain.Subscription) (domain.SubscriptionUpdate, error)) (*domain.Subscription, error) {
+	ret := _mock.Called(ctx, id, version, patch)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Patch")
 	}
 
 	var r0 *domain.Subscription
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.Subscription) (*domain.Subscription, error)); ok {
-		return returnFunc(ctx, sub)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *int, func(current domain.Subscription) (domain.SubscriptionUpdate, error)) (*domain.Subscription, error)); ok {
+		return returnFunc(ctx, id, version, patch)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.Subscription) *domain.Subscription); ok {
-		r0 = returnFunc(ctx, sub)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *int, func(current domain.Subscription) (domain.SubscriptionUpdate, error)) *domain.Subscription); ok {
+		r0 = returnFunc(ctx, id, version, patch)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Subscription)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.Subscription) error); ok {
-		r1 = returnFunc(ctx, sub)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, *int, func(current domain.Subscription) (domain.SubscriptionUpdate, error)) error); ok {
+		r1 = returnFunc(ctx, id, version, patch)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockSubscriptionsService_Create_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Create'
-type MockSubscriptionsService_Create_Call struct {
+// MockSubscriptionsService_Patch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Patch'
+type MockSubscriptionsService_Patch_Call struct {
 	*mock.Call
 }
 
-// Create is a helper method to define mock.On call
+// Patch is a helper method to define mock.On call
 //   - ctx context.Context
-//   - sub domain.Subscription
-func (_e *MockSubscriptionsService_Expecter) Create(ctx interface{}, sub interface{}) *MockSubscriptionsService_Create_Call {
-	return &MockSubscriptionsService_Create_Call{Call: _e.mock.On("Create", ctx, sub)}
+//   - id uuid.UUID
+//   - version *int
+//   - patch func(current domain.Subscription) (domain.SubscriptionUpdate, error)
+func (_e *MockSubscriptionsService_Expecter) Patch(ctx interface{}, id interface{}, version interface{}, patch interface{}) *MockSubscriptionsService_Patch_Call {
+	return &MockSubscriptionsService_Patch_Call{Call: _e.mock.On("Patch", ctx, id, version, patch)}
 }
 
-func (_c *MockSubscriptionsService_Create_Call) Run(run func(ctx context.Context, sub domain.Subscription)) *MockSubscriptionsService_Create_Call {
+func (_c *MockSubscriptionsService_Patch_Call) Run(run func(ctx context.Context, id uuid.UUID, version *int, patch func(current domain.Subscription) (domain.SubscriptionUpdate, error))) *MockSubscriptionsService_Patch_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 domain.Subscription
+		var arg1 uuid.UUID
 		if args[1] != nil {
-			arg1 = args[1].(domain.Subscription)
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 *int
+		if args[2] != nil {
+			arg2 = args[2].(*int)
+		}
+		var arg3 func(current domain.Subscription) (domain.SubscriptionUpdate, error)
+		if args[3] != nil {
+			arg3 = args[3].(func(current domain.Subscription) (domain.SubscriptionUpdate, error))
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockSubscriptionsService_Create_Call) Return(subscription *domain.Subscription, err error) *MockSubscriptionsService_Create_Call {
+func (_c *MockSubscriptionsService_Patch_Call) Return(subscription *domain.Subscription, err error) *MockSubscriptionsService_Patch_Call {
 	_c.Call.Return(subscription, err)
 	return _c
 }
 
-func (_c *MockSubscriptionsService_Create_Call) RunAndReturn(run func(ctx context.Context, sub domain.Subscription) (*domain.Subscription, error)) *MockSubscriptionsService_Create_Call {
+func (_c *MockSubscriptionsService_Patch_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, version *int, patch func(current domain.Subscription) (domain.SubscriptionUpdate, error)) (*domain.Subscription, error)) *MockSubscriptionsService_Patch_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Delete provides a mock function for the type MockSubscriptionsService
-func (_mock *MockSubscriptionsService) Delete(ctx context.Context, id uuid.UUID) error {
+// Pause provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) Pause(ctx context.Context, id uuid.UUID, pausedFrom *time.Time, resumedFrom *time.Time) (*domain.Pause, error) {
+	ret := _mock.Called(ctx, id, pausedFrom, resumedFrom)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Pause")
+	}
+
+	var r0 *domain.Pause
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *time.Time, *time.Time) (*domain.Pause, error)); ok {
+		return returnFunc(ctx, id, pausedFrom, resumedFrom)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *time.Time, *time.Time) *domain.Pause); ok {
+		r0 = returnFunc(ctx, id, pausedFrom, resumedFrom)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Pause)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, *time.Time, *time.Time) error); ok {
+		r1 = returnFunc(ctx, id, pausedFrom, resumedFrom)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsService_Pause_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Pause'
+type MockSubscriptionsService_Pause_Call struct {
+	*mock.Call
+}
+
+// Pause is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - pausedFrom *time.Time
+//   - resumedFrom *time.Time
+func (_e *MockSubscriptionsService_Expecter) Pause(ctx interface{}, id interface{}, pausedFrom interface{}, resumedFrom interface{}) *MockSubscriptionsService_Pause_Call {
+	return &MockSubscriptionsService_Pause_Call{Call: _e.mock.On("Pause", ctx, id, pausedFrom, resumedFrom)}
+}
+
+func (_c *MockSubscriptionsService_Pause_Call) Run(run func(ctx context.Context, id uuid.UUID, pausedFrom *time.Time, resumedFrom *time.Time)) *MockSubscriptionsService_Pause_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 *time.Time
+		if args[2] != nil {
+			arg2 = args[2].(*time.Time)
+		}
+		var arg3 *time.Time
+		if args[3] != nil {
+			arg3 = args[3].(*time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+			arg3,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsService_Pause_Call) Return(pause *domain.Pause, err error) *MockSubscriptionsService_Pause_Call {
+	_c.Call.Return(pause, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsService_Pause_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, pausedFrom *time.Time, resumedFrom *time.Time) (*domain.Pause, error)) *MockSubscriptionsService_Pause_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Resume provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) Resume(ctx context.Context, id uuid.UUID, resumedFrom *time.Time) (*domain.Pause, error) {
+	ret := _mock.Called(ctx, id, resumedFrom)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Resume")
+	}
+
+	var r0 *domain.Pause
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *time.Time) (*domain.Pause, error)); ok {
+		return returnFunc(ctx, id, resumedFrom)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *time.Time) *domain.Pause); ok {
+		r0 = returnFunc(ctx, id, resumedFrom)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*domain.Pause)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, *time.Time) error); ok {
+		r1 = returnFunc(ctx, id, resumedFrom)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockSubscriptionsService_Resume_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Resume'
+type MockSubscriptionsService_Resume_Call struct {
+	*mock.Call
+}
+
+// Resume is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id uuid.UUID
+//   - resumedFrom *time.Time
+func (_e *MockSubscriptionsService_Expecter) Resume(ctx interface{}, id interface{}, resumedFrom interface{}) *MockSubscriptionsService_Resume_Call {
+	return &MockSubscriptionsService_Resume_Call{Call: _e.mock.On("Resume", ctx, id, resumedFrom)}
+}
+
+func (_c *MockSubscriptionsService_Resume_Call) Run(run func(ctx context.Context, id uuid.UUID, resumedFrom *time.Time)) *MockSubscriptionsService_Resume_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 uuid.UUID
+		if args[1] != nil {
+			arg1 = args[1].(uuid.UUID)
+		}
+		var arg2 *time.Time
+		if args[2] != nil {
+			arg2 = args[2].(*time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+			arg2,
+		)
+	})
+	return _c
+}
+
+func (_c *MockSubscriptionsService_Resume_Call) Return(pause *domain.Pause, err error) *MockSubscriptionsService_Resume_Call {
+	_c.Call.Return(pause, err)
+	return _c
+}
+
+func (_c *MockSubscriptionsService_Resume_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, resumedFrom *time.Time) (*domain.Pause, error)) *MockSubscriptionsService_Resume_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// RevokeAPIKey provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) RevokeAPIKey(ctx context.Context, id uuid.UUID) error {
 	ret := _mock.Called(ctx, id)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Delete")
+		panic("no return value specified for RevokeAPIKey")
 	}
 
 	var r0 error
@@ -125,19 +1477,19 @@ func (_mock *MockSubscriptionsService) Delete(ctx context.Context, id uuid.UUID)
 	return r0
 }
 
-// MockSubscriptionsService_Delete_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Delete'
-type MockSubscriptionsService_Delete_Call struct {
+// MockSubscriptionsService_RevokeAPIKey_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RevokeAPIKey'
+type MockSubscriptionsService_RevokeAPIKey_Call struct {
 	*mock.Call
 }
 
-// Delete is a helper method to define mock.On call
+// RevokeAPIKey is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id uuid.UUID
-func (_e *MockSubscriptionsService_Expecter) Delete(ctx interface{}, id interface{}) *MockSubscriptionsService_Delete_Call {
-	return &MockSubscriptionsService_Delete_Call{Call: _e.mock.On("Delete", ctx, id)}
+func (_e *MockSubscriptionsService_Expecter) RevokeAPIKey(ctx interface{}, id interface{}) *MockSubscriptionsService_RevokeAPIKey_Call {
+	return &MockSubscriptionsService_RevokeAPIKey_Call{Call: _e.mock.On("RevokeAPIKey", ctx, id)}
 }
 
-func (_c *MockSubscriptionsService_Delete_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockSubscriptionsService_Delete_Call {
+func (_c *MockSubscriptionsService_RevokeAPIKey_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockSubscriptionsService_RevokeAPIKey_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -155,65 +1507,65 @@ func (_c *MockSubscriptionsService_Delete_Call) Run(run func(ctx context.Context
 	return _c
 }
 
-func (_c *MockSubscriptionsService_Delete_Call) Return(err error) *MockSubscriptionsService_Delete_Call {
+func (_c *MockSubscriptionsService_RevokeAPIKey_Call) Return(err error) *MockSubscriptionsService_RevokeAPIKey_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockSubscriptionsService_Delete_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) error) *MockSubscriptionsService_Delete_Call {
+func (_c *MockSubscriptionsService_RevokeAPIKey_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) error) *MockSubscriptionsService_RevokeAPIKey_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// GetByID provides a mock function for the type MockSubscriptionsService
-func (_mock *MockSubscriptionsService) GetByID(ctx context.Context, id uuid.UUID) (*domain.Subscription, error) {
-	ret := _mock.Called(ctx, id)
+// SetCurrencyRate provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) SetCurrencyRate(ctx context.Context, rate domain.CurrencyRate) (*domain.CurrencyRate, error) {
+	ret := _mock.Called(ctx, rate)
 
 	if len(ret) == 0 {
-		panic("no return value specified for GetByID")
+		panic("no return value specified for SetCurrencyRate")
 	}
 
-	var r0 *domain.Subscription
+	var r0 *domain.CurrencyRate
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) (*domain.Subscription, error)); ok {
-		return returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.CurrencyRate) (*domain.CurrencyRate, error)); ok {
+		return returnFunc(ctx, rate)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID) *domain.Subscription); ok {
-		r0 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.CurrencyRate) *domain.CurrencyRate); ok {
+		r0 = returnFunc(ctx, rate)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*domain.Subscription)
+			r0 = ret.Get(0).(*domain.CurrencyRate)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
-		r1 = returnFunc(ctx, id)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.CurrencyRate) error); ok {
+		r1 = returnFunc(ctx, rate)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockSubscriptionsService_GetByID_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetByID'
-type MockSubscriptionsService_GetByID_Call struct {
+// MockSubscriptionsService_SetCurrencyRate_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SetCurrencyRate'
+type MockSubscriptionsService_SetCurrencyRate_Call struct {
 	*mock.Call
 }
 
-// GetByID is a helper method to define mock.On call
+// SetCurrencyRate is a helper method to define mock.On call
 //   - ctx context.Context
-//   - id uuid.UUID
-func (_e *MockSubscriptionsService_Expecter) GetByID(ctx interface{}, id interface{}) *MockSubscriptionsService_GetByID_Call {
-	return &MockSubscriptionsService_GetByID_Call{Call: _e.mock.On("GetByID", ctx, id)}
+//   - rate domain.CurrencyRate
+func (_e *MockSubscriptionsService_Expecter) SetCurrencyRate(ctx interface{}, rate interface{}) *MockSubscriptionsService_SetCurrencyRate_Call {
+	return &MockSubscriptionsService_SetCurrencyRate_Call{Call: _e.mock.On("SetCurrencyRate", ctx, rate)}
 }
 
-func (_c *MockSubscriptionsService_GetByID_Call) Run(run func(ctx context.Context, id uuid.UUID)) *MockSubscriptionsService_GetByID_Call {
+func (_c *MockSubscriptionsService_SetCurrencyRate_Call) Run(run func(ctx context.Context, rate domain.CurrencyRate)) *MockSubscriptionsService_SetCurrencyRate_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 uuid.UUID
+		var arg1 domain.CurrencyRate
 		if args[1] != nil {
-			arg1 = args[1].(uuid.UUID)
+			arg1 = args[1].(domain.CurrencyRate)
 		}
 		run(
 			arg0,
@@ -223,87 +1575,93 @@ func (_c *MockSubscriptionsService_GetByID_Call) Run(run func(ctx context.Contex
 	return _c
 }
 
-func (_c *MockSubscriptionsService_GetByID_Call) Return(subscription *domain.Subscription, err error) *MockSubscriptionsService_GetByID_Call {
-	_c.Call.Return(subscription, err)
+func (_c *MockSubscriptionsService_SetCurrencyRate_Call) Return(currencyRate *domain.CurrencyRate, err error) *MockSubscriptionsService_SetCurrencyRate_Call {
+	_c.Call.Return(currencyRate, err)
 	return _c
 }
 
-func (_c *MockSubscriptionsService_GetByID_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID) (*domain.Subscription, error)) *MockSubscriptionsService_GetByID_Call {
+func (_c *MockSubscriptionsService_SetCurrencyRate_Call) RunAndReturn(run func(ctx context.Context, rate domain.CurrencyRate) (*domain.CurrencyRate, error)) *MockSubscriptionsService_SetCurrencyRate_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// List provides a mock function for the type MockSubscriptionsService
-func (_mock *MockSubscriptionsService) List(ctx context.Context, filter domain.SubscriptionFilter) ([]domain.Subscription, error) {
-	ret := _mock.Called(ctx, filter)
+// SuggestServiceNames provides a mock function for the type MockSubscriptionsService
+func (_mock *MockSubscriptionsService) SuggestServiceNames(ctx context.Context, query string, limit *int) ([]string, error) {
+	ret := _mock.Called(ctx, query, limit)
 
 	if len(ret) == 0 {
-		panic("no return value specified for List")
+		panic("no return value specified for SuggestServiceNames")
 	}
 
-	var r0 []domain.Subscription
+	var r0 []string
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter) ([]domain.Subscription, error)); ok {
-		return returnFunc(ctx, filter)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *int) ([]string, error)); ok {
+		return returnFunc(ctx, query, limit)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter) []domain.Subscription); ok {
-		r0 = returnFunc(ctx, filter)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, string, *int) []string); ok {
+		r0 = returnFunc(ctx, query, limit)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]domain.Subscription)
+			r0 = ret.Get(0).([]string)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.SubscriptionFilter) error); ok {
-		r1 = returnFunc(ctx, filter)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, string, *int) error); ok {
+		r1 = returnFunc(ctx, query, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
 	return r0, r1
 }
 
-// MockSubscriptionsService_List_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'List'
-type MockSubscriptionsService_List_Call struct {
+// MockSubscriptionsService_SuggestServiceNames_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SuggestServiceNames'
+type MockSubscriptionsService_SuggestServiceNames_Call struct {
 	*mock.Call
 }
 
-// List is a helper method to define mock.On call
+// SuggestServiceNames is a helper method to define mock.On call
 //   - ctx context.Context
-//   - filter domain.SubscriptionFilter
-func (_e *MockSubscriptionsService_Expecter) List(ctx interface{}, filter interface{}) *MockSubscriptionsService_List_Call {
-	return &MockSubscriptionsService_List_Call{Call: _e.mock.On("List", ctx, filter)}
+//   - query string
+//   - limit *int
+func (_e *MockSubscriptionsService_Expecter) SuggestServiceNames(ctx interface{}, query interface{}, limit interface{}) *MockSubscriptionsService_SuggestServiceNames_Call {
+	return &MockSubscriptionsService_SuggestServiceNames_Call{Call: _e.mock.On("SuggestServiceNames", ctx, query, limit)}
 }
 
-func (_c *MockSubscriptionsService_List_Call) Run(run func(ctx context.Context, filter domain.SubscriptionFilter)) *MockSubscriptionsService_List_Call {
+func (_c *MockSubscriptionsService_SuggestServiceNames_Call) Run(run func(ctx context.Context, query string, limit *int)) *MockSubscriptionsService_SuggestServiceNames_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 domain.SubscriptionFilter
+		var arg1 string
 		if args[1] != nil {
-			arg1 = args[1].(domain.SubscriptionFilter)
+			arg1 = args[1].(string)
+		}
+		var arg2 *int
+		if args[2] != nil {
+			arg2 = args[2].(*int)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockSubscriptionsService_List_Call) Return(subscriptions []domain.Subscription, err error) *MockSubscriptionsService_List_Call {
-	_c.Call.Return(subscriptions, err)
+func (_c *MockSubscriptionsService_SuggestServiceNames_Call) Return(strings []string, err error) *MockSubscriptionsService_SuggestServiceNames_Call {
+	_c.Call.Return(strings, err)
 	return _c
 }
 
-func (_c *MockSubscriptionsService_List_Call) RunAndReturn(run func(ctx context.Context, filter domain.SubscriptionFilter) ([]domain.Subscription, error)) *MockSubscriptionsService_List_Call {
+func (_c *MockSubscriptionsService_SuggestServiceNames_Call) RunAndReturn(run func(ctx context.Context, query string, limit *int) ([]string, error)) *MockSubscriptionsService_SuggestServiceNames_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // TotalCost provides a mock function for the type MockSubscriptionsService
-func (_mock *MockSubscriptionsService) TotalCost(ctx context.Context, filter domain.SubscriptionFilter, start time.Time, end time.Time) (int, error) {
-	ret := _mock.Called(ctx, filter, start, end)
+func (_mock *MockSubscriptionsService) TotalCost(ctx context.Context, filter domain.SubscriptionFilter, start time.Time, end time.Time, currency string, mode domain.CostMode) (int, error) {
+	ret := _mock.Called(ctx, filter, start, end, currency, mode)
 
 	if len(ret) == 0 {
 		panic("no return value specified for TotalCost")
@@ -311,16 +1669,16 @@ func (_mock *MockSubscriptionsService) TotalCost(ctx context.Context, filter dom
 
 	var r0 int
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter, time.Time, time.Time) (int, error)); ok {
-		return returnFunc(ctx, filter, start, end)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter, time.Time, time.Time, string, domain.CostMode) (int, error)); ok {
+		return returnFunc(ctx, filter, start, end, currency, mode)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter, time.Time, time.Time) int); ok {
-		r0 = returnFunc(ctx, filter, start, end)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, domain.SubscriptionFilter, time.Time, time.Time, string, domain.CostMode) int); ok {
+		r0 = returnFunc(ctx, filter, start, end, currency, mode)
 	} else {
 		r0 = ret.Get(0).(int)
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.SubscriptionFilter, time.Time, time.Time) error); ok {
-		r1 = returnFunc(ctx, filter, start, end)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, domain.SubscriptionFilter, time.Time, time.Time, string, domain.CostMode) error); ok {
+		r1 = returnFunc(ctx, filter, start, end, currency, mode)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -337,11 +1695,13 @@ type MockSubscriptionsService_TotalCost_Call struct {
 //   - filter domain.SubscriptionFilter
 //   - start time.Time
 //   - end time.Time
-func (_e *MockSubscriptionsService_Expecter) TotalCost(ctx interface{}, filter interface{}, start interface{}, end interface{}) *MockSubscriptionsService_TotalCost_Call {
-	return &MockSubscriptionsService_TotalCost_Call{Call: _e.mock.On("TotalCost", ctx, filter, start, end)}
+//   - currency string
+//   - mode domain.CostMode
+func (_e *MockSubscriptionsService_Expecter) TotalCost(ctx interface{}, filter interface{}, start interface{}, end interface{}, currency interface{}, mode interface{}) *MockSubscriptionsService_TotalCost_Call {
+	return &MockSubscriptionsService_TotalCost_Call{Call: _e.mock.On("TotalCost", ctx, filter, start, end, currency, mode)}
 }
 
-func (_c *MockSubscriptionsService_TotalCost_Call) Run(run func(ctx context.Context, filter domain.SubscriptionFilter, start time.Time, end time.Time)) *MockSubscriptionsService_TotalCost_Call {
+func (_c *MockSubscriptionsService_TotalCost_Call) Run(run func(ctx context.Context, filter domain.SubscriptionFilter, start time.Time, end time.Time, currency string, mode domain.CostMode)) *MockSubscriptionsService_TotalCost_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -359,11 +1719,21 @@ func (_c *MockSubscriptionsService_TotalCost_Call) Run(run func(ctx context.Cont
 		if args[3] != nil {
 			arg3 = args[3].(time.Time)
 		}
+		var arg4 string
+		if args[4] != nil {
+			arg4 = args[4].(string)
+		}
+		var arg5 domain.CostMode
+		if args[5] != nil {
+			arg5 = args[5].(domain.CostMode)
+		}
 		run(
 			arg0,
 			arg1,
 			arg2,
 			arg3,
+			arg4,
+			arg5,
 		)
 	})
 	return _c
@@ -374,14 +1744,14 @@ func (_c *MockSubscriptionsService_TotalCost_Call) Return(n int, err error) *Moc
 	return _c
 }
 
-func (_c *MockSubscriptionsService_TotalCost_Call) RunAndReturn(run func(ctx context.Context, filter domain.SubscriptionFilter, start time.Time, end time.Time) (int, error)) *MockSubscriptionsService_TotalCost_Call {
+func (_c *MockSubscriptionsService_TotalCost_Call) RunAndReturn(run func(ctx context.Context, filter domain.SubscriptionFilter, start time.Time, end time.Time, currency string, mode domain.CostMode) (int, error)) *MockSubscriptionsService_TotalCost_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
 // Update provides a mock function for the type MockSubscriptionsService
-func (_mock *MockSubscriptionsService) Update(ctx context.Context, id uuid.UUID, update domain.SubscriptionUpdate) (*domain.Subscription, error) {
-	ret := _mock.Called(ctx, id, update)
+func (_mock *MockSubscriptionsService) Update(ctx context.Context, id uuid.UUID, version *int, update domain.SubscriptionUpdate) (*domain.Subscription, error) {
+	ret := _mock.Called(ctx, id, version, update)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Update")
@@ -389,18 +1759,18 @@ func (_mock *MockSubscriptionsService) Update(ctx context.Context, id uuid.UUID,
 
 	var r0 *domain.Subscription
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.SubscriptionUpdate) (*domain.Subscription, error)); ok {
-		return returnFunc(ctx, id, update)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *int, domain.SubscriptionUpdate) (*domain.Subscription, error)); ok {
+		return returnFunc(ctx, id, version, update)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, domain.SubscriptionUpdate) *domain.Subscription); ok {
-		r0 = returnFunc(ctx, id, update)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, uuid.UUID, *int, domain.SubscriptionUpdate) *domain.Subscription); ok {
+		r0 = returnFunc(ctx, id, version, update)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*domain.Subscription)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, domain.SubscriptionUpdate) error); ok {
-		r1 = returnFunc(ctx, id, update)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, uuid.UUID, *int, domain.SubscriptionUpdate) error); ok {
+		r1 = returnFunc(ctx, id, version, update)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -415,12 +1785,13 @@ type MockSubscriptionsService_Update_Call struct {
 // Update is a helper method to define mock.On call
 //   - ctx context.Context
 //   - id uuid.UUID
+//   - version *int
 //   - update domain.SubscriptionUpdate
-func (_e *MockSubscriptionsService_Expecter) Update(ctx interface{}, id interface{}, update interface{}) *MockSubscriptionsService_Update_Call {
-	return &MockSubscriptionsService_Update_Call{Call: _e.mock.On("Update", ctx, id, update)}
+func (_e *MockSubscriptionsService_Expecter) Update(ctx interface{}, id interface{}, version interface{}, update interface{}) *MockSubscriptionsService_Update_Call {
+	return &MockSubscriptionsService_Update_Call{Call: _e.mock.On("Update", ctx, id, version, update)}
 }
 
-func (_c *MockSubscriptionsService_Update_Call) Run(run func(ctx context.Context, id uuid.UUID, update domain.SubscriptionUpdate)) *MockSubscriptionsService_Update_Call {
+func (_c *MockSubscriptionsService_Update_Call) Run(run func(ctx context.Context, id uuid.UUID, version *int, update domain.SubscriptionUpdate)) *MockSubscriptionsService_Update_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -430,14 +1801,19 @@ func (_c *MockSubscriptionsService_Update_Call) Run(run func(ctx context.Context
 		if args[1] != nil {
 			arg1 = args[1].(uuid.UUID)
 		}
-		var arg2 domain.SubscriptionUpdate
+		var arg2 *int
 		if args[2] != nil {
-			arg2 = args[2].(domain.SubscriptionUpdate)
+			arg2 = args[2].(*int)
+		}
+		var arg3 domain.SubscriptionUpdate
+		if args[3] != nil {
+			arg3 = args[3].(domain.SubscriptionUpdate)
 		}
 		run(
 			arg0,
 			arg1,
 			arg2,
+			arg3,
 		)
 	})
 	return _c
@@ -448,7 +1824,7 @@ func (_c *MockSubscriptionsService_Update_Call) Return(subscription *domain.Subs
 	return _c
 }
 
-func (_c *MockSubscriptionsService_Update_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, update domain.SubscriptionUpdate) (*domain.Subscription, error)) *MockSubscriptionsService_Update_Call {
+func (_c *MockSubscriptionsService_Update_Call) RunAndReturn(run func(ctx context.Context, id uuid.UUID, version *int, update domain.SubscriptionUpdate) (*domain.Subscription, error)) *MockSubscriptionsService_Update_Call {
 	_c.Call.Return(run)
 	return _c
 }
@@ -2,6 +2,7 @@ package subservice
 
 import (
 	"context"
+	"iter"
 	"time"
 
 	"github.com/google/uuid"
@@ -11,9 +12,49 @@ import (
 //go:generate mockery
 type SubscriptionsService interface {
 	Create(ctx context.Context, sub domain.Subscription) (*domain.Subscription, error)
+	// CreateIdempotent creates sub once per key. Retries with the same request get
+	// the subscription created by the first one and replayed set. Reusing the key
+	// for another request fails with KindDuplicate.
+	CreateIdempotent(ctx context.Context, sub domain.Subscription, key domain.IdempotencyKey) (created *domain.Subscription, replayed bool, err error)
 	GetByID(ctx context.Context, id uuid.UUID) (*domain.Subscription, error)
-	Update(ctx context.Context, id uuid.UUID, update domain.SubscriptionUpdate) (*domain.Subscription, error)
-	Delete(ctx context.Context, id uuid.UUID) error
-	List(ctx context.Context, filter domain.SubscriptionFilter) ([]domain.Subscription, error)
-	TotalCost(ctx context.Context, filter domain.SubscriptionFilter, start, end time.Time) (int, error)
+	// Update, Patch and Delete fail with KindConflict when version is set and the
+	// subscription is at another version.
+	Update(ctx context.Context, id uuid.UUID, version *int, update domain.SubscriptionUpdate) (*domain.Subscription, error)
+	// Patch updates the subscription with the update patch makes of its current
+	// state. patch runs in the same transaction as the update and its errors are
+	// returned as is.
+	Patch(ctx context.Context, id uuid.UUID, version *int, patch func(current domain.Subscription) (domain.SubscriptionUpdate, error)) (*domain.Subscription, error)
+	Delete(ctx context.Context, id uuid.UUID, version *int) error
+	// Batch applies ops in order and returns their results in the same order.
+	// Atomic batches run in one transaction and stop at the first failed op, the
+	// others get ErrNotApplied. Otherwise every op is applied on its own.
+	Batch(ctx context.Context, ops []domain.BatchOp, atomic bool) ([]domain.BatchResult, error)
+	List(ctx context.Context, filter domain.SubscriptionFilter) (*domain.SubscriptionPage, error)
+	// Export yields all subscriptions matching filter like List, one at a time as
+	// they are read.
+	Export(ctx context.Context, filter domain.SubscriptionFilter) iter.Seq2[domain.Subscription, error]
+	SuggestServiceNames(ctx context.Context, query string, limit *int) ([]string, error)
+	TotalCost(ctx context.Context, filter domain.SubscriptionFilter, start, end time.Time, currency string, mode domain.CostMode) (int, error)
+	CostBreakdown(ctx context.Context, filter domain.SubscriptionFilter, start, end time.Time, currency string, mode domain.CostMode) ([]domain.MonthCost, error)
+	ListPriceChanges(ctx context.Context, id uuid.UUID) ([]domain.PriceChange, error)
+	Pause(ctx context.Context, id uuid.UUID, pausedFrom, resumedFrom *time.Time) (*domain.Pause, error)
+	Resume(ctx context.Context, id uuid.UUID, resumedFrom *time.Time) (*domain.Pause, error)
+	ListPauses(ctx context.Context, id uuid.UUID) ([]domain.Pause, error)
+	// History returns the audit entries of the subscription, oldest first. It
+	// outlives the subscription.
+	History(ctx context.Context, id uuid.UUID) ([]domain.AuditEntry, error)
+	ListAudit(ctx context.Context, filter domain.AuditFilter) (*domain.AuditPage, error)
+
+	ListCurrencyRates(ctx context.Context) ([]domain.CurrencyRate, error)
+	SetCurrencyRate(ctx context.Context, rate domain.CurrencyRate) (*domain.CurrencyRate, error)
+	DeleteCurrencyRate(ctx context.Context, from, to string) error
+
+	// MintAPIKey creates a key with scopes and returns it along with the secret
+	// callers authenticate with. Only the hash of the secret is kept.
+	MintAPIKey(ctx context.Context, name string, scopes []domain.Scope, expiresAt *time.Time) (key *domain.APIKey, secret string, err error)
+	ListAPIKeys(ctx context.Context) ([]domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id uuid.UUID) error
+	// AuthenticateAPIKey returns the live key of secret and records it was used.
+	// Unknown, expired and revoked keys fail with KindNotFound.
+	AuthenticateAPIKey(ctx context.Context, secret string) (*domain.APIKey, error)
 }
@@ -39,6 +39,98 @@ func (_m *MockUnitOfWork) EXPECT() *MockUnitOfWork_Expecter {
 	return &MockUnitOfWork_Expecter{mock: &_m.Mock}
 }
 
+// Audit provides a mock function for the type MockUnitOfWork
+func (_mock *MockUnitOfWork) Audit() repos.AuditRepository {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Audit")
+	}
+
+	var r0 repos.AuditRepository
+	if returnFunc, ok := ret.Get(0).(func() repos.AuditRepository); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repos.AuditRepository)
+		}
+	}
+	return r0
+}
+
+// MockUnitOfWork_Audit_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Audit'
+type MockUnitOfWork_Audit_Call struct {
+	*mock.Call
+}
+
+// Audit is a helper method to define mock.On call
+func (_e *MockUnitOfWork_Expecter) Audit() *MockUnitOfWork_Audit_Call {
+	return &MockUnitOfWork_Audit_Call{Call: _e.mock.On("Audit")}
+}
+
+func (_c *MockUnitOfWork_Audit_Call) Run(run func()) *MockUnitOfWork_Audit_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockUnitOfWork_Audit_Call) Return(auditRepository repos.AuditRepository) *MockUnitOfWork_Audit_Call {
+	_c.Call.Return(auditRepository)
+	return _c
+}
+
+func (_c *MockUnitOfWork_Audit_Call) RunAndReturn(run func() repos.AuditRepository) *MockUnitOfWork_Audit_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// IdempotencyKeys provides a mock function for the type MockUnitOfWork
+func (_mock *MockUnitOfWork) IdempotencyKeys() repos.IdempotencyKeyRepository {
+	ret := _mock.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for IdempotencyKeys")
+	}
+
+	var r0 repos.IdempotencyKeyRepository
+	if returnFunc, ok := ret.Get(0).(func() repos.IdempotencyKeyRepository); ok {
+		r0 = returnFunc()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(repos.IdempotencyKeyRepository)
+		}
+	}
+	return r0
+}
+
+// MockUnitOfWork_IdempotencyKeys_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'IdempotencyKeys'
+type MockUnitOfWork_IdempotencyKeys_Call struct {
+	*mock.Call
+}
+
+// IdempotencyKeys is a helper method to define mock.On call
+func (_e *MockUnitOfWork_Expecter) IdempotencyKeys() *MockUnitOfWork_IdempotencyKeys_Call {
+	return &MockUnitOfWork_IdempotencyKeys_Call{Call: _e.mock.On("IdempotencyKeys")}
+}
+
+func (_c *MockUnitOfWork_IdempotencyKeys_Call) Run(run func()) *MockUnitOfWork_IdempotencyKeys_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockUnitOfWork_IdempotencyKeys_Call) Return(idempotencyKeyRepository repos.IdempotencyKeyRepository) *MockUnitOfWork_IdempotencyKeys_Call {
+	_c.Call.Return(idempotencyKeyRepository)
+	return _c
+}
+
+func (_c *MockUnitOfWork_IdempotencyKeys_Call) RunAndReturn(run func() repos.IdempotencyKeyRepository) *MockUnitOfWork_IdempotencyKeys_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Subscriptions provides a mock function for the type MockUnitOfWork
 func (_mock *MockUnitOfWork) Subscriptions() repos.SubscriptionRepository {
 	ret := _mock.Called()
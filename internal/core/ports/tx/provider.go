@@ -9,6 +9,8 @@ import (
 //go:generate mockery
 type UnitOfWork interface {
 	Subscriptions() repos.SubscriptionRepository
+	IdempotencyKeys() repos.IdempotencyKeyRepository
+	Audit() repos.AuditRepository
 }
 
 //go:generate mockery
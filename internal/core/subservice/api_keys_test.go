@@ -0,0 +1,188 @@
+package subservice
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
+	"github.com/shrtyk/subscriptions-service/pkg/errkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_MintAPIKey(t *testing.T) {
+	ctx := context.Background()
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+
+	testCases := []struct {
+		name         string
+		scopes       []domain.Scope
+		expiresAt    *time.Time
+		setupMocks   func(bundle serviceTestBundle)
+		expectedKind *subservice.ServiceKind
+	}{
+		{
+			name:      "Success",
+			scopes:    []domain.Scope{domain.ScopeWrite, domain.ScopeRead, domain.ScopeWrite},
+			expiresAt: &future,
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.apiKeys.On("Create", ctx, mock.AnythingOfType("*domain.APIKey")).Return(nil).Once()
+			},
+		},
+		{
+			name:         "No scopes",
+			setupMocks:   func(bundle serviceTestBundle) {},
+			expectedKind: func(k subservice.ServiceKind) *subservice.ServiceKind { return &k }(subservice.KindBusinessLogic),
+		},
+		{
+			name:         "Unknown scope",
+			scopes:       []domain.Scope{"delete"},
+			setupMocks:   func(bundle serviceTestBundle) {},
+			expectedKind: func(k subservice.ServiceKind) *subservice.ServiceKind { return &k }(subservice.KindBusinessLogic),
+		},
+		{
+			name:         "Expired",
+			scopes:       []domain.Scope{domain.ScopeRead},
+			expiresAt:    &past,
+			setupMocks:   func(bundle serviceTestBundle) {},
+			expectedKind: func(k subservice.ServiceKind) *subservice.ServiceKind { return &k }(subservice.KindBusinessLogic),
+		},
+		{
+			name:   "Repo Create fails",
+			scopes: []domain.Scope{domain.ScopeRead},
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.apiKeys.On("Create", ctx, mock.AnythingOfType("*domain.APIKey")).Return(errors.New("db error")).Once()
+			},
+			expectedKind: func(k subservice.ServiceKind) *subservice.ServiceKind { return &k }(subservice.KindUnknown),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bundle := setup(t)
+			tc.setupMocks(bundle)
+
+			key, secret, err := bundle.svc.MintAPIKey(ctx, "billing", tc.scopes, tc.expiresAt)
+			if tc.expectedKind != nil {
+				assert.Nil(t, key)
+				assert.Empty(t, secret)
+				var svcErr *errkit.BaseErr[subservice.ServiceKind]
+				require.ErrorAs(t, err, &svcErr)
+				assert.Equal(t, *tc.expectedKind, svcErr.Kind)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.True(t, strings.HasPrefix(secret, "sk_"))
+			assert.True(t, strings.HasPrefix(secret, key.Prefix))
+			assert.Equal(t, hashAPIKey(secret), key.Hash)
+			assert.Equal(t, []domain.Scope{domain.ScopeRead, domain.ScopeWrite}, key.Scopes)
+			assert.Equal(t, tc.expiresAt, key.ExpiresAt)
+		})
+	}
+
+	t.Run("Secrets differ", func(t *testing.T) {
+		bundle := setup(t)
+		bundle.apiKeys.On("Create", ctx, mock.AnythingOfType("*domain.APIKey")).Return(nil).Twice()
+
+		_, first, err := bundle.svc.MintAPIKey(ctx, "a", []domain.Scope{domain.ScopeRead}, nil)
+		require.NoError(t, err)
+		_, second, err := bundle.svc.MintAPIKey(ctx, "b", []domain.Scope{domain.ScopeRead}, nil)
+		require.NoError(t, err)
+		assert.NotEqual(t, first, second)
+	})
+}
+
+func TestService_RevokeAPIKey(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+
+	testCases := []struct {
+		name         string
+		repoErr      error
+		expectedKind *subservice.ServiceKind
+	}{
+		{
+			name: "Success",
+		},
+		{
+			name:         "Not Found",
+			repoErr:      errkit.WrapErr("op", repos.KindNotFound, errors.New("not found")),
+			expectedKind: func(k subservice.ServiceKind) *subservice.ServiceKind { return &k }(subservice.KindNotFound),
+		},
+		{
+			name:         "Generic Repo Error",
+			repoErr:      errors.New("db error"),
+			expectedKind: func(k subservice.ServiceKind) *subservice.ServiceKind { return &k }(subservice.KindUnknown),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bundle := setup(t)
+			bundle.apiKeys.On("Revoke", ctx, id).Return(tc.repoErr).Once()
+
+			err := bundle.svc.RevokeAPIKey(ctx, id)
+			if tc.expectedKind == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var svcErr *errkit.BaseErr[subservice.ServiceKind]
+			require.ErrorAs(t, err, &svcErr)
+			assert.Equal(t, *tc.expectedKind, svcErr.Kind)
+		})
+	}
+}
+
+func TestService_AuthenticateAPIKey(t *testing.T) {
+	ctx := context.Background()
+	secret := "sk_secret"
+
+	testCases := []struct {
+		name         string
+		key          *domain.APIKey
+		repoErr      error
+		expectedKind *subservice.ServiceKind
+	}{
+		{
+			name: "Success",
+			key:  &domain.APIKey{ID: uuid.New(), Scopes: []domain.Scope{domain.ScopeRead}},
+		},
+		{
+			name:         "Unknown key",
+			repoErr:      errkit.WrapErr("op", repos.KindNotFound, errors.New("not found")),
+			expectedKind: func(k subservice.ServiceKind) *subservice.ServiceKind { return &k }(subservice.KindNotFound),
+		},
+		{
+			name:         "Generic Repo Error",
+			repoErr:      errors.New("db error"),
+			expectedKind: func(k subservice.ServiceKind) *subservice.ServiceKind { return &k }(subservice.KindUnknown),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bundle := setup(t)
+			bundle.apiKeys.On("Use", ctx, hashAPIKey(secret)).Return(tc.key, tc.repoErr).Once()
+
+			key, err := bundle.svc.AuthenticateAPIKey(ctx, secret)
+			if tc.expectedKind == nil {
+				require.NoError(t, err)
+				assert.Equal(t, tc.key, key)
+				return
+			}
+			assert.Nil(t, key)
+			var svcErr *errkit.BaseErr[subservice.ServiceKind]
+			require.ErrorAs(t, err, &svcErr)
+			assert.Equal(t, *tc.expectedKind, svcErr.Kind)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package subservice
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/authz"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
+	"github.com/shrtyk/subscriptions-service/pkg/log"
+)
+
+const (
+	opHistory   = "subservice.History"
+	opListAudit = "subservice.ListAudit"
+)
+
+// History authorizes reading the subscription as of its last entry, the one it
+// was deleted with if it is gone. Subscriptions without entries are looked up.
+// The entries are cut off before the latest one with a snapshot of an owner the
+// caller may not read, so the owner of a subscription handed over to them isn't
+// shown how it was before.
+func (s *service) History(ctx context.Context, id uuid.UUID) ([]domain.AuditEntry, error) {
+	entries, err := s.audit.ListBySubscription(ctx, id)
+	if err != nil {
+		return nil, subservice.WrapErr(opHistory, subservice.KindUnknown, err)
+	}
+
+	var userID uuid.UUID
+	if len(entries) == 0 {
+		sub, err := s.repo.GetByID(ctx, id)
+		if err != nil {
+			if isRepoNotFound(err) {
+				return nil, subservice.WrapErr(opHistory, subservice.KindNotFound, err)
+			}
+			return nil, subservice.WrapErr(opHistory, subservice.KindUnknown, err)
+		}
+		userID = sub.UserID
+	} else {
+		last := entries[len(entries)-1]
+		if last.After != nil {
+			userID = last.After.UserID
+		} else {
+			userID = last.Before.UserID
+		}
+	}
+	if err := s.authorize(ctx, opHistory, authz.ReadSubscriptions, userID); err != nil {
+		return nil, err
+	}
+
+	for i := len(entries) - 1; i >= 0; i-- {
+		for _, snapshot := range []*domain.Subscription{entries[i].Before, entries[i].After} {
+			if snapshot == nil || snapshot.UserID == userID {
+				continue
+			}
+			err := s.authz.Authorize(ctx, authz.ReadSubscriptions, snapshot.UserID)
+			if errors.Is(err, authz.ErrDenied) {
+				return entries[i+1:], nil
+			}
+			if err != nil {
+				return nil, wrapAuthzErr(opHistory, err)
+			}
+		}
+	}
+	return entries, nil
+}
+
+func (s *service) ListAudit(ctx context.Context, filter domain.AuditFilter) (*domain.AuditPage, error) {
+	if err := s.authorizeAll(ctx, opListAudit, authz.ReadAudit); err != nil {
+		return nil, err
+	}
+
+	log.FromCtx(ctx).Debug("listing audit entries", slog.Any("filter", filter))
+	page, err := s.audit.List(ctx, filter)
+	if err != nil {
+		return nil, subservice.WrapErr(opListAudit, subservice.KindUnknown, err)
+	}
+	return page, nil
+}
+
+// recordAudit records op changing a subscription from before to after with
+// audit, by the caller of the request ctx belongs to. It has to run in the
+// transaction of the change, so that entries are never missing or made up.
+func recordAudit(ctx context.Context, audit repos.AuditRepository, op domain.AuditOp, before, after *domain.Subscription) error {
+	entry := domain.AuditEntry{
+		Op:        op,
+		RequestID: domain.RequestIDFromCtx(ctx),
+		Before:    before,
+		After:     after,
+	}
+	if after != nil {
+		entry.SubscriptionID = after.ID
+	} else {
+		entry.SubscriptionID = before.ID
+	}
+	if caller, ok := domain.PrincipalFromCtx(ctx); ok {
+		entry.ActorID = caller.UserID
+		entry.ActorRole = caller.Role
+		entry.APIKeyID = caller.APIKeyID
+	}
+	return audit.Record(ctx, &entry)
+}
@@ -0,0 +1,192 @@
+package subservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/authz"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
+	"github.com/shrtyk/subscriptions-service/pkg/errkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_History(t *testing.T) {
+	ctx := context.Background()
+	subID := uuid.New()
+	userID := uuid.New()
+	sub := &domain.Subscription{ID: subID, UserID: userID}
+	repoErrNotFound := errkit.WrapErr("op", repos.KindNotFound, errors.New("not found"))
+	handedOver := &domain.Subscription{ID: subID, UserID: uuid.New()}
+	entries := []domain.AuditEntry{
+		{ID: 1, SubscriptionID: subID, Op: domain.AuditCreate, After: handedOver},
+		{ID: 2, SubscriptionID: subID, Op: domain.AuditUpdate, Before: handedOver, After: sub},
+		{ID: 3, SubscriptionID: subID, Op: domain.AuditDelete, Before: sub},
+	}
+
+	testCases := []struct {
+		name       string
+		setupMocks func(bundle serviceTestBundle)
+		assertFunc func(t *testing.T, entries []domain.AuditEntry, err error)
+	}{
+		{
+			name: "Success - Deleted Subscription",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.audit.On("ListBySubscription", ctx, subID).Return(entries, nil).Once()
+			},
+			assertFunc: func(t *testing.T, actual []domain.AuditEntry, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, entries, actual)
+			},
+		},
+		{
+			name: "Success - No Entries",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.audit.On("ListBySubscription", ctx, subID).Return([]domain.AuditEntry{}, nil).Once()
+				bundle.repo.On("GetByID", ctx, subID).Return(sub, nil).Once()
+			},
+			assertFunc: func(t *testing.T, actual []domain.AuditEntry, err error) {
+				require.NoError(t, err)
+				assert.Empty(t, actual)
+			},
+		},
+		{
+			name: "Not Found",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.audit.On("ListBySubscription", ctx, subID).Return([]domain.AuditEntry{}, nil).Once()
+				bundle.repo.On("GetByID", ctx, subID).Return(nil, repoErrNotFound).Once()
+			},
+			assertFunc: func(t *testing.T, actual []domain.AuditEntry, err error) {
+				assert.Nil(t, actual)
+				assertServiceErrKind(t, err, subservice.KindNotFound)
+			},
+		},
+		{
+			name: "Generic Error",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.audit.On("ListBySubscription", ctx, subID).Return(nil, errors.New("db is down")).Once()
+			},
+			assertFunc: func(t *testing.T, actual []domain.AuditEntry, err error) {
+				assert.Nil(t, actual)
+				assertServiceErrKind(t, err, subservice.KindUnknown)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bundle := setup(t)
+			tc.setupMocks(bundle)
+			actual, err := bundle.svc.History(ctx, subID)
+			tc.assertFunc(t, actual, err)
+		})
+	}
+}
+
+func TestService_HistoryAuthorization(t *testing.T) {
+	callerID := uuid.New()
+	subID := uuid.New()
+	ctx := domain.PrincipalToCtx(context.Background(), &domain.Principal{UserID: callerID, Role: domain.RoleViewer})
+	owned := func(userID uuid.UUID) []domain.AuditEntry {
+		return []domain.AuditEntry{{ID: 1, SubscriptionID: subID, Op: domain.AuditCreate, After: &domain.Subscription{ID: subID, UserID: userID}}}
+	}
+
+	t.Run("Own Subscription", func(t *testing.T) {
+		bundle := setupWithPolicy(t)
+		bundle.audit.On("ListBySubscription", ctx, subID).Return(owned(callerID), nil).Once()
+
+		entries, err := bundle.svc.History(ctx, subID)
+		require.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("Subscription Of Another User", func(t *testing.T) {
+		bundle := setupWithPolicy(t)
+		bundle.audit.On("ListBySubscription", ctx, subID).Return(owned(uuid.New()), nil).Once()
+
+		entries, err := bundle.svc.History(ctx, subID)
+		assert.Nil(t, entries)
+		assertServiceErrKind(t, err, subservice.KindForbidden)
+	})
+
+	t.Run("Entries Of A Previous Owner Are Cut Off", func(t *testing.T) {
+		bundle := setupWithPolicy(t)
+		previous := &domain.Subscription{ID: subID, UserID: uuid.New()}
+		current := &domain.Subscription{ID: subID, UserID: callerID}
+		history := []domain.AuditEntry{
+			{ID: 1, SubscriptionID: subID, Op: domain.AuditCreate, After: previous},
+			{ID: 2, SubscriptionID: subID, Op: domain.AuditUpdate, Before: previous, After: current},
+			{ID: 3, SubscriptionID: subID, Op: domain.AuditUpdate, Before: current, After: current},
+		}
+		bundle.audit.On("ListBySubscription", ctx, subID).Return(history, nil).Once()
+
+		entries, err := bundle.svc.History(ctx, subID)
+		require.NoError(t, err)
+		assert.Equal(t, history[2:], entries)
+	})
+
+	t.Run("Audit Log Needs A Role Granting It", func(t *testing.T) {
+		bundle := setupWithPolicy(t)
+
+		page, err := bundle.svc.ListAudit(ctx, domain.AuditFilter{})
+		assert.Nil(t, page)
+		assertServiceErrKind(t, err, subservice.KindForbidden)
+	})
+}
+
+func TestService_ListAudit(t *testing.T) {
+	ctx := context.Background()
+	op := domain.AuditUpdate
+	filter := domain.AuditFilter{Op: &op}
+	page := &domain.AuditPage{Items: []domain.AuditEntry{{ID: 3, Op: op}}}
+
+	t.Run("Success", func(t *testing.T) {
+		bundle := setup(t)
+		bundle.audit.On("List", ctx, filter).Return(page, nil).Once()
+
+		actual, err := bundle.svc.ListAudit(ctx, filter)
+		require.NoError(t, err)
+		assert.Equal(t, page, actual)
+	})
+
+	t.Run("Generic Error", func(t *testing.T) {
+		bundle := setup(t)
+		bundle.audit.On("List", ctx, filter).Return(nil, errors.New("db is down")).Once()
+
+		actual, err := bundle.svc.ListAudit(ctx, filter)
+		assert.Nil(t, actual)
+		assertServiceErrKind(t, err, subservice.KindUnknown)
+	})
+
+	t.Run("Authorizes Reading The Audit Log", func(t *testing.T) {
+		bundle := setup(t)
+		bundle.audit.On("List", ctx, filter).Return(page, nil).Once()
+
+		_, err := bundle.svc.ListAudit(ctx, filter)
+		require.NoError(t, err)
+		bundle.authz.AssertCalled(t, "AuthorizeAll", ctx, authz.ReadAudit)
+	})
+}
+
+func TestRecordAudit(t *testing.T) {
+	keyID := uuid.New()
+	sub := &domain.Subscription{ID: uuid.New()}
+	ctx := domain.PrincipalToCtx(context.Background(), &domain.Principal{Role: domain.RoleService, APIKeyID: &keyID})
+	ctx = domain.RequestIDToCtx(ctx, "req-1")
+
+	bundle := setup(t)
+	bundle.audit.On("Record", ctx, &domain.AuditEntry{
+		SubscriptionID: sub.ID,
+		Op:             domain.AuditDelete,
+		ActorRole:      domain.RoleService,
+		APIKeyID:       &keyID,
+		RequestID:      "req-1",
+		Before:         sub,
+	}).Return(nil).Once()
+
+	require.NoError(t, recordAudit(ctx, bundle.audit, domain.AuditDelete, sub, nil))
+}
@@ -0,0 +1,76 @@
+package subservice
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/authz"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
+)
+
+// authorize fails with KindForbidden unless the caller may perform action on
+// the subscriptions of userID.
+func (s *service) authorize(ctx context.Context, op string, action authz.Action, userID uuid.UUID) error {
+	return wrapAuthzErr(op, s.authz.Authorize(ctx, action, userID))
+}
+
+// authorizeAll fails with KindForbidden unless the caller may perform action on
+// the subscriptions of all users.
+func (s *service) authorizeAll(ctx context.Context, op string, action authz.Action) error {
+	return wrapAuthzErr(op, s.authz.AuthorizeAll(ctx, action))
+}
+
+// scopeFilter limits filter to the subscriptions of the caller, unless it may
+// perform action on those of all users. Filters for another user fail.
+func (s *service) scopeFilter(ctx context.Context, op string, action authz.Action, filter *domain.SubscriptionFilter) error {
+	if filter.UserID != nil {
+		return s.authorize(ctx, op, action, *filter.UserID)
+	}
+
+	err := s.authz.AuthorizeAll(ctx, action)
+	if !errors.Is(err, authz.ErrDenied) {
+		return wrapAuthzErr(op, err)
+	}
+
+	caller, ok := domain.PrincipalFromCtx(ctx)
+	if !ok || caller.UserID == uuid.Nil {
+		return wrapAuthzErr(op, err)
+	}
+	if err := s.authorize(ctx, op, action, caller.UserID); err != nil {
+		return err
+	}
+	filter.UserID = &caller.UserID
+	return nil
+}
+
+// authorizedPatch wraps patch to fail unless the caller may change the current
+// subscription and, if the update moves it, the subscriptions of the new user.
+func (s *service) authorizedPatch(
+	ctx context.Context,
+	op string,
+	patch func(current domain.Subscription) (domain.SubscriptionUpdate, error),
+) func(current domain.Subscription) (domain.SubscriptionUpdate, error) {
+	return func(current domain.Subscription) (domain.SubscriptionUpdate, error) {
+		if err := s.authorize(ctx, op, authz.WriteSubscriptions, current.UserID); err != nil {
+			return domain.SubscriptionUpdate{}, err
+		}
+		update, err := patch(current)
+		if err != nil || update.UserID == nil || *update.UserID == current.UserID {
+			return update, err
+		}
+		return update, s.authorize(ctx, op, authz.WriteSubscriptions, *update.UserID)
+	}
+}
+
+func wrapAuthzErr(op string, err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, authz.ErrDenied):
+		return subservice.WrapErr(op, subservice.KindForbidden, err)
+	default:
+		return subservice.WrapErr(op, subservice.KindUnknown, err)
+	}
+}
@@ -0,0 +1,249 @@
+package subservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/config"
+	coreauthz "github.com/shrtyk/subscriptions-service/internal/core/authz"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	reposmocks "github.com/shrtyk/subscriptions-service/internal/core/ports/repos/mocks"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
+	txmocks "github.com/shrtyk/subscriptions-service/internal/core/ports/tx/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// setupWithPolicy is setup with a real policy in place of the permissive mock.
+func setupWithPolicy(t *testing.T) serviceTestBundle {
+	t.Helper()
+	policy, err := coreauthz.NewPolicy(&config.PolicyCfg{Roles: map[string]config.RoleGrants{
+		"viewer":  {Own: []string{"subscriptions:read", "costs:read"}},
+		"editor":  {Own: []string{"subscriptions:read", "subscriptions:write", "costs:read"}},
+		"finance": {Own: []string{"subscriptions:read"}, Any: []string{"costs:read"}},
+	}})
+	require.NoError(t, err)
+
+	bundle := serviceTestBundle{
+		repo:       reposmocks.NewMockSubscriptionRepository(t),
+		ratesRepo:  reposmocks.NewMockCurrencyRateRepository(t),
+		apiKeys:    reposmocks.NewMockAPIKeyRepository(t),
+		audit:      reposmocks.NewMockAuditRepository(t),
+		txProvider: txmocks.NewMockProvider(t),
+	}
+	bundle.svc = New(bundle.repo, bundle.ratesRepo, bundle.apiKeys, bundle.audit, policy, bundle.txProvider)
+	return bundle
+}
+
+func TestService_Authorization(t *testing.T) {
+	callerID := uuid.New()
+	otherID := uuid.New()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	as := func(role domain.Role) context.Context {
+		return domain.PrincipalToCtx(context.Background(), &domain.Principal{UserID: callerID, Role: role})
+	}
+	editorCtx := as(domain.RoleEditor)
+	ownSub := &domain.Subscription{ID: uuid.New(), ServiceName: "Okko", Price: 300, UserID: callerID, StartDate: start}
+	otherSub := &domain.Subscription{ID: uuid.New(), ServiceName: "Ivi", Price: 200, UserID: otherID, StartDate: start}
+
+	testCases := []struct {
+		name       string
+		setupMocks func(bundle serviceTestBundle)
+		call       func(svc subservice.SubscriptionsService) error
+		wantKind   *subservice.ServiceKind
+	}{
+		{
+			name: "Viewer cannot create",
+			call: func(svc subservice.SubscriptionsService) error {
+				_, err := svc.Create(as(domain.RoleViewer), domain.Subscription{ServiceName: "Okko", Price: 300, UserID: callerID, StartDate: start})
+				return err
+			},
+			wantKind: kindOf(subservice.KindForbidden),
+		},
+		{
+			name: "Editor cannot create for another user",
+			call: func(svc subservice.SubscriptionsService) error {
+				_, err := svc.Create(as(domain.RoleEditor), domain.Subscription{ServiceName: "Okko", Price: 300, UserID: otherID, StartDate: start})
+				return err
+			},
+			wantKind: kindOf(subservice.KindForbidden),
+		},
+		{
+			name: "List is limited to the caller",
+			setupMocks: func(bundle serviceTestBundle) {
+				filter := domain.SubscriptionFilter{UserID: &callerID}
+				bundle.repo.On("List", mock.Anything, filter).Return(&domain.SubscriptionPage{Items: []domain.Subscription{*ownSub}}, nil).Once()
+				bundle.repo.On("Count", mock.Anything, filter).Return(1, nil).Once()
+			},
+			call: func(svc subservice.SubscriptionsService) error {
+				_, err := svc.List(as(domain.RoleViewer), domain.SubscriptionFilter{})
+				return err
+			},
+		},
+		{
+			name: "List of another user",
+			call: func(svc subservice.SubscriptionsService) error {
+				_, err := svc.List(as(domain.RoleEditor), domain.SubscriptionFilter{UserID: &otherID})
+				return err
+			},
+			wantKind: kindOf(subservice.KindForbidden),
+		},
+		{
+			name: "Get subscription of another user",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("GetByID", mock.Anything, otherSub.ID).Return(otherSub, nil).Once()
+			},
+			call: func(svc subservice.SubscriptionsService) error {
+				_, err := svc.GetByID(as(domain.RoleEditor), otherSub.ID)
+				return err
+			},
+			wantKind: kindOf(subservice.KindForbidden),
+		},
+		{
+			name: "Delete subscription of another user",
+			setupMocks: func(bundle serviceTestBundle) {
+				expectTx(t, editorCtx, bundle)
+				bundle.repo.On("Delete", mock.Anything, otherSub.ID, (*int)(nil)).Return(otherSub, nil).Once()
+			},
+			call: func(svc subservice.SubscriptionsService) error {
+				return svc.Delete(editorCtx, otherSub.ID, nil)
+			},
+			wantKind: kindOf(subservice.KindForbidden),
+		},
+		{
+			name: "Update cannot move the subscription to another user",
+			setupMocks: func(bundle serviceTestBundle) {
+				expectTx(t, editorCtx, bundle)
+				bundle.repo.On("GetByID", mock.Anything, ownSub.ID).Return(ownSub, nil).Once()
+			},
+			call: func(svc subservice.SubscriptionsService) error {
+				_, err := svc.Update(editorCtx, ownSub.ID, nil, domain.SubscriptionUpdate{UserID: &otherID})
+				return err
+			},
+			wantKind: kindOf(subservice.KindForbidden),
+		},
+		{
+			name: "Finance gets the total cost of any user",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("TotalCostByCurrency", mock.Anything, domain.SubscriptionFilter{UserID: &otherID}, start, end).
+					Return(map[string]int{"RUB": 2400}, nil).Once()
+			},
+			call: func(svc subservice.SubscriptionsService) error {
+				_, err := svc.TotalCost(as(domain.RoleFinance), domain.SubscriptionFilter{UserID: &otherID}, start, end, "RUB", domain.CostModeBilled)
+				return err
+			},
+		},
+		{
+			name: "Editor cannot get the total cost of another user",
+			call: func(svc subservice.SubscriptionsService) error {
+				_, err := svc.TotalCost(as(domain.RoleEditor), domain.SubscriptionFilter{UserID: &otherID}, start, end, "RUB", domain.CostModeBilled)
+				return err
+			},
+			wantKind: kindOf(subservice.KindForbidden),
+		},
+		{
+			name: "Finance cannot list subscriptions of another user",
+			call: func(svc subservice.SubscriptionsService) error {
+				_, err := svc.List(as(domain.RoleFinance), domain.SubscriptionFilter{UserID: &otherID})
+				return err
+			},
+			wantKind: kindOf(subservice.KindForbidden),
+		},
+		{
+			name: "Currency rates need a role granting them",
+			call: func(svc subservice.SubscriptionsService) error {
+				_, err := svc.ListCurrencyRates(as(domain.RoleFinance))
+				return err
+			},
+			wantKind: kindOf(subservice.KindForbidden),
+		},
+		{
+			name: "Unknown role",
+			call: func(svc subservice.SubscriptionsService) error {
+				_, err := svc.List(as("root"), domain.SubscriptionFilter{})
+				return err
+			},
+			wantKind: kindOf(subservice.KindForbidden),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bundle := setupWithPolicy(t)
+			if tc.setupMocks != nil {
+				tc.setupMocks(bundle)
+			}
+
+			err := tc.call(bundle.svc)
+			if tc.wantKind != nil {
+				assertServiceErrKind(t, err, *tc.wantKind)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_BatchAuthorization(t *testing.T) {
+	callerID := uuid.New()
+	otherID := uuid.New()
+	ctx := domain.PrincipalToCtx(context.Background(), &domain.Principal{UserID: callerID, Role: domain.RoleEditor})
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	create := func(userID uuid.UUID) domain.BatchOp {
+		return domain.BatchOp{Kind: domain.BatchCreate, Sub: domain.Subscription{ServiceName: "Okko", Price: 300, UserID: userID, StartDate: start}}
+	}
+	ops := []domain.BatchOp{create(callerID), create(otherID)}
+
+	t.Run("Atomic", func(t *testing.T) {
+		bundle := setupWithPolicy(t)
+
+		results, err := bundle.svc.Batch(ctx, ops, true)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.ErrorIs(t, results[0].Err, subservice.ErrNotApplied)
+		assertServiceErrKind(t, results[1].Err, subservice.KindForbidden)
+	})
+
+	t.Run("Best Effort", func(t *testing.T) {
+		bundle := setupWithPolicy(t)
+		expectTx(t, ctx, bundle)
+		bundle.repo.On("CreateBatch", ctx, mock.MatchedBy(func(subs []*domain.Subscription) bool {
+			return len(subs) == 1 && subs[0].UserID == callerID
+		})).Return(nil).Once()
+		bundle.audit.On("Record", ctx, mock.MatchedBy(func(e *domain.AuditEntry) bool {
+			return e.ActorID == callerID && e.ActorRole == domain.RoleEditor
+		})).Return(nil).Once()
+
+		results, err := bundle.svc.Batch(ctx, ops, false)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.NoError(t, results[0].Err)
+		assert.Equal(t, callerID, results[0].Sub.UserID)
+		assertServiceErrKind(t, results[1].Err, subservice.KindForbidden)
+	})
+
+	t.Run("Deletes Are Authorized Against The Stored Subscriptions", func(t *testing.T) {
+		bundle := setupWithPolicy(t)
+		own, others := uuid.New(), uuid.New()
+		expectTx(t, ctx, bundle)
+		bundle.repo.On("GetByID", ctx, own).Return(&domain.Subscription{ID: own, UserID: callerID, Version: 1}, nil).Once()
+		bundle.repo.On("GetByID", ctx, others).Return(&domain.Subscription{ID: others, UserID: otherID, Version: 1}, nil).Once()
+		bundle.repo.On("DeleteBatch", ctx, []uuid.UUID{own}, []int{1}).Return([]domain.Subscription{{ID: own, UserID: callerID}}, nil).Once()
+		expectAudit(bundle, domain.AuditDelete, 1)
+
+		deletes := []domain.BatchOp{{Kind: domain.BatchDelete, ID: own}, {Kind: domain.BatchDelete, ID: others}}
+		results, err := bundle.svc.Batch(ctx, deletes, false)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		require.NoError(t, results[0].Err)
+		assertServiceErrKind(t, results[1].Err, subservice.KindForbidden)
+	})
+}
+
+func kindOf(k subservice.ServiceKind) *subservice.ServiceKind {
+	return &k
+}
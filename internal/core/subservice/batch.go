@@ -0,0 +1,358 @@
+package subservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/authz"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/tx"
+	"github.com/shrtyk/subscriptions-service/pkg/log"
+)
+
+const opBatch = "subservice.Batch"
+
+// Batch writes consecutive creates and consecutive deletes with one statement each,
+// or one per chunk of creates too many for a statement.
+func (s *service) Batch(ctx context.Context, ops []domain.BatchOp, atomic bool) ([]domain.BatchResult, error) {
+	results := make([]domain.BatchResult, len(ops))
+	// Ops the caller may not apply are left out, index maps the others back to
+	// their position in ops.
+	allowed := make([]domain.BatchOp, 0, len(ops))
+	index := make([]int, 0, len(ops))
+	for i, op := range ops {
+		if err := s.authorizeOp(ctx, op); err != nil {
+			if atomic {
+				return rolledBack(ctx, len(ops), i, err), nil
+			}
+			results[i].Err = err
+			continue
+		}
+		allowed = append(allowed, op)
+		index = append(index, i)
+	}
+
+	if atomic {
+		return s.atomicBatch(ctx, ops)
+	}
+
+	applied := make([]domain.BatchResult, len(allowed))
+	for i := 0; i < len(allowed); {
+		n := 1
+		switch op := allowed[i]; op.Kind {
+		case domain.BatchCreate:
+			n = runLength(allowed[i:])
+			s.createEach(ctx, allowed[i:i+n], applied[i:i+n])
+		case domain.BatchUpdate:
+			applied[i].Sub, applied[i].Err = s.Update(ctx, op.ID, op.Version, op.Update)
+		case domain.BatchDelete:
+			n = runLength(allowed[i:])
+			s.deleteEach(ctx, allowed[i:i+n], applied[i:i+n])
+		}
+		i += n
+	}
+	for j, res := range applied {
+		results[index[j]] = res
+	}
+
+	logBatch(ctx, results)
+
+	return results, nil
+}
+
+// authorizeOp fails unless the caller may apply op. Updates and deletes are
+// authorized against the subscription they change when applied.
+func (s *service) authorizeOp(ctx context.Context, op domain.BatchOp) error {
+	if op.Kind != domain.BatchCreate {
+		return nil
+	}
+	return s.authorize(ctx, opBatch, authz.WriteSubscriptions, op.Sub.UserID)
+}
+
+func (s *service) atomicBatch(ctx context.Context, ops []domain.BatchOp) ([]domain.BatchResult, error) {
+	var results []domain.BatchResult
+	run := func(rowByRow bool) error {
+		return s.txProvider.WithTransaction(ctx, func(uow tx.UnitOfWork) error {
+			results = make([]domain.BatchResult, len(ops))
+			return s.applyBatch(ctx, uow, ops, results, rowByRow)
+		})
+	}
+
+	err := run(false)
+	var failure *batchFailure
+	// A failed multi-row insert doesn't tell which of its rows failed, the batch
+	// is retried row by row to find out.
+	if errors.As(err, &failure) && failure.bulk {
+		err = run(true)
+	}
+	if err == nil {
+		logBatch(ctx, results)
+		return results, nil
+	}
+	if !errors.As(err, &failure) {
+		return nil, subservice.WrapErr(opBatch, subservice.KindUnknown, err)
+	}
+
+	return rolledBack(ctx, len(ops), failure.index, s.writeErr(ctx, opBatch, failure.sub, failure.err)), nil
+}
+
+// rolledBack returns the results of an atomic batch of n ops that stopped at
+// op failed with err.
+func rolledBack(ctx context.Context, n, failed int, err error) []domain.BatchResult {
+	results := make([]domain.BatchResult, n)
+	for i := range results {
+		results[i] = domain.BatchResult{Err: subservice.ErrNotApplied}
+	}
+	results[failed].Err = err
+
+	log.FromCtx(ctx).Info("batch rolled back", slog.Int("failed_op", failed))
+
+	return results
+}
+
+// batchFailure is the op an atomic batch stopped at.
+type batchFailure struct {
+	index int
+	sub   *domain.Subscription // Subscription the op tried to store, if it got that far
+	bulk  bool                 // Set for multi-row inserts starting at index, any of their rows may have failed
+	err   error
+}
+
+func (f *batchFailure) Error() string {
+	return fmt.Sprintf("operation %d: %v", f.index, f.err)
+}
+
+func (f *batchFailure) Unwrap() error {
+	return f.err
+}
+
+// applyBatch applies and records ops in uow into results and stops at the first
+// failed op with a *batchFailure. Consecutive creates share a multi-row insert
+// unless rowByRow is set.
+func (s *service) applyBatch(
+	ctx context.Context,
+	uow tx.UnitOfWork,
+	ops []domain.BatchOp,
+	results []domain.BatchResult,
+	rowByRow bool,
+) error {
+	for i := 0; i < len(ops); {
+		n := 1
+		switch op := ops[i]; op.Kind {
+		case domain.BatchCreate:
+			if !rowByRow {
+				n = runLength(ops[i:])
+			}
+			subs := newSubs(ops[i : i+n])
+			if err := uow.Subscriptions().CreateBatch(ctx, subs); err != nil {
+				return &batchFailure{index: i, sub: subs[0], bulk: n > 1, err: err}
+			}
+			for j, sub := range subs {
+				if err := recordAudit(ctx, uow.Audit(), domain.AuditCreate, nil, sub); err != nil {
+					return &batchFailure{index: i + j, err: err}
+				}
+				results[i+j].Sub = sub
+			}
+		case domain.BatchUpdate:
+			sub, err := applyUpdate(ctx, opBatch, uow, op.ID, op.Version, s.authorizedPatch(ctx, opBatch, func(domain.Subscription) (domain.SubscriptionUpdate, error) {
+				return op.Update, nil
+			}))
+			if err != nil {
+				return &batchFailure{index: i, sub: sub, err: err}
+			}
+			results[i].Sub = sub
+		case domain.BatchDelete:
+			n = runLength(ops[i:])
+			deleted, errs, err := s.deleteRun(ctx, uow, ops[i:i+n])
+			if err != nil {
+				return &batchFailure{index: i, err: err}
+			}
+			for j, err := range errs {
+				if err != nil {
+					return &batchFailure{index: i + j, err: err}
+				}
+			}
+			if err := recordDeletes(ctx, uow.Audit(), deleted); err != nil {
+				return &batchFailure{index: i, err: err}
+			}
+		default:
+			return &batchFailure{index: i, err: subservice.WrapErr(opBatch, subservice.KindBusinessLogic, fmt.Errorf("unknown operation %q", op.Kind))}
+		}
+		i += n
+	}
+	return nil
+}
+
+// createEach creates the subscriptions of ops with one insert and falls back to
+// one insert per subscription to tell which ones failed.
+func (s *service) createEach(ctx context.Context, ops []domain.BatchOp, results []domain.BatchResult) {
+	subs := newSubs(ops)
+	err := s.createBatch(ctx, subs)
+	if err == nil {
+		for i, sub := range subs {
+			results[i].Sub = sub
+		}
+		return
+	}
+	if len(subs) == 1 {
+		results[0].Err = s.writeErr(ctx, opBatch, subs[0], err)
+		return
+	}
+
+	for i, sub := range subs {
+		if err := s.createBatch(ctx, []*domain.Subscription{sub}); err != nil {
+			results[i].Err = s.writeErr(ctx, opBatch, sub, err)
+			continue
+		}
+		results[i].Sub = sub
+	}
+}
+
+// createBatch creates and records subs in a transaction of their own.
+func (s *service) createBatch(ctx context.Context, subs []*domain.Subscription) error {
+	return s.txProvider.WithTransaction(ctx, func(uow tx.UnitOfWork) error {
+		if err := uow.Subscriptions().CreateBatch(ctx, subs); err != nil {
+			return err
+		}
+		for _, sub := range subs {
+			if err := recordAudit(ctx, uow.Audit(), domain.AuditCreate, nil, sub); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (s *service) deleteEach(ctx context.Context, ops []domain.BatchOp, results []domain.BatchResult) {
+	var errs []error
+	err := s.txProvider.WithTransaction(ctx, func(uow tx.UnitOfWork) error {
+		deleted, opErrs, err := s.deleteRun(ctx, uow, ops)
+		if err != nil {
+			return err
+		}
+		errs = opErrs
+		return recordDeletes(ctx, uow.Audit(), deleted)
+	})
+	if err != nil {
+		for i := range results {
+			results[i].Err = subservice.WrapErr(opBatch, subservice.KindUnknown, err)
+		}
+		return
+	}
+	for i, err := range errs {
+		results[i].Err = err
+	}
+}
+
+// deleteRun deletes the subscriptions of consecutive delete ops in uow with one
+// statement. Each subscription is authorized as read in uow and deleted at the
+// version read, so a subscription changed in between isn't deleted. errs tells
+// why ops failed, err fails all of them.
+func (s *service) deleteRun(
+	ctx context.Context,
+	uow tx.UnitOfWork,
+	ops []domain.BatchOp,
+) (deleted []domain.Subscription, errs []error, err error) {
+	repo := uow.Subscriptions()
+	errs = make([]error, len(ops))
+	ids := make([]uuid.UUID, 0, len(ops))
+	versions := make([]int, 0, len(ops))
+	index := make([]int, 0, len(ops))
+	for i, op := range ops {
+		// Of ops deleting the same subscription, only the first one finds it.
+		if slices.Contains(ids, op.ID) {
+			errs[i] = subservice.NewErr(opBatch, subservice.KindNotFound)
+			continue
+		}
+		sub, err := repo.GetByID(ctx, op.ID)
+		if err != nil {
+			if !isRepoNotFound(err) {
+				return nil, nil, err
+			}
+			errs[i] = subservice.WrapErr(opBatch, subservice.KindNotFound, err)
+			continue
+		}
+		if err := s.authorize(ctx, opBatch, authz.WriteSubscriptions, sub.UserID); err != nil {
+			errs[i] = err
+			continue
+		}
+		if op.Version != nil && *op.Version != sub.Version {
+			errs[i] = subservice.NewErr(opBatch, subservice.KindConflict)
+			continue
+		}
+		ids = append(ids, sub.ID)
+		versions = append(versions, sub.Version)
+		index = append(index, i)
+	}
+	if len(ids) == 0 {
+		return nil, errs, nil
+	}
+
+	deleted, err = repo.DeleteBatch(ctx, ids, versions)
+	if err != nil {
+		return nil, nil, err
+	}
+	for j, missing := range missingIDs(ids, deleted) {
+		if missing {
+			errs[index[j]] = subservice.NewErr(opBatch, subservice.KindConflict)
+		}
+	}
+	return deleted, errs, nil
+}
+
+// runLength returns the number of ops from the first one on of the same kind.
+func runLength(ops []domain.BatchOp) int {
+	n := 1
+	for n < len(ops) && ops[n].Kind == ops[0].Kind {
+		n++
+	}
+	return n
+}
+
+func newSubs(ops []domain.BatchOp) []*domain.Subscription {
+	subs := make([]*domain.Subscription, len(ops))
+	for i := range ops {
+		sub := ops[i].Sub
+		subs[i] = &sub
+	}
+	return subs
+}
+
+func recordDeletes(ctx context.Context, audit repos.AuditRepository, deleted []domain.Subscription) error {
+	for i := range deleted {
+		if err := recordAudit(ctx, audit, domain.AuditDelete, &deleted[i], nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// missingIDs reports for each id whether its subscription wasn't among deleted.
+func missingIDs(ids []uuid.UUID, deleted []domain.Subscription) []bool {
+	found := make(map[uuid.UUID]bool, len(deleted))
+	for _, sub := range deleted {
+		found[sub.ID] = true
+	}
+
+	missing := make([]bool, len(ids))
+	for i, id := range ids {
+		missing[i] = !found[id]
+	}
+	return missing
+}
+
+func logBatch(ctx context.Context, results []domain.BatchResult) {
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+		}
+	}
+	log.FromCtx(ctx).Info("batch applied", slog.Int("ops", len(results)), slog.Int("failed", failed))
+}
@@ -0,0 +1,216 @@
+package subservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
+	"github.com/shrtyk/subscriptions-service/pkg/errkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_Batch(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	subID := uuid.New()
+	otherID := uuid.New()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	repoErrDuplicate := errkit.WrapErr("op", repos.KindDuplicate, errors.New("conflicting key value"))
+	repoErrNotFound := errkit.WrapErr("op", repos.KindNotFound, errors.New("not found"))
+	newName := "Okko Premium"
+
+	create := func(name string) domain.BatchOp {
+		return domain.BatchOp{Kind: domain.BatchCreate, Sub: domain.Subscription{ServiceName: name, Price: 400, UserID: userID, StartDate: start}}
+	}
+	update := domain.BatchOp{Kind: domain.BatchUpdate, ID: subID, Update: domain.SubscriptionUpdate{ServiceName: &newName}}
+	deleteOp := func(id uuid.UUID) domain.BatchOp {
+		return domain.BatchOp{Kind: domain.BatchDelete, ID: id}
+	}
+	version := func(op domain.BatchOp, v int) domain.BatchOp {
+		op.Version = &v
+		return op
+	}
+	stored := func(id uuid.UUID, version int) *domain.Subscription {
+		return &domain.Subscription{ID: id, UserID: userID, Version: version}
+	}
+	subsLen := func(n int) any {
+		return mock.MatchedBy(func(subs []*domain.Subscription) bool { return len(subs) == n })
+	}
+	named := func(name string) any {
+		return mock.MatchedBy(func(subs []*domain.Subscription) bool { return len(subs) == 1 && subs[0].ServiceName == name })
+	}
+
+	testCases := []struct {
+		name       string
+		ops        []domain.BatchOp
+		atomic     bool
+		setupMocks func(bundle serviceTestBundle)
+		assertFunc func(t *testing.T, results []domain.BatchResult, err error)
+	}{
+		{
+			name:   "Atomic - Success",
+			ops:    []domain.BatchOp{create("Okko"), create("Ivi"), update, deleteOp(otherID)},
+			atomic: true,
+			setupMocks: func(bundle serviceTestBundle) {
+				expectTx(t, ctx, bundle)
+				bundle.repo.On("CreateBatch", ctx, subsLen(2)).Return(nil).Once()
+				bundle.repo.On("GetByID", ctx, subID).Return(&domain.Subscription{ID: subID, ServiceName: "Okko", StartDate: start}, nil).Once()
+				bundle.repo.On("Update", ctx, mock.AnythingOfType("*domain.Subscription")).Return(nil).Once()
+				bundle.repo.On("GetByID", ctx, otherID).Return(stored(otherID, 2), nil).Once()
+				bundle.repo.On("DeleteBatch", ctx, []uuid.UUID{otherID}, []int{2}).Return([]domain.Subscription{{ID: otherID}}, nil).Once()
+				expectAudit(bundle, domain.AuditCreate, 2)
+				expectAudit(bundle, domain.AuditUpdate, 1)
+				expectAudit(bundle, domain.AuditDelete, 1)
+			},
+			assertFunc: func(t *testing.T, results []domain.BatchResult, err error) {
+				require.NoError(t, err)
+				require.Len(t, results, 4)
+				assert.Equal(t, "Okko", results[0].Sub.ServiceName)
+				assert.Equal(t, "Ivi", results[1].Sub.ServiceName)
+				assert.Equal(t, newName, results[2].Sub.ServiceName)
+				assert.Equal(t, domain.BatchResult{}, results[3])
+			},
+		},
+		{
+			name:   "Atomic - Failed Bulk Insert Is Retried Row By Row",
+			ops:    []domain.BatchOp{create("Okko"), create("Ivi"), deleteOp(otherID)},
+			atomic: true,
+			setupMocks: func(bundle serviceTestBundle) {
+				expectTx(t, ctx, bundle)
+				expectTx(t, ctx, bundle)
+				bundle.repo.On("CreateBatch", ctx, subsLen(2)).Return(repoErrDuplicate).Once()
+				bundle.repo.On("CreateBatch", ctx, named("Okko")).Return(nil).Once()
+				bundle.repo.On("CreateBatch", ctx, named("Ivi")).Return(repoErrDuplicate).Once()
+				bundle.repo.On("FindOverlapping", ctx, mock.AnythingOfType("*domain.Subscription")).Return(subID, nil).Once()
+				expectAudit(bundle, domain.AuditCreate, 1)
+			},
+			assertFunc: func(t *testing.T, results []domain.BatchResult, err error) {
+				require.NoError(t, err)
+				require.Len(t, results, 3)
+				assert.ErrorIs(t, results[0].Err, subservice.ErrNotApplied)
+				assertServiceErrKind(t, results[1].Err, subservice.KindDuplicate)
+				var overlapErr *subservice.OverlapError
+				require.ErrorAs(t, results[1].Err, &overlapErr)
+				assert.Equal(t, subID, overlapErr.ConflictingID)
+				assert.ErrorIs(t, results[2].Err, subservice.ErrNotApplied)
+			},
+		},
+		{
+			name:   "Atomic - Missing Subscription",
+			ops:    []domain.BatchOp{deleteOp(subID), deleteOp(otherID)},
+			atomic: true,
+			setupMocks: func(bundle serviceTestBundle) {
+				expectTx(t, ctx, bundle)
+				bundle.repo.On("GetByID", ctx, subID).Return(stored(subID, 1), nil).Once()
+				bundle.repo.On("GetByID", ctx, otherID).Return(nil, repoErrNotFound).Once()
+				bundle.repo.On("DeleteBatch", ctx, []uuid.UUID{subID}, []int{1}).Return([]domain.Subscription{{ID: subID}}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, results []domain.BatchResult, err error) {
+				require.NoError(t, err)
+				assert.ErrorIs(t, results[0].Err, subservice.ErrNotApplied)
+				assertServiceErrKind(t, results[1].Err, subservice.KindNotFound)
+			},
+		},
+		{
+			name:   "Atomic - Repository Error",
+			ops:    []domain.BatchOp{deleteOp(subID)},
+			atomic: true,
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.txProvider.On("WithTransaction", ctx, mock.Anything).Return(errors.New("connection refused")).Once()
+			},
+			assertFunc: func(t *testing.T, results []domain.BatchResult, err error) {
+				assertServiceErrKind(t, err, subservice.KindUnknown)
+				assert.Nil(t, results)
+			},
+		},
+		{
+			name: "Best Effort - Failed Creates Fall Back To One Insert Each",
+			ops:  []domain.BatchOp{create("Okko"), create("Ivi")},
+			setupMocks: func(bundle serviceTestBundle) {
+				for range 3 {
+					expectTx(t, ctx, bundle)
+				}
+				bundle.repo.On("CreateBatch", ctx, subsLen(2)).Return(repoErrDuplicate).Once()
+				bundle.repo.On("CreateBatch", ctx, named("Okko")).Return(repoErrDuplicate).Once()
+				bundle.repo.On("CreateBatch", ctx, named("Ivi")).Return(nil).Once()
+				bundle.repo.On("FindOverlapping", ctx, mock.AnythingOfType("*domain.Subscription")).Return(subID, nil).Once()
+				expectAudit(bundle, domain.AuditCreate, 1)
+			},
+			assertFunc: func(t *testing.T, results []domain.BatchResult, err error) {
+				require.NoError(t, err)
+				assertServiceErrKind(t, results[0].Err, subservice.KindDuplicate)
+				assert.Nil(t, results[0].Sub)
+				require.NoError(t, results[1].Err)
+				assert.Equal(t, "Ivi", results[1].Sub.ServiceName)
+			},
+		},
+		{
+			name: "Best Effort - Missing And Repeated Deletes",
+			ops:  []domain.BatchOp{deleteOp(subID), deleteOp(otherID), deleteOp(subID)},
+			setupMocks: func(bundle serviceTestBundle) {
+				expectTx(t, ctx, bundle)
+				bundle.repo.On("GetByID", ctx, subID).Return(stored(subID, 1), nil).Once()
+				bundle.repo.On("GetByID", ctx, otherID).Return(nil, repoErrNotFound).Once()
+				bundle.repo.On("DeleteBatch", ctx, []uuid.UUID{subID}, []int{1}).Return([]domain.Subscription{{ID: subID}}, nil).Once()
+				expectAudit(bundle, domain.AuditDelete, 1)
+			},
+			assertFunc: func(t *testing.T, results []domain.BatchResult, err error) {
+				require.NoError(t, err)
+				require.NoError(t, results[0].Err)
+				assertServiceErrKind(t, results[1].Err, subservice.KindNotFound)
+				assertServiceErrKind(t, results[2].Err, subservice.KindNotFound)
+			},
+		},
+		{
+			name: "Best Effort - Deletes At Stale Versions",
+			ops:  []domain.BatchOp{version(deleteOp(subID), 1), version(deleteOp(otherID), 4)},
+			setupMocks: func(bundle serviceTestBundle) {
+				expectTx(t, ctx, bundle)
+				bundle.repo.On("GetByID", ctx, subID).Return(stored(subID, 2), nil).Once()
+				bundle.repo.On("GetByID", ctx, otherID).Return(stored(otherID, 4), nil).Once()
+				// Changed after it was read
+				bundle.repo.On("DeleteBatch", ctx, []uuid.UUID{otherID}, []int{4}).Return([]domain.Subscription{}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, results []domain.BatchResult, err error) {
+				require.NoError(t, err)
+				assertServiceErrKind(t, results[0].Err, subservice.KindConflict)
+				assertServiceErrKind(t, results[1].Err, subservice.KindConflict)
+			},
+		},
+		{
+			name: "Best Effort - Failed Update",
+			ops:  []domain.BatchOp{update, deleteOp(otherID)},
+			setupMocks: func(bundle serviceTestBundle) {
+				expectTx(t, ctx, bundle)
+				expectTx(t, ctx, bundle)
+				bundle.repo.On("GetByID", ctx, subID).Return(nil, repoErrNotFound).Once()
+				bundle.repo.On("GetByID", ctx, otherID).Return(stored(otherID, 1), nil).Once()
+				bundle.repo.On("DeleteBatch", ctx, []uuid.UUID{otherID}, []int{1}).Return([]domain.Subscription{{ID: otherID}}, nil).Once()
+				expectAudit(bundle, domain.AuditDelete, 1)
+			},
+			assertFunc: func(t *testing.T, results []domain.BatchResult, err error) {
+				require.NoError(t, err)
+				assertServiceErrKind(t, results[0].Err, subservice.KindNotFound)
+				require.NoError(t, results[1].Err)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bundle := setup(t)
+			tc.setupMocks(bundle)
+
+			results, err := bundle.svc.Batch(ctx, tc.ops, tc.atomic)
+
+			tc.assertFunc(t, results, err)
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package subservice
+
+import (
+	"math"
+	"time"
+
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+)
+
+const daysInWeek = 7
+
+func startOfMonth(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func endOfMonth(t time.Time) time.Time {
+	return startOfMonth(t).AddDate(0, 1, -1)
+}
+
+func daysInMonth(t time.Time) int {
+	return endOfMonth(t).Day()
+}
+
+// pricePeriod is a range of days with the same price, to is nil for the current price.
+type pricePeriod struct {
+	from  time.Time
+	to    *time.Time
+	price int
+}
+
+// pricePeriods splits the life of sub by its price history.
+// Without history the current price applies from the start.
+func pricePeriods(sub domain.Subscription) []pricePeriod {
+	if len(sub.PriceHistory) == 0 {
+		return []pricePeriod{{from: sub.StartDate, price: sub.Price}}
+	}
+
+	periods := make([]pricePeriod, 0, len(sub.PriceHistory))
+	for i, change := range sub.PriceHistory {
+		period := pricePeriod{from: change.EffectiveFrom, price: change.Price}
+		if i+1 < len(sub.PriceHistory) {
+			to := sub.PriceHistory[i+1].EffectiveFrom.AddDate(0, 0, -1)
+			period.to = &to
+		}
+		periods = append(periods, period)
+	}
+	return periods
+}
+
+// calculateCostForSubscription is the reference implementation of the charge
+// counting that the repository performs in SQL for TotalCost. A subscription is
+// charged on every billing date (start date plus a whole number of billing
+// periods) that falls into the months from periodStart to periodEnd, at the
+// price in effect on that date. Billing dates in paused months or within the
+// trial are skipped.
+func calculateCostForSubscription(sub domain.Subscription, periodStart, periodEnd time.Time) int {
+	cost := 0
+	for _, pp := range pricePeriods(sub) {
+		for _, w := range billedWindows(sub, pp, periodStart, periodEnd) {
+			cost += countCharges(sub, w) * pp.price
+		}
+	}
+	return cost
+}
+
+// calculateProratedCost charges the monthly rate for every month from periodStart
+// to periodEnd the subscription is active and not paused in, by day count for
+// partial months.
+func calculateProratedCost(sub domain.Subscription, periodStart, periodEnd time.Time) float64 {
+	cost := 0.0
+	for _, pp := range pricePeriods(sub) {
+		priced := sub
+		priced.Price = pp.price
+		for _, w := range billedWindows(sub, pp, periodStart, periodEnd) {
+			cost += priced.MonthlyRate() * activeMonths(w.from, w.to)
+		}
+	}
+	return cost
+}
+
+// activeMonths returns the number of months in [from, to], partial ones by day count.
+func activeMonths(from, to time.Time) float64 {
+	if monthsBetween(from, to) == 0 {
+		return float64(daysBetween(from, to)+1) / float64(daysInMonth(from))
+	}
+
+	months := float64(monthsBetween(from, to) - 1)
+	months += float64(daysInMonth(from)-from.Day()+1) / float64(daysInMonth(from))
+	months += float64(to.Day()) / float64(daysInMonth(to))
+	return months
+}
+
+// window is a range of days, both ends inclusive.
+type window struct {
+	from, to time.Time
+}
+
+func countCharges(sub domain.Subscription, w window) int {
+	// Charges up to and including w.to, minus the ones made before w.from.
+	beforeFrom := w.from.AddDate(0, 0, -1)
+	if sub.BillingPeriod == domain.BillingWeekly {
+		return floorDiv(daysBetween(sub.StartDate, w.to), daysInWeek) -
+			floorDiv(daysBetween(sub.StartDate, beforeFrom), daysInWeek)
+	}
+
+	months := sub.BillingPeriod.Months()
+	return floorDiv(monthsUntil(sub.StartDate, w.to), months) -
+		floorDiv(monthsUntil(sub.StartDate, beforeFrom), months)
+}
+
+// billedWindows returns the days of the active window of pp that aren't paused.
+func billedWindows(sub domain.Subscription, pp pricePeriod, periodStart, periodEnd time.Time) []window {
+	from, to, ok := activeWindow(sub, pp, periodStart, periodEnd)
+	if !ok {
+		return nil
+	}
+
+	windows := make([]window, 0, 1)
+	for _, pause := range sub.Pauses {
+		if pause.PausedFrom.After(to) {
+			break
+		}
+		if pause.PausedFrom.After(from) {
+			windows = append(windows, window{from: from, to: pause.PausedFrom.AddDate(0, 0, -1)})
+		}
+		if pause.ResumedFrom == nil {
+			return windows
+		}
+		if pause.ResumedFrom.After(from) {
+			from = *pause.ResumedFrom
+		}
+	}
+
+	if !from.After(to) {
+		windows = append(windows, window{from: from, to: to})
+	}
+	return windows
+}
+
+// activeWindow returns the days of pp within the months from periodStart to
+// periodEnd the subscription is active and out of its trial. ok is false when
+// there are none.
+func activeWindow(sub domain.Subscription, pp pricePeriod, periodStart, periodEnd time.Time) (from, to time.Time, ok bool) {
+	starts := []time.Time{sub.StartDate, pp.from}
+	if sub.TrialEnd != nil {
+		starts = append(starts, sub.TrialEnd.AddDate(0, 0, 1))
+	}
+
+	from = startOfMonth(periodStart)
+	for _, start := range starts {
+		if start.After(from) {
+			from = start
+		}
+	}
+
+	to = endOfMonth(periodEnd)
+	for _, end := range []*time.Time{sub.EndDate, pp.to} {
+		if end != nil && end.Before(to) {
+			to = *end
+		}
+	}
+
+	return from, to, !from.After(to)
+}
+
+// calculateCostBreakdown returns one entry per month in [periodStart, periodEnd].
+// Subscriptions that cost nothing in a month are omitted from its items.
+// Costs are converted with rates, keyed by the subscription currency. Only the
+// running total is rounded, every item gets its change, so the months add up to
+// the rounded total of the period like TotalCost.
+func calculateCostBreakdown(
+	subs []domain.Subscription,
+	rates map[string]float64,
+	periodStart, periodEnd time.Time,
+	mode domain.CostMode,
+) []domain.MonthCost {
+	pEnd := startOfMonth(periodEnd)
+	var exact float64
+	var rounded int
+
+	breakdown := make([]domain.MonthCost, 0)
+	for month := startOfMonth(periodStart); !month.After(pEnd); month = month.AddDate(0, 1, 0) {
+		monthCost := domain.MonthCost{
+			Month: month,
+			Items: make([]domain.CostItem, 0),
+		}
+
+		for _, sub := range subs {
+			var cost float64
+			if mode == domain.CostModeProrated {
+				cost = calculateProratedCost(sub, month, month)
+			} else {
+				cost = float64(calculateCostForSubscription(sub, month, month))
+			}
+			if cost == 0 {
+				continue
+			}
+			exact += cost * rates[sub.Currency]
+			converted := int(math.Round(exact)) - rounded
+			rounded += converted
+
+			monthCost.Items = append(monthCost.Items, domain.CostItem{
+				SubscriptionID: sub.ID,
+				ServiceName:    sub.ServiceName,
+				Cost:           converted,
+			})
+			monthCost.Total += converted
+		}
+
+		breakdown = append(breakdown, monthCost)
+	}
+
+	return breakdown
+}
+
+// daysBetween works on calendar days, time.Duration can't hold the
+// distance between the beginning and the end of time.
+func daysBetween(from, to time.Time) int {
+	return int((dayStart(to).Unix() - dayStart(from).Unix()) / (24 * 60 * 60))
+}
+
+func dayStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func monthsBetween(from, to time.Time) int {
+	return (to.Year()-from.Year())*12 + int(to.Month()) - int(from.Month())
+}
+
+// monthsUntil returns the number of whole months from start to t. Monthly
+// billing dates keep the day of start, clamped to the length of shorter months.
+func monthsUntil(start, t time.Time) int {
+	months := monthsBetween(start, t)
+	if t.Day() < min(start.Day(), daysInMonth(t)) {
+		months--
+	}
+	return months
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
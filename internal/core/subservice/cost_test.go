@@ -0,0 +1,359 @@
+package subservice
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_calculateCostForSubscription(t *testing.T) {
+	parseDate := func(s string) time.Time {
+		tm, err := time.Parse("2006-01", s)
+		require.NoError(t, err)
+		return tm
+	}
+	parseDay := func(s string) time.Time {
+		tm, err := time.Parse(time.DateOnly, s)
+		require.NoError(t, err)
+		return tm
+	}
+	ptrDate := func(s string) *time.Time {
+		tm := parseDate(s)
+		return &tm
+	}
+	ptrDay := func(s string) *time.Time {
+		tm := parseDay(s)
+		return &tm
+	}
+
+	periodStart := parseDate("2025-01")
+	periodEnd := parseDate("2025-12")
+
+	testCases := []struct {
+		name         string
+		sub          domain.Subscription
+		expectedCost int
+	}{
+		{
+			name:         "Full overlap, no end date",
+			sub:          domain.Subscription{Price: 100, StartDate: parseDate("2025-01")},
+			expectedCost: 1200,
+		},
+		{
+			name:         "Partial overlap, starts during period",
+			sub:          domain.Subscription{Price: 100, StartDate: parseDate("2025-06")},
+			expectedCost: 700,
+		},
+		{
+			name:         "Partial overlap, ends during period",
+			sub:          domain.Subscription{Price: 100, StartDate: parseDate("2025-01"), EndDate: ptrDate("2025-06")},
+			expectedCost: 600,
+		},
+		{
+			name:         "Subscription contained within period",
+			sub:          domain.Subscription{Price: 100, StartDate: parseDate("2025-03"), EndDate: ptrDate("2025-08")},
+			expectedCost: 600,
+		},
+		{
+			name:         "Period contained within subscription",
+			sub:          domain.Subscription{Price: 100, StartDate: parseDate("2024-01"), EndDate: ptrDate("2026-12")},
+			expectedCost: 1200,
+		},
+		{
+			name:         "No overlap, sub ends before period starts",
+			sub:          domain.Subscription{Price: 100, StartDate: parseDate("2024-01"), EndDate: ptrDate("2024-12")},
+			expectedCost: 0,
+		},
+		{
+			name:         "No overlap, sub starts after period ends",
+			sub:          domain.Subscription{Price: 100, StartDate: parseDate("2026-01")},
+			expectedCost: 0,
+		},
+		{
+			name:         "Single month subscription within period",
+			sub:          domain.Subscription{Price: 100, StartDate: parseDate("2025-06"), EndDate: ptrDate("2025-06")},
+			expectedCost: 100,
+		},
+		{
+			name:         "Mid-month start and end",
+			sub:          domain.Subscription{Price: 100, StartDate: parseDay("2025-03-28"), EndDate: ptrDay("2025-06-27")},
+			expectedCost: 300,
+		},
+		{
+			name:         "Ends on a billing date",
+			sub:          domain.Subscription{Price: 100, StartDate: parseDay("2025-03-28"), EndDate: ptrDay("2025-06-28")},
+			expectedCost: 400,
+		},
+		{
+			name:         "Billing day clamped to shorter months",
+			sub:          domain.Subscription{Price: 100, StartDate: parseDay("2025-01-31"), EndDate: ptrDay("2025-02-28")},
+			expectedCost: 200,
+		},
+		{
+			name:         "Weekly, charged on every week within the period",
+			sub:          domain.Subscription{Price: 10, BillingPeriod: domain.BillingWeekly, StartDate: parseDay("2024-12-30")},
+			expectedCost: 520,
+		},
+		{
+			name:         "Weekly, first charge in the period",
+			sub:          domain.Subscription{Price: 10, BillingPeriod: domain.BillingWeekly, StartDate: parseDay("2025-12-25")},
+			expectedCost: 10,
+		},
+		{
+			name:         "Weekly, billing dates before the period are not counted",
+			sub:          domain.Subscription{Price: 10, BillingPeriod: domain.BillingWeekly, StartDate: parseDay("2024-12-27"), EndDate: ptrDay("2025-01-31")},
+			expectedCost: 50,
+		},
+		{
+			name:         "Quarterly, full overlap",
+			sub:          domain.Subscription{Price: 300, BillingPeriod: domain.BillingQuarterly, StartDate: parseDate("2024-11")},
+			expectedCost: 1200,
+		},
+		{
+			name:         "Quarterly, ends before the next billing date",
+			sub:          domain.Subscription{Price: 300, BillingPeriod: domain.BillingQuarterly, StartDate: parseDate("2025-01"), EndDate: ptrDate("2025-03")},
+			expectedCost: 300,
+		},
+		{
+			name:         "Yearly, billing date within the period",
+			sub:          domain.Subscription{Price: 1200, BillingPeriod: domain.BillingYearly, StartDate: parseDate("2023-07")},
+			expectedCost: 1200,
+		},
+		{
+			name:         "Yearly, started within the period",
+			sub:          domain.Subscription{Price: 1200, BillingPeriod: domain.BillingYearly, StartDate: parseDate("2025-12")},
+			expectedCost: 1200,
+		},
+		{
+			name:         "Yearly, no billing date within the period",
+			sub:          domain.Subscription{Price: 1200, BillingPeriod: domain.BillingYearly, StartDate: parseDate("2024-01"), EndDate: ptrDate("2024-12")},
+			expectedCost: 0,
+		},
+		{
+			name: "Price changed during the period",
+			sub: domain.Subscription{Price: 150, StartDate: parseDate("2024-01"), PriceHistory: []domain.PriceChange{
+				{EffectiveFrom: parseDate("2024-01"), Price: 100},
+				{EffectiveFrom: parseDate("2025-07"), Price: 150},
+			}},
+			expectedCost: 6*100 + 6*150,
+		},
+		{
+			name: "Price changed before the period",
+			sub: domain.Subscription{Price: 150, StartDate: parseDate("2024-01"), PriceHistory: []domain.PriceChange{
+				{EffectiveFrom: parseDate("2024-01"), Price: 100},
+				{EffectiveFrom: parseDate("2024-07"), Price: 150},
+			}},
+			expectedCost: 12 * 150,
+		},
+		{
+			name: "Yearly, charged at the price in effect on the billing date",
+			sub: domain.Subscription{Price: 1500, BillingPeriod: domain.BillingYearly, StartDate: parseDate("2024-03"), PriceHistory: []domain.PriceChange{
+				{EffectiveFrom: parseDate("2024-03"), Price: 1200},
+				{EffectiveFrom: parseDate("2025-02"), Price: 1500},
+			}},
+			expectedCost: 1500,
+		},
+		{
+			name: "Paused months are not charged",
+			sub: domain.Subscription{Price: 100, StartDate: parseDate("2024-01"), Pauses: []domain.Pause{
+				{PausedFrom: parseDate("2025-03"), ResumedFrom: ptrDate("2025-06")},
+			}},
+			expectedCost: 9 * 100,
+		},
+		{
+			name: "Paused until resumed",
+			sub: domain.Subscription{Price: 100, StartDate: parseDate("2024-01"), Pauses: []domain.Pause{
+				{PausedFrom: parseDate("2024-02"), ResumedFrom: ptrDate("2025-02")},
+				{PausedFrom: parseDate("2025-10")},
+			}},
+			expectedCost: 8 * 100,
+		},
+		{
+			name: "Yearly, billing date in a paused month is skipped",
+			sub: domain.Subscription{Price: 1200, BillingPeriod: domain.BillingYearly, StartDate: parseDate("2024-03"), Pauses: []domain.Pause{
+				{PausedFrom: parseDate("2025-02"), ResumedFrom: ptrDate("2025-04")},
+			}},
+			expectedCost: 0,
+		},
+		{
+			name: "Weekly, paused mid-period",
+			sub: domain.Subscription{Price: 10, BillingPeriod: domain.BillingWeekly, StartDate: parseDay("2025-01-01"), EndDate: ptrDay("2025-03-31"), Pauses: []domain.Pause{
+				{PausedFrom: parseDate("2025-02"), ResumedFrom: ptrDate("2025-03")},
+			}},
+			// Charged on Jan 1, 8, 15, 22, 29 and Mar 5, 12, 19, 26.
+			expectedCost: 9 * 10,
+		},
+		{
+			name: "Price change during a pause",
+			sub: domain.Subscription{
+				Price: 150, StartDate: parseDate("2024-01"),
+				PriceHistory: []domain.PriceChange{
+					{EffectiveFrom: parseDate("2024-01"), Price: 100},
+					{EffectiveFrom: parseDate("2025-05"), Price: 150},
+				},
+				Pauses: []domain.Pause{{PausedFrom: parseDate("2025-04"), ResumedFrom: ptrDate("2025-07")}},
+			},
+			expectedCost: 3*100 + 6*150,
+		},
+		{
+			name:         "Trial months are not charged",
+			sub:          domain.Subscription{Price: 100, StartDate: parseDay("2025-01-15"), TrialEnd: ptrDay("2025-03-14")},
+			expectedCost: 10 * 100,
+		},
+		{
+			name:         "Trial ending between billing dates",
+			sub:          domain.Subscription{Price: 100, StartDate: parseDay("2025-01-15"), TrialEnd: ptrDay("2025-02-01")},
+			expectedCost: 11 * 100,
+		},
+		{
+			name:         "Invalid sub, starts after ends",
+			sub:          domain.Subscription{Price: 100, StartDate: parseDate("2025-06"), EndDate: ptrDate("2025-05")},
+			expectedCost: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cost := calculateCostForSubscription(tc.sub, periodStart, periodEnd)
+			assert.Equal(t, tc.expectedCost, cost)
+		})
+	}
+}
+
+func Test_calculateProratedCost(t *testing.T) {
+	day := func(s string) time.Time {
+		tm, err := time.Parse(time.DateOnly, s)
+		require.NoError(t, err)
+		return tm
+	}
+	ptrDay := func(s string) *time.Time {
+		tm := day(s)
+		return &tm
+	}
+
+	periodStart := day("2025-01-01")
+	periodEnd := day("2025-12-01")
+
+	testCases := []struct {
+		name         string
+		sub          domain.Subscription
+		expectedCost float64
+	}{
+		{
+			name:         "Full months",
+			sub:          domain.Subscription{Price: 100, StartDate: day("2024-12-15")},
+			expectedCost: 1200,
+		},
+		{
+			name:         "Partial first and last month",
+			sub:          domain.Subscription{Price: 310, StartDate: day("2025-01-22"), EndDate: ptrDay("2025-03-10")},
+			expectedCost: 310*10.0/31 + 310 + 310*10.0/31,
+		},
+		{
+			name:         "Within a single month",
+			sub:          domain.Subscription{Price: 280, StartDate: day("2025-02-08"), EndDate: ptrDay("2025-02-14")},
+			expectedCost: 70,
+		},
+		{
+			name:         "Yearly price spread over months",
+			sub:          domain.Subscription{Price: 1200, BillingPeriod: domain.BillingYearly, StartDate: day("2025-12-01")},
+			expectedCost: 100,
+		},
+		{
+			name: "Price changed during the period",
+			sub: domain.Subscription{Price: 200, StartDate: day("2024-12-15"), PriceHistory: []domain.PriceChange{
+				{EffectiveFrom: day("2024-12-01"), Price: 100},
+				{EffectiveFrom: day("2025-07-01"), Price: 200},
+			}},
+			expectedCost: 6*100 + 6*200,
+		},
+		{
+			name: "Paused months are not charged",
+			sub: domain.Subscription{Price: 310, StartDate: day("2025-01-22"), EndDate: ptrDay("2025-06-10"), Pauses: []domain.Pause{
+				{PausedFrom: day("2025-02-01"), ResumedFrom: ptrDay("2025-05-01")},
+			}},
+			expectedCost: 310*10.0/31 + 310 + 310*10.0/30,
+		},
+		{
+			name:         "Trial days are not charged",
+			sub:          domain.Subscription{Price: 310, StartDate: day("2024-12-15"), TrialEnd: ptrDay("2025-01-10")},
+			expectedCost: 310*21.0/31 + 11*310,
+		},
+		{
+			name:         "No overlap",
+			sub:          domain.Subscription{Price: 100, StartDate: day("2026-01-01")},
+			expectedCost: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cost := calculateProratedCost(tc.sub, periodStart, periodEnd)
+			assert.InDelta(t, tc.expectedCost, cost, 1e-9)
+		})
+	}
+}
+
+func Test_calculateCostBreakdown(t *testing.T) {
+	periodStart := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	periodEnd := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	rates := map[string]float64{"USD": 1.0 / 3}
+
+	subs := make([]domain.Subscription, 3)
+	for i := range subs {
+		subs[i] = domain.Subscription{
+			ID: uuid.New(), Price: 1, BillingPeriod: domain.BillingMonthly, Currency: "USD",
+			StartDate: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC),
+		}
+	}
+
+	testCases := []struct {
+		name  string
+		mode  domain.CostMode
+		items [][]int
+	}{
+		{
+			name:  "Billed",
+			mode:  domain.CostModeBilled,
+			items: [][]int{{0, 1, 0}, {0, 1, 0}},
+		},
+		{
+			name:  "Prorated",
+			mode:  domain.CostModeProrated,
+			items: [][]int{{0, 1, 0}, {0, 1, 0}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			breakdown := calculateCostBreakdown(subs, rates, periodStart, periodEnd, tc.mode)
+			require.Len(t, breakdown, len(tc.items))
+
+			// Rounding every item on its own would make them all 0.
+			total := 0
+			for i, month := range breakdown {
+				costs := make([]int, 0, len(month.Items))
+				monthTotal := 0
+				for _, item := range month.Items {
+					costs = append(costs, item.Cost)
+					monthTotal += item.Cost
+				}
+				assert.Equal(t, tc.items[i], costs)
+				assert.Equal(t, monthTotal, month.Total)
+				total += month.Total
+			}
+
+			// TotalCost rounds the converted total of the period.
+			exact := 0.0
+			for _, sub := range subs {
+				exact += calculateProratedCost(sub, periodStart, periodEnd) * rates[sub.Currency]
+			}
+			assert.Equal(t, int(math.Round(exact)), total)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package subservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/authz"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
+	"github.com/shrtyk/subscriptions-service/pkg/errkit"
+	"github.com/shrtyk/subscriptions-service/pkg/log"
+)
+
+const (
+	opListCurrencyRates  = "subservice.ListCurrencyRates"
+	opSetCurrencyRate    = "subservice.SetCurrencyRate"
+	opDeleteCurrencyRate = "subservice.DeleteCurrencyRate"
+)
+
+var errMissingRate = errors.New("no conversion rate")
+
+func (s *service) ListCurrencyRates(ctx context.Context) ([]domain.CurrencyRate, error) {
+	if err := s.authorizeAll(ctx, opListCurrencyRates, authz.ReadRates); err != nil {
+		return nil, err
+	}
+
+	rates, err := s.ratesRepo.List(ctx)
+	if err != nil {
+		return nil, subservice.WrapErr(opListCurrencyRates, subservice.KindUnknown, err)
+	}
+	return rates, nil
+}
+
+func (s *service) SetCurrencyRate(ctx context.Context, rate domain.CurrencyRate) (*domain.CurrencyRate, error) {
+	if err := s.authorizeAll(ctx, opSetCurrencyRate, authz.WriteRates); err != nil {
+		return nil, err
+	}
+	if rate.From == rate.To {
+		return nil, subservice.WrapErr(
+			opSetCurrencyRate, subservice.KindBusinessLogic,
+			errors.New("cannot set conversion rate of a currency to itself"),
+		)
+	}
+	if rate.Rate <= 0 {
+		return nil, subservice.WrapErr(opSetCurrencyRate, subservice.KindBusinessLogic, errors.New("rate must be positive"))
+	}
+
+	if err := s.ratesRepo.Upsert(ctx, &rate); err != nil {
+		return nil, subservice.WrapErr(opSetCurrencyRate, subservice.KindUnknown, err)
+	}
+
+	log.FromCtx(ctx).Info("currency rate set", slog.String("from", rate.From), slog.String("to", rate.To))
+
+	return &rate, nil
+}
+
+func (s *service) DeleteCurrencyRate(ctx context.Context, from, to string) error {
+	if err := s.authorizeAll(ctx, opDeleteCurrencyRate, authz.WriteRates); err != nil {
+		return err
+	}
+
+	err := s.ratesRepo.Delete(ctx, from, to)
+	if err != nil {
+		var repoErr *errkit.BaseErr[repos.RepoKind]
+		if errors.As(err, &repoErr) && repoErr.Kind == repos.KindNotFound {
+			return subservice.WrapErr(opDeleteCurrencyRate, subservice.KindNotFound, err)
+		}
+		return subservice.WrapErr(opDeleteCurrencyRate, subservice.KindUnknown, err)
+	}
+
+	log.FromCtx(ctx).Info("currency rate deleted", slog.String("from", from), slog.String("to", to))
+
+	return nil
+}
+
+// conversionRates returns the rate to convert each of currencies into target.
+// A missing direct rate falls back to the inverse of the opposite pair.
+func (s *service) conversionRates(ctx context.Context, currencies []string, target string) (map[string]float64, error) {
+	rates := make(map[string]float64)
+	for _, from := range currencies {
+		if _, ok := rates[from]; ok {
+			continue
+		}
+		if from == target {
+			rates[from] = 1
+			continue
+		}
+
+		rate, err := s.conversionRate(ctx, from, target)
+		if err != nil {
+			return nil, err
+		}
+		rates[from] = rate
+	}
+	return rates, nil
+}
+
+func (s *service) conversionRate(ctx context.Context, from, to string) (float64, error) {
+	rate, err := s.ratesRepo.Get(ctx, from, to)
+	if err == nil {
+		return rate.Rate, nil
+	}
+	if !isRepoNotFound(err) {
+		return 0, err
+	}
+
+	inverse, err := s.ratesRepo.Get(ctx, to, from)
+	if err == nil {
+		return 1 / inverse.Rate, nil
+	}
+	if !isRepoNotFound(err) {
+		return 0, err
+	}
+
+	return 0, fmt.Errorf("%w from %s to %s", errMissingRate, from, to)
+}
+
+func wrapConversionErr(op string, err error) error {
+	if errors.Is(err, errMissingRate) {
+		return subservice.WrapErr(op, subservice.KindBusinessLogic, err)
+	}
+	return subservice.WrapErr(op, subservice.KindUnknown, err)
+}
+
+func isRepoNotFound(err error) bool {
+	var repoErr *errkit.BaseErr[repos.RepoKind]
+	return errors.As(err, &repoErr) && repoErr.Kind == repos.KindNotFound
+}
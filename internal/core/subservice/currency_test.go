@@ -0,0 +1,147 @@
+package subservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
+	"github.com/shrtyk/subscriptions-service/pkg/errkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_SetCurrencyRate(t *testing.T) {
+	ctx := context.Background()
+
+	testCases := []struct {
+		name       string
+		rate       domain.CurrencyRate
+		setupMocks func(bundle serviceTestBundle)
+		assertFunc func(t *testing.T, rate *domain.CurrencyRate, err error)
+	}{
+		{
+			name: "Success",
+			rate: domain.CurrencyRate{From: "USD", To: "RUB", Rate: 81.5},
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.ratesRepo.On("Upsert", ctx, mock.AnythingOfType("*domain.CurrencyRate")).Return(nil).Once()
+			},
+			assertFunc: func(t *testing.T, rate *domain.CurrencyRate, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, 81.5, rate.Rate)
+			},
+		},
+		{
+			name:       "Same currency",
+			rate:       domain.CurrencyRate{From: "RUB", To: "RUB", Rate: 1},
+			setupMocks: func(bundle serviceTestBundle) {},
+			assertFunc: func(t *testing.T, rate *domain.CurrencyRate, err error) {
+				assert.Nil(t, rate)
+				var svcErr *errkit.BaseErr[subservice.ServiceKind]
+				require.ErrorAs(t, err, &svcErr)
+				assert.Equal(t, subservice.KindBusinessLogic, svcErr.Kind)
+			},
+		},
+		{
+			name:       "Non-positive rate",
+			rate:       domain.CurrencyRate{From: "USD", To: "RUB", Rate: 0},
+			setupMocks: func(bundle serviceTestBundle) {},
+			assertFunc: func(t *testing.T, rate *domain.CurrencyRate, err error) {
+				assert.Nil(t, rate)
+				var svcErr *errkit.BaseErr[subservice.ServiceKind]
+				require.ErrorAs(t, err, &svcErr)
+				assert.Equal(t, subservice.KindBusinessLogic, svcErr.Kind)
+			},
+		},
+		{
+			name: "Repo Upsert fails",
+			rate: domain.CurrencyRate{From: "USD", To: "RUB", Rate: 81.5},
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.ratesRepo.On("Upsert", ctx, mock.AnythingOfType("*domain.CurrencyRate")).Return(errors.New("db error")).Once()
+			},
+			assertFunc: func(t *testing.T, rate *domain.CurrencyRate, err error) {
+				assert.Nil(t, rate)
+				var svcErr *errkit.BaseErr[subservice.ServiceKind]
+				require.ErrorAs(t, err, &svcErr)
+				assert.Equal(t, subservice.KindUnknown, svcErr.Kind)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bundle := setup(t)
+			tc.setupMocks(bundle)
+			rate, err := bundle.svc.SetCurrencyRate(ctx, tc.rate)
+			tc.assertFunc(t, rate, err)
+		})
+	}
+}
+
+func TestService_DeleteCurrencyRate(t *testing.T) {
+	ctx := context.Background()
+
+	testCases := []struct {
+		name         string
+		repoErr      error
+		expectedKind *subservice.ServiceKind
+	}{
+		{
+			name: "Success",
+		},
+		{
+			name:         "Not Found",
+			repoErr:      errkit.WrapErr("op", repos.KindNotFound, errors.New("not found")),
+			expectedKind: func(k subservice.ServiceKind) *subservice.ServiceKind { return &k }(subservice.KindNotFound),
+		},
+		{
+			name:         "Generic Repo Error",
+			repoErr:      errors.New("db error"),
+			expectedKind: func(k subservice.ServiceKind) *subservice.ServiceKind { return &k }(subservice.KindUnknown),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bundle := setup(t)
+			bundle.ratesRepo.On("Delete", ctx, "USD", "RUB").Return(tc.repoErr).Once()
+
+			err := bundle.svc.DeleteCurrencyRate(ctx, "USD", "RUB")
+			if tc.expectedKind == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var svcErr *errkit.BaseErr[subservice.ServiceKind]
+			require.ErrorAs(t, err, &svcErr)
+			assert.Equal(t, *tc.expectedKind, svcErr.Kind)
+		})
+	}
+}
+
+func TestService_ListCurrencyRates(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success", func(t *testing.T) {
+		bundle := setup(t)
+		expected := []domain.CurrencyRate{{From: "USD", To: "RUB", Rate: 81.5}}
+		bundle.ratesRepo.On("List", ctx).Return(expected, nil).Once()
+
+		rates, err := bundle.svc.ListCurrencyRates(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, expected, rates)
+	})
+
+	t.Run("Repo List fails", func(t *testing.T) {
+		bundle := setup(t)
+		bundle.ratesRepo.On("List", ctx).Return(nil, errors.New("db error")).Once()
+
+		rates, err := bundle.svc.ListCurrencyRates(ctx)
+		assert.Nil(t, rates)
+		var svcErr *errkit.BaseErr[subservice.ServiceKind]
+		require.ErrorAs(t, err, &svcErr)
+		assert.Equal(t, subservice.KindUnknown, svcErr.Kind)
+	})
+}
@@ -0,0 +1,191 @@
+package subservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/authz"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/tx"
+	"github.com/shrtyk/subscriptions-service/pkg/log"
+)
+
+const (
+	opPause      = "subservice.Pause"
+	opResume     = "subservice.Resume"
+	opListPauses = "subservice.ListPauses"
+)
+
+// Pause stops billing of the subscription from the pausedFrom month, the current
+// one when nil. With resumedFrom the pause is closed right away, otherwise it
+// lasts until Resume.
+func (s *service) Pause(ctx context.Context, id uuid.UUID, pausedFrom, resumedFrom *time.Time) (*domain.Pause, error) {
+	pause := domain.Pause{PausedFrom: startOfMonth(time.Now().UTC())}
+	if pausedFrom != nil {
+		pause.PausedFrom = startOfMonth(*pausedFrom)
+	}
+	if resumedFrom != nil {
+		month := startOfMonth(*resumedFrom)
+		pause.ResumedFrom = &month
+	}
+
+	err := s.txProvider.WithTransaction(ctx, func(uow tx.UnitOfWork) error {
+		repo := uow.Subscriptions()
+
+		sub, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.authorize(ctx, opPause, authz.WriteSubscriptions, sub.UserID); err != nil {
+			return err
+		}
+		if err := validatePause(sub, pause); err != nil {
+			return subservice.WrapErr(opPause, subservice.KindBusinessLogic, err)
+		}
+
+		pauses, err := repo.ListPauses(ctx, id)
+		if err != nil {
+			return err
+		}
+		for _, other := range pauses {
+			if pausesOverlap(pause, other) {
+				return subservice.WrapErr(
+					opPause, subservice.KindBusinessLogic,
+					fmt.Errorf("pause overlaps the pause from %s", other.PausedFrom.Format("01-2006")),
+				)
+			}
+		}
+
+		// The exclusion constraint fails pauses added since the check.
+		if err := repo.AddPause(ctx, id, &pause); err != nil {
+			if isRepoKind(err, repos.KindDuplicate) {
+				return subservice.WrapErr(opPause, subservice.KindBusinessLogic, errPausesOverlap)
+			}
+			return err
+		}
+		before, after := withPauses(sub, pauses), withPauses(sub, append(slices.Clone(pauses), pause))
+		return recordAudit(ctx, uow.Audit(), domain.AuditPause, before, after)
+	})
+	if err != nil {
+		return nil, wrapTxErr(opPause, err)
+	}
+
+	log.FromCtx(ctx).Info("subscription paused", slog.String("subscription_id", id.String()))
+
+	return &pause, nil
+}
+
+// Resume closes the open pause of the subscription. Billing restarts in the
+// resumedFrom month, by default the current one or the month after the pause
+// started if that is later.
+func (s *service) Resume(ctx context.Context, id uuid.UUID, resumedFrom *time.Time) (*domain.Pause, error) {
+	var resumed *domain.Pause
+	err := s.txProvider.WithTransaction(ctx, func(uow tx.UnitOfWork) error {
+		repo := uow.Subscriptions()
+
+		sub, err := repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.authorize(ctx, opResume, authz.WriteSubscriptions, sub.UserID); err != nil {
+			return err
+		}
+
+		pauses, err := repo.ListPauses(ctx, id)
+		if err != nil {
+			return err
+		}
+		var open *domain.Pause
+		for i := range pauses {
+			if pauses[i].ResumedFrom == nil {
+				open = &pauses[i]
+			}
+		}
+		if open == nil {
+			return subservice.WrapErr(opResume, subservice.KindBusinessLogic, errors.New("subscription is not paused"))
+		}
+
+		month := startOfMonth(time.Now().UTC())
+		if resumedFrom != nil {
+			month = startOfMonth(*resumedFrom)
+		} else if !month.After(open.PausedFrom) {
+			month = open.PausedFrom.AddDate(0, 1, 0)
+		}
+		if !month.After(open.PausedFrom) {
+			return subservice.WrapErr(opResume, subservice.KindBusinessLogic, errPauseResumedEarly)
+		}
+
+		before := withPauses(sub, pauses)
+		open.ResumedFrom = &month
+		if err := repo.ResumePause(ctx, id, open); err != nil {
+			return err
+		}
+		resumed = open
+		return recordAudit(ctx, uow.Audit(), domain.AuditResume, before, withPauses(sub, pauses))
+	})
+	if err != nil {
+		return nil, wrapTxErr(opResume, err)
+	}
+
+	log.FromCtx(ctx).Info("subscription resumed", slog.String("subscription_id", id.String()))
+
+	return resumed, nil
+}
+
+func (s *service) ListPauses(ctx context.Context, id uuid.UUID) ([]domain.Pause, error) {
+	sub, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if isRepoNotFound(err) {
+			return nil, subservice.WrapErr(opListPauses, subservice.KindNotFound, err)
+		}
+		return nil, subservice.WrapErr(opListPauses, subservice.KindUnknown, err)
+	}
+	if err := s.authorize(ctx, opListPauses, authz.ReadSubscriptions, sub.UserID); err != nil {
+		return nil, err
+	}
+
+	pauses, err := s.repo.ListPauses(ctx, id)
+	if err != nil {
+		return nil, subservice.WrapErr(opListPauses, subservice.KindUnknown, err)
+	}
+	return pauses, nil
+}
+
+var errPauseResumedEarly = errors.New("subscription must be resumed after the month it was paused in")
+
+var errPausesOverlap = errors.New("pause overlaps another pause of the subscription")
+
+func validatePause(sub *domain.Subscription, pause domain.Pause) error {
+	if pause.PausedFrom.Before(startOfMonth(sub.StartDate)) {
+		return errors.New("subscription can't be paused before it starts")
+	}
+	if sub.EndDate != nil && pause.PausedFrom.After(*sub.EndDate) {
+		return errors.New("subscription can't be paused after it ends")
+	}
+	if pause.ResumedFrom != nil && !pause.ResumedFrom.After(pause.PausedFrom) {
+		return errPauseResumedEarly
+	}
+	return nil
+}
+
+// withPauses returns a copy of sub with pauses ordered by PausedFrom.
+func withPauses(sub *domain.Subscription, pauses []domain.Pause) *domain.Subscription {
+	snapshot := *sub
+	snapshot.Pauses = slices.SortedFunc(slices.Values(pauses), func(a, b domain.Pause) int {
+		return a.PausedFrom.Compare(b.PausedFrom)
+	})
+	return &snapshot
+}
+
+// pausesOverlap reports whether a and b share a month, open pauses last forever.
+func pausesOverlap(a, b domain.Pause) bool {
+	return (b.ResumedFrom == nil || a.PausedFrom.Before(*b.ResumedFrom)) &&
+		(a.ResumedFrom == nil || b.PausedFrom.Before(*a.ResumedFrom))
+}
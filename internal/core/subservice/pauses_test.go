@@ -0,0 +1,294 @@
+package subservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/tx"
+	txmocks "github.com/shrtyk/subscriptions-service/internal/core/ports/tx/mocks"
+	"github.com/shrtyk/subscriptions-service/pkg/errkit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func expectTx(t *testing.T, ctx context.Context, bundle serviceTestBundle) {
+	t.Helper()
+	bundle.txProvider.On("WithTransaction", ctx, mock.Anything).
+		Return(func(ctx context.Context, fn func(uow tx.UnitOfWork) error) error {
+			uowMock := txmocks.NewMockUnitOfWork(t)
+			uowMock.On("Subscriptions").Return(bundle.repo)
+			uowMock.On("Audit").Return(bundle.audit).Maybe()
+			return fn(uowMock)
+		}).Once()
+}
+
+// expectAudit expects n entries of op to be recorded.
+func expectAudit(bundle serviceTestBundle, op domain.AuditOp, n int) {
+	bundle.audit.On("Record", mock.Anything, mock.MatchedBy(func(e *domain.AuditEntry) bool {
+		return e.Op == op
+	})).Return(nil).Times(n)
+}
+
+func assertServiceErrKind(t *testing.T, err error, kind subservice.ServiceKind) {
+	t.Helper()
+	var svcErr *errkit.BaseErr[subservice.ServiceKind]
+	require.ErrorAs(t, err, &svcErr)
+	assert.Equal(t, kind, svcErr.Kind)
+}
+
+func TestService_Pause(t *testing.T) {
+	ctx := context.Background()
+	subID := uuid.New()
+	month := func(year int, m time.Month) time.Time { return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC) }
+	monthPtr := func(year int, m time.Month) *time.Time { t := month(year, m); return &t }
+	sub := &domain.Subscription{ID: subID, StartDate: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)}
+	repoErrNotFound := errkit.WrapErr("op", repos.KindNotFound, errors.New("not found"))
+
+	testCases := []struct {
+		name        string
+		pausedFrom  *time.Time
+		resumedFrom *time.Time
+		setupMocks  func(bundle serviceTestBundle)
+		assertFunc  func(t *testing.T, pause *domain.Pause, err error)
+	}{
+		{
+			name:        "Success",
+			pausedFrom:  monthPtr(2025, time.March),
+			resumedFrom: monthPtr(2025, time.June),
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("GetByID", ctx, subID).Return(sub, nil).Once()
+				bundle.repo.On("ListPauses", ctx, subID).Return([]domain.Pause{
+					{PausedFrom: month(2024, time.June), ResumedFrom: monthPtr(2024, time.August)},
+				}, nil).Once()
+				bundle.repo.On("AddPause", ctx, subID, &domain.Pause{
+					PausedFrom: month(2025, time.March), ResumedFrom: monthPtr(2025, time.June),
+				}).Return(nil).Once()
+				bundle.audit.On("Record", ctx, mock.MatchedBy(func(e *domain.AuditEntry) bool {
+					return e.Op == domain.AuditPause && len(e.Before.Pauses) == 1 && len(e.After.Pauses) == 2 &&
+						e.After.Pauses[1].PausedFrom.Equal(month(2025, time.March))
+				})).Return(nil).Once()
+			},
+			assertFunc: func(t *testing.T, pause *domain.Pause, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, month(2025, time.March), pause.PausedFrom)
+				assert.Equal(t, monthPtr(2025, time.June), pause.ResumedFrom)
+			},
+		},
+		{
+			name: "Success - Defaults to the current month",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("GetByID", ctx, subID).Return(sub, nil).Once()
+				bundle.repo.On("ListPauses", ctx, subID).Return([]domain.Pause{}, nil).Once()
+				bundle.repo.On("AddPause", ctx, subID, mock.AnythingOfType("*domain.Pause")).Return(nil).Once()
+				expectAudit(bundle, domain.AuditPause, 1)
+			},
+			assertFunc: func(t *testing.T, pause *domain.Pause, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, startOfMonth(time.Now().UTC()), pause.PausedFrom)
+				assert.Nil(t, pause.ResumedFrom)
+			},
+		},
+		{
+			name:       "Overlaps an open pause",
+			pausedFrom: monthPtr(2025, time.March),
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("GetByID", ctx, subID).Return(sub, nil).Once()
+				bundle.repo.On("ListPauses", ctx, subID).Return([]domain.Pause{
+					{PausedFrom: month(2025, time.January)},
+				}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, pause *domain.Pause, err error) {
+				assert.Nil(t, pause)
+				assertServiceErrKind(t, err, subservice.KindBusinessLogic)
+			},
+		},
+		{
+			name:        "Overlaps a later pause",
+			pausedFrom:  monthPtr(2025, time.March),
+			resumedFrom: monthPtr(2025, time.July),
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("GetByID", ctx, subID).Return(sub, nil).Once()
+				bundle.repo.On("ListPauses", ctx, subID).Return([]domain.Pause{
+					{PausedFrom: month(2025, time.June), ResumedFrom: monthPtr(2025, time.August)},
+				}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, pause *domain.Pause, err error) {
+				assert.Nil(t, pause)
+				assertServiceErrKind(t, err, subservice.KindBusinessLogic)
+			},
+		},
+		{
+			name:       "Overlaps a pause added concurrently",
+			pausedFrom: monthPtr(2025, time.March),
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("GetByID", ctx, subID).Return(sub, nil).Once()
+				bundle.repo.On("ListPauses", ctx, subID).Return([]domain.Pause{}, nil).Once()
+				bundle.repo.On("AddPause", ctx, subID, mock.AnythingOfType("*domain.Pause")).
+					Return(errkit.WrapErr("op", repos.KindDuplicate, errors.New("exclusion violation"))).Once()
+			},
+			assertFunc: func(t *testing.T, pause *domain.Pause, err error) {
+				assert.Nil(t, pause)
+				assertServiceErrKind(t, err, subservice.KindBusinessLogic)
+			},
+		},
+		{
+			name:       "Before the subscription starts",
+			pausedFrom: monthPtr(2023, time.December),
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("GetByID", ctx, subID).Return(sub, nil).Once()
+			},
+			assertFunc: func(t *testing.T, pause *domain.Pause, err error) {
+				assert.Nil(t, pause)
+				assertServiceErrKind(t, err, subservice.KindBusinessLogic)
+			},
+		},
+		{
+			name:        "Resumed before paused",
+			pausedFrom:  monthPtr(2025, time.March),
+			resumedFrom: monthPtr(2025, time.March),
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("GetByID", ctx, subID).Return(sub, nil).Once()
+			},
+			assertFunc: func(t *testing.T, pause *domain.Pause, err error) {
+				assert.Nil(t, pause)
+				assertServiceErrKind(t, err, subservice.KindBusinessLogic)
+			},
+		},
+		{
+			name: "Subscription Not Found",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("GetByID", ctx, subID).Return(nil, repoErrNotFound).Once()
+			},
+			assertFunc: func(t *testing.T, pause *domain.Pause, err error) {
+				assert.Nil(t, pause)
+				assertServiceErrKind(t, err, subservice.KindNotFound)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bundle := setup(t)
+			expectTx(t, ctx, bundle)
+			tc.setupMocks(bundle)
+			pause, err := bundle.svc.Pause(ctx, subID, tc.pausedFrom, tc.resumedFrom)
+			tc.assertFunc(t, pause, err)
+		})
+	}
+}
+
+func TestService_Resume(t *testing.T) {
+	ctx := context.Background()
+	subID := uuid.New()
+	sub := &domain.Subscription{ID: subID}
+	currentMonth := startOfMonth(time.Now().UTC())
+	nextMonth := currentMonth.AddDate(0, 1, 0)
+	lastYear := currentMonth.AddDate(-1, 0, 0)
+
+	testCases := []struct {
+		name        string
+		resumedFrom *time.Time
+		setupMocks  func(bundle serviceTestBundle)
+		assertFunc  func(t *testing.T, pause *domain.Pause, err error)
+	}{
+		{
+			name: "Success - Resumed this month",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("GetByID", ctx, subID).Return(sub, nil).Once()
+				bundle.repo.On("ListPauses", ctx, subID).Return([]domain.Pause{{PausedFrom: lastYear}}, nil).Once()
+				bundle.repo.On("ResumePause", ctx, subID, &domain.Pause{PausedFrom: lastYear, ResumedFrom: &currentMonth}).
+					Return(nil).Once()
+				bundle.audit.On("Record", ctx, mock.MatchedBy(func(e *domain.AuditEntry) bool {
+					return e.Op == domain.AuditResume && e.Before.Pauses[0].ResumedFrom == nil &&
+						e.After.Pauses[0].ResumedFrom.Equal(currentMonth)
+				})).Return(nil).Once()
+			},
+			assertFunc: func(t *testing.T, pause *domain.Pause, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, &currentMonth, pause.ResumedFrom)
+			},
+		},
+		{
+			name: "Success - Paused this month keeps it paused",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("GetByID", ctx, subID).Return(sub, nil).Once()
+				bundle.repo.On("ListPauses", ctx, subID).Return([]domain.Pause{{PausedFrom: currentMonth}}, nil).Once()
+				bundle.repo.On("ResumePause", ctx, subID, &domain.Pause{PausedFrom: currentMonth, ResumedFrom: &nextMonth}).
+					Return(nil).Once()
+				expectAudit(bundle, domain.AuditResume, 1)
+			},
+			assertFunc: func(t *testing.T, pause *domain.Pause, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, &nextMonth, pause.ResumedFrom)
+			},
+		},
+		{
+			name:        "Resumed in the month it was paused",
+			resumedFrom: &currentMonth,
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("GetByID", ctx, subID).Return(sub, nil).Once()
+				bundle.repo.On("ListPauses", ctx, subID).Return([]domain.Pause{{PausedFrom: currentMonth}}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, pause *domain.Pause, err error) {
+				assert.Nil(t, pause)
+				assertServiceErrKind(t, err, subservice.KindBusinessLogic)
+			},
+		},
+		{
+			name: "Not Paused",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("GetByID", ctx, subID).Return(sub, nil).Once()
+				bundle.repo.On("ListPauses", ctx, subID).Return([]domain.Pause{
+					{PausedFrom: lastYear, ResumedFrom: &currentMonth},
+				}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, pause *domain.Pause, err error) {
+				assert.Nil(t, pause)
+				assertServiceErrKind(t, err, subservice.KindBusinessLogic)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bundle := setup(t)
+			expectTx(t, ctx, bundle)
+			tc.setupMocks(bundle)
+			pause, err := bundle.svc.Resume(ctx, subID, tc.resumedFrom)
+			tc.assertFunc(t, pause, err)
+		})
+	}
+}
+
+func TestService_ListPauses(t *testing.T) {
+	ctx := context.Background()
+	subID := uuid.New()
+	pauses := []domain.Pause{{PausedFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}}
+	repoErrNotFound := errkit.WrapErr("op", repos.KindNotFound, errors.New("not found"))
+
+	t.Run("Success", func(t *testing.T) {
+		bundle := setup(t)
+		bundle.repo.On("GetByID", ctx, subID).Return(&domain.Subscription{ID: subID}, nil).Once()
+		bundle.repo.On("ListPauses", ctx, subID).Return(pauses, nil).Once()
+
+		actual, err := bundle.svc.ListPauses(ctx, subID)
+		require.NoError(t, err)
+		assert.Equal(t, pauses, actual)
+	})
+
+	t.Run("Subscription Not Found", func(t *testing.T) {
+		bundle := setup(t)
+		bundle.repo.On("GetByID", ctx, subID).Return(nil, repoErrNotFound).Once()
+
+		actual, err := bundle.svc.ListPauses(ctx, subID)
+		assert.Nil(t, actual)
+		assertServiceErrKind(t, err, subservice.KindNotFound)
+	})
+}
@@ -0,0 +1,76 @@
+package subservice
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/authz"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
+)
+
+const opListPriceChanges = "subservice.ListPriceChanges"
+
+func (s *service) ListPriceChanges(ctx context.Context, id uuid.UUID) ([]domain.PriceChange, error) {
+	sub, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		if isRepoNotFound(err) {
+			return nil, subservice.WrapErr(opListPriceChanges, subservice.KindNotFound, err)
+		}
+		return nil, subservice.WrapErr(opListPriceChanges, subservice.KindUnknown, err)
+	}
+	if err := s.authorize(ctx, opListPriceChanges, authz.ReadSubscriptions, sub.UserID); err != nil {
+		return nil, err
+	}
+
+	changes, err := s.repo.ListPriceChanges(ctx, id)
+	if err != nil {
+		return nil, subservice.WrapErr(opListPriceChanges, subservice.KindUnknown, err)
+	}
+	return changes, nil
+}
+
+// changePrice records price as the price of sub from effectiveFrom on and updates
+// sub.Price to the latest one. Past months keep the price they were billed at.
+// effectiveFrom defaults to the current month and can't be later than that, or
+// before the start month.
+func changePrice(
+	ctx context.Context,
+	repo repos.SubscriptionRepository,
+	sub *domain.Subscription,
+	price int,
+	effectiveFrom *time.Time,
+) error {
+	earliest := startOfMonth(sub.StartDate)
+	latest := startOfMonth(time.Now().UTC())
+	if earliest.After(latest) {
+		latest = earliest
+	}
+
+	change := domain.PriceChange{EffectiveFrom: latest, Price: price}
+	if effectiveFrom != nil {
+		change.EffectiveFrom = startOfMonth(*effectiveFrom)
+	}
+	if change.EffectiveFrom.Before(earliest) || change.EffectiveFrom.After(latest) {
+		return subservice.WrapErr(
+			opUpdate, subservice.KindBusinessLogic,
+			errors.New("price change must take effect between the start month and the current month"),
+		)
+	}
+
+	if err := repo.AddPriceChange(ctx, sub.ID, &change); err != nil {
+		return err
+	}
+
+	history, err := repo.ListPriceChanges(ctx, sub.ID)
+	if err != nil {
+		return err
+	}
+	if len(history) > 0 {
+		sub.Price = history[len(history)-1].Price
+	}
+	return nil
+}
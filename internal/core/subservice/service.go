@@ -3,11 +3,16 @@ package subservice
 import (
 	"context"
 	"errors"
+	"iter"
 	"log/slog"
+	"maps"
+	"math"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/authz"
 	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
 	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
 	"github.com/shrtyk/subscriptions-service/internal/core/ports/tx"
@@ -16,34 +21,64 @@ import (
 )
 
 const (
-	opCreate    = "subservice.Create"
-	opGetByID   = "subservice.GetByID"
-	opUpdate    = "subservice.Update"
-	opDelete    = "subservice.Delete"
-	opList      = "subservice.List"
-	opTotalCost = "subservice.TotalCost"
+	opCreate           = "subservice.Create"
+	opCreateIdempotent = "subservice.CreateIdempotent"
+	opGetByID          = "subservice.GetByID"
+	opUpdate           = "subservice.Update"
+	opPatch            = "subservice.Patch"
+	opDelete           = "subservice.Delete"
+	opList             = "subservice.List"
+	opExport           = "subservice.Export"
+	opSuggest          = "subservice.SuggestServiceNames"
+	opTotalCost        = "subservice.TotalCost"
+	opCostBreakdown    = "subservice.CostBreakdown"
+)
+
+var (
+	errVersionMismatch = errors.New("subscription is at another version")
+	errKeyReused       = errors.New("idempotency key was used for another request")
 )
 
 type service struct {
 	repo       repos.SubscriptionRepository
+	ratesRepo  repos.CurrencyRateRepository
+	apiKeys    repos.APIKeyRepository
+	audit      repos.AuditRepository
+	authz      authz.Authorizer
 	txProvider tx.Provider
 }
 
-func New(repo repos.SubscriptionRepository, txProvider tx.Provider) subservice.SubscriptionsService {
+func New(
+	repo repos.SubscriptionRepository,
+	ratesRepo repos.CurrencyRateRepository,
+	apiKeys repos.APIKeyRepository,
+	auditRepo repos.AuditRepository,
+	authorizer authz.Authorizer,
+	txProvider tx.Provider,
+) subservice.SubscriptionsService {
 	return &service{
 		repo:       repo,
+		ratesRepo:  ratesRepo,
+		apiKeys:    apiKeys,
+		audit:      auditRepo,
+		authz:      authorizer,
 		txProvider: txProvider,
 	}
 }
 
 func (s *service) Create(ctx context.Context, sub domain.Subscription) (*domain.Subscription, error) {
-	err := s.repo.Create(ctx, &sub)
-	if err != nil {
-		var repoErr *errkit.BaseErr[repos.RepoKind]
-		if errors.As(err, &repoErr) && repoErr.Kind == repos.KindDuplicate {
-			return nil, subservice.WrapErr(opCreate, subservice.KindBusinessLogic, err)
+	if err := s.authorize(ctx, opCreate, authz.WriteSubscriptions, sub.UserID); err != nil {
+		return nil, err
+	}
+
+	err := s.txProvider.WithTransaction(ctx, func(uow tx.UnitOfWork) error {
+		if err := uow.Subscriptions().Create(ctx, &sub); err != nil {
+			return err
 		}
-		return nil, subservice.WrapErr(opCreate, subservice.KindUnknown, err)
+		return recordAudit(ctx, uow.Audit(), domain.AuditCreate, nil, &sub)
+	})
+	if err != nil {
+		return nil, s.writeErr(ctx, opCreate, &sub, err)
 	}
 
 	log.FromCtx(ctx).Info("subscription created", slog.String("subscription_id", sub.ID.String()))
@@ -51,6 +86,61 @@ func (s *service) Create(ctx context.Context, sub domain.Subscription) (*domain.
 	return &sub, nil
 }
 
+func (s *service) CreateIdempotent(
+	ctx context.Context,
+	sub domain.Subscription,
+	key domain.IdempotencyKey,
+) (*domain.Subscription, bool, error) {
+	if err := s.authorize(ctx, opCreateIdempotent, authz.WriteSubscriptions, sub.UserID); err != nil {
+		return nil, false, err
+	}
+	// Callers picking the same key must not get each other's response.
+	if caller, ok := domain.PrincipalFromCtx(ctx); ok {
+		key.Caller = caller.CallerID()
+	}
+
+	var created *domain.Subscription
+	var replayed bool
+	err := s.txProvider.WithTransaction(ctx, func(uow tx.UnitOfWork) error {
+		keys := uow.IdempotencyKeys()
+
+		rec, err := keys.Reserve(ctx, key)
+		if err != nil {
+			return err
+		}
+		if rec != nil {
+			if rec.RequestHash != key.RequestHash {
+				return subservice.WrapErr(opCreateIdempotent, subservice.KindDuplicate, errKeyReused)
+			}
+			created, replayed = rec.Response, true
+			return nil
+		}
+
+		if err := uow.Subscriptions().Create(ctx, &sub); err != nil {
+			return err
+		}
+		if err := recordAudit(ctx, uow.Audit(), domain.AuditCreate, nil, &sub); err != nil {
+			return err
+		}
+		if err := keys.Complete(ctx, key, &sub); err != nil {
+			return err
+		}
+		created = &sub
+		return nil
+	})
+	if err != nil {
+		return nil, false, s.writeErr(ctx, opCreateIdempotent, &sub, err)
+	}
+
+	if replayed {
+		log.FromCtx(ctx).Info("subscription creation replayed", slog.String("subscription_id", created.ID.String()))
+	} else {
+		log.FromCtx(ctx).Info("subscription created", slog.String("subscription_id", created.ID.String()))
+	}
+
+	return created, replayed, nil
+}
+
 func (s *service) GetByID(ctx context.Context, id uuid.UUID) (*domain.Subscription, error) {
 	sub, err := s.repo.GetByID(ctx, id)
 	if err != nil {
@@ -60,73 +150,206 @@ func (s *service) GetByID(ctx context.Context, id uuid.UUID) (*domain.Subscripti
 		}
 		return nil, subservice.WrapErr(opGetByID, subservice.KindUnknown, err)
 	}
+	if err := s.authorize(ctx, opGetByID, authz.ReadSubscriptions, sub.UserID); err != nil {
+		return nil, err
+	}
 	return sub, nil
 }
 
-func (s *service) Update(ctx context.Context, id uuid.UUID, update domain.SubscriptionUpdate) (*domain.Subscription, error) {
+func (s *service) Update(
+	ctx context.Context,
+	id uuid.UUID,
+	version *int,
+	update domain.SubscriptionUpdate,
+) (*domain.Subscription, error) {
+	return s.update(ctx, opUpdate, id, version, func(domain.Subscription) (domain.SubscriptionUpdate, error) {
+		return update, nil
+	})
+}
+
+func (s *service) Patch(
+	ctx context.Context,
+	id uuid.UUID,
+	version *int,
+	patch func(current domain.Subscription) (domain.SubscriptionUpdate, error),
+) (*domain.Subscription, error) {
+	return s.update(ctx, opPatch, id, version, patch)
+}
+
+// update applies the update patch makes of the current subscription. Errors
+// of patch are returned as is, so are those of authorizing the update.
+func (s *service) update(
+	ctx context.Context,
+	op string,
+	id uuid.UUID,
+	version *int,
+	patch func(current domain.Subscription) (domain.SubscriptionUpdate, error),
+) (*domain.Subscription, error) {
 	var updatedSub *domain.Subscription
+	var patchErr error
+	patch = s.authorizedPatch(ctx, op, patch)
 	err := s.txProvider.WithTransaction(ctx, func(uow tx.UnitOfWork) error {
-		repo := uow.Subscriptions()
+		var err error
+		updatedSub, err = applyUpdate(ctx, op, uow, id, version, func(current domain.Subscription) (domain.SubscriptionUpdate, error) {
+			update, err := patch(current)
+			patchErr = err
+			return update, err
+		})
+		return err
+	})
 
-		existing, err := repo.GetByID(ctx, id)
-		if err != nil {
-			return err
-		}
+	if patchErr != nil {
+		return nil, patchErr
+	}
+	if err != nil {
+		return nil, s.writeErr(ctx, op, updatedSub, err)
+	}
 
-		if update.ServiceName != nil {
-			existing.ServiceName = *update.ServiceName
-		}
-		if update.MonthlyCost != nil {
-			existing.MonthlyCost = *update.MonthlyCost
-		}
-		if update.ClearEndDate {
-			existing.EndDate = nil
-		} else if update.EndDate != nil {
-			existing.EndDate = update.EndDate
-		}
-		existing.UpdatedAt = time.Now().UTC()
+	log.FromCtx(ctx).Info("subscription updated", slog.String("subscription_id", updatedSub.ID.String()))
 
-		if existing.EndDate != nil && existing.StartDate.After(*existing.EndDate) {
-			return subservice.WrapErr(opUpdate, subservice.KindBusinessLogic, errors.New("end_date cannot be before start_date"))
-		}
+	return updatedSub, nil
+}
 
-		if err := repo.Update(ctx, existing); err != nil {
-			return err
-		}
-		updatedSub = existing
-		return nil
-	})
+// applyUpdate applies the update patch makes of subscription id in uow and
+// records it. Once it got to storing the subscription, it returns it along
+// with the error.
+func applyUpdate(
+	ctx context.Context,
+	op string,
+	uow tx.UnitOfWork,
+	id uuid.UUID,
+	version *int,
+	patch func(current domain.Subscription) (domain.SubscriptionUpdate, error),
+) (*domain.Subscription, error) {
+	repo := uow.Subscriptions()
+	existing, err := repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	before := *existing
+	if version != nil && *version != existing.Version {
+		return nil, subservice.WrapErr(op, subservice.KindConflict, errVersionMismatch)
+	}
 
+	update, err := patch(*existing)
 	if err != nil {
-		var repoErr *errkit.BaseErr[repos.RepoKind]
-		if errors.As(err, &repoErr) {
-			switch repoErr.Kind {
-			case repos.KindNotFound:
-				return nil, subservice.WrapErr(opUpdate, subservice.KindNotFound, err)
-			case repos.KindDuplicate:
-				return nil, subservice.WrapErr(opUpdate, subservice.KindBusinessLogic, err)
+		return nil, err
+	}
+
+	if update.ServiceName != nil {
+		existing.ServiceName = *update.ServiceName
+	}
+	if update.UserID != nil {
+		existing.UserID = *update.UserID
+	}
+	if update.StartDate != nil {
+		moved := !startOfMonth(*update.StartDate).Equal(startOfMonth(existing.StartDate))
+		existing.StartDate = *update.StartDate
+		// The price history has to start in the start month for the earlier months to be billed.
+		if moved {
+			if err := repo.RebasePriceHistory(ctx, existing.ID, startOfMonth(existing.StartDate)); err != nil {
+				return nil, err
 			}
 		}
-		var serviceErr *errkit.BaseErr[subservice.ServiceKind]
-		if errors.As(err, &serviceErr) && serviceErr.Kind == subservice.KindBusinessLogic {
+	}
+	if update.Price != nil && (*update.Price != existing.Price || update.PriceEffectiveFrom != nil) {
+		if err := changePrice(ctx, repo, existing, *update.Price, update.PriceEffectiveFrom); err != nil {
 			return nil, err
 		}
-		return nil, subservice.WrapErr(opUpdate, subservice.KindUnknown, err)
 	}
+	if update.BillingPeriod != nil {
+		existing.BillingPeriod = *update.BillingPeriod
+	}
+	if update.Currency != nil {
+		existing.Currency = *update.Currency
+	}
+	if update.ClearEndDate {
+		existing.EndDate = nil
+	} else if update.EndDate != nil {
+		existing.EndDate = update.EndDate
+	}
+	if update.ClearTrialEnd {
+		existing.TrialEnd = nil
+	} else if update.TrialEnd != nil {
+		existing.TrialEnd = update.TrialEnd
+	}
+	existing.UpdatedAt = time.Now().UTC()
 
-	log.FromCtx(ctx).Info("subscription updated", slog.String("subscription_id", updatedSub.ID.String()))
+	if existing.EndDate != nil && existing.StartDate.After(*existing.EndDate) {
+		return nil, subservice.WrapErr(op, subservice.KindBusinessLogic, errors.New("end_date cannot be before start_date"))
+	}
+	if existing.TrialEnd != nil && existing.TrialEnd.Before(existing.StartDate) {
+		return nil, subservice.WrapErr(op, subservice.KindBusinessLogic, errors.New("trial_end cannot be before start_date"))
+	}
 
-	return updatedSub, nil
+	if err := repo.Update(ctx, existing); err != nil {
+		return existing, err
+	}
+	return existing, recordAudit(ctx, uow.Audit(), domain.AuditUpdate, &before, existing)
 }
 
-func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
-	err := s.repo.Delete(ctx, id)
-	if err != nil {
-		var repoErr *errkit.BaseErr[repos.RepoKind]
-		if errors.As(err, &repoErr) && repoErr.Kind == repos.KindNotFound {
-			return subservice.WrapErr(opDelete, subservice.KindNotFound, err)
+// writeErr maps the error of a write transaction that tried to store sub to a
+// service error of op. sub may be nil if the transaction failed before.
+func (s *service) writeErr(ctx context.Context, op string, sub *domain.Subscription, err error) error {
+	if sub != nil && isRepoKind(err, repos.KindDuplicate) {
+		return s.overlapErr(ctx, op, sub, err)
+	}
+	return wrapTxErr(op, err)
+}
+
+// wrapTxErr maps the error of a write transaction to a service error of op.
+// Service errors raised inside the transaction are returned as is.
+func wrapTxErr(op string, err error) error {
+	var repoErr *errkit.BaseErr[repos.RepoKind]
+	if errors.As(err, &repoErr) {
+		switch repoErr.Kind {
+		case repos.KindNotFound:
+			return subservice.WrapErr(op, subservice.KindNotFound, err)
+		case repos.KindDuplicate:
+			return subservice.WrapErr(op, subservice.KindDuplicate, err)
+		case repos.KindConflict:
+			return subservice.WrapErr(op, subservice.KindConflict, err)
 		}
-		return subservice.WrapErr(opDelete, subservice.KindUnknown, err)
+	}
+	var serviceErr *errkit.BaseErr[subservice.ServiceKind]
+	if errors.As(err, &serviceErr) && serviceErr.Kind != subservice.KindUnknown {
+		return err
+	}
+	return subservice.WrapErr(op, subservice.KindUnknown, err)
+}
+
+func isRepoKind(err error, kind repos.RepoKind) bool {
+	var repoErr *errkit.BaseErr[repos.RepoKind]
+	return errors.As(err, &repoErr) && repoErr.Kind == kind
+}
+
+// overlapErr maps the duplicate error of writing sub to a KindDuplicate error of
+// op naming the subscription sub overlaps. The lookup runs after the failed
+// write, so the name is left out if that subscription is gone by then.
+func (s *service) overlapErr(ctx context.Context, op string, sub *domain.Subscription, err error) error {
+	id, findErr := s.repo.FindOverlapping(ctx, sub)
+	if findErr != nil {
+		log.FromCtx(ctx).Warn("failed to find overlapping subscription", log.WithErr(findErr))
+		return subservice.WrapErr(op, subservice.KindDuplicate, err)
+	}
+	return subservice.WrapErr(op, subservice.KindDuplicate, &subservice.OverlapError{ConflictingID: id, Err: err})
+}
+
+// Delete authorizes the caller against the subscription it deleted, the
+// transaction is rolled back if it may not.
+func (s *service) Delete(ctx context.Context, id uuid.UUID, version *int) error {
+	err := s.txProvider.WithTransaction(ctx, func(uow tx.UnitOfWork) error {
+		deleted, err := uow.Subscriptions().Delete(ctx, id, version)
+		if err != nil {
+			return err
+		}
+		if err := s.authorize(ctx, opDelete, authz.WriteSubscriptions, deleted.UserID); err != nil {
+			return err
+		}
+		return recordAudit(ctx, uow.Audit(), domain.AuditDelete, deleted, nil)
+	})
+	if err != nil {
+		return wrapTxErr(opDelete, err)
 	}
 
 	log.FromCtx(ctx).Info("subscription deleted", slog.String("subscription_id", id.String()))
@@ -134,69 +357,151 @@ func (s *service) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-func (s *service) List(ctx context.Context, filter domain.SubscriptionFilter) ([]domain.Subscription, error) {
+func (s *service) List(ctx context.Context, filter domain.SubscriptionFilter) (*domain.SubscriptionPage, error) {
+	if err := s.scopeFilter(ctx, opList, authz.ReadSubscriptions, &filter); err != nil {
+		return nil, err
+	}
+
 	log.FromCtx(ctx).Debug("listing subscriptions", slog.Any("filter", filter))
-	subs, err := s.repo.List(ctx, filter)
+	page, err := s.repo.List(ctx, filter)
 	if err != nil {
 		return nil, subservice.WrapErr(opList, subservice.KindUnknown, err)
 	}
-	return subs, nil
+	if page.Total, err = s.repo.Count(ctx, filter); err != nil {
+		return nil, subservice.WrapErr(opList, subservice.KindUnknown, err)
+	}
+	return page, nil
 }
 
-func (s *service) TotalCost(ctx context.Context, filter domain.SubscriptionFilter, start, end time.Time) (int, error) {
-	log.FromCtx(ctx).Debug("calculating total cost", slog.Any("filter", filter), slog.Time("start", start), slog.Time("end", end))
-	subs, err := s.repo.ListAll(ctx, filter)
-	if err != nil {
-		return 0, subservice.WrapErr(opTotalCost, subservice.KindUnknown, err)
+func (s *service) Export(ctx context.Context, filter domain.SubscriptionFilter) iter.Seq2[domain.Subscription, error] {
+	return func(yield func(domain.Subscription, error) bool) {
+		if err := s.scopeFilter(ctx, opExport, authz.ReadSubscriptions, &filter); err != nil {
+			yield(domain.Subscription{}, err)
+			return
+		}
+
+		log.FromCtx(ctx).Debug("exporting subscriptions", slog.Any("filter", filter))
+		for sub, err := range s.repo.Stream(ctx, filter) {
+			if err != nil {
+				yield(domain.Subscription{}, subservice.WrapErr(opExport, subservice.KindUnknown, err))
+				return
+			}
+			if !yield(sub, nil) {
+				return
+			}
+		}
 	}
+}
 
-	totalCost := 0
-	for _, sub := range subs {
-		totalCost += calculateCostForSubscription(sub, start, end)
+func (s *service) SuggestServiceNames(ctx context.Context, query string, limit *int) ([]string, error) {
+	// Names aren't limited to the subscriptions of the caller, any reader gets them.
+	if err := s.scopeFilter(ctx, opSuggest, authz.ReadSubscriptions, &domain.SubscriptionFilter{}); err != nil {
+		return nil, err
 	}
 
-	return totalCost, nil
+	log.FromCtx(ctx).Debug("suggesting service names", slog.String("query", query))
+	names, err := s.repo.SuggestServiceNames(ctx, query, limit)
+	if err != nil {
+		return nil, subservice.WrapErr(opSuggest, subservice.KindUnknown, err)
+	}
+	return names, nil
 }
 
-func startOfMonth(t time.Time) time.Time {
-	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
-}
+func (s *service) TotalCost(
+	ctx context.Context,
+	filter domain.SubscriptionFilter,
+	start, end time.Time,
+	currency string,
+	mode domain.CostMode,
+) (int, error) {
+	if err := s.scopeFilter(ctx, opTotalCost, authz.ReadCosts, &filter); err != nil {
+		return 0, err
+	}
 
-func calculateCostForSubscription(sub domain.Subscription, periodStart, periodEnd time.Time) int {
-	subStart := startOfMonth(sub.StartDate)
-	pStart := startOfMonth(periodStart)
-	pEnd := startOfMonth(periodEnd)
+	log.FromCtx(ctx).Debug(
+		"calculating total cost",
+		slog.Any("filter", filter), slog.Time("start", start), slog.Time("end", end),
+		slog.String("currency", currency), slog.String("mode", string(mode)),
+	)
+	totals, err := s.totalsByCurrency(ctx, filter, start, end, mode)
+	if err != nil {
+		return 0, subservice.WrapErr(opTotalCost, subservice.KindUnknown, err)
+	}
 
-	if subStart.After(pEnd) {
-		return 0
+	rates, err := s.conversionRates(ctx, slices.Collect(maps.Keys(totals)), currency)
+	if err != nil {
+		return 0, wrapConversionErr(opTotalCost, err)
 	}
 
-	if sub.EndDate != nil {
-		subEnd := startOfMonth(*sub.EndDate)
-		if subEnd.Before(pStart) {
-			return 0
+	totalCost := 0.0
+	for from, total := range totals {
+		totalCost += total * rates[from]
+	}
+
+	return int(math.Round(totalCost)), nil
+}
+
+// totalsByCurrency leaves billed totals to the repository. Prorated ones depend on
+// the active days of every month and are calculated from the subscriptions.
+func (s *service) totalsByCurrency(
+	ctx context.Context,
+	filter domain.SubscriptionFilter,
+	start, end time.Time,
+	mode domain.CostMode,
+) (map[string]float64, error) {
+	totals := make(map[string]float64)
+
+	if mode == domain.CostModeProrated {
+		subs, err := s.repo.ListAll(ctx, filter)
+		if err != nil {
+			return nil, err
 		}
+		for _, sub := range subs {
+			totals[sub.Currency] += calculateProratedCost(sub, start, end)
+		}
+		return totals, nil
 	}
 
-	billingStart := pStart
-	if subStart.After(pStart) {
-		billingStart = subStart
+	billed, err := s.repo.TotalCostByCurrency(ctx, filter, start, end)
+	if err != nil {
+		return nil, err
 	}
+	for currency, total := range billed {
+		totals[currency] = float64(total)
+	}
+	return totals, nil
+}
 
-	billingEnd := pEnd
-	if sub.EndDate != nil {
-		subEnd := startOfMonth(*sub.EndDate)
-		if subEnd.Before(pEnd) {
-			billingEnd = subEnd
-		}
+func (s *service) CostBreakdown(
+	ctx context.Context,
+	filter domain.SubscriptionFilter,
+	start, end time.Time,
+	currency string,
+	mode domain.CostMode,
+) ([]domain.MonthCost, error) {
+	if err := s.scopeFilter(ctx, opCostBreakdown, authz.ReadCosts, &filter); err != nil {
+		return nil, err
+	}
+
+	log.FromCtx(ctx).Debug(
+		"calculating cost breakdown",
+		slog.Any("filter", filter), slog.Time("start", start), slog.Time("end", end),
+		slog.String("currency", currency), slog.String("mode", string(mode)),
+	)
+	subs, err := s.repo.ListAll(ctx, filter)
+	if err != nil {
+		return nil, subservice.WrapErr(opCostBreakdown, subservice.KindUnknown, err)
 	}
 
-	if billingStart.After(billingEnd) {
-		return 0
+	currencies := make([]string, 0, len(subs))
+	for _, sub := range subs {
+		currencies = append(currencies, sub.Currency)
 	}
 
-	months := (billingEnd.Year()-billingStart.Year())*12 +
-		int(billingEnd.Month()) - int(billingStart.Month()) + 1
+	rates, err := s.conversionRates(ctx, currencies, currency)
+	if err != nil {
+		return nil, wrapConversionErr(opCostBreakdown, err)
+	}
 
-	return months * sub.MonthlyCost
+	return calculateCostBreakdown(subs, rates, start, end, mode), nil
 }
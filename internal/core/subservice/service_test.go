@@ -3,11 +3,13 @@ package subservice
 import (
 	"context"
 	"errors"
+	"iter"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	authzmocks "github.com/shrtyk/subscriptions-service/internal/core/ports/authz/mocks"
 	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
 	reposmocks "github.com/shrtyk/subscriptions-service/internal/core/ports/repos/mocks"
 	"github.com/shrtyk/subscriptions-service/internal/core/ports/subservice"
@@ -22,17 +24,33 @@ import (
 type serviceTestBundle struct {
 	svc        subservice.SubscriptionsService
 	repo       *reposmocks.MockSubscriptionRepository
+	ratesRepo  *reposmocks.MockCurrencyRateRepository
+	apiKeys    *reposmocks.MockAPIKeyRepository
+	audit      *reposmocks.MockAuditRepository
+	authz      *authzmocks.MockAuthorizer
 	txProvider *txmocks.MockProvider
 }
 
 func setup(t *testing.T) serviceTestBundle {
 	t.Helper()
 	repo := reposmocks.NewMockSubscriptionRepository(t)
+	ratesRepo := reposmocks.NewMockCurrencyRateRepository(t)
+	apiKeys := reposmocks.NewMockAPIKeyRepository(t)
+	auditRepo := reposmocks.NewMockAuditRepository(t)
 	txProvider := txmocks.NewMockProvider(t)
-	svc := New(repo, txProvider)
+	// Callers may do anything unless a test says otherwise, authorization has
+	// its own tests with the real policy.
+	authorizer := authzmocks.NewMockAuthorizer(t)
+	authorizer.EXPECT().Authorize(mock.Anything, mock.Anything, mock.Anything).Return(nil).Maybe()
+	authorizer.EXPECT().AuthorizeAll(mock.Anything, mock.Anything).Return(nil).Maybe()
+	svc := New(repo, ratesRepo, apiKeys, auditRepo, authorizer, txProvider)
 	return serviceTestBundle{
 		svc:        svc,
 		repo:       repo,
+		ratesRepo:  ratesRepo,
+		apiKeys:    apiKeys,
+		audit:      auditRepo,
+		authz:      authorizer,
 		txProvider: txProvider,
 	}
 }
@@ -103,6 +121,8 @@ func TestService_Create(t *testing.T) {
 	ctx := context.Background()
 	sub := domain.Subscription{ServiceName: "Test"}
 	repoErrDuplicate := errkit.WrapErr("op", repos.KindDuplicate, errors.New("duplicate"))
+	repoErrNotFound := errkit.WrapErr("op", repos.KindNotFound, errors.New("not found"))
+	otherID := uuid.New()
 
 	testCases := []struct {
 		name       string
@@ -112,7 +132,11 @@ func TestService_Create(t *testing.T) {
 		{
 			name: "Success",
 			setupMocks: func(bundle serviceTestBundle) {
+				expectTx(t, ctx, bundle)
 				bundle.repo.On("Create", ctx, mock.AnythingOfType("*domain.Subscription")).Return(nil).Once()
+				bundle.audit.On("Record", ctx, mock.MatchedBy(func(e *domain.AuditEntry) bool {
+					return e.Op == domain.AuditCreate && e.Before == nil && e.After.ServiceName == "Test"
+				})).Return(nil).Once()
 			},
 			assertFunc: func(t *testing.T, sub *domain.Subscription, err error) {
 				require.NoError(t, err)
@@ -120,16 +144,51 @@ func TestService_Create(t *testing.T) {
 			},
 		},
 		{
-			name: "Duplicate",
+			name: "Audit Failure",
+			setupMocks: func(bundle serviceTestBundle) {
+				expectTx(t, ctx, bundle)
+				bundle.repo.On("Create", ctx, mock.AnythingOfType("*domain.Subscription")).Return(nil).Once()
+				bundle.audit.On("Record", ctx, mock.Anything).Return(errors.New("db is down")).Once()
+			},
+			assertFunc: func(t *testing.T, sub *domain.Subscription, err error) {
+				assert.Nil(t, sub)
+				assertServiceErrKind(t, err, subservice.KindUnknown)
+			},
+		},
+		{
+			name: "Overlaps Another Subscription",
 			setupMocks: func(bundle serviceTestBundle) {
+				expectTx(t, ctx, bundle)
 				bundle.repo.On("Create", ctx, mock.AnythingOfType("*domain.Subscription")).Return(repoErrDuplicate).Once()
+				bundle.repo.On("FindOverlapping", ctx, mock.AnythingOfType("*domain.Subscription")).Return(otherID, nil).Once()
 			},
 			assertFunc: func(t *testing.T, sub *domain.Subscription, err error) {
 				require.Error(t, err)
 				assert.Nil(t, sub)
 				var svcErr *errkit.BaseErr[subservice.ServiceKind]
 				require.ErrorAs(t, err, &svcErr)
-				assert.Equal(t, subservice.KindBusinessLogic, svcErr.Kind)
+				assert.Equal(t, subservice.KindDuplicate, svcErr.Kind)
+				var overlapErr *subservice.OverlapError
+				require.ErrorAs(t, err, &overlapErr)
+				assert.Equal(t, otherID, overlapErr.ConflictingID)
+				assert.ErrorIs(t, err, repoErrDuplicate)
+			},
+		},
+		{
+			name: "Overlapping Subscription Gone",
+			setupMocks: func(bundle serviceTestBundle) {
+				expectTx(t, ctx, bundle)
+				bundle.repo.On("Create", ctx, mock.AnythingOfType("*domain.Subscription")).Return(repoErrDuplicate).Once()
+				bundle.repo.On("FindOverlapping", ctx, mock.AnythingOfType("*domain.Subscription")).Return(uuid.Nil, repoErrNotFound).Once()
+			},
+			assertFunc: func(t *testing.T, sub *domain.Subscription, err error) {
+				require.Error(t, err)
+				assert.Nil(t, sub)
+				var svcErr *errkit.BaseErr[subservice.ServiceKind]
+				require.ErrorAs(t, err, &svcErr)
+				assert.Equal(t, subservice.KindDuplicate, svcErr.Kind)
+				var overlapErr *subservice.OverlapError
+				assert.False(t, errors.As(err, &overlapErr))
 			},
 		},
 	}
@@ -144,21 +203,117 @@ func TestService_Create(t *testing.T) {
 	}
 }
 
+func TestService_CreateIdempotent(t *testing.T) {
+	callerID := uuid.New()
+	ctx := domain.PrincipalToCtx(context.Background(), &domain.Principal{UserID: callerID, Role: domain.RoleEditor})
+	sub := domain.Subscription{ServiceName: "Test"}
+	sent := domain.IdempotencyKey{Key: "key-1", RequestHash: "hash"}
+	// The key is scoped to the caller of ctx.
+	key := domain.IdempotencyKey{Key: "key-1", Caller: "user:" + callerID.String(), RequestHash: "hash"}
+	stored := &domain.Subscription{ID: uuid.New(), ServiceName: "Test"}
+	reserveErr := errors.New("db is down")
+
+	testCases := []struct {
+		name       string
+		setupMocks func(bundle serviceTestBundle, keys *reposmocks.MockIdempotencyKeyRepository)
+		assertFunc func(t *testing.T, sub *domain.Subscription, replayed bool, err error)
+	}{
+		{
+			name: "Success - New Key",
+			setupMocks: func(bundle serviceTestBundle, keys *reposmocks.MockIdempotencyKeyRepository) {
+				keys.On("Reserve", ctx, key).Return(nil, nil).Once()
+				bundle.repo.On("Create", ctx, mock.AnythingOfType("*domain.Subscription")).Return(nil).Once()
+				expectAudit(bundle, domain.AuditCreate, 1)
+				keys.On("Complete", ctx, key, mock.AnythingOfType("*domain.Subscription")).Return(nil).Once()
+			},
+			assertFunc: func(t *testing.T, sub *domain.Subscription, replayed bool, err error) {
+				require.NoError(t, err)
+				assert.False(t, replayed)
+				assert.Equal(t, "Test", sub.ServiceName)
+			},
+		},
+		{
+			name: "Success - Replayed",
+			setupMocks: func(bundle serviceTestBundle, keys *reposmocks.MockIdempotencyKeyRepository) {
+				keys.On("Reserve", ctx, key).Return(&domain.IdempotencyRecord{IdempotencyKey: key, Response: stored}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, sub *domain.Subscription, replayed bool, err error) {
+				require.NoError(t, err)
+				assert.True(t, replayed)
+				assert.Equal(t, stored, sub)
+			},
+		},
+		{
+			name: "Key Reused With Another Request",
+			setupMocks: func(bundle serviceTestBundle, keys *reposmocks.MockIdempotencyKeyRepository) {
+				other := domain.IdempotencyKey{Key: key.Key, RequestHash: "other"}
+				keys.On("Reserve", ctx, key).Return(&domain.IdempotencyRecord{IdempotencyKey: other, Response: stored}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, sub *domain.Subscription, replayed bool, err error) {
+				require.Error(t, err)
+				assert.Nil(t, sub)
+				var svcErr *errkit.BaseErr[subservice.ServiceKind]
+				require.ErrorAs(t, err, &svcErr)
+				assert.Equal(t, subservice.KindDuplicate, svcErr.Kind)
+			},
+		},
+		{
+			name: "Reserve Error",
+			setupMocks: func(bundle serviceTestBundle, keys *reposmocks.MockIdempotencyKeyRepository) {
+				keys.On("Reserve", ctx, key).Return(nil, reserveErr).Once()
+			},
+			assertFunc: func(t *testing.T, sub *domain.Subscription, replayed bool, err error) {
+				require.ErrorIs(t, err, reserveErr)
+				assert.Nil(t, sub)
+				var svcErr *errkit.BaseErr[subservice.ServiceKind]
+				require.ErrorAs(t, err, &svcErr)
+				assert.Equal(t, subservice.KindUnknown, svcErr.Kind)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bundle := setup(t)
+			keys := reposmocks.NewMockIdempotencyKeyRepository(t)
+			tc.setupMocks(bundle, keys)
+			bundle.txProvider.On("WithTransaction", ctx, mock.Anything).
+				Return(func(ctx context.Context, fn func(uow tx.UnitOfWork) error) error {
+					uowMock := txmocks.NewMockUnitOfWork(t)
+					uowMock.On("IdempotencyKeys").Return(keys)
+					uowMock.On("Subscriptions").Return(bundle.repo).Maybe()
+					uowMock.On("Audit").Return(bundle.audit).Maybe()
+					return fn(uowMock)
+				}).Once()
+
+			createdSub, replayed, err := bundle.svc.CreateIdempotent(ctx, sub, sent)
+			tc.assertFunc(t, createdSub, replayed, err)
+		})
+	}
+}
+
 func TestService_Delete(t *testing.T) {
 	ctx := context.Background()
 	subID := uuid.New()
 	repoErrNotFound := errkit.WrapErr("op", repos.KindNotFound, errors.New("not found"))
 	repoErrGeneric := errkit.WrapErr("op", repos.KindUnknown, errors.New("db is down"))
+	repoErrConflict := errkit.WrapErr("op", repos.KindConflict, errors.New("version mismatch"))
+	version := 2
 
 	testCases := []struct {
 		name       string
+		version    *int
 		setupMocks func(bundle serviceTestBundle)
 		assertFunc func(t *testing.T, err error)
 	}{
 		{
 			name: "Success",
 			setupMocks: func(bundle serviceTestBundle) {
-				bundle.repo.On("Delete", ctx, subID).Return(nil).Once()
+				expectTx(t, ctx, bundle)
+				bundle.repo.On("Delete", ctx, subID, (*int)(nil)).Return(&domain.Subscription{ID: subID}, nil).Once()
+				bundle.audit.On("Record", ctx, mock.MatchedBy(func(e *domain.AuditEntry) bool {
+					return e.Op == domain.AuditDelete && e.SubscriptionID == subID && e.After == nil
+				})).Return(nil).Once()
 			},
 			assertFunc: func(t *testing.T, err error) {
 				require.NoError(t, err)
@@ -167,7 +322,8 @@ func TestService_Delete(t *testing.T) {
 		{
 			name: "Not Found",
 			setupMocks: func(bundle serviceTestBundle) {
-				bundle.repo.On("Delete", ctx, subID).Return(repoErrNotFound).Once()
+				expectTx(t, ctx, bundle)
+				bundle.repo.On("Delete", ctx, subID, (*int)(nil)).Return(nil, repoErrNotFound).Once()
 			},
 			assertFunc: func(t *testing.T, err error) {
 				require.Error(t, err)
@@ -176,10 +332,25 @@ func TestService_Delete(t *testing.T) {
 				assert.Equal(t, subservice.KindNotFound, svcErr.Kind)
 			},
 		},
+		{
+			name:    "Version Conflict",
+			version: &version,
+			setupMocks: func(bundle serviceTestBundle) {
+				expectTx(t, ctx, bundle)
+				bundle.repo.On("Delete", ctx, subID, &version).Return(nil, repoErrConflict).Once()
+			},
+			assertFunc: func(t *testing.T, err error) {
+				require.Error(t, err)
+				var svcErr *errkit.BaseErr[subservice.ServiceKind]
+				require.ErrorAs(t, err, &svcErr)
+				assert.Equal(t, subservice.KindConflict, svcErr.Kind)
+			},
+		},
 		{
 			name: "Generic Error",
 			setupMocks: func(bundle serviceTestBundle) {
-				bundle.repo.On("Delete", ctx, subID).Return(repoErrGeneric).Once()
+				expectTx(t, ctx, bundle)
+				bundle.repo.On("Delete", ctx, subID, (*int)(nil)).Return(nil, repoErrGeneric).Once()
 			},
 			assertFunc: func(t *testing.T, err error) {
 				require.Error(t, err)
@@ -194,7 +365,7 @@ func TestService_Delete(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			bundle := setup(t)
 			tc.setupMocks(bundle)
-			err := bundle.svc.Delete(ctx, subID)
+			err := bundle.svc.Delete(ctx, subID, tc.version)
 			tc.assertFunc(t, err)
 		})
 	}
@@ -205,13 +376,18 @@ func TestService_Update(t *testing.T) {
 	subID := uuid.New()
 	repoErrNotFound := errkit.WrapErr("op", repos.KindNotFound, errors.New("not found"))
 	repoErrDuplicate := errkit.WrapErr("op", repos.KindDuplicate, errors.New("duplicate value"))
+	repoErrConflict := errkit.WrapErr("op", repos.KindConflict, errors.New("version mismatch"))
+	userID := uuid.New()
+	otherID := uuid.New()
 
 	strPtr := func(s string) *string { return &s }
 	intPtr := func(i int) *int { return &i }
 	timePtr := func(t time.Time) *time.Time { return &t }
+	yearly := domain.BillingYearly
 
 	testCases := []struct {
 		name        string
+		version     *int
 		update      domain.SubscriptionUpdate
 		existingSub *domain.Subscription
 		setupMocks  func(bundle serviceTestBundle, existingSub *domain.Subscription)
@@ -220,18 +396,31 @@ func TestService_Update(t *testing.T) {
 		{
 			name: "Success - Full Update",
 			update: domain.SubscriptionUpdate{
-				ServiceName: strPtr("New Name"),
-				MonthlyCost: intPtr(200),
-				EndDate:     timePtr(time.Now().Add(24 * time.Hour)),
+				ServiceName:   strPtr("New Name"),
+				Price:         intPtr(2000),
+				BillingPeriod: &yearly,
+				Currency:      strPtr("USD"),
+				EndDate:       timePtr(time.Now().Add(24 * time.Hour)),
 			},
-			existingSub: &domain.Subscription{ID: subID, ServiceName: "Old Name", MonthlyCost: 100},
+			existingSub: &domain.Subscription{ID: subID, ServiceName: "Old Name", Price: 100, BillingPeriod: domain.BillingMonthly, Currency: "RUB"},
 			setupMocks: func(bundle serviceTestBundle, existingSub *domain.Subscription) {
 				bundle.repo.On("GetByID", ctx, subID).Return(existingSub, nil).Once()
+				bundle.repo.On("AddPriceChange", ctx, subID, mock.MatchedBy(func(change *domain.PriceChange) bool {
+					return change.Price == 2000 && change.EffectiveFrom.Day() == 1
+				})).Return(nil).Once()
+				bundle.repo.On("ListPriceChanges", ctx, subID).Return([]domain.PriceChange{
+					{EffectiveFrom: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Price: 100},
+					{EffectiveFrom: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Price: 2000},
+				}, nil).Once()
 				bundle.repo.On("Update", ctx, mock.AnythingOfType("*domain.Subscription")).Return(nil).Once()
+				bundle.audit.On("Record", ctx, mock.MatchedBy(func(e *domain.AuditEntry) bool {
+					return e.Op == domain.AuditUpdate && e.Before.ServiceName == "Old Name" && e.After.ServiceName == "New Name"
+				})).Return(nil).Once()
 				bundle.txProvider.On("WithTransaction", ctx, mock.Anything).
 					Return(func(ctx context.Context, fn func(uow tx.UnitOfWork) error) error {
 						uowMock := txmocks.NewMockUnitOfWork(t)
 						uowMock.On("Subscriptions").Return(bundle.repo)
+						uowMock.On("Audit").Return(bundle.audit).Maybe()
 						return fn(uowMock)
 					}).Once()
 			},
@@ -239,7 +428,9 @@ func TestService_Update(t *testing.T) {
 				require.NoError(t, err)
 				assert.NotNil(t, sub)
 				assert.Equal(t, "New Name", sub.ServiceName)
-				assert.Equal(t, 200, sub.MonthlyCost)
+				assert.Equal(t, 2000, sub.Price)
+				assert.Equal(t, domain.BillingYearly, sub.BillingPeriod)
+				assert.Equal(t, "USD", sub.Currency)
 				assert.NotNil(t, sub.EndDate)
 			},
 		},
@@ -250,10 +441,12 @@ func TestService_Update(t *testing.T) {
 			setupMocks: func(bundle serviceTestBundle, existingSub *domain.Subscription) {
 				bundle.repo.On("GetByID", ctx, subID).Return(existingSub, nil).Once()
 				bundle.repo.On("Update", ctx, mock.AnythingOfType("*domain.Subscription")).Return(nil).Once()
+				expectAudit(bundle, domain.AuditUpdate, 1)
 				bundle.txProvider.On("WithTransaction", ctx, mock.Anything).
 					Return(func(ctx context.Context, fn func(uow tx.UnitOfWork) error) error {
 						uowMock := txmocks.NewMockUnitOfWork(t)
 						uowMock.On("Subscriptions").Return(bundle.repo)
+						uowMock.On("Audit").Return(bundle.audit).Maybe()
 						return fn(uowMock)
 					}).Once()
 			},
@@ -263,6 +456,102 @@ func TestService_Update(t *testing.T) {
 				assert.Nil(t, sub.EndDate)
 			},
 		},
+		{
+			name: "Success - Move Start Date",
+			update: domain.SubscriptionUpdate{
+				Price:     intPtr(100),
+				UserID:    &userID,
+				StartDate: timePtr(time.Date(2023, 3, 10, 0, 0, 0, 0, time.UTC)),
+			},
+			existingSub: &domain.Subscription{ID: subID, Price: 100, StartDate: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+			setupMocks: func(bundle serviceTestBundle, existingSub *domain.Subscription) {
+				bundle.repo.On("GetByID", ctx, subID).Return(existingSub, nil).Once()
+				bundle.repo.On("RebasePriceHistory", ctx, subID, time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC)).Return(nil).Once()
+				bundle.repo.On("Update", ctx, mock.AnythingOfType("*domain.Subscription")).Return(nil).Once()
+				expectAudit(bundle, domain.AuditUpdate, 1)
+				bundle.txProvider.On("WithTransaction", ctx, mock.Anything).
+					Return(func(ctx context.Context, fn func(uow tx.UnitOfWork) error) error {
+						uowMock := txmocks.NewMockUnitOfWork(t)
+						uowMock.On("Subscriptions").Return(bundle.repo)
+						uowMock.On("Audit").Return(bundle.audit).Maybe()
+						return fn(uowMock)
+					}).Once()
+			},
+			assertFunc: func(t *testing.T, sub *domain.Subscription, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, time.Date(2023, 3, 10, 0, 0, 0, 0, time.UTC), sub.StartDate)
+				assert.Equal(t, userID, sub.UserID)
+				assert.Equal(t, 100, sub.Price)
+			},
+		},
+		{
+			name:        "Success - Start Date Within Start Month",
+			update:      domain.SubscriptionUpdate{StartDate: timePtr(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))},
+			existingSub: &domain.Subscription{ID: subID, Price: 100, StartDate: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+			setupMocks: func(bundle serviceTestBundle, existingSub *domain.Subscription) {
+				bundle.repo.On("GetByID", ctx, subID).Return(existingSub, nil).Once()
+				bundle.repo.On("Update", ctx, mock.AnythingOfType("*domain.Subscription")).Return(nil).Once()
+				expectAudit(bundle, domain.AuditUpdate, 1)
+				bundle.txProvider.On("WithTransaction", ctx, mock.Anything).
+					Return(func(ctx context.Context, fn func(uow tx.UnitOfWork) error) error {
+						uowMock := txmocks.NewMockUnitOfWork(t)
+						uowMock.On("Subscriptions").Return(bundle.repo)
+						uowMock.On("Audit").Return(bundle.audit).Maybe()
+						return fn(uowMock)
+					}).Once()
+			},
+			assertFunc: func(t *testing.T, sub *domain.Subscription, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, 1, sub.StartDate.Day())
+			},
+		},
+		{
+			name:        "Trial end before start date",
+			update:      domain.SubscriptionUpdate{TrialEnd: timePtr(time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC))},
+			existingSub: &domain.Subscription{ID: subID, Price: 100, StartDate: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+			setupMocks: func(bundle serviceTestBundle, existingSub *domain.Subscription) {
+				bundle.repo.On("GetByID", ctx, subID).Return(existingSub, nil).Once()
+				bundle.txProvider.On("WithTransaction", ctx, mock.Anything).
+					Return(func(ctx context.Context, fn func(uow tx.UnitOfWork) error) error {
+						uowMock := txmocks.NewMockUnitOfWork(t)
+						uowMock.On("Subscriptions").Return(bundle.repo)
+						uowMock.On("Audit").Return(bundle.audit).Maybe()
+						return fn(uowMock)
+					}).Once()
+			},
+			assertFunc: func(t *testing.T, sub *domain.Subscription, err error) {
+				require.Error(t, err)
+				assert.Nil(t, sub)
+				var svcErr *errkit.BaseErr[subservice.ServiceKind]
+				require.ErrorAs(t, err, &svcErr)
+				assert.Equal(t, subservice.KindBusinessLogic, svcErr.Kind)
+			},
+		},
+		{
+			name: "Price change before start month",
+			update: domain.SubscriptionUpdate{
+				Price:              intPtr(2000),
+				PriceEffectiveFrom: timePtr(time.Date(2023, 12, 1, 0, 0, 0, 0, time.UTC)),
+			},
+			existingSub: &domain.Subscription{ID: subID, Price: 100, StartDate: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)},
+			setupMocks: func(bundle serviceTestBundle, existingSub *domain.Subscription) {
+				bundle.repo.On("GetByID", ctx, subID).Return(existingSub, nil).Once()
+				bundle.txProvider.On("WithTransaction", ctx, mock.Anything).
+					Return(func(ctx context.Context, fn func(uow tx.UnitOfWork) error) error {
+						uowMock := txmocks.NewMockUnitOfWork(t)
+						uowMock.On("Subscriptions").Return(bundle.repo)
+						uowMock.On("Audit").Return(bundle.audit).Maybe()
+						return fn(uowMock)
+					}).Once()
+			},
+			assertFunc: func(t *testing.T, sub *domain.Subscription, err error) {
+				require.Error(t, err)
+				assert.Nil(t, sub)
+				var svcErr *errkit.BaseErr[subservice.ServiceKind]
+				require.ErrorAs(t, err, &svcErr)
+				assert.Equal(t, subservice.KindBusinessLogic, svcErr.Kind)
+			},
+		},
 		{
 			name:        "GetByID returns Not Found",
 			update:      domain.SubscriptionUpdate{ServiceName: strPtr("New Name")},
@@ -273,6 +562,7 @@ func TestService_Update(t *testing.T) {
 					Return(func(ctx context.Context, fn func(uow tx.UnitOfWork) error) error {
 						uowMock := txmocks.NewMockUnitOfWork(t)
 						uowMock.On("Subscriptions").Return(bundle.repo)
+						uowMock.On("Audit").Return(bundle.audit).Maybe()
 						return fn(uowMock)
 					}).Once()
 			},
@@ -284,6 +574,74 @@ func TestService_Update(t *testing.T) {
 				assert.Equal(t, subservice.KindNotFound, svcErr.Kind)
 			},
 		},
+		{
+			name:        "Success - Version Matches",
+			version:     intPtr(3),
+			update:      domain.SubscriptionUpdate{ServiceName: strPtr("New Name")},
+			existingSub: &domain.Subscription{ID: subID, ServiceName: "Old Name", Version: 3},
+			setupMocks: func(bundle serviceTestBundle, existingSub *domain.Subscription) {
+				bundle.repo.On("GetByID", ctx, subID).Return(existingSub, nil).Once()
+				bundle.repo.On("Update", ctx, mock.AnythingOfType("*domain.Subscription")).Return(nil).Once()
+				expectAudit(bundle, domain.AuditUpdate, 1)
+				bundle.txProvider.On("WithTransaction", ctx, mock.Anything).
+					Return(func(ctx context.Context, fn func(uow tx.UnitOfWork) error) error {
+						uowMock := txmocks.NewMockUnitOfWork(t)
+						uowMock.On("Subscriptions").Return(bundle.repo)
+						uowMock.On("Audit").Return(bundle.audit).Maybe()
+						return fn(uowMock)
+					}).Once()
+			},
+			assertFunc: func(t *testing.T, sub *domain.Subscription, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, "New Name", sub.ServiceName)
+			},
+		},
+		{
+			name:        "Stale Version",
+			version:     intPtr(2),
+			update:      domain.SubscriptionUpdate{ServiceName: strPtr("New Name")},
+			existingSub: &domain.Subscription{ID: subID, ServiceName: "Old Name", Version: 3},
+			setupMocks: func(bundle serviceTestBundle, existingSub *domain.Subscription) {
+				bundle.repo.On("GetByID", ctx, subID).Return(existingSub, nil).Once()
+				bundle.txProvider.On("WithTransaction", ctx, mock.Anything).
+					Return(func(ctx context.Context, fn func(uow tx.UnitOfWork) error) error {
+						uowMock := txmocks.NewMockUnitOfWork(t)
+						uowMock.On("Subscriptions").Return(bundle.repo)
+						uowMock.On("Audit").Return(bundle.audit).Maybe()
+						return fn(uowMock)
+					}).Once()
+			},
+			assertFunc: func(t *testing.T, sub *domain.Subscription, err error) {
+				require.Error(t, err)
+				assert.Nil(t, sub)
+				var svcErr *errkit.BaseErr[subservice.ServiceKind]
+				require.ErrorAs(t, err, &svcErr)
+				assert.Equal(t, subservice.KindConflict, svcErr.Kind)
+			},
+		},
+		{
+			name:        "Update returns Conflict",
+			update:      domain.SubscriptionUpdate{ServiceName: strPtr("New Name")},
+			existingSub: &domain.Subscription{ID: subID, ServiceName: "Old Name"},
+			setupMocks: func(bundle serviceTestBundle, existingSub *domain.Subscription) {
+				bundle.repo.On("GetByID", ctx, subID).Return(existingSub, nil).Once()
+				bundle.repo.On("Update", ctx, mock.AnythingOfType("*domain.Subscription")).Return(repoErrConflict).Once()
+				bundle.txProvider.On("WithTransaction", ctx, mock.Anything).
+					Return(func(ctx context.Context, fn func(uow tx.UnitOfWork) error) error {
+						uowMock := txmocks.NewMockUnitOfWork(t)
+						uowMock.On("Subscriptions").Return(bundle.repo)
+						uowMock.On("Audit").Return(bundle.audit).Maybe()
+						return fn(uowMock)
+					}).Once()
+			},
+			assertFunc: func(t *testing.T, sub *domain.Subscription, err error) {
+				require.Error(t, err)
+				assert.Nil(t, sub)
+				var svcErr *errkit.BaseErr[subservice.ServiceKind]
+				require.ErrorAs(t, err, &svcErr)
+				assert.Equal(t, subservice.KindConflict, svcErr.Kind)
+			},
+		},
 		{
 			name:        "Update returns Duplicate",
 			update:      domain.SubscriptionUpdate{ServiceName: strPtr("Duplicate Name")},
@@ -291,10 +649,14 @@ func TestService_Update(t *testing.T) {
 			setupMocks: func(bundle serviceTestBundle, existingSub *domain.Subscription) {
 				bundle.repo.On("GetByID", ctx, subID).Return(existingSub, nil).Once()
 				bundle.repo.On("Update", ctx, mock.AnythingOfType("*domain.Subscription")).Return(repoErrDuplicate).Once()
+				bundle.repo.On("FindOverlapping", ctx, mock.MatchedBy(func(sub *domain.Subscription) bool {
+					return sub.ServiceName == "Duplicate Name"
+				})).Return(otherID, nil).Once()
 				bundle.txProvider.On("WithTransaction", ctx, mock.Anything).
 					Return(func(ctx context.Context, fn func(uow tx.UnitOfWork) error) error {
 						uowMock := txmocks.NewMockUnitOfWork(t)
 						uowMock.On("Subscriptions").Return(bundle.repo)
+						uowMock.On("Audit").Return(bundle.audit).Maybe()
 						return fn(uowMock)
 					}).Once()
 			},
@@ -303,7 +665,10 @@ func TestService_Update(t *testing.T) {
 				assert.Nil(t, sub)
 				var svcErr *errkit.BaseErr[subservice.ServiceKind]
 				require.ErrorAs(t, err, &svcErr)
-				assert.Equal(t, subservice.KindBusinessLogic, svcErr.Kind)
+				assert.Equal(t, subservice.KindDuplicate, svcErr.Kind)
+				var overlapErr *subservice.OverlapError
+				require.ErrorAs(t, err, &overlapErr)
+				assert.Equal(t, otherID, overlapErr.ConflictingID)
 			},
 		},
 	}
@@ -312,41 +677,134 @@ func TestService_Update(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			bundle := setup(t)
 			tc.setupMocks(bundle, tc.existingSub)
-			updatedSub, err := bundle.svc.Update(ctx, subID, tc.update)
+			updatedSub, err := bundle.svc.Update(ctx, subID, tc.version, tc.update)
 			tc.assertFunc(t, updatedSub, err)
 		})
 	}
 }
 
-func TestService_List(t *testing.T) {
+func TestService_Patch(t *testing.T) {
 	ctx := context.Background()
-	filter := domain.SubscriptionFilter{}
-	expectedSubs := []domain.Subscription{{ID: uuid.New()}}
+	subID := uuid.New()
+	patchErr := errors.New("invalid patch")
+
+	testCases := []struct {
+		name       string
+		version    *int
+		patch      func(current domain.Subscription) (domain.SubscriptionUpdate, error)
+		setupMocks func(bundle serviceTestBundle)
+		assertFunc func(t *testing.T, sub *domain.Subscription, err error)
+	}{
+		{
+			name: "Success",
+			patch: func(current domain.Subscription) (domain.SubscriptionUpdate, error) {
+				name := current.ServiceName + " Premium"
+				return domain.SubscriptionUpdate{ServiceName: &name}, nil
+			},
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("Update", ctx, mock.AnythingOfType("*domain.Subscription")).Return(nil).Once()
+				expectAudit(bundle, domain.AuditUpdate, 1)
+			},
+			assertFunc: func(t *testing.T, sub *domain.Subscription, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, "Okko Premium", sub.ServiceName)
+			},
+		},
+		{
+			name:    "Stale Version Is Checked Before Patch",
+			version: new(int),
+			patch: func(domain.Subscription) (domain.SubscriptionUpdate, error) {
+				t.Fatal("patch called for a stale version")
+				return domain.SubscriptionUpdate{}, nil
+			},
+			setupMocks: func(serviceTestBundle) {},
+			assertFunc: func(t *testing.T, sub *domain.Subscription, err error) {
+				var svcErr *errkit.BaseErr[subservice.ServiceKind]
+				require.ErrorAs(t, err, &svcErr)
+				assert.Equal(t, subservice.KindConflict, svcErr.Kind)
+				assert.Nil(t, sub)
+			},
+		},
+		{
+			name: "Patch Error Is Returned As Is",
+			patch: func(domain.Subscription) (domain.SubscriptionUpdate, error) {
+				return domain.SubscriptionUpdate{}, patchErr
+			},
+			setupMocks: func(serviceTestBundle) {},
+			assertFunc: func(t *testing.T, sub *domain.Subscription, err error) {
+				assert.Same(t, patchErr, err)
+				assert.Nil(t, sub)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bundle := setup(t)
+			bundle.repo.On("GetByID", ctx, subID).Return(&domain.Subscription{ID: subID, ServiceName: "Okko", Version: 1}, nil).Once()
+			bundle.txProvider.On("WithTransaction", ctx, mock.Anything).
+				Return(func(ctx context.Context, fn func(uow tx.UnitOfWork) error) error {
+					uowMock := txmocks.NewMockUnitOfWork(t)
+					uowMock.On("Subscriptions").Return(bundle.repo)
+					uowMock.On("Audit").Return(bundle.audit).Maybe()
+					return fn(uowMock)
+				}).Once()
+			tc.setupMocks(bundle)
+
+			sub, err := bundle.svc.Patch(ctx, subID, tc.version, tc.patch)
+			tc.assertFunc(t, sub, err)
+		})
+	}
+}
+
+func TestService_ListPriceChanges(t *testing.T) {
+	ctx := context.Background()
+	subID := uuid.New()
+	changes := []domain.PriceChange{
+		{EffectiveFrom: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Price: 100},
+		{EffectiveFrom: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), Price: 150},
+	}
+	repoErrNotFound := errkit.WrapErr("op", repos.KindNotFound, errors.New("not found"))
 	repoErrGeneric := errkit.WrapErr("op", repos.KindUnknown, errors.New("db error"))
 
 	testCases := []struct {
 		name       string
 		setupMocks func(bundle serviceTestBundle)
-		assertFunc func(t *testing.T, subs []domain.Subscription, err error)
+		assertFunc func(t *testing.T, changes []domain.PriceChange, err error)
 	}{
 		{
 			name: "Success",
 			setupMocks: func(bundle serviceTestBundle) {
-				bundle.repo.On("List", ctx, filter).Return(expectedSubs, nil).Once()
+				bundle.repo.On("GetByID", ctx, subID).Return(&domain.Subscription{ID: subID}, nil).Once()
+				bundle.repo.On("ListPriceChanges", ctx, subID).Return(changes, nil).Once()
 			},
-			assertFunc: func(t *testing.T, subs []domain.Subscription, err error) {
+			assertFunc: func(t *testing.T, actual []domain.PriceChange, err error) {
 				require.NoError(t, err)
-				assert.Equal(t, expectedSubs, subs)
+				assert.Equal(t, changes, actual)
 			},
 		},
 		{
-			name: "Generic Repo Error",
+			name: "Subscription Not Found",
 			setupMocks: func(bundle serviceTestBundle) {
-				bundle.repo.On("List", ctx, filter).Return(nil, repoErrGeneric).Once()
+				bundle.repo.On("GetByID", ctx, subID).Return(nil, repoErrNotFound).Once()
+			},
+			assertFunc: func(t *testing.T, actual []domain.PriceChange, err error) {
+				require.Error(t, err)
+				assert.Nil(t, actual)
+				var svcErr *errkit.BaseErr[subservice.ServiceKind]
+				require.ErrorAs(t, err, &svcErr)
+				assert.Equal(t, subservice.KindNotFound, svcErr.Kind)
 			},
-			assertFunc: func(t *testing.T, subs []domain.Subscription, err error) {
+		},
+		{
+			name: "Repo Error",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("GetByID", ctx, subID).Return(&domain.Subscription{ID: subID}, nil).Once()
+				bundle.repo.On("ListPriceChanges", ctx, subID).Return(nil, repoErrGeneric).Once()
+			},
+			assertFunc: func(t *testing.T, actual []domain.PriceChange, err error) {
 				require.Error(t, err)
-				assert.Nil(t, subs)
+				assert.Nil(t, actual)
 				var svcErr *errkit.BaseErr[subservice.ServiceKind]
 				require.ErrorAs(t, err, &svcErr)
 				assert.Equal(t, subservice.KindUnknown, svcErr.Kind)
@@ -358,52 +816,164 @@ func TestService_List(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			bundle := setup(t)
 			tc.setupMocks(bundle)
-			subs, err := bundle.svc.List(ctx, filter)
-			tc.assertFunc(t, subs, err)
+			actual, err := bundle.svc.ListPriceChanges(ctx, subID)
+			tc.assertFunc(t, actual, err)
 		})
 	}
 }
 
-func TestService_TotalCost(t *testing.T) {
+func TestService_List(t *testing.T) {
 	ctx := context.Background()
 	filter := domain.SubscriptionFilter{}
-	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
-	end := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	expectedPage := &domain.SubscriptionPage{Items: []domain.Subscription{{ID: uuid.New()}}}
+	repoErrGeneric := errkit.WrapErr("op", repos.KindUnknown, errors.New("db error"))
 
-	subs := []domain.Subscription{
+	testCases := []struct {
+		name       string
+		setupMocks func(bundle serviceTestBundle)
+		assertFunc func(t *testing.T, page *domain.SubscriptionPage, err error)
+	}{
 		{
-			MonthlyCost: 100,
-			StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			name: "Success",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("List", ctx, filter).Return(expectedPage, nil).Once()
+				bundle.repo.On("Count", ctx, filter).Return(42, nil).Once()
+			},
+			assertFunc: func(t *testing.T, page *domain.SubscriptionPage, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, expectedPage.Items, page.Items)
+				assert.Equal(t, 42, page.Total)
+			},
 		},
 		{
-			MonthlyCost: 50,
-			StartDate:   time.Date(2025, 10, 1, 0, 0, 0, 0, time.UTC),
+			name: "Count Error",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("List", ctx, filter).Return(expectedPage, nil).Once()
+				bundle.repo.On("Count", ctx, filter).Return(0, repoErrGeneric).Once()
+			},
+			assertFunc: func(t *testing.T, page *domain.SubscriptionPage, err error) {
+				require.Error(t, err)
+				assert.Nil(t, page)
+				var svcErr *errkit.BaseErr[subservice.ServiceKind]
+				require.ErrorAs(t, err, &svcErr)
+				assert.Equal(t, subservice.KindUnknown, svcErr.Kind)
+			},
+		},
+		{
+			name: "Generic Repo Error",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("List", ctx, filter).Return(nil, repoErrGeneric).Once()
+			},
+			assertFunc: func(t *testing.T, page *domain.SubscriptionPage, err error) {
+				require.Error(t, err)
+				assert.Nil(t, page)
+				var svcErr *errkit.BaseErr[subservice.ServiceKind]
+				require.ErrorAs(t, err, &svcErr)
+				assert.Equal(t, subservice.KindUnknown, svcErr.Kind)
+			},
 		},
 	}
 
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bundle := setup(t)
+			tc.setupMocks(bundle)
+			page, err := bundle.svc.List(ctx, filter)
+			tc.assertFunc(t, page, err)
+		})
+	}
+}
+
+func TestService_Export(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	filter := domain.SubscriptionFilter{UserID: &userID}
+	subs := []domain.Subscription{{ID: uuid.New()}, {ID: uuid.New()}}
+	repoErrGeneric := errkit.WrapErr("op", repos.KindUnknown, errors.New("db error"))
+
+	stream := func(err error) iter.Seq2[domain.Subscription, error] {
+		return func(yield func(domain.Subscription, error) bool) {
+			for _, sub := range subs {
+				if !yield(sub, nil) {
+					return
+				}
+			}
+			if err != nil {
+				yield(domain.Subscription{}, err)
+			}
+		}
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		bundle := setup(t)
+		bundle.repo.On("Stream", ctx, filter).Return(stream(nil)).Once()
+
+		var got []domain.Subscription
+		for sub, err := range bundle.svc.Export(ctx, filter) {
+			require.NoError(t, err)
+			got = append(got, sub)
+		}
+		assert.Equal(t, subs, got)
+	})
+
+	t.Run("Repo Error", func(t *testing.T) {
+		bundle := setup(t)
+		bundle.repo.On("Stream", ctx, filter).Return(stream(repoErrGeneric)).Once()
+
+		var got []domain.Subscription
+		var gotErr error
+		for sub, err := range bundle.svc.Export(ctx, filter) {
+			if err != nil {
+				gotErr = err
+				continue
+			}
+			got = append(got, sub)
+		}
+		assert.Equal(t, subs, got)
+		assertServiceErrKind(t, gotErr, subservice.KindUnknown)
+	})
+
+	t.Run("Stops Early", func(t *testing.T) {
+		bundle := setup(t)
+		bundle.repo.On("Stream", ctx, filter).Return(stream(nil)).Once()
+
+		for sub := range bundle.svc.Export(ctx, filter) {
+			assert.Equal(t, subs[0], sub)
+			break
+		}
+	})
+}
+
+func TestService_SuggestServiceNames(t *testing.T) {
+	ctx := context.Background()
+	limit := new(int)
+	*limit = 5
+	expectedNames := []string{"Yandex Plus"}
+	repoErrGeneric := errkit.WrapErr("op", repos.KindUnknown, errors.New("db error"))
+
 	testCases := []struct {
 		name       string
 		setupMocks func(bundle serviceTestBundle)
-		assertFunc func(t *testing.T, cost int, err error)
+		assertFunc func(t *testing.T, names []string, err error)
 	}{
 		{
 			name: "Success",
 			setupMocks: func(bundle serviceTestBundle) {
-				bundle.repo.On("ListAll", ctx, filter).Return(subs, nil).Once()
+				bundle.repo.On("SuggestServiceNames", ctx, "yan", limit).Return(expectedNames, nil).Once()
 			},
-			assertFunc: func(t *testing.T, cost int, err error) {
+			assertFunc: func(t *testing.T, names []string, err error) {
 				require.NoError(t, err)
-				assert.Equal(t, 12*100+3*50, cost)
+				assert.Equal(t, expectedNames, names)
 			},
 		},
 		{
-			name: "Repo ListAll fails",
+			name: "Generic Repo Error",
 			setupMocks: func(bundle serviceTestBundle) {
-				bundle.repo.On("ListAll", ctx, filter).Return(nil, errors.New("db error")).Once()
+				bundle.repo.On("SuggestServiceNames", ctx, "yan", limit).Return(nil, repoErrGeneric).Once()
 			},
-			assertFunc: func(t *testing.T, cost int, err error) {
+			assertFunc: func(t *testing.T, names []string, err error) {
 				require.Error(t, err)
-				assert.Zero(t, cost)
+				assert.Nil(t, names)
 				var svcErr *errkit.BaseErr[subservice.ServiceKind]
 				require.ErrorAs(t, err, &svcErr)
 				assert.Equal(t, subservice.KindUnknown, svcErr.Kind)
@@ -415,82 +985,215 @@ func TestService_TotalCost(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			bundle := setup(t)
 			tc.setupMocks(bundle)
-			cost, err := bundle.svc.TotalCost(ctx, filter, start, end)
-			tc.assertFunc(t, cost, err)
+			names, err := bundle.svc.SuggestServiceNames(ctx, "yan", limit)
+			tc.assertFunc(t, names, err)
 		})
 	}
 }
 
-func Test_calculateCostForSubscription(t *testing.T) {
-	parseDate := func(s string) time.Time {
-		tm, err := time.Parse("2006-01", s)
-		require.NoError(t, err)
-		return tm
-	}
-	ptrDate := func(s string) *time.Time {
-		tm := parseDate(s)
-		return &tm
-	}
-
-	periodStart := parseDate("2025-01")
-	periodEnd := parseDate("2025-12")
+func TestService_TotalCost(t *testing.T) {
+	ctx := context.Background()
+	filter := domain.SubscriptionFilter{}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	repoErrNotFound := errkit.WrapErr("op", repos.KindNotFound, errors.New("not found"))
+	timePtr := func(t time.Time) *time.Time { return &t }
 
 	testCases := []struct {
-		name         string
-		sub          domain.Subscription
-		expectedCost int
+		name       string
+		currency   string
+		mode       domain.CostMode
+		setupMocks func(bundle serviceTestBundle)
+		assertFunc func(t *testing.T, cost int, err error)
 	}{
 		{
-			name:         "Full overlap, no end date",
-			sub:          domain.Subscription{MonthlyCost: 100, StartDate: parseDate("2025-01")},
-			expectedCost: 1200,
+			name:     "Success - Same currency",
+			currency: "RUB",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("TotalCostByCurrency", ctx, filter, start, end).
+					Return(map[string]int{"RUB": 12*100 + 3*50}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, cost int, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, 12*100+3*50, cost)
+			},
 		},
 		{
-			name:         "Partial overlap, starts during period",
-			sub:          domain.Subscription{MonthlyCost: 100, StartDate: parseDate("2025-06")},
-			expectedCost: 700,
+			name:     "Success - Direct and inverse rates",
+			currency: "RUB",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("TotalCostByCurrency", ctx, filter, start, end).
+					Return(map[string]int{"RUB": 1000, "USD": 10, "EUR": 5}, nil).Once()
+				bundle.ratesRepo.On("Get", ctx, "USD", "RUB").
+					Return(&domain.CurrencyRate{From: "USD", To: "RUB", Rate: 80}, nil).Once()
+				bundle.ratesRepo.On("Get", ctx, "EUR", "RUB").Return(nil, repoErrNotFound).Once()
+				bundle.ratesRepo.On("Get", ctx, "RUB", "EUR").
+					Return(&domain.CurrencyRate{From: "RUB", To: "EUR", Rate: 0.01}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, cost int, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, 1000+10*80+5*100, cost)
+			},
 		},
 		{
-			name:         "Partial overlap, ends during period",
-			sub:          domain.Subscription{MonthlyCost: 100, StartDate: parseDate("2025-01"), EndDate: ptrDate("2025-06")},
-			expectedCost: 600,
+			name:     "Success - Prorated",
+			currency: "RUB",
+			mode:     domain.CostModeProrated,
+			setupMocks: func(bundle serviceTestBundle) {
+				subs := []domain.Subscription{
+					{
+						Price:     300,
+						Currency:  "RUB",
+						StartDate: time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC),
+						EndDate:   timePtr(time.Date(2025, 2, 14, 0, 0, 0, 0, time.UTC)),
+					},
+					{
+						Price:         1200,
+						BillingPeriod: domain.BillingYearly,
+						Currency:      "RUB",
+						StartDate:     time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+					},
+				}
+				bundle.repo.On("ListAll", ctx, filter).Return(subs, nil).Once()
+			},
+			assertFunc: func(t *testing.T, cost int, err error) {
+				require.NoError(t, err)
+				// 16 of 31 days in January and 14 of 28 in February plus a full year.
+				assert.Equal(t, 305+1200, cost)
+			},
+		},
+		{
+			name:     "Missing conversion rate",
+			currency: "RUB",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("TotalCostByCurrency", ctx, filter, start, end).
+					Return(map[string]int{"USD": 10}, nil).Once()
+				bundle.ratesRepo.On("Get", ctx, "USD", "RUB").Return(nil, repoErrNotFound).Once()
+				bundle.ratesRepo.On("Get", ctx, "RUB", "USD").Return(nil, repoErrNotFound).Once()
+			},
+			assertFunc: func(t *testing.T, cost int, err error) {
+				require.Error(t, err)
+				assert.Zero(t, cost)
+				var svcErr *errkit.BaseErr[subservice.ServiceKind]
+				require.ErrorAs(t, err, &svcErr)
+				assert.Equal(t, subservice.KindBusinessLogic, svcErr.Kind)
+			},
 		},
 		{
-			name:         "Subscription contained within period",
-			sub:          domain.Subscription{MonthlyCost: 100, StartDate: parseDate("2025-03"), EndDate: ptrDate("2025-08")},
-			expectedCost: 600,
+			name:     "Repo TotalCostByCurrency fails",
+			currency: "RUB",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("TotalCostByCurrency", ctx, filter, start, end).Return(nil, errors.New("db error")).Once()
+			},
+			assertFunc: func(t *testing.T, cost int, err error) {
+				require.Error(t, err)
+				assert.Zero(t, cost)
+				var svcErr *errkit.BaseErr[subservice.ServiceKind]
+				require.ErrorAs(t, err, &svcErr)
+				assert.Equal(t, subservice.KindUnknown, svcErr.Kind)
+			},
 		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			bundle := setup(t)
+			tc.setupMocks(bundle)
+			cost, err := bundle.svc.TotalCost(ctx, filter, start, end, tc.currency, tc.mode)
+			tc.assertFunc(t, cost, err)
+		})
+	}
+}
+
+func TestService_CostBreakdown(t *testing.T) {
+	ctx := context.Background()
+	filter := domain.SubscriptionFilter{}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	endDate := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	subs := []domain.Subscription{
 		{
-			name:         "Period contained within subscription",
-			sub:          domain.Subscription{MonthlyCost: 100, StartDate: parseDate("2024-01"), EndDate: ptrDate("2026-12")},
-			expectedCost: 1200,
+			ID:          uuid.New(),
+			ServiceName: "First",
+			Price:       100,
+			Currency:    "RUB",
+			StartDate:   time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC),
+			EndDate:     &endDate,
 		},
 		{
-			name:         "No overlap, sub ends before period starts",
-			sub:          domain.Subscription{MonthlyCost: 100, StartDate: parseDate("2024-01"), EndDate: ptrDate("2024-12")},
-			expectedCost: 0,
+			ID:          uuid.New(),
+			ServiceName: "Second",
+			Price:       5,
+			Currency:    "USD",
+			StartDate:   time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC),
 		},
+	}
+
+	testCases := []struct {
+		name       string
+		setupMocks func(bundle serviceTestBundle)
+		assertFunc func(t *testing.T, months []domain.MonthCost, err error)
+	}{
 		{
-			name:         "No overlap, sub starts after period ends",
-			sub:          domain.Subscription{MonthlyCost: 100, StartDate: parseDate("2026-01")},
-			expectedCost: 0,
+			name: "Success",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("ListAll", ctx, filter).Return(subs, nil).Once()
+				bundle.ratesRepo.On("Get", ctx, "USD", "RUB").
+					Return(&domain.CurrencyRate{From: "USD", To: "RUB", Rate: 10}, nil).Once()
+			},
+			assertFunc: func(t *testing.T, months []domain.MonthCost, err error) {
+				require.NoError(t, err)
+				require.Len(t, months, 3)
+
+				assert.Equal(t, start, months[0].Month)
+				assert.Equal(t, 100, months[0].Total)
+				assert.Equal(t, []domain.CostItem{{SubscriptionID: subs[0].ID, ServiceName: "First", Cost: 100}}, months[0].Items)
+
+				assert.Equal(t, 150, months[1].Total)
+				assert.Len(t, months[1].Items, 2)
+
+				assert.Equal(t, end, months[2].Month)
+				assert.Equal(t, 50, months[2].Total)
+				assert.Equal(t, []domain.CostItem{{SubscriptionID: subs[1].ID, ServiceName: "Second", Cost: 50}}, months[2].Items)
+			},
 		},
 		{
-			name:         "Single month subscription within period",
-			sub:          domain.Subscription{MonthlyCost: 100, StartDate: parseDate("2025-06"), EndDate: ptrDate("2025-06")},
-			expectedCost: 100,
+			name: "Rates repo fails",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("ListAll", ctx, filter).Return(subs, nil).Once()
+				bundle.ratesRepo.On("Get", ctx, "USD", "RUB").Return(nil, errkit.WrapErr("op", repos.KindNotFound, errors.New("not found"))).Once()
+				bundle.ratesRepo.On("Get", ctx, "RUB", "USD").Return(nil, errors.New("db error")).Once()
+			},
+			assertFunc: func(t *testing.T, months []domain.MonthCost, err error) {
+				require.Error(t, err)
+				assert.Nil(t, months)
+				var svcErr *errkit.BaseErr[subservice.ServiceKind]
+				require.ErrorAs(t, err, &svcErr)
+				assert.Equal(t, subservice.KindUnknown, svcErr.Kind)
+			},
 		},
 		{
-			name:         "Invalid sub, starts after ends",
-			sub:          domain.Subscription{MonthlyCost: 100, StartDate: parseDate("2025-06"), EndDate: ptrDate("2025-05")},
-			expectedCost: 0,
+			name: "Repo ListAll fails",
+			setupMocks: func(bundle serviceTestBundle) {
+				bundle.repo.On("ListAll", ctx, filter).Return(nil, errors.New("db error")).Once()
+			},
+			assertFunc: func(t *testing.T, months []domain.MonthCost, err error) {
+				require.Error(t, err)
+				assert.Nil(t, months)
+				var svcErr *errkit.BaseErr[subservice.ServiceKind]
+				require.ErrorAs(t, err, &svcErr)
+				assert.Equal(t, subservice.KindUnknown, svcErr.Kind)
+			},
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			cost := calculateCostForSubscription(tc.sub, periodStart, periodEnd)
-			assert.Equal(t, tc.expectedCost, cost)
+			bundle := setup(t)
+			tc.setupMocks(bundle)
+			months, err := bundle.svc.CostBreakdown(ctx, filter, start, end, "RUB", domain.CostModeBilled)
+			tc.assertFunc(t, months, err)
 		})
 	}
 }
@@ -0,0 +1,121 @@
+//go:build integration
+
+package subservice
+
+import (
+	"context"
+	"database/sql"
+	"math/rand/v2"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/config"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/infra/postgres"
+	"github.com/shrtyk/subscriptions-service/pkg/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Test_TotalCostSQLMatchesReference checks that the SQL aggregation of the
+// repository agrees with calculateCostForSubscription on randomized data,
+// including price changes and pauses.
+// It requires a migrated database reachable through PG_TEST_DSN.
+func Test_TotalCostSQLMatchesReference(t *testing.T) {
+	dsn := os.Getenv("PG_TEST_DSN")
+	if dsn == "" {
+		t.Skip("PG_TEST_DSN is not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	l, _ := log.NewTestLogger()
+	ctx := log.ToCtx(domain.PrincipalToCtx(context.Background(), &domain.Principal{TenantID: "acme"}), l)
+
+	sqlTx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlTx.Rollback() })
+	require.NoError(t, postgres.SetTenant(ctx, sqlTx, "acme"))
+
+	repo := postgres.NewSubsRepo(sqlTx, &config.RepoConfig{DefaultPageSize: 10, MaxPageSize: 100})
+
+	seed := uint64(time.Now().UnixNano())
+	t.Logf("seed: %d", seed)
+	rng := rand.New(rand.NewPCG(seed, seed))
+
+	randomDate := func() time.Time {
+		return time.Date(2000+rng.IntN(30), time.Month(1+rng.IntN(12)), 1+rng.IntN(28), 0, 0, 0, 0, time.UTC)
+	}
+
+	billingPeriods := []domain.BillingPeriod{
+		domain.BillingWeekly, domain.BillingMonthly, domain.BillingQuarterly, domain.BillingYearly,
+	}
+
+	for range 50 {
+		userID := uuid.New()
+
+		subs := make([]domain.Subscription, 0)
+		for range 1 + rng.IntN(10) {
+			sub := domain.Subscription{
+				ServiceName:   "Random Service",
+				Price:         rng.IntN(2000),
+				BillingPeriod: billingPeriods[rng.IntN(len(billingPeriods))],
+				Currency:      []string{"RUB", "USD", "EUR"}[rng.IntN(3)],
+				UserID:        userID,
+				StartDate:     randomDate(),
+			}
+			if rng.IntN(3) > 0 {
+				endDate := sub.StartDate.AddDate(0, rng.IntN(60)-6, rng.IntN(28))
+				sub.EndDate = &endDate
+			}
+			if rng.IntN(3) == 0 {
+				trialEnd := sub.StartDate.AddDate(0, rng.IntN(12), rng.IntN(28))
+				sub.TrialEnd = &trialEnd
+			}
+
+			require.NoError(t, repo.Create(ctx, &sub))
+			for range rng.IntN(4) {
+				change := domain.PriceChange{
+					EffectiveFrom: startOfMonth(sub.StartDate).AddDate(0, 1+rng.IntN(48), 0),
+					Price:         rng.IntN(2000),
+				}
+				require.NoError(t, repo.AddPriceChange(ctx, sub.ID, &change))
+			}
+			sub.PriceHistory, err = repo.ListPriceChanges(ctx, sub.ID)
+			require.NoError(t, err)
+
+			pausedFrom := startOfMonth(sub.StartDate).AddDate(0, rng.IntN(24), 0)
+			for range rng.IntN(3) {
+				pause := domain.Pause{PausedFrom: pausedFrom}
+				if months := rng.IntN(6); months > 0 {
+					resumedFrom := pausedFrom.AddDate(0, months, 0)
+					pause.ResumedFrom = &resumedFrom
+				}
+				require.NoError(t, repo.AddPause(ctx, sub.ID, &pause))
+				if pause.ResumedFrom == nil {
+					break
+				}
+				pausedFrom = pause.ResumedFrom.AddDate(0, rng.IntN(12), 0)
+			}
+			sub.Pauses, err = repo.ListPauses(ctx, sub.ID)
+			require.NoError(t, err)
+			subs = append(subs, sub)
+		}
+
+		periodStart := randomDate()
+		periodEnd := periodStart.AddDate(0, rng.IntN(120)-12, 0)
+
+		expected := make(map[string]int)
+		for _, sub := range subs {
+			expected[sub.Currency] += calculateCostForSubscription(sub, periodStart, periodEnd)
+		}
+
+		actual, err := repo.TotalCostByCurrency(ctx, domain.SubscriptionFilter{UserID: &userID}, periodStart, periodEnd)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual, "period %s - %s", periodStart, periodEnd)
+	}
+}
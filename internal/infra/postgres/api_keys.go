@@ -0,0 +1,163 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/pkg/log"
+)
+
+const (
+	opCreateAPIKey = "apiKeysRepo.Create"
+	opListAPIKeys  = "apiKeysRepo.List"
+	opUseAPIKey    = "apiKeysRepo.Use"
+	opRevokeAPIKey = "apiKeysRepo.Revoke"
+)
+
+type apiKeysRepo struct {
+	db DBTX
+}
+
+func NewAPIKeysRepo(db DBTX) *apiKeysRepo {
+	return &apiKeysRepo{db: db}
+}
+
+func (r *apiKeysRepo) Create(ctx context.Context, key *domain.APIKey) error {
+	l := log.FromCtx(ctx).With(slog.String("op", opCreateAPIKey))
+	l.Debug("creating api key in db", slog.String("name", key.Name))
+
+	tenant, err := tenantOf(ctx, opCreateAPIKey)
+	if err != nil {
+		return err
+	}
+
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		return db.QueryRowContext(ctx, createAPIKeyQuery, key.Name, key.Prefix, key.Hash, joinScopes(key.Scopes), key.ExpiresAt, tenant).
+			Scan(&key.ID, &key.CreatedAt)
+	})
+	if err != nil {
+		return repos.WrapErr(opCreateAPIKey, repos.KindUnknown, err)
+	}
+	key.TenantID = domain.TenantID(tenant)
+
+	return nil
+}
+
+func (r *apiKeysRepo) List(ctx context.Context) ([]domain.APIKey, error) {
+	l := log.FromCtx(ctx).With(slog.String("op", opListAPIKeys))
+	l.Debug("listing api keys from db")
+
+	tenant, err := tenantOf(ctx, opListAPIKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]domain.APIKey, 0)
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		rows, err := db.QueryContext(ctx, listAPIKeysQuery, tenant)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := rows.Close(); cerr != nil {
+				log.FromCtx(ctx).Warn("failed to close sql.Rows", log.WithErr(cerr))
+			}
+		}()
+
+		for rows.Next() {
+			key, err := scanAPIKey(rows)
+			if err != nil {
+				return err
+			}
+			keys = append(keys, *key)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, repos.WrapErr(opListAPIKeys, repos.KindUnknown, err)
+	}
+
+	return keys, nil
+}
+
+func (r *apiKeysRepo) Use(ctx context.Context, hash string) (*domain.APIKey, error) {
+	l := log.FromCtx(ctx).With(slog.String("op", opUseAPIKey))
+	l.Debug("using api key in db")
+
+	var key *domain.APIKey
+	err := acrossTenants(ctx, r.db, func(db DBTX) error {
+		var err error
+		key, err = scanAPIKey(db.QueryRowContext(ctx, useAPIKeyQuery, hash))
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repos.WrapErr(opUseAPIKey, repos.KindNotFound, err)
+		}
+		return nil, repos.WrapErr(opUseAPIKey, repos.KindUnknown, err)
+	}
+
+	return key, nil
+}
+
+func (r *apiKeysRepo) Revoke(ctx context.Context, id uuid.UUID) error {
+	l := log.FromCtx(ctx).With(slog.String("op", opRevokeAPIKey))
+	l.Debug("revoking api key in db", slog.String("id", id.String()))
+
+	tenant, err := tenantOf(ctx, opRevokeAPIKey)
+	if err != nil {
+		return err
+	}
+
+	var rowsAffected int64
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		res, err := db.ExecContext(ctx, revokeAPIKeyQuery, id, tenant)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return repos.WrapErr(opRevokeAPIKey, repos.KindUnknown, err)
+	}
+	if rowsAffected == 0 {
+		return repos.NewErr(opRevokeAPIKey, repos.KindNotFound)
+	}
+
+	return nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAPIKey(row rowScanner) (*domain.APIKey, error) {
+	var key domain.APIKey
+	var scopes string
+	err := row.Scan(
+		&key.ID, &key.TenantID, &key.Name, &key.Prefix, &key.Hash, &scopes,
+		&key.ExpiresAt, &key.CreatedAt, &key.LastUsedAt, &key.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	for scope := range strings.SplitSeq(scopes, ",") {
+		key.Scopes = append(key.Scopes, domain.Scope(scope))
+	}
+	return &key, nil
+}
+
+func joinScopes(scopes []domain.Scope) string {
+	s := make([]string, len(scopes))
+	for i, scope := range scopes {
+		s[i] = string(scope)
+	}
+	return strings.Join(s, ",")
+}
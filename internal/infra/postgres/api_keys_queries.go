@@ -0,0 +1,30 @@
+package postgres
+
+// Scopes are passed as comma separated strings, database/sql has no arrays.
+// Keys are used before the tenant of the caller is known, useAPIKeyQuery is the
+// only query across tenants, runs as CrossTenantRole and returns the tenant of
+// the key.
+const (
+	createAPIKeyQuery = `
+		INSERT INTO api_keys (name, prefix, key_hash, scopes, expires_at, tenant_id)
+		VALUES ($1, $2, $3, string_to_array($4, ','), $5, $6)
+		RETURNING id, created_at;
+	`
+
+	listAPIKeysQuery = `
+		SELECT id, tenant_id, name, prefix, key_hash, array_to_string(scopes, ','), expires_at, created_at, last_used_at, revoked_at
+		FROM api_keys
+		WHERE tenant_id = $1
+		ORDER BY created_at, id;
+	`
+
+	useAPIKeyQuery = `
+		UPDATE api_keys SET last_used_at = NOW()
+		WHERE key_hash = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > NOW())
+		RETURNING id, tenant_id, name, prefix, key_hash, array_to_string(scopes, ','), expires_at, created_at, last_used_at, revoked_at;
+	`
+
+	revokeAPIKeyQuery = `
+		UPDATE api_keys SET revoked_at = NOW() WHERE id = $1 AND tenant_id = $2 AND revoked_at IS NULL;
+	`
+)
@@ -0,0 +1,241 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/pkg/errkit"
+)
+
+var apiKeyColumns = []string{
+	"id", "tenant_id", "name", "prefix", "key_hash", "scopes", "expires_at", "created_at", "last_used_at", "revoked_at",
+}
+
+func setupAPIKeysRepo(t *testing.T) (*apiKeysRepo, sqlmock.Sqlmock) {
+	t.Helper()
+	tx, mock := setupTx(t)
+
+	return NewAPIKeysRepo(tx), mock
+}
+
+func TestAPIKeysRepo_Create(t *testing.T) {
+	ctx := tenantCtx()
+	id := uuid.New()
+	createdAt := time.Now()
+	dbErr := errors.New("db error")
+
+	testCases := []struct {
+		name       string
+		setupMock  func(mock sqlmock.Sqlmock, key *domain.APIKey)
+		assertFunc func(t *testing.T, key *domain.APIKey, err error)
+	}{
+		{
+			name: "Success",
+			setupMock: func(mock sqlmock.Sqlmock, key *domain.APIKey) {
+				mock.ExpectQuery(createAPIKeyQuery).
+					WithArgs(key.Name, key.Prefix, key.Hash, "read,write", nil, testTenant).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(id, createdAt))
+			},
+			assertFunc: func(t *testing.T, key *domain.APIKey, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, id, key.ID)
+				assert.Equal(t, domain.TenantID(testTenant), key.TenantID)
+				assert.Equal(t, createdAt, key.CreatedAt)
+			},
+		},
+		{
+			name: "Generic DB Error",
+			setupMock: func(mock sqlmock.Sqlmock, key *domain.APIKey) {
+				mock.ExpectQuery(createAPIKeyQuery).
+					WithArgs(key.Name, key.Prefix, key.Hash, "read,write", nil, testTenant).
+					WillReturnError(dbErr)
+			},
+			assertFunc: func(t *testing.T, key *domain.APIKey, err error) {
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindUnknown, baseErr.Kind)
+				assert.ErrorIs(t, err, dbErr)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := setupAPIKeysRepo(t)
+			key := &domain.APIKey{
+				Name:   "billing",
+				Prefix: "sk_abcdefgh",
+				Hash:   "hash",
+				Scopes: []domain.Scope{domain.ScopeRead, domain.ScopeWrite},
+			}
+			tc.setupMock(mock, key)
+			err := repo.Create(ctx, key)
+			tc.assertFunc(t, key, err)
+		})
+	}
+}
+
+func TestAPIKeysRepo_List(t *testing.T) {
+	ctx := tenantCtx()
+	createdAt := time.Now()
+	expiresAt := createdAt.Add(time.Hour)
+	key := domain.APIKey{
+		ID:        uuid.New(),
+		TenantID:  testTenant,
+		Name:      "billing",
+		Prefix:    "sk_abcdefgh",
+		Hash:      "hash",
+		Scopes:    []domain.Scope{domain.ScopeRead, domain.ScopeAdmin},
+		ExpiresAt: &expiresAt,
+		CreatedAt: createdAt,
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		repo, mock := setupAPIKeysRepo(t)
+		rows := sqlmock.NewRows(apiKeyColumns).
+			AddRow(key.ID, testTenant, key.Name, key.Prefix, key.Hash, "read,admin", expiresAt, createdAt, nil, nil)
+		mock.ExpectQuery(listAPIKeysQuery).WithArgs(testTenant).WillReturnRows(rows)
+
+		keys, err := repo.List(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, []domain.APIKey{key}, keys)
+	})
+
+	t.Run("Generic DB Error", func(t *testing.T) {
+		repo, mock := setupAPIKeysRepo(t)
+		mock.ExpectQuery(listAPIKeysQuery).WithArgs(testTenant).WillReturnError(errors.New("db error"))
+
+		keys, err := repo.List(ctx)
+		assert.Nil(t, keys)
+		var baseErr *errkit.BaseErr[repos.RepoKind]
+		require.ErrorAs(t, err, &baseErr)
+		assert.Equal(t, repos.KindUnknown, baseErr.Kind)
+	})
+}
+
+func TestAPIKeysRepo_Use(t *testing.T) {
+	createdAt := time.Now()
+	id := uuid.New()
+
+	testCases := []struct {
+		name       string
+		setupMock  func(mock sqlmock.Sqlmock)
+		assertFunc func(t *testing.T, key *domain.APIKey, err error)
+	}{
+		{
+			name: "Success",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(apiKeyColumns).
+					AddRow(id, "globex", "billing", "sk_abcdefgh", "hash", "write", nil, createdAt, createdAt, nil)
+				mock.ExpectQuery(useAPIKeyQuery).WithArgs("hash").WillReturnRows(rows)
+			},
+			assertFunc: func(t *testing.T, key *domain.APIKey, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, id, key.ID)
+				assert.Equal(t, domain.TenantID("globex"), key.TenantID)
+				assert.Equal(t, []domain.Scope{domain.ScopeWrite}, key.Scopes)
+				assert.Equal(t, &createdAt, key.LastUsedAt)
+				assert.Nil(t, key.ExpiresAt)
+			},
+		},
+		{
+			name: "Not Found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(useAPIKeyQuery).WithArgs("hash").WillReturnError(sql.ErrNoRows)
+			},
+			assertFunc: func(t *testing.T, key *domain.APIKey, err error) {
+				assert.Nil(t, key)
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindNotFound, baseErr.Kind)
+			},
+		},
+		{
+			name: "Generic DB Error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(useAPIKeyQuery).WithArgs("hash").WillReturnError(errors.New("db error"))
+			},
+			assertFunc: func(t *testing.T, key *domain.APIKey, err error) {
+				assert.Nil(t, key)
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindUnknown, baseErr.Kind)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := setupAPIKeysRepo(t)
+			tc.setupMock(mock)
+			key, err := repo.Use(context.Background(), "hash")
+			tc.assertFunc(t, key, err)
+		})
+	}
+}
+
+func TestAPIKeysRepo_Revoke(t *testing.T) {
+	ctx := tenantCtx()
+	id := uuid.New()
+	dbErr := errors.New("db error")
+
+	testCases := []struct {
+		name         string
+		setupMock    func(mock sqlmock.Sqlmock)
+		expectedKind *repos.RepoKind
+	}{
+		{
+			name: "Success",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(revokeAPIKeyQuery).WithArgs(id, testTenant).WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name: "Not Found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(revokeAPIKeyQuery).WithArgs(id, testTenant).WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			expectedKind: func(k repos.RepoKind) *repos.RepoKind { return &k }(repos.KindNotFound),
+		},
+		{
+			name: "Generic DB Error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(revokeAPIKeyQuery).WithArgs(id, testTenant).WillReturnError(dbErr)
+			},
+			expectedKind: func(k repos.RepoKind) *repos.RepoKind { return &k }(repos.KindUnknown),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := setupAPIKeysRepo(t)
+			tc.setupMock(mock)
+			err := repo.Revoke(ctx, id)
+			if tc.expectedKind == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var baseErr *errkit.BaseErr[repos.RepoKind]
+			require.ErrorAs(t, err, &baseErr)
+			assert.Equal(t, *tc.expectedKind, baseErr.Kind)
+		})
+	}
+}
+
+func TestAPIKeysRepo_NoTenant(t *testing.T) {
+	repo, _ := setupAPIKeysRepo(t)
+
+	keys, err := repo.List(context.Background())
+	assert.Nil(t, keys)
+	assert.ErrorIs(t, err, errNoTenant)
+}
@@ -0,0 +1,263 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shrtyk/subscriptions-service/internal/config"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/pkg/log"
+)
+
+const (
+	opRecordAudit           = "auditRepo.Record"
+	opListSubscriptionAudit = "auditRepo.ListBySubscription"
+	opListAudit             = "auditRepo.List"
+)
+
+type auditRepo struct {
+	db  DBTX
+	cfg *config.RepoConfig
+}
+
+func NewAuditRepo(db DBTX, cfg *config.RepoConfig) *auditRepo {
+	return &auditRepo{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+func (r *auditRepo) Record(ctx context.Context, entry *domain.AuditEntry) error {
+	l := log.FromCtx(ctx).With(slog.String("op", opRecordAudit))
+	l.Debug(
+		"recording audit entry in db",
+		slog.String("subscription_id", entry.SubscriptionID.String()),
+		slog.String("operation", string(entry.Op)),
+	)
+
+	tenant, err := tenantOf(ctx, opRecordAudit)
+	if err != nil {
+		return err
+	}
+
+	before, err := marshalSnapshot(entry.Before)
+	if err != nil {
+		return repos.WrapErr(opRecordAudit, repos.KindUnknown, err)
+	}
+	after, err := marshalSnapshot(entry.After)
+	if err != nil {
+		return repos.WrapErr(opRecordAudit, repos.KindUnknown, err)
+	}
+
+	var actorID *uuid.UUID
+	if entry.ActorID != uuid.Nil {
+		actorID = &entry.ActorID
+	}
+
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		return db.QueryRowContext(
+			ctx, recordAuditQuery, tenant, entry.SubscriptionID, string(entry.Op),
+			actorID, string(entry.ActorRole), entry.APIKeyID, entry.RequestID, before, after).
+			Scan(&entry.ID, &entry.CreatedAt)
+	})
+	if err != nil {
+		return repos.WrapErr(opRecordAudit, repos.KindUnknown, err)
+	}
+
+	return nil
+}
+
+func (r *auditRepo) ListBySubscription(ctx context.Context, subID uuid.UUID) ([]domain.AuditEntry, error) {
+	l := log.FromCtx(ctx).With(slog.String("op", opListSubscriptionAudit))
+	l.Debug("listing audit entries of subscription from db", slog.String("subscription_id", subID.String()))
+
+	tenant, err := tenantOf(ctx, opListSubscriptionAudit)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := r.queryEntries(ctx, tenant, listSubscriptionAuditQuery, tenant, subID)
+	if err != nil {
+		return nil, repos.WrapErr(opListSubscriptionAudit, repos.KindUnknown, err)
+	}
+	return entries, nil
+}
+
+func (r *auditRepo) List(ctx context.Context, filter domain.AuditFilter) (*domain.AuditPage, error) {
+	l := log.FromCtx(ctx).With(slog.String("op", opListAudit))
+	l.Debug("listing audit entries from db", slog.Any("filter", filter))
+
+	tenant, err := tenantOf(ctx, opListAudit)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := r.cfg.DefaultPageSize
+	if filter.Limit != nil && *filter.Limit > 0 {
+		limit = min(*filter.Limit, r.cfg.MaxPageSize)
+	}
+
+	query, args, err := r.buildAuditQuery(tenant, filter, limit)
+	if err != nil {
+		return nil, repos.WrapErr(opListAudit, repos.KindUnknown, err)
+	}
+	entries, err := r.queryEntries(ctx, tenant, query, args...)
+	if err != nil {
+		return nil, repos.WrapErr(opListAudit, repos.KindUnknown, err)
+	}
+
+	page := &domain.AuditPage{Items: entries}
+	if len(entries) > limit {
+		page.Items = entries[:limit]
+		page.Next = &page.Items[limit-1].ID
+	}
+	return page, nil
+}
+
+func (r *auditRepo) queryEntries(ctx context.Context, tenant, query string, args ...any) ([]domain.AuditEntry, error) {
+	entries := make([]domain.AuditEntry, 0)
+	err := inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		rows, err := db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := rows.Close(); cerr != nil {
+				log.FromCtx(ctx).Warn("failed to close sql.Rows", log.WithErr(cerr))
+			}
+		}()
+
+		for rows.Next() {
+			entry, err := scanAuditEntry(rows)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func scanAuditEntry(rows *sql.Rows) (domain.AuditEntry, error) {
+	var entry domain.AuditEntry
+	var actorID *uuid.UUID
+	var before, after []byte
+	err := rows.Scan(
+		&entry.ID, &entry.SubscriptionID, &entry.Op, &actorID, &entry.ActorRole, &entry.APIKeyID,
+		&entry.RequestID, &before, &after, &entry.CreatedAt,
+	)
+	if err != nil {
+		return entry, err
+	}
+	if actorID != nil {
+		entry.ActorID = *actorID
+	}
+	if entry.Before, err = unmarshalSnapshot(before); err != nil {
+		return entry, err
+	}
+	if entry.After, err = unmarshalSnapshot(after); err != nil {
+		return entry, err
+	}
+	return entry, nil
+}
+
+// subscriptionSnapshot is the JSON of subscriptions in the audit log. Its
+// fields are only ever added, older entries keep the ones they were written
+// with.
+type subscriptionSnapshot struct {
+	ID            uuid.UUID            `json:"id"`
+	ServiceName   string               `json:"service_name"`
+	Price         int                  `json:"price"`
+	BillingPeriod domain.BillingPeriod `json:"billing_period"`
+	Currency      string               `json:"currency"`
+	UserID        uuid.UUID            `json:"user_id"`
+	StartDate     time.Time            `json:"start_date"`
+	EndDate       *time.Time           `json:"end_date,omitempty"`
+	TrialEnd      *time.Time           `json:"trial_end,omitempty"`
+	Version       int                  `json:"version"`
+	CreatedAt     time.Time            `json:"created_at"`
+	UpdatedAt     time.Time            `json:"updated_at"`
+	Pauses        []pauseSnapshot      `json:"pauses,omitempty"`
+}
+
+type pauseSnapshot struct {
+	PausedFrom  time.Time  `json:"paused_from"`
+	ResumedFrom *time.Time `json:"resumed_from,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// marshalSnapshot returns the JSON of sub, nil for no subscription.
+func marshalSnapshot(sub *domain.Subscription) (*string, error) {
+	if sub == nil {
+		return nil, nil
+	}
+	snapshot := subscriptionSnapshot{
+		ID:            sub.ID,
+		ServiceName:   sub.ServiceName,
+		Price:         sub.Price,
+		BillingPeriod: sub.BillingPeriod,
+		Currency:      sub.Currency,
+		UserID:        sub.UserID,
+		StartDate:     sub.StartDate,
+		EndDate:       sub.EndDate,
+		TrialEnd:      sub.TrialEnd,
+		Version:       sub.Version,
+		CreatedAt:     sub.CreatedAt,
+		UpdatedAt:     sub.UpdatedAt,
+	}
+	for _, p := range sub.Pauses {
+		snapshot.Pauses = append(snapshot.Pauses, pauseSnapshot{
+			PausedFrom:  p.PausedFrom,
+			ResumedFrom: p.ResumedFrom,
+			CreatedAt:   p.CreatedAt,
+		})
+	}
+
+	b, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	s := string(b)
+	return &s, nil
+}
+
+func unmarshalSnapshot(b []byte) (*domain.Subscription, error) {
+	if b == nil {
+		return nil, nil
+	}
+	var snapshot subscriptionSnapshot
+	if err := json.Unmarshal(b, &snapshot); err != nil {
+		return nil, err
+	}
+	sub := &domain.Subscription{
+		ID:            snapshot.ID,
+		ServiceName:   snapshot.ServiceName,
+		Price:         snapshot.Price,
+		BillingPeriod: snapshot.BillingPeriod,
+		Currency:      snapshot.Currency,
+		UserID:        snapshot.UserID,
+		StartDate:     snapshot.StartDate,
+		EndDate:       snapshot.EndDate,
+		TrialEnd:      snapshot.TrialEnd,
+		Version:       snapshot.Version,
+		CreatedAt:     snapshot.CreatedAt,
+		UpdatedAt:     snapshot.UpdatedAt,
+	}
+	for _, p := range snapshot.Pauses {
+		sub.Pauses = append(sub.Pauses, domain.Pause{
+			PausedFrom:  p.PausedFrom,
+			ResumedFrom: p.ResumedFrom,
+			CreatedAt:   p.CreatedAt,
+		})
+	}
+	return sub, nil
+}
@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+)
+
+const (
+	recordAuditQuery = `
+		INSERT INTO audit_log (tenant_id, subscription_id, operation, actor_id, actor_role, api_key_id, request_id, before, after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8::jsonb, $9::jsonb)
+		RETURNING id, created_at;
+	`
+
+	listSubscriptionAuditQuery = `
+		SELECT id, subscription_id, operation, actor_id, actor_role, api_key_id, request_id, before, after, created_at
+		FROM audit_log
+		WHERE tenant_id = $1 AND subscription_id = $2
+		ORDER BY id;
+	`
+)
+
+// buildAuditQuery selects a page of the entries of tenant matching filter,
+// newest first, with one more entry to tell whether there is a next page.
+func (r *auditRepo) buildAuditQuery(tenant string, filter domain.AuditFilter, limit int) (string, []any, error) {
+	queryBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select(
+			"id", "subscription_id", "operation", "actor_id", "actor_role", "api_key_id",
+			"request_id", "before", "after", "created_at",
+		).
+		From("audit_log").
+		Where(squirrel.Eq{"tenant_id": tenant})
+
+	if filter.SubscriptionID != nil {
+		queryBuilder = queryBuilder.Where(squirrel.Eq{"subscription_id": *filter.SubscriptionID})
+	}
+	if filter.ActorID != nil {
+		queryBuilder = queryBuilder.Where(squirrel.Eq{"actor_id": *filter.ActorID})
+	}
+	if filter.APIKeyID != nil {
+		queryBuilder = queryBuilder.Where(squirrel.Eq{"api_key_id": *filter.APIKeyID})
+	}
+	if filter.Op != nil {
+		queryBuilder = queryBuilder.Where(squirrel.Eq{"operation": string(*filter.Op)})
+	}
+	if filter.From != nil {
+		queryBuilder = queryBuilder.Where(squirrel.GtOrEq{"created_at": *filter.From})
+	}
+	if filter.To != nil {
+		queryBuilder = queryBuilder.Where(squirrel.Lt{"created_at": *filter.To})
+	}
+	if filter.Before != nil {
+		queryBuilder = queryBuilder.Where(squirrel.Lt{"id": *filter.Before})
+	}
+
+	return queryBuilder.OrderBy("id DESC").Limit(uint64(limit + 1)).ToSql()
+}
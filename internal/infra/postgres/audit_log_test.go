@@ -0,0 +1,332 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shrtyk/subscriptions-service/internal/config"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/pkg/errkit"
+)
+
+var auditColumns = []string{
+	"id", "subscription_id", "operation", "actor_id", "actor_role", "api_key_id",
+	"request_id", "before", "after", "created_at",
+}
+
+func setupAuditRepo(t *testing.T) (*auditRepo, sqlmock.Sqlmock) {
+	t.Helper()
+	tx, mock := setupTx(t)
+
+	return NewAuditRepo(tx, &config.RepoConfig{DefaultPageSize: 2, MaxPageSize: 3}), mock
+}
+
+func TestAuditRepo_Record(t *testing.T) {
+	ctx := tenantCtx()
+	createdAt := time.Now()
+	dbErr := errors.New("db error")
+	sub := &domain.Subscription{
+		ID:            uuid.New(),
+		ServiceName:   "Netflix",
+		Price:         500,
+		BillingPeriod: domain.BillingMonthly,
+		Currency:      "RUB",
+		UserID:        uuid.New(),
+		StartDate:     time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+		Version:       1,
+	}
+	snapshot, err := marshalSnapshot(sub)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		entry      *domain.AuditEntry
+		setupMock  func(mock sqlmock.Sqlmock, entry *domain.AuditEntry)
+		assertFunc func(t *testing.T, entry *domain.AuditEntry, err error)
+	}{
+		{
+			name: "Success",
+			entry: &domain.AuditEntry{
+				SubscriptionID: sub.ID,
+				Op:             domain.AuditCreate,
+				ActorID:        sub.UserID,
+				ActorRole:      domain.RoleEditor,
+				RequestID:      "req-1",
+				After:          sub,
+			},
+			setupMock: func(mock sqlmock.Sqlmock, entry *domain.AuditEntry) {
+				mock.ExpectQuery(recordAuditQuery).
+					WithArgs(testTenant, sub.ID, "create", &entry.ActorID, "editor", nil, "req-1", nil, snapshot).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(int64(7), createdAt))
+			},
+			assertFunc: func(t *testing.T, entry *domain.AuditEntry, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, int64(7), entry.ID)
+				assert.Equal(t, createdAt, entry.CreatedAt)
+			},
+		},
+		{
+			name: "API key actor is stored without user",
+			entry: &domain.AuditEntry{
+				SubscriptionID: sub.ID,
+				Op:             domain.AuditDelete,
+				ActorRole:      domain.RoleService,
+				APIKeyID:       &sub.ID,
+				Before:         sub,
+			},
+			setupMock: func(mock sqlmock.Sqlmock, entry *domain.AuditEntry) {
+				mock.ExpectQuery(recordAuditQuery).
+					WithArgs(testTenant, sub.ID, "delete", nil, "service", entry.APIKeyID, "", snapshot, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(int64(8), createdAt))
+			},
+			assertFunc: func(t *testing.T, entry *domain.AuditEntry, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, int64(8), entry.ID)
+			},
+		},
+		{
+			name: "Generic DB Error",
+			entry: &domain.AuditEntry{
+				SubscriptionID: sub.ID,
+				Op:             domain.AuditCreate,
+				ActorID:        sub.UserID,
+				ActorRole:      domain.RoleEditor,
+				After:          sub,
+			},
+			setupMock: func(mock sqlmock.Sqlmock, entry *domain.AuditEntry) {
+				mock.ExpectQuery(recordAuditQuery).
+					WithArgs(testTenant, sub.ID, "create", &entry.ActorID, "editor", nil, "", nil, snapshot).
+					WillReturnError(dbErr)
+			},
+			assertFunc: func(t *testing.T, entry *domain.AuditEntry, err error) {
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindUnknown, baseErr.Kind)
+				assert.ErrorIs(t, err, dbErr)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := setupAuditRepo(t)
+			tc.setupMock(mock, tc.entry)
+			err := repo.Record(ctx, tc.entry)
+			tc.assertFunc(t, tc.entry, err)
+		})
+	}
+}
+
+func TestAuditRepo_ListBySubscription(t *testing.T) {
+	ctx := tenantCtx()
+	subID := uuid.New()
+	actorID := uuid.New()
+	createdAt := time.Now()
+	dbErr := errors.New("db error")
+	after := `{"id":"` + subID.String() + `","service_name":"Netflix","price":500,"billing_period":"monthly",` +
+		`"currency":"RUB","user_id":"` + actorID.String() + `","start_date":"2025-07-01T00:00:00Z","version":1,` +
+		`"created_at":"0001-01-01T00:00:00Z","updated_at":"0001-01-01T00:00:00Z",` +
+		`"pauses":[{"paused_from":"2025-08-01T00:00:00Z"}]}`
+
+	testCases := []struct {
+		name       string
+		setupMock  func(mock sqlmock.Sqlmock)
+		assertFunc func(t *testing.T, entries []domain.AuditEntry, err error)
+	}{
+		{
+			name: "Success",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(auditColumns).
+					AddRow(int64(1), subID, "create", actorID, "editor", nil, "req-1", nil, []byte(after), createdAt).
+					AddRow(int64(2), subID, "delete", nil, "service", actorID, "", []byte(after), nil, createdAt)
+				mock.ExpectQuery(listSubscriptionAuditQuery).WithArgs(testTenant, subID).WillReturnRows(rows)
+			},
+			assertFunc: func(t *testing.T, entries []domain.AuditEntry, err error) {
+				require.NoError(t, err)
+				require.Len(t, entries, 2)
+
+				assert.Equal(t, domain.AuditCreate, entries[0].Op)
+				assert.Equal(t, actorID, entries[0].ActorID)
+				assert.Nil(t, entries[0].APIKeyID)
+				assert.Nil(t, entries[0].Before)
+				require.NotNil(t, entries[0].After)
+				assert.Equal(t, subID, entries[0].After.ID)
+				assert.Equal(t, domain.BillingMonthly, entries[0].After.BillingPeriod)
+				require.Len(t, entries[0].After.Pauses, 1)
+				assert.Nil(t, entries[0].After.Pauses[0].ResumedFrom)
+
+				assert.Equal(t, uuid.Nil, entries[1].ActorID)
+				require.NotNil(t, entries[1].APIKeyID)
+				assert.Equal(t, actorID, *entries[1].APIKeyID)
+				assert.Equal(t, domain.RoleService, entries[1].ActorRole)
+				assert.Nil(t, entries[1].After)
+			},
+		},
+		{
+			name: "No entries",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(listSubscriptionAuditQuery).
+					WithArgs(testTenant, subID).
+					WillReturnRows(sqlmock.NewRows(auditColumns))
+			},
+			assertFunc: func(t *testing.T, entries []domain.AuditEntry, err error) {
+				require.NoError(t, err)
+				assert.Empty(t, entries)
+			},
+		},
+		{
+			name: "Generic DB Error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(listSubscriptionAuditQuery).WithArgs(testTenant, subID).WillReturnError(dbErr)
+			},
+			assertFunc: func(t *testing.T, entries []domain.AuditEntry, err error) {
+				assert.Nil(t, entries)
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindUnknown, baseErr.Kind)
+				assert.ErrorIs(t, err, dbErr)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := setupAuditRepo(t)
+			tc.setupMock(mock)
+			entries, err := repo.ListBySubscription(ctx, subID)
+			tc.assertFunc(t, entries, err)
+		})
+	}
+}
+
+func TestAuditRepo_List(t *testing.T) {
+	ctx := tenantCtx()
+	subID := uuid.New()
+	createdAt := time.Now()
+	dbErr := errors.New("db error")
+	selectSQL := "SELECT id, subscription_id, operation, actor_id, actor_role, api_key_id, " +
+		"request_id, before, after, created_at FROM audit_log WHERE tenant_id = $1"
+
+	entryRows := func(ids ...int64) *sqlmock.Rows {
+		rows := sqlmock.NewRows(auditColumns)
+		for _, id := range ids {
+			rows.AddRow(id, subID, "update", nil, "admin", nil, "", nil, nil, createdAt)
+		}
+		return rows
+	}
+
+	testCases := []struct {
+		name       string
+		filter     domain.AuditFilter
+		setupMock  func(mock sqlmock.Sqlmock)
+		assertFunc func(t *testing.T, page *domain.AuditPage, err error)
+	}{
+		{
+			name: "Last page",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL + " ORDER BY id DESC LIMIT 3").
+					WithArgs(testTenant).
+					WillReturnRows(entryRows(2, 1))
+			},
+			assertFunc: func(t *testing.T, page *domain.AuditPage, err error) {
+				require.NoError(t, err)
+				assert.Len(t, page.Items, 2)
+				assert.Nil(t, page.Next)
+			},
+		},
+		{
+			name:   "Page with next cursor",
+			filter: domain.AuditFilter{Before: ptr(int64(10))},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL+" AND id < $2 ORDER BY id DESC LIMIT 3").
+					WithArgs(testTenant, int64(10)).
+					WillReturnRows(entryRows(9, 8, 7))
+			},
+			assertFunc: func(t *testing.T, page *domain.AuditPage, err error) {
+				require.NoError(t, err)
+				require.Len(t, page.Items, 2)
+				require.NotNil(t, page.Next)
+				assert.Equal(t, int64(8), *page.Next)
+			},
+		},
+		{
+			name:   "Limit is capped",
+			filter: domain.AuditFilter{Limit: ptr(50)},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL + " ORDER BY id DESC LIMIT 4").
+					WithArgs(testTenant).
+					WillReturnRows(entryRows(1))
+			},
+			assertFunc: func(t *testing.T, page *domain.AuditPage, err error) {
+				require.NoError(t, err)
+				assert.Len(t, page.Items, 1)
+			},
+		},
+		{
+			name: "Generic DB Error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL + " ORDER BY id DESC LIMIT 3").
+					WithArgs(testTenant).
+					WillReturnError(dbErr)
+			},
+			assertFunc: func(t *testing.T, page *domain.AuditPage, err error) {
+				assert.Nil(t, page)
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.ErrorIs(t, err, dbErr)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := setupAuditRepo(t)
+			tc.setupMock(mock)
+			page, err := repo.List(ctx, tc.filter)
+			tc.assertFunc(t, page, err)
+		})
+	}
+}
+
+func TestAuditRepo_BuildAuditQuery(t *testing.T) {
+	repo, _ := setupAuditRepo(t)
+	subID := uuid.New()
+	actorID := uuid.New()
+	keyID := uuid.New()
+	op := domain.AuditPause
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	query, args, err := repo.buildAuditQuery(testTenant, domain.AuditFilter{
+		SubscriptionID: &subID,
+		ActorID:        &actorID,
+		APIKeyID:       &keyID,
+		Op:             &op,
+		From:           &from,
+		To:             &to,
+		Before:         ptr(int64(42)),
+	}, 5)
+	require.NoError(t, err)
+
+	assert.Equal(t, "SELECT id, subscription_id, operation, actor_id, actor_role, api_key_id, "+
+		"request_id, before, after, created_at FROM audit_log WHERE tenant_id = $1 AND subscription_id = $2 "+
+		"AND actor_id = $3 AND api_key_id = $4 AND operation = $5 AND created_at >= $6 AND created_at < $7 "+
+		"AND id < $8 ORDER BY id DESC LIMIT 6", query)
+	assert.Equal(t, []any{testTenant, subID.String(), actorID.String(), keyID.String(), "pause", from, to, int64(42)}, args)
+}
+
+func TestAuditRepo_NoTenant(t *testing.T) {
+	repo, _ := setupAuditRepo(t)
+
+	entries, err := repo.ListBySubscription(context.Background(), uuid.New())
+	assert.Nil(t, entries)
+	assert.ErrorIs(t, err, errNoTenant)
+}
@@ -0,0 +1,136 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/pkg/log"
+)
+
+const (
+	opUpsertRate = "ratesRepo.Upsert"
+	opGetRate    = "ratesRepo.Get"
+	opListRates  = "ratesRepo.List"
+	opDeleteRate = "ratesRepo.Delete"
+)
+
+type ratesRepo struct {
+	db DBTX
+}
+
+func NewRatesRepo(db DBTX) *ratesRepo {
+	return &ratesRepo{db: db}
+}
+
+func (r *ratesRepo) Upsert(ctx context.Context, rate *domain.CurrencyRate) error {
+	l := log.FromCtx(ctx).With(slog.String("op", opUpsertRate))
+	l.Debug("upserting currency rate in db", slog.String("from", rate.From), slog.String("to", rate.To))
+
+	tenant, err := tenantOf(ctx, opUpsertRate)
+	if err != nil {
+		return err
+	}
+
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		return db.QueryRowContext(ctx, upsertRateQuery, rate.From, rate.To, rate.Rate, tenant).Scan(&rate.UpdatedAt)
+	})
+	if err != nil {
+		return repos.WrapErr(opUpsertRate, repos.KindUnknown, err)
+	}
+
+	return nil
+}
+
+func (r *ratesRepo) Get(ctx context.Context, from, to string) (*domain.CurrencyRate, error) {
+	l := log.FromCtx(ctx).With(slog.String("op", opGetRate))
+	l.Debug("getting currency rate from db", slog.String("from", from), slog.String("to", to))
+
+	tenant, err := tenantOf(ctx, opGetRate)
+	if err != nil {
+		return nil, err
+	}
+
+	rate := &domain.CurrencyRate{}
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		return db.QueryRowContext(ctx, getRateQuery, from, to, tenant).Scan(
+			&rate.From, &rate.To, &rate.Rate, &rate.UpdatedAt,
+		)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, repos.WrapErr(opGetRate, repos.KindNotFound, err)
+		}
+		return nil, repos.WrapErr(opGetRate, repos.KindUnknown, err)
+	}
+
+	return rate, nil
+}
+
+func (r *ratesRepo) List(ctx context.Context) ([]domain.CurrencyRate, error) {
+	l := log.FromCtx(ctx).With(slog.String("op", opListRates))
+	l.Debug("listing currency rates from db")
+
+	tenant, err := tenantOf(ctx, opListRates)
+	if err != nil {
+		return nil, err
+	}
+
+	rates := make([]domain.CurrencyRate, 0)
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		rows, err := db.QueryContext(ctx, listRatesQuery, tenant)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := rows.Close(); cerr != nil {
+				log.FromCtx(ctx).Warn("failed to close sql.Rows", log.WithErr(cerr))
+			}
+		}()
+
+		for rows.Next() {
+			var rate domain.CurrencyRate
+			if err := rows.Scan(&rate.From, &rate.To, &rate.Rate, &rate.UpdatedAt); err != nil {
+				return err
+			}
+			rates = append(rates, rate)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, repos.WrapErr(opListRates, repos.KindUnknown, err)
+	}
+
+	return rates, nil
+}
+
+func (r *ratesRepo) Delete(ctx context.Context, from, to string) error {
+	l := log.FromCtx(ctx).With(slog.String("op", opDeleteRate))
+	l.Debug("deleting currency rate from db", slog.String("from", from), slog.String("to", to))
+
+	tenant, err := tenantOf(ctx, opDeleteRate)
+	if err != nil {
+		return err
+	}
+
+	var rowsAffected int64
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		res, err := db.ExecContext(ctx, deleteRateQuery, from, to, tenant)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return repos.WrapErr(opDeleteRate, repos.KindUnknown, err)
+	}
+	if rowsAffected == 0 {
+		return repos.NewErr(opDeleteRate, repos.KindNotFound)
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package postgres
+
+const (
+	upsertRateQuery = `
+		INSERT INTO currency_rates (from_currency, to_currency, rate, tenant_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (tenant_id, from_currency, to_currency) DO UPDATE
+		SET rate = EXCLUDED.rate, updated_at = NOW()
+		RETURNING updated_at;
+	`
+
+	getRateQuery = `
+		SELECT from_currency, to_currency, rate, updated_at
+		FROM currency_rates
+		WHERE from_currency = $1 AND to_currency = $2 AND tenant_id = $3;
+	`
+
+	listRatesQuery = `
+		SELECT from_currency, to_currency, rate, updated_at
+		FROM currency_rates
+		WHERE tenant_id = $1
+		ORDER BY from_currency, to_currency;
+	`
+
+	deleteRateQuery = `
+		DELETE FROM currency_rates WHERE from_currency = $1 AND to_currency = $2 AND tenant_id = $3;
+	`
+)
@@ -0,0 +1,194 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/pkg/errkit"
+)
+
+func setupRatesRepo(t *testing.T) (*ratesRepo, sqlmock.Sqlmock) {
+	t.Helper()
+	tx, mock := setupTx(t)
+
+	return NewRatesRepo(tx), mock
+}
+
+func TestRatesRepo_Upsert(t *testing.T) {
+	ctx := tenantCtx()
+	updatedAt := time.Now()
+	dbErr := errors.New("db error")
+
+	testCases := []struct {
+		name       string
+		setupMock  func(mock sqlmock.Sqlmock, rate *domain.CurrencyRate)
+		assertFunc func(t *testing.T, rate *domain.CurrencyRate, err error)
+	}{
+		{
+			name: "Success",
+			setupMock: func(mock sqlmock.Sqlmock, rate *domain.CurrencyRate) {
+				mock.ExpectQuery(upsertRateQuery).
+					WithArgs(rate.From, rate.To, rate.Rate, testTenant).
+					WillReturnRows(sqlmock.NewRows([]string{"updated_at"}).AddRow(updatedAt))
+			},
+			assertFunc: func(t *testing.T, rate *domain.CurrencyRate, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, updatedAt, rate.UpdatedAt)
+			},
+		},
+		{
+			name: "Generic DB Error",
+			setupMock: func(mock sqlmock.Sqlmock, rate *domain.CurrencyRate) {
+				mock.ExpectQuery(upsertRateQuery).
+					WithArgs(rate.From, rate.To, rate.Rate, testTenant).
+					WillReturnError(dbErr)
+			},
+			assertFunc: func(t *testing.T, rate *domain.CurrencyRate, err error) {
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindUnknown, baseErr.Kind)
+				assert.ErrorIs(t, err, dbErr)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := setupRatesRepo(t)
+			rate := &domain.CurrencyRate{From: "USD", To: "RUB", Rate: 81.5}
+			tc.setupMock(mock, rate)
+			err := repo.Upsert(ctx, rate)
+			tc.assertFunc(t, rate, err)
+		})
+	}
+}
+
+func TestRatesRepo_Get(t *testing.T) {
+	ctx := tenantCtx()
+	updatedAt := time.Now()
+
+	testCases := []struct {
+		name       string
+		setupMock  func(mock sqlmock.Sqlmock)
+		assertFunc func(t *testing.T, rate *domain.CurrencyRate, err error)
+	}{
+		{
+			name: "Success",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows([]string{"from_currency", "to_currency", "rate", "updated_at"}).
+					AddRow("USD", "RUB", 81.5, updatedAt)
+				mock.ExpectQuery(getRateQuery).WithArgs("USD", "RUB", testTenant).WillReturnRows(rows)
+			},
+			assertFunc: func(t *testing.T, rate *domain.CurrencyRate, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, &domain.CurrencyRate{From: "USD", To: "RUB", Rate: 81.5, UpdatedAt: updatedAt}, rate)
+			},
+		},
+		{
+			name: "Not Found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(getRateQuery).WithArgs("USD", "RUB", testTenant).WillReturnError(sql.ErrNoRows)
+			},
+			assertFunc: func(t *testing.T, rate *domain.CurrencyRate, err error) {
+				assert.Nil(t, rate)
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindNotFound, baseErr.Kind)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := setupRatesRepo(t)
+			tc.setupMock(mock)
+			rate, err := repo.Get(ctx, "USD", "RUB")
+			tc.assertFunc(t, rate, err)
+		})
+	}
+}
+
+func TestRatesRepo_List(t *testing.T) {
+	ctx := tenantCtx()
+	updatedAt := time.Now()
+
+	t.Run("Success", func(t *testing.T) {
+		repo, mock := setupRatesRepo(t)
+		rows := sqlmock.NewRows([]string{"from_currency", "to_currency", "rate", "updated_at"}).
+			AddRow("EUR", "RUB", 90.1, updatedAt).
+			AddRow("USD", "RUB", 81.5, updatedAt)
+		mock.ExpectQuery(listRatesQuery).WithArgs(testTenant).WillReturnRows(rows)
+
+		rates, err := repo.List(ctx)
+		require.NoError(t, err)
+		require.Len(t, rates, 2)
+		assert.Equal(t, "EUR", rates[0].From)
+		assert.Equal(t, 81.5, rates[1].Rate)
+	})
+
+	t.Run("DB Query Error", func(t *testing.T) {
+		repo, mock := setupRatesRepo(t)
+		mock.ExpectQuery(listRatesQuery).WithArgs(testTenant).WillReturnError(errors.New("db error"))
+
+		rates, err := repo.List(ctx)
+		assert.Nil(t, rates)
+		var baseErr *errkit.BaseErr[repos.RepoKind]
+		require.ErrorAs(t, err, &baseErr)
+		assert.Equal(t, repos.KindUnknown, baseErr.Kind)
+	})
+}
+
+func TestRatesRepo_Delete(t *testing.T) {
+	ctx := tenantCtx()
+
+	testCases := []struct {
+		name         string
+		result       sql.Result
+		mockErr      error
+		expectedKind *repos.RepoKind
+	}{
+		{
+			name:   "Success",
+			result: sqlmock.NewResult(0, 1),
+		},
+		{
+			name:         "Not Found",
+			result:       sqlmock.NewResult(0, 0),
+			expectedKind: ptr(repos.KindNotFound),
+		},
+		{
+			name:         "Generic DB Error",
+			mockErr:      errors.New("db error"),
+			expectedKind: ptr(repos.KindUnknown),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := setupRatesRepo(t)
+			exec := mock.ExpectExec(deleteRateQuery).WithArgs("USD", "RUB", testTenant)
+			if tc.mockErr != nil {
+				exec.WillReturnError(tc.mockErr)
+			} else {
+				exec.WillReturnResult(tc.result)
+			}
+
+			err := repo.Delete(ctx, "USD", "RUB")
+			if tc.expectedKind == nil {
+				assert.NoError(t, err)
+				return
+			}
+			var baseErr *errkit.BaseErr[repos.RepoKind]
+			require.ErrorAs(t, err, &baseErr)
+			assert.Equal(t, *tc.expectedKind, baseErr.Kind)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log/slog"
+
+	"github.com/shrtyk/subscriptions-service/internal/config"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/pkg/log"
+)
+
+const (
+	opReserveKey  = "idempotencyRepo.Reserve"
+	opCompleteKey = "idempotencyRepo.Complete"
+	opPurgeKeys   = "idempotencyRepo.PurgeExpired"
+)
+
+type idempotencyRepo struct {
+	db  DBTX
+	cfg *config.RepoConfig
+}
+
+func NewIdempotencyRepo(db DBTX, cfg *config.RepoConfig) *idempotencyRepo {
+	return &idempotencyRepo{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+// Reserve waits for a transaction holding the same key to finish, so retries
+// racing the first request see its response.
+func (r *idempotencyRepo) Reserve(ctx context.Context, key domain.IdempotencyKey) (*domain.IdempotencyRecord, error) {
+	l := log.FromCtx(ctx).With(slog.String("op", opReserveKey))
+	l.Debug("reserving idempotency key in db", slog.String("key", key.Key))
+
+	tenant, err := tenantOf(ctx, opReserveKey)
+	if err != nil {
+		return nil, err
+	}
+
+	var rec *domain.IdempotencyRecord
+	var response []byte
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		var reserved string
+		err := db.QueryRowContext(ctx, reserveIdempotencyKeyQuery, key.Key, key.Caller, key.RequestHash, r.cfg.IdempotencyKeyTTL.Seconds(), tenant).
+			Scan(&reserved)
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		rec = &domain.IdempotencyRecord{}
+		return db.QueryRowContext(ctx, getIdempotencyKeyQuery, key.Key, key.Caller, tenant).Scan(
+			&rec.Key, &rec.Caller, &rec.RequestHash, &response, &rec.CreatedAt, &rec.ExpiresAt,
+		)
+	})
+	if err != nil {
+		return nil, repos.WrapErr(opReserveKey, repos.KindUnknown, err)
+	}
+	if rec == nil {
+		return nil, nil
+	}
+	if response != nil {
+		if err := json.Unmarshal(response, &rec.Response); err != nil {
+			return nil, repos.WrapErr(opReserveKey, repos.KindUnknown, err)
+		}
+	}
+
+	return rec, nil
+}
+
+func (r *idempotencyRepo) Complete(ctx context.Context, key domain.IdempotencyKey, response *domain.Subscription) error {
+	l := log.FromCtx(ctx).With(slog.String("op", opCompleteKey))
+	l.Debug("completing idempotency key in db", slog.String("key", key.Key))
+
+	tenant, err := tenantOf(ctx, opCompleteKey)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(response)
+	if err != nil {
+		return repos.WrapErr(opCompleteKey, repos.KindUnknown, err)
+	}
+
+	var rowsAffected int64
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		res, err := db.ExecContext(ctx, completeIdempotencyKeyQuery, key.Key, key.Caller, string(b), tenant)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return repos.WrapErr(opCompleteKey, repos.KindUnknown, err)
+	}
+	if rowsAffected == 0 {
+		return repos.NewErr(opCompleteKey, repos.KindNotFound)
+	}
+
+	return nil
+}
+
+func (r *idempotencyRepo) PurgeExpired(ctx context.Context) (int64, error) {
+	l := log.FromCtx(ctx).With(slog.String("op", opPurgeKeys))
+	l.Debug("purging expired idempotency keys from db")
+
+	var purged int64
+	err := acrossTenants(ctx, r.db, func(db DBTX) error {
+		res, err := db.ExecContext(ctx, purgeIdempotencyKeysQuery)
+		if err != nil {
+			return err
+		}
+		purged, err = res.RowsAffected()
+		return err
+	})
+	if err != nil {
+		return 0, repos.WrapErr(opPurgeKeys, repos.KindUnknown, err)
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,29 @@
+package postgres
+
+const (
+	// reserveIdempotencyKeyQuery takes over expired keys, live ones return no row.
+	reserveIdempotencyKeyQuery = `
+		INSERT INTO idempotency_keys (key, caller, request_hash, expires_at, tenant_id)
+		VALUES ($1, $2, $3, NOW() + make_interval(secs => $4), $5)
+		ON CONFLICT (tenant_id, caller, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, response = NULL, created_at = NOW(), expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= NOW()
+		RETURNING key;
+	`
+
+	getIdempotencyKeyQuery = `
+		SELECT key, caller, request_hash, response, created_at, expires_at
+		FROM idempotency_keys
+		WHERE key = $1 AND caller = $2 AND tenant_id = $3;
+	`
+
+	completeIdempotencyKeyQuery = `
+		UPDATE idempotency_keys SET response = $3::jsonb WHERE key = $1 AND caller = $2 AND tenant_id = $4;
+	`
+
+	// purgeIdempotencyKeysQuery is the only one across tenants, expired keys of
+	// all of them are purged at once as CrossTenantRole.
+	purgeIdempotencyKeysQuery = `
+		DELETE FROM idempotency_keys WHERE expires_at <= NOW();
+	`
+)
@@ -0,0 +1,170 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shrtyk/subscriptions-service/internal/config"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/pkg/errkit"
+)
+
+func setupIdempotencyRepo(t *testing.T) (*idempotencyRepo, sqlmock.Sqlmock) {
+	t.Helper()
+	tx, mock := setupTx(t)
+
+	return NewIdempotencyRepo(tx, &config.RepoConfig{IdempotencyKeyTTL: time.Hour}), mock
+}
+
+func TestIdempotencyRepo_Reserve(t *testing.T) {
+	ctx := tenantCtx()
+	key := domain.IdempotencyKey{Key: "key-1", Caller: "user:1", RequestHash: "hash"}
+	now := time.Now()
+	stored := &domain.Subscription{ID: uuid.New(), ServiceName: "Test", Price: 100, UserID: uuid.New()}
+	response, err := json.Marshal(stored)
+	require.NoError(t, err)
+	dbErr := errors.New("db error")
+
+	recordRows := func(response []byte) *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"key", "caller", "request_hash", "response", "created_at", "expires_at"}).
+			AddRow(key.Key, key.Caller, key.RequestHash, response, now, now.Add(time.Hour))
+	}
+
+	testCases := []struct {
+		name       string
+		setupMock  func(mock sqlmock.Sqlmock)
+		assertFunc func(t *testing.T, rec *domain.IdempotencyRecord, err error)
+	}{
+		{
+			name: "Reserved",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(reserveIdempotencyKeyQuery).
+					WithArgs(key.Key, key.Caller, key.RequestHash, time.Hour.Seconds(), testTenant).
+					WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow(key.Key))
+			},
+			assertFunc: func(t *testing.T, rec *domain.IdempotencyRecord, err error) {
+				require.NoError(t, err)
+				assert.Nil(t, rec)
+			},
+		},
+		{
+			name: "Existing Record",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(reserveIdempotencyKeyQuery).
+					WithArgs(key.Key, key.Caller, key.RequestHash, time.Hour.Seconds(), testTenant).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(getIdempotencyKeyQuery).WithArgs(key.Key, key.Caller, testTenant).WillReturnRows(recordRows(response))
+			},
+			assertFunc: func(t *testing.T, rec *domain.IdempotencyRecord, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, rec)
+				assert.Equal(t, key, rec.IdempotencyKey)
+				assert.Equal(t, stored, rec.Response)
+			},
+		},
+		{
+			name: "Existing Record Without Response",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(reserveIdempotencyKeyQuery).
+					WithArgs(key.Key, key.Caller, key.RequestHash, time.Hour.Seconds(), testTenant).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(getIdempotencyKeyQuery).WithArgs(key.Key, key.Caller, testTenant).WillReturnRows(recordRows(nil))
+			},
+			assertFunc: func(t *testing.T, rec *domain.IdempotencyRecord, err error) {
+				require.NoError(t, err)
+				require.NotNil(t, rec)
+				assert.Nil(t, rec.Response)
+			},
+		},
+		{
+			name: "Generic DB Error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(reserveIdempotencyKeyQuery).
+					WithArgs(key.Key, key.Caller, key.RequestHash, time.Hour.Seconds(), testTenant).
+					WillReturnError(dbErr)
+			},
+			assertFunc: func(t *testing.T, rec *domain.IdempotencyRecord, err error) {
+				assert.Nil(t, rec)
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindUnknown, baseErr.Kind)
+				assert.ErrorIs(t, err, dbErr)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := setupIdempotencyRepo(t)
+			tc.setupMock(mock)
+			rec, err := repo.Reserve(ctx, key)
+			tc.assertFunc(t, rec, err)
+		})
+	}
+}
+
+func TestIdempotencyRepo_Complete(t *testing.T) {
+	ctx := tenantCtx()
+	key := domain.IdempotencyKey{Key: "key-1", Caller: "user:1", RequestHash: "hash"}
+	sub := &domain.Subscription{ID: uuid.New(), ServiceName: "Test"}
+	response, err := json.Marshal(sub)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name       string
+		setupMock  func(mock sqlmock.Sqlmock)
+		assertFunc func(t *testing.T, err error)
+	}{
+		{
+			name: "Success",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(completeIdempotencyKeyQuery).
+					WithArgs(key.Key, key.Caller, string(response), testTenant).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			assertFunc: func(t *testing.T, err error) {
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "Not Found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(completeIdempotencyKeyQuery).
+					WithArgs(key.Key, key.Caller, string(response), testTenant).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+			assertFunc: func(t *testing.T, err error) {
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindNotFound, baseErr.Kind)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := setupIdempotencyRepo(t)
+			tc.setupMock(mock)
+			tc.assertFunc(t, repo.Complete(ctx, key, sub))
+		})
+	}
+}
+
+func TestIdempotencyRepo_PurgeExpired(t *testing.T) {
+	repo, mock := setupIdempotencyRepo(t)
+	mock.ExpectExec(purgeIdempotencyKeysQuery).WillReturnResult(sqlmock.NewResult(0, 3))
+
+	purged, err := repo.PurgeExpired(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+}
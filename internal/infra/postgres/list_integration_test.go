@@ -0,0 +1,98 @@
+//go:build integration
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"math/rand/v2"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shrtyk/subscriptions-service/internal/config"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/pkg/log"
+)
+
+// Test_ListCursorWalksSortedList checks that following next cursors visits the
+// same subscriptions in the same order as one large page, for several sorts.
+// It requires a migrated database reachable through PG_TEST_DSN.
+func Test_ListCursorWalksSortedList(t *testing.T) {
+	dsn := os.Getenv("PG_TEST_DSN")
+	if dsn == "" {
+		t.Skip("PG_TEST_DSN is not set")
+	}
+
+	db, err := sql.Open("pgx", dsn)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+
+	l, _ := log.NewTestLogger()
+	ctx := log.ToCtx(domain.PrincipalToCtx(context.Background(), &domain.Principal{TenantID: "acme"}), l)
+
+	sqlTx, err := db.BeginTx(ctx, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = sqlTx.Rollback() })
+	require.NoError(t, SetTenant(ctx, sqlTx, "acme"))
+
+	repo := NewSubsRepo(sqlTx, &config.RepoConfig{DefaultPageSize: 10, MaxPageSize: 100})
+
+	seed := uint64(time.Now().UnixNano())
+	t.Logf("seed: %d", seed)
+	rng := rand.New(rand.NewPCG(seed, seed))
+
+	userID := uuid.New()
+	billingPeriods := []domain.BillingPeriod{
+		domain.BillingWeekly, domain.BillingMonthly, domain.BillingQuarterly, domain.BillingYearly,
+	}
+	for range 40 {
+		// Few distinct values so that ties between sort keys are common.
+		sub := domain.Subscription{
+			ServiceName:   []string{"Okko", "Kinopoisk", "Yandex Plus"}[rng.IntN(3)],
+			Price:         300 * (1 + rng.IntN(3)),
+			BillingPeriod: billingPeriods[rng.IntN(len(billingPeriods))],
+			Currency:      "RUB",
+			UserID:        userID,
+			StartDate:     time.Date(2025, time.Month(1+rng.IntN(3)), 1, 0, 0, 0, 0, time.UTC),
+		}
+		require.NoError(t, repo.Create(ctx, &sub))
+	}
+
+	sorts := [][]domain.SortKey{
+		nil,
+		{{Field: domain.SortCreatedAt, Desc: true}},
+		{{Field: domain.SortMonthlyCost, Desc: true}, {Field: domain.SortStartDate, Desc: true}},
+		{{Field: domain.SortServiceName}, {Field: domain.SortStartDate, Desc: true}, {Field: domain.SortMonthlyCost}},
+	}
+
+	for _, sort := range sorts {
+		all, err := repo.List(ctx, domain.SubscriptionFilter{UserID: &userID, Sort: sort, PageSize: ptr(100)})
+		require.NoError(t, err)
+		require.Nil(t, all.NextCursor)
+
+		walked := make([]uuid.UUID, 0, len(all.Items))
+		filter := domain.SubscriptionFilter{UserID: &userID, Sort: sort, PageSize: ptr(7)}
+		for {
+			page, err := repo.List(ctx, filter)
+			require.NoError(t, err)
+			for _, sub := range page.Items {
+				walked = append(walked, sub.ID)
+			}
+			if page.NextCursor == nil {
+				break
+			}
+			filter.After = page.NextCursor
+		}
+
+		expected := make([]uuid.UUID, 0, len(all.Items))
+		for _, sub := range all.Items {
+			expected = append(expected, sub.ID)
+		}
+		assert.Equal(t, expected, walked, "sort %v", sort)
+	}
+}
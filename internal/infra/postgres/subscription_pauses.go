@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/pkg/log"
+)
+
+const (
+	opAddPause    = "subsRepo.AddPause"
+	opResumePause = "subsRepo.ResumePause"
+	opListPauses  = "subsRepo.ListPauses"
+)
+
+func (r *subsRepo) AddPause(ctx context.Context, subID uuid.UUID, pause *domain.Pause) error {
+	l := log.FromCtx(ctx).With(slog.String("op", opAddPause))
+	l.Debug(
+		"adding pause in db",
+		slog.String("subscription_id", subID.String()),
+		slog.Time("paused_from", pause.PausedFrom),
+	)
+
+	tenant, err := tenantOf(ctx, opAddPause)
+	if err != nil {
+		return err
+	}
+
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		return db.QueryRowContext(ctx, addPauseQuery, subID, pause.PausedFrom, pause.ResumedFrom, tenant).
+			Scan(&pause.CreatedAt)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repos.WrapErr(opAddPause, repos.KindNotFound, err)
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			switch pgErr.Code {
+			case "23503":
+				return repos.WrapErr(opAddPause, repos.KindNotFound, err)
+			case "23505", "23P01":
+				return repos.WrapErr(opAddPause, repos.KindDuplicate, err)
+			}
+		}
+		return repos.WrapErr(opAddPause, repos.KindUnknown, err)
+	}
+
+	return nil
+}
+
+func (r *subsRepo) ResumePause(ctx context.Context, subID uuid.UUID, pause *domain.Pause) error {
+	l := log.FromCtx(ctx).With(slog.String("op", opResumePause))
+	l.Debug(
+		"resuming pause in db",
+		slog.String("subscription_id", subID.String()),
+		slog.Time("paused_from", pause.PausedFrom),
+	)
+
+	tenant, err := tenantOf(ctx, opResumePause)
+	if err != nil {
+		return err
+	}
+
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		return db.QueryRowContext(ctx, resumePauseQuery, subID, pause.PausedFrom, pause.ResumedFrom, tenant).
+			Scan(&pause.CreatedAt)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repos.WrapErr(opResumePause, repos.KindNotFound, err)
+		}
+		return repos.WrapErr(opResumePause, repos.KindUnknown, err)
+	}
+
+	return nil
+}
+
+func (r *subsRepo) ListPauses(ctx context.Context, subID uuid.UUID) ([]domain.Pause, error) {
+	l := log.FromCtx(ctx).With(slog.String("op", opListPauses))
+	l.Debug("listing pauses from db", slog.String("subscription_id", subID.String()))
+
+	tenant, err := tenantOf(ctx, opListPauses)
+	if err != nil {
+		return nil, err
+	}
+
+	pauses := make([]domain.Pause, 0)
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		rows, err := db.QueryContext(ctx, listPausesQuery, subID, tenant)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := rows.Close(); cerr != nil {
+				log.FromCtx(ctx).Warn("failed to close sql.Rows", log.WithErr(cerr))
+			}
+		}()
+
+		for rows.Next() {
+			var pause domain.Pause
+			if err := rows.Scan(&pause.PausedFrom, &pause.ResumedFrom, &pause.CreatedAt); err != nil {
+				return err
+			}
+			pauses = append(pauses, pause)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, repos.WrapErr(opListPauses, repos.KindUnknown, err)
+	}
+
+	return pauses, nil
+}
+
+// attachPauses loads the pauses of subs, which have to be the subscriptions
+// of tenant matching filter.
+func (r *subsRepo) attachPauses(ctx context.Context, tenant string, filter domain.SubscriptionFilter, subs []domain.Subscription) error {
+	if len(subs) == 0 {
+		return nil
+	}
+
+	query, args, err := r.buildPausesQuery(tenant, filter)
+	if err != nil {
+		return err
+	}
+
+	pauses := make(map[uuid.UUID][]domain.Pause, len(subs))
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		rows, err := db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := rows.Close(); cerr != nil {
+				log.FromCtx(ctx).Warn("failed to close sql.Rows", log.WithErr(cerr))
+			}
+		}()
+
+		for rows.Next() {
+			var subID uuid.UUID
+			var pause domain.Pause
+			if err := rows.Scan(&subID, &pause.PausedFrom, &pause.ResumedFrom, &pause.CreatedAt); err != nil {
+				return err
+			}
+			pauses[subID] = append(pauses[subID], pause)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range subs {
+		subs[i].Pauses = pauses[subs[i].ID]
+	}
+
+	return nil
+}
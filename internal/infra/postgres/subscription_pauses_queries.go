@@ -0,0 +1,44 @@
+package postgres
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+)
+
+// Pauses have no tenant of their own, the queries reach them through the
+// subscription of the tenant with the id.
+const (
+	addPauseQuery = `
+		INSERT INTO subscription_pauses (subscription_id, paused_from, resumed_from)
+		SELECT id, $2, $3 FROM subscriptions WHERE id = $1 AND tenant_id = $4
+		RETURNING created_at;
+	`
+
+	resumePauseQuery = `
+		UPDATE subscription_pauses
+		SET resumed_from = $3
+		WHERE subscription_id IN (SELECT id FROM subscriptions WHERE id = $1 AND tenant_id = $4)
+			AND paused_from = $2 AND resumed_from IS NULL
+		RETURNING created_at;
+	`
+
+	listPausesQuery = `
+		SELECT paused_from, resumed_from, created_at
+		FROM subscription_pauses
+		WHERE subscription_id IN (SELECT id FROM subscriptions WHERE id = $1 AND tenant_id = $2)
+		ORDER BY paused_from;
+	`
+)
+
+// buildPausesQuery selects the pauses of the subscriptions matching filter.
+func (r *subsRepo) buildPausesQuery(tenant string, filter domain.SubscriptionFilter) (string, []any, error) {
+	subIDs := applyFilter(squirrel.Select("id").From("subscriptions"), tenant, filter)
+
+	queryBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("subscription_id", "paused_from", "resumed_from", "created_at").
+		From("subscription_pauses").
+		Where(squirrel.Expr("subscription_id IN (?)", subIDs)).
+		OrderBy("subscription_id", "paused_from")
+
+	return queryBuilder.ToSql()
+}
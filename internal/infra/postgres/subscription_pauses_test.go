@@ -0,0 +1,156 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/pkg/errkit"
+)
+
+func TestSubsRepo_AddPause(t *testing.T) {
+	ctx := tenantCtx()
+	subID := uuid.New()
+	createdAt := time.Now()
+	dbErr := errors.New("db error")
+
+	testCases := []struct {
+		name         string
+		dbErr        error
+		expectedKind repos.RepoKind
+	}{
+		{name: "Success"},
+		{name: "Subscription Not Found", dbErr: &pgconn.PgError{Code: "23503"}, expectedKind: repos.KindNotFound},
+		{name: "Already Paused", dbErr: &pgconn.PgError{Code: "23505"}, expectedKind: repos.KindDuplicate},
+		{name: "Overlapping Pause", dbErr: &pgconn.PgError{Code: "23P01"}, expectedKind: repos.KindDuplicate},
+		{name: "Generic DB Error", dbErr: dbErr, expectedKind: repos.KindUnknown},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := setup(t)
+			pause := &domain.Pause{PausedFrom: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)}
+
+			query := mock.ExpectQuery(addPauseQuery).WithArgs(subID, pause.PausedFrom, pause.ResumedFrom, testTenant)
+			if tc.dbErr != nil {
+				query.WillReturnError(tc.dbErr)
+			} else {
+				query.WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+			}
+
+			err := repo.AddPause(ctx, subID, pause)
+			if tc.dbErr != nil {
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, tc.expectedKind, baseErr.Kind)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, createdAt, pause.CreatedAt)
+			}
+		})
+	}
+}
+
+func TestSubsRepo_ResumePause(t *testing.T) {
+	ctx := tenantCtx()
+	subID := uuid.New()
+	createdAt := time.Now()
+	resumedFrom := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name         string
+		dbErr        error
+		expectedKind repos.RepoKind
+	}{
+		{name: "Success"},
+		{name: "No Open Pause", dbErr: sql.ErrNoRows, expectedKind: repos.KindNotFound},
+		{name: "Generic DB Error", dbErr: errors.New("db error"), expectedKind: repos.KindUnknown},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := setup(t)
+			pause := &domain.Pause{PausedFrom: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), ResumedFrom: &resumedFrom}
+
+			query := mock.ExpectQuery(resumePauseQuery).WithArgs(subID, pause.PausedFrom, pause.ResumedFrom, testTenant)
+			if tc.dbErr != nil {
+				query.WillReturnError(tc.dbErr)
+			} else {
+				query.WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+			}
+
+			err := repo.ResumePause(ctx, subID, pause)
+			if tc.dbErr != nil {
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, tc.expectedKind, baseErr.Kind)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, createdAt, pause.CreatedAt)
+			}
+		})
+	}
+}
+
+func TestSubsRepo_ListPauses(t *testing.T) {
+	ctx := tenantCtx()
+	subID := uuid.New()
+	createdAt := time.Now()
+	first := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	firstEnd := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	cols := []string{"paused_from", "resumed_from", "created_at"}
+
+	testCases := []struct {
+		name       string
+		setupMock  func(mock sqlmock.Sqlmock)
+		assertFunc func(t *testing.T, pauses []domain.Pause, err error)
+	}{
+		{
+			name: "Success",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(cols).
+					AddRow(first, firstEnd, createdAt).
+					AddRow(second, nil, createdAt)
+				mock.ExpectQuery(listPausesQuery).WithArgs(subID, testTenant).WillReturnRows(rows)
+			},
+			assertFunc: func(t *testing.T, pauses []domain.Pause, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, []domain.Pause{
+					{PausedFrom: first, ResumedFrom: &firstEnd, CreatedAt: createdAt},
+					{PausedFrom: second, CreatedAt: createdAt},
+				}, pauses)
+			},
+		},
+		{
+			name: "DB Query Error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(listPausesQuery).WithArgs(subID, testTenant).WillReturnError(errors.New("db error"))
+			},
+			assertFunc: func(t *testing.T, pauses []domain.Pause, err error) {
+				assert.Nil(t, pauses)
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindUnknown, baseErr.Kind)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := setup(t)
+			tc.setupMock(mock)
+			pauses, err := repo.ListPauses(ctx, subID)
+			tc.assertFunc(t, pauses, err)
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/pkg/log"
+)
+
+const (
+	opAddPriceChange   = "subsRepo.AddPriceChange"
+	opListPriceChanges = "subsRepo.ListPriceChanges"
+	opRebasePrices     = "subsRepo.RebasePriceHistory"
+)
+
+func (r *subsRepo) AddPriceChange(ctx context.Context, subID uuid.UUID, change *domain.PriceChange) error {
+	l := log.FromCtx(ctx).With(slog.String("op", opAddPriceChange))
+	l.Debug(
+		"adding price change in db",
+		slog.String("subscription_id", subID.String()),
+		slog.Time("effective_from", change.EffectiveFrom),
+	)
+
+	tenant, err := tenantOf(ctx, opAddPriceChange)
+	if err != nil {
+		return err
+	}
+
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		return db.QueryRowContext(ctx, addPriceChangeQuery, subID, change.EffectiveFrom, change.Price, tenant).
+			Scan(&change.CreatedAt)
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.Is(err, sql.ErrNoRows) || errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return repos.WrapErr(opAddPriceChange, repos.KindNotFound, err)
+		}
+		return repos.WrapErr(opAddPriceChange, repos.KindUnknown, err)
+	}
+
+	return nil
+}
+
+func (r *subsRepo) ListPriceChanges(ctx context.Context, subID uuid.UUID) ([]domain.PriceChange, error) {
+	l := log.FromCtx(ctx).With(slog.String("op", opListPriceChanges))
+	l.Debug("listing price changes from db", slog.String("subscription_id", subID.String()))
+
+	tenant, err := tenantOf(ctx, opListPriceChanges)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make([]domain.PriceChange, 0)
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		rows, err := db.QueryContext(ctx, listPriceChangesQuery, subID, tenant)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := rows.Close(); cerr != nil {
+				log.FromCtx(ctx).Warn("failed to close sql.Rows", log.WithErr(cerr))
+			}
+		}()
+
+		for rows.Next() {
+			var change domain.PriceChange
+			if err := rows.Scan(&change.EffectiveFrom, &change.Price, &change.CreatedAt); err != nil {
+				return err
+			}
+			changes = append(changes, change)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, repos.WrapErr(opListPriceChanges, repos.KindUnknown, err)
+	}
+
+	return changes, nil
+}
+
+func (r *subsRepo) RebasePriceHistory(ctx context.Context, subID uuid.UUID, startMonth time.Time) error {
+	l := log.FromCtx(ctx).With(slog.String("op", opRebasePrices))
+	l.Debug(
+		"rebasing price history in db",
+		slog.String("subscription_id", subID.String()),
+		slog.Time("start_month", startMonth),
+	)
+
+	tenant, err := tenantOf(ctx, opRebasePrices)
+	if err != nil {
+		return err
+	}
+
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		_, err := db.ExecContext(ctx, rebasePriceHistoryQuery, subID, startMonth, tenant)
+		return err
+	})
+	if err != nil {
+		return repos.WrapErr(opRebasePrices, repos.KindUnknown, err)
+	}
+
+	return nil
+}
+
+// attachPriceHistory loads the price changes of subs, which have to be
+// the subscriptions of tenant matching filter.
+func (r *subsRepo) attachPriceHistory(ctx context.Context, tenant string, filter domain.SubscriptionFilter, subs []domain.Subscription) error {
+	if len(subs) == 0 {
+		return nil
+	}
+
+	query, args, err := r.buildPriceHistoryQuery(tenant, filter)
+	if err != nil {
+		return err
+	}
+
+	history := make(map[uuid.UUID][]domain.PriceChange, len(subs))
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		rows, err := db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := rows.Close(); cerr != nil {
+				log.FromCtx(ctx).Warn("failed to close sql.Rows", log.WithErr(cerr))
+			}
+		}()
+
+		for rows.Next() {
+			var subID uuid.UUID
+			var change domain.PriceChange
+			if err := rows.Scan(&subID, &change.EffectiveFrom, &change.Price, &change.CreatedAt); err != nil {
+				return err
+			}
+			history[subID] = append(history[subID], change)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := range subs {
+		subs[i].PriceHistory = history[subs[i].ID]
+	}
+
+	return nil
+}
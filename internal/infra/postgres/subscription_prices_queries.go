@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"github.com/Masterminds/squirrel"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+)
+
+// Price changes have no tenant of their own, the queries reach them through
+// the subscription of the tenant with the id.
+const (
+	addPriceChangeQuery = `
+		INSERT INTO subscription_prices (subscription_id, effective_from, price)
+		SELECT id, $2, $3 FROM subscriptions WHERE id = $1 AND tenant_id = $4
+		ON CONFLICT (subscription_id, effective_from) DO UPDATE SET price = EXCLUDED.price, created_at = NOW()
+		RETURNING created_at;
+	`
+
+	listPriceChangesQuery = `
+		SELECT effective_from, price, created_at
+		FROM subscription_prices
+		WHERE subscription_id IN (SELECT id FROM subscriptions WHERE id = $1 AND tenant_id = $2)
+		ORDER BY effective_from;
+	`
+
+	// rebasePriceHistoryQuery moves the price in effect on the start month $2, or
+	// the first one when the start moved earlier, to $2 and drops the ones before.
+	rebasePriceHistoryQuery = `
+		WITH sub AS (
+			SELECT id FROM subscriptions WHERE id = $1 AND tenant_id = $3
+		), first_price AS (
+			SELECT COALESCE(
+				(SELECT MAX(effective_from) FROM subscription_prices WHERE subscription_id = $1 AND effective_from <= $2),
+				(SELECT MIN(effective_from) FROM subscription_prices WHERE subscription_id = $1)
+			) AS effective_from
+		), dropped AS (
+			DELETE FROM subscription_prices
+			WHERE subscription_id IN (SELECT id FROM sub) AND effective_from < (SELECT effective_from FROM first_price)
+		)
+		UPDATE subscription_prices SET effective_from = $2
+		WHERE subscription_id IN (SELECT id FROM sub) AND effective_from = (SELECT effective_from FROM first_price);
+	`
+)
+
+// buildPriceHistoryQuery selects the price changes of the subscriptions matching filter.
+func (r *subsRepo) buildPriceHistoryQuery(tenant string, filter domain.SubscriptionFilter) (string, []any, error) {
+	subIDs := applyFilter(squirrel.Select("id").From("subscriptions"), tenant, filter)
+
+	queryBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("subscription_id", "effective_from", "price", "created_at").
+		From("subscription_prices").
+		Where(squirrel.Expr("subscription_id IN (?)", subIDs)).
+		OrderBy("subscription_id", "effective_from")
+
+	return queryBuilder.ToSql()
+}
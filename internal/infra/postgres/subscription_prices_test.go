@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/pkg/errkit"
+)
+
+func TestSubsRepo_AddPriceChange(t *testing.T) {
+	ctx := tenantCtx()
+	subID := uuid.New()
+	createdAt := time.Now()
+	dbErr := errors.New("db error")
+
+	testCases := []struct {
+		name       string
+		setupMock  func(mock sqlmock.Sqlmock, change *domain.PriceChange)
+		assertFunc func(t *testing.T, change *domain.PriceChange, err error)
+	}{
+		{
+			name: "Success",
+			setupMock: func(mock sqlmock.Sqlmock, change *domain.PriceChange) {
+				mock.ExpectQuery(addPriceChangeQuery).
+					WithArgs(subID, change.EffectiveFrom, change.Price, testTenant).
+					WillReturnRows(sqlmock.NewRows([]string{"created_at"}).AddRow(createdAt))
+			},
+			assertFunc: func(t *testing.T, change *domain.PriceChange, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, createdAt, change.CreatedAt)
+			},
+		},
+		{
+			name: "Subscription Not Found",
+			setupMock: func(mock sqlmock.Sqlmock, change *domain.PriceChange) {
+				mock.ExpectQuery(addPriceChangeQuery).
+					WithArgs(subID, change.EffectiveFrom, change.Price, testTenant).
+					WillReturnError(&pgconn.PgError{Code: "23503"})
+			},
+			assertFunc: func(t *testing.T, change *domain.PriceChange, err error) {
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindNotFound, baseErr.Kind)
+			},
+		},
+		{
+			name: "Generic DB Error",
+			setupMock: func(mock sqlmock.Sqlmock, change *domain.PriceChange) {
+				mock.ExpectQuery(addPriceChangeQuery).
+					WithArgs(subID, change.EffectiveFrom, change.Price, testTenant).
+					WillReturnError(dbErr)
+			},
+			assertFunc: func(t *testing.T, change *domain.PriceChange, err error) {
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindUnknown, baseErr.Kind)
+				assert.ErrorIs(t, err, dbErr)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := setup(t)
+			change := &domain.PriceChange{EffectiveFrom: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), Price: 150}
+			tc.setupMock(mock, change)
+			err := repo.AddPriceChange(ctx, subID, change)
+			tc.assertFunc(t, change, err)
+		})
+	}
+}
+
+func TestSubsRepo_ListPriceChanges(t *testing.T) {
+	ctx := tenantCtx()
+	subID := uuid.New()
+	createdAt := time.Now()
+	first := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	cols := []string{"effective_from", "price", "created_at"}
+
+	testCases := []struct {
+		name       string
+		setupMock  func(mock sqlmock.Sqlmock)
+		assertFunc func(t *testing.T, changes []domain.PriceChange, err error)
+	}{
+		{
+			name: "Success",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(cols).
+					AddRow(first, 100, createdAt).
+					AddRow(second, 150, createdAt)
+				mock.ExpectQuery(listPriceChangesQuery).WithArgs(subID, testTenant).WillReturnRows(rows)
+			},
+			assertFunc: func(t *testing.T, changes []domain.PriceChange, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, []domain.PriceChange{
+					{EffectiveFrom: first, Price: 100, CreatedAt: createdAt},
+					{EffectiveFrom: second, Price: 150, CreatedAt: createdAt},
+				}, changes)
+			},
+		},
+		{
+			name: "Scan Error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				rows := sqlmock.NewRows(cols).AddRow("not-a-date", 100, createdAt)
+				mock.ExpectQuery(listPriceChangesQuery).WithArgs(subID, testTenant).WillReturnRows(rows)
+			},
+			assertFunc: func(t *testing.T, changes []domain.PriceChange, err error) {
+				assert.Nil(t, changes)
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindUnknown, baseErr.Kind)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := setup(t)
+			tc.setupMock(mock)
+			changes, err := repo.ListPriceChanges(ctx, subID)
+			tc.assertFunc(t, changes, err)
+		})
+	}
+}
+
+func TestSubsRepo_RebasePriceHistory(t *testing.T) {
+	ctx := tenantCtx()
+	subID := uuid.New()
+	startMonth := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	dbErr := errors.New("db error")
+
+	testCases := []struct {
+		name    string
+		mockErr error
+	}{
+		{name: "Success"},
+		{name: "Generic DB Error", mockErr: dbErr},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := setup(t)
+			exec := mock.ExpectExec(rebasePriceHistoryQuery).WithArgs(subID, startMonth, testTenant)
+			if tc.mockErr != nil {
+				exec.WillReturnError(tc.mockErr)
+			} else {
+				exec.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			err := repo.RebasePriceHistory(ctx, subID, startMonth)
+			if tc.mockErr != nil {
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindUnknown, baseErr.Kind)
+				assert.ErrorIs(t, err, dbErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
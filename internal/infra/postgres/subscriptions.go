@@ -4,7 +4,10 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"iter"
 	"log/slog"
+	"slices"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
@@ -15,12 +18,19 @@ import (
 )
 
 const (
-	opCreate  = "subsRepo.Create"
-	opGetByID = "subsRepo.GetByID"
-	opUpdate  = "subsRepo.Update"
-	opDelete  = "subsRepo.Delete"
-	opList    = "subsRepo.List"
-	opListAll = "subsRepo.ListAll"
+	opCreate              = "subsRepo.Create"
+	opCreateBatch         = "subsRepo.CreateBatch"
+	opGetByID             = "subsRepo.GetByID"
+	opUpdate              = "subsRepo.Update"
+	opDelete              = "subsRepo.Delete"
+	opDeleteBatch         = "subsRepo.DeleteBatch"
+	opList                = "subsRepo.List"
+	opListAll             = "subsRepo.ListAll"
+	opStream              = "subsRepo.Stream"
+	opCount               = "subsRepo.Count"
+	opTotalCostByCurrency = "subsRepo.TotalCostByCurrency"
+	opSuggestServiceNames = "subsRepo.SuggestServiceNames"
+	opFindOverlapping     = "subsRepo.FindOverlapping"
 )
 
 type subsRepo struct {
@@ -43,13 +53,20 @@ func (r *subsRepo) Create(ctx context.Context, sub *domain.Subscription) error {
 		slog.String("user_id", sub.UserID.String()),
 	)
 
-	err := r.db.QueryRowContext(
-		ctx, createQuery, sub.ServiceName,
-		sub.MonthlyCost, sub.UserID, sub.StartDate, sub.EndDate).
-		Scan(&sub.ID, &sub.CreatedAt, &sub.UpdatedAt)
+	tenant, err := tenantOf(ctx, opCreate)
 	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return err
+	}
+
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		return db.QueryRowContext(
+			ctx, createQuery, sub.ServiceName,
+			sub.Price, string(sub.BillingPeriod), sub.Currency, sub.UserID, sub.StartDate, sub.EndDate, sub.TrialEnd,
+			r.cfg.ExclusiveSubscriptions, tenant).
+			Scan(&sub.ID, &sub.Version, &sub.CreatedAt, &sub.UpdatedAt)
+	})
+	if err != nil {
+		if isDuplicateErr(err) {
 			return repos.WrapErr(opCreate, repos.KindDuplicate, err)
 		}
 		return repos.WrapErr(opCreate, repos.KindUnknown, err)
@@ -58,15 +75,91 @@ func (r *subsRepo) Create(ctx context.Context, sub *domain.Subscription) error {
 	return nil
 }
 
+func (r *subsRepo) CreateBatch(ctx context.Context, subs []*domain.Subscription) error {
+	l := log.FromCtx(ctx).With(slog.String("op", opCreateBatch))
+	l.Debug("creating subscriptions in db", slog.Int("count", len(subs)))
+
+	tenant, err := tenantOf(ctx, opCreateBatch)
+	if err != nil {
+		return err
+	}
+
+	created := make(map[uuid.UUID]*domain.Subscription, len(subs))
+	for _, sub := range subs {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return repos.WrapErr(opCreateBatch, repos.KindUnknown, err)
+		}
+		sub.ID = id
+		created[id] = sub
+	}
+
+	// Batches too large for one statement are inserted in chunks, in one transaction.
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		for chunk := range slices.Chunk(subs, createBatchRows) {
+			query, args, err := r.buildCreateBatchQuery(tenant, chunk)
+			if err != nil {
+				return err
+			}
+			if err := insertBatch(ctx, db, query, args, created); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if isDuplicateErr(err) {
+			return repos.WrapErr(opCreateBatch, repos.KindDuplicate, err)
+		}
+		return repos.WrapErr(opCreateBatch, repos.KindUnknown, err)
+	}
+
+	return nil
+}
+
+// insertBatch runs a query of buildCreateBatchQuery and sets the stored fields
+// of the subscriptions in created.
+func insertBatch(ctx context.Context, db DBTX, query string, args []any, created map[uuid.UUID]*domain.Subscription) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := rows.Close(); cerr != nil {
+			log.FromCtx(ctx).Warn("failed to close sql.Rows", log.WithErr(cerr))
+		}
+	}()
+
+	for rows.Next() {
+		var id uuid.UUID
+		var version int
+		var createdAt, updatedAt time.Time
+		if err := rows.Scan(&id, &version, &createdAt, &updatedAt); err != nil {
+			return err
+		}
+		if sub, ok := created[id]; ok {
+			sub.Version, sub.CreatedAt, sub.UpdatedAt = version, createdAt, updatedAt
+		}
+	}
+	return rows.Err()
+}
+
 func (r *subsRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Subscription, error) {
 	l := log.FromCtx(ctx).With(slog.String("op", opGetByID))
 	l.Debug("getting subscription from db", slog.String("id", id.String()))
 
+	tenant, err := tenantOf(ctx, opGetByID)
+	if err != nil {
+		return nil, err
+	}
+
 	sub := &domain.Subscription{}
-	err := r.db.QueryRowContext(ctx, getByIDQuery, id).Scan(
-		&sub.ID, &sub.ServiceName, &sub.MonthlyCost, &sub.UserID,
-		&sub.StartDate, &sub.EndDate, &sub.CreatedAt, &sub.UpdatedAt,
-	)
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		return db.QueryRowContext(ctx, getByIDQuery, id, tenant).Scan(
+			&sub.ID, &sub.ServiceName, &sub.Price, &sub.BillingPeriod, &sub.Currency, &sub.UserID,
+			&sub.StartDate, &sub.EndDate, &sub.TrialEnd, &sub.Version, &sub.CreatedAt, &sub.UpdatedAt,
+		)
+	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, repos.WrapErr(opGetByID, repos.KindNotFound, err)
@@ -81,97 +174,408 @@ func (r *subsRepo) Update(ctx context.Context, sub *domain.Subscription) error {
 	l := log.FromCtx(ctx).With(slog.String("op", opUpdate))
 	l.Debug("updating subscription in db", slog.String("id", sub.ID.String()))
 
-	res, err := r.db.ExecContext(ctx, updateQuery, sub.ServiceName, sub.MonthlyCost, sub.UserID, sub.StartDate, sub.EndDate, sub.ID)
+	tenant, err := tenantOf(ctx, opUpdate)
 	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			return repos.WrapErr(opUpdate, repos.KindDuplicate, err)
-		}
-		return repos.WrapErr(opUpdate, repos.KindUnknown, err)
+		return err
 	}
 
-	rowsAffected, err := res.RowsAffected()
+	var rowsAffected int64
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		res, err := db.ExecContext(ctx, updateQuery, sub.ServiceName, sub.Price, string(sub.BillingPeriod), sub.Currency, sub.UserID, sub.StartDate, sub.EndDate, sub.TrialEnd, sub.ID, sub.Version, tenant)
+		if err != nil {
+			return err
+		}
+		rowsAffected, err = res.RowsAffected()
+		return err
+	})
 	if err != nil {
+		if isDuplicateErr(err) {
+			return repos.WrapErr(opUpdate, repos.KindDuplicate, err)
+		}
 		return repos.WrapErr(opUpdate, repos.KindUnknown, err)
 	}
 	if rowsAffected == 0 {
-		return repos.NewErr(opUpdate, repos.KindNotFound)
+		return r.notFoundOrConflict(ctx, opUpdate, tenant, sub.ID)
 	}
+	sub.Version++
 
 	return nil
 }
 
-func (r *subsRepo) Delete(ctx context.Context, id uuid.UUID) error {
+func (r *subsRepo) Delete(ctx context.Context, id uuid.UUID, version *int) (*domain.Subscription, error) {
 	l := log.FromCtx(ctx).With(slog.String("op", opDelete))
 	l.Debug("deleting subscription from db", slog.String("id", id.String()))
 
-	res, err := r.db.ExecContext(ctx, deleteQuery, id)
+	tenant, err := tenantOf(ctx, opDelete)
 	if err != nil {
-		return repos.WrapErr(opDelete, repos.KindUnknown, err)
+		return nil, err
 	}
 
-	rowsAffected, err := res.RowsAffected()
+	sub := &domain.Subscription{}
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		return db.QueryRowContext(ctx, deleteQuery, id, version, tenant).Scan(
+			&sub.ID, &sub.ServiceName, &sub.Price, &sub.BillingPeriod, &sub.Currency, &sub.UserID,
+			&sub.StartDate, &sub.EndDate, &sub.TrialEnd, &sub.Version, &sub.CreatedAt, &sub.UpdatedAt,
+		)
+	})
 	if err != nil {
-		return repos.WrapErr(opDelete, repos.KindUnknown, err)
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, repos.WrapErr(opDelete, repos.KindUnknown, err)
+		}
+		if version == nil {
+			return nil, repos.NewErr(opDelete, repos.KindNotFound)
+		}
+		return nil, r.notFoundOrConflict(ctx, opDelete, tenant, id)
 	}
-	if rowsAffected == 0 {
-		return repos.NewErr(opDelete, repos.KindNotFound)
+
+	return sub, nil
+}
+
+// notFoundOrConflict tells why a write of op guarded by the version of the
+// subscription of tenant with id affected no rows.
+func (r *subsRepo) notFoundOrConflict(ctx context.Context, op, tenant string, id uuid.UUID) error {
+	var exists bool
+	err := inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		return db.QueryRowContext(ctx, existsQuery, id, tenant).Scan(&exists)
+	})
+	if err != nil {
+		return repos.WrapErr(op, repos.KindUnknown, err)
 	}
+	if !exists {
+		return repos.NewErr(op, repos.KindNotFound)
+	}
+	return repos.NewErr(op, repos.KindConflict)
+}
 
-	return nil
+func (r *subsRepo) DeleteBatch(ctx context.Context, ids []uuid.UUID, versions []int) ([]domain.Subscription, error) {
+	l := log.FromCtx(ctx).With(slog.String("op", opDeleteBatch))
+	l.Debug("deleting subscriptions from db", slog.Int("count", len(ids)))
+
+	tenant, err := tenantOf(ctx, opDeleteBatch)
+	if err != nil {
+		return nil, err
+	}
+
+	deleted := make([]domain.Subscription, 0, len(ids))
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		rows, err := db.QueryContext(ctx, deleteBatchQuery, ids, versions, tenant)
+		if err != nil {
+			return err
+		}
+		deleted, err = scanSubscriptions(ctx, rows, deleted)
+		return err
+	})
+	if err != nil {
+		return nil, repos.WrapErr(opDeleteBatch, repos.KindUnknown, err)
+	}
+
+	return deleted, nil
+}
+
+// scanSubscriptions appends the subscriptions of rows to subs and closes rows.
+func scanSubscriptions(ctx context.Context, rows *sql.Rows, subs []domain.Subscription) ([]domain.Subscription, error) {
+	defer func() {
+		if cerr := rows.Close(); cerr != nil {
+			log.FromCtx(ctx).Warn("failed to close sql.Rows", log.WithErr(cerr))
+		}
+	}()
+
+	for rows.Next() {
+		sub, err := scanSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return subs, nil
+}
+
+func scanSubscription(rows *sql.Rows) (domain.Subscription, error) {
+	var sub domain.Subscription
+	err := rows.Scan(
+		&sub.ID, &sub.ServiceName, &sub.Price, &sub.BillingPeriod, &sub.Currency, &sub.UserID,
+		&sub.StartDate, &sub.EndDate, &sub.TrialEnd, &sub.Version, &sub.CreatedAt, &sub.UpdatedAt,
+	)
+	return sub, err
+}
+
+// isDuplicateErr reports whether err violates a unique constraint or the
+// exclusion constraint of overlapping subscriptions.
+func isDuplicateErr(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && (pgErr.Code == "23505" || pgErr.Code == "23P01")
+}
+
+func (r *subsRepo) FindOverlapping(ctx context.Context, sub *domain.Subscription) (uuid.UUID, error) {
+	l := log.FromCtx(ctx).With(slog.String("op", opFindOverlapping))
+	l.Debug(
+		"finding overlapping subscription in db",
+		slog.String("service_name", sub.ServiceName),
+		slog.String("user_id", sub.UserID.String()),
+	)
+
+	tenant, err := tenantOf(ctx, opFindOverlapping)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	var id uuid.UUID
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		return db.QueryRowContext(ctx, findOverlappingQuery, sub.UserID, sub.ServiceName, sub.ID, sub.StartDate, sub.EndDate, tenant).
+			Scan(&id)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, repos.WrapErr(opFindOverlapping, repos.KindNotFound, err)
+		}
+		return uuid.Nil, repos.WrapErr(opFindOverlapping, repos.KindUnknown, err)
+	}
+
+	return id, nil
 }
 
 func (r *subsRepo) List(
 	ctx context.Context,
 	filter domain.SubscriptionFilter,
-) ([]domain.Subscription, error) {
-	return r.listSubs(ctx, filter, opList, r.buildListQuery)
+) (*domain.SubscriptionPage, error) {
+	pageSize := r.pageSize(filter.PageSize)
+	subs, err := r.listSubs(ctx, filter, opList, func(tenant string, filter domain.SubscriptionFilter) (string, []any, error) {
+		return r.buildListQuery(tenant, filter, pageSize)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &domain.SubscriptionPage{Items: subs, PageSize: pageSize}
+	if filter.After == nil {
+		page.Page = pageNumber(filter)
+	}
+	if len(subs) > pageSize {
+		page.Items = subs[:pageSize]
+		next := domain.CursorOf(page.Items[pageSize-1])
+		page.NextCursor = &next
+	}
+
+	return page, nil
 }
 
 func (r *subsRepo) ListAll(
 	ctx context.Context,
 	filter domain.SubscriptionFilter,
 ) ([]domain.Subscription, error) {
-	return r.listSubs(ctx, filter, opListAll, r.buildListAllQuery)
+	subs, err := r.listSubs(ctx, filter, opListAll, r.buildListAllQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	tenant, err := tenantOf(ctx, opListAll)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.attachPriceHistory(ctx, tenant, filter, subs); err != nil {
+		return nil, repos.WrapErr(opListAll, repos.KindUnknown, err)
+	}
+	if err := r.attachPauses(ctx, tenant, filter, subs); err != nil {
+		return nil, repos.WrapErr(opListAll, repos.KindUnknown, err)
+	}
+
+	return subs, nil
+}
+
+func (r *subsRepo) Stream(ctx context.Context, filter domain.SubscriptionFilter) iter.Seq2[domain.Subscription, error] {
+	return func(yield func(domain.Subscription, error) bool) {
+		l := log.FromCtx(ctx).With(slog.String("op", opStream))
+		l.Debug("streaming subscriptions from db", slog.Any("filter", filter))
+
+		tenant, err := tenantOf(ctx, opStream)
+		if err != nil {
+			yield(domain.Subscription{}, err)
+			return
+		}
+
+		query, args, err := r.buildStreamQuery(tenant, filter)
+		if err != nil {
+			yield(domain.Subscription{}, repos.WrapErr(opStream, repos.KindUnknown, err))
+			return
+		}
+
+		stopped := false
+		err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+			rows, err := db.QueryContext(ctx, query, args...)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if cerr := rows.Close(); cerr != nil {
+					log.FromCtx(ctx).Warn("failed to close sql.Rows", log.WithErr(cerr))
+				}
+			}()
+
+			for rows.Next() {
+				sub, err := scanSubscription(rows)
+				if err != nil {
+					return err
+				}
+				if !yield(sub, nil) {
+					stopped = true
+					return nil
+				}
+			}
+			return rows.Err()
+		})
+		if err != nil && !stopped {
+			yield(domain.Subscription{}, repos.WrapErr(opStream, repos.KindUnknown, err))
+		}
+	}
+}
+
+func (r *subsRepo) Count(ctx context.Context, filter domain.SubscriptionFilter) (int, error) {
+	l := log.FromCtx(ctx).With(slog.String("op", opCount))
+	l.Debug("counting subscriptions in db", slog.Any("filter", filter))
+
+	tenant, err := tenantOf(ctx, opCount)
+	if err != nil {
+		return 0, err
+	}
+
+	query, args, err := r.buildCountQuery(tenant, filter)
+	if err != nil {
+		return 0, repos.WrapErr(opCount, repos.KindUnknown, err)
+	}
+
+	var count int
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		return db.QueryRowContext(ctx, query, args...).Scan(&count)
+	})
+	if err != nil {
+		return 0, repos.WrapErr(opCount, repos.KindUnknown, err)
+	}
+
+	return count, nil
+}
+
+func (r *subsRepo) SuggestServiceNames(ctx context.Context, query string, limit *int) ([]string, error) {
+	l := log.FromCtx(ctx).With(slog.String("op", opSuggestServiceNames))
+	l.Debug("suggesting service names from db", slog.String("query", query))
+
+	tenant, err := tenantOf(ctx, opSuggestServiceNames)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlQuery, args, err := r.buildSuggestQuery(tenant, query, r.pageSize(limit))
+	if err != nil {
+		return nil, repos.WrapErr(opSuggestServiceNames, repos.KindUnknown, err)
+	}
+
+	names := make([]string, 0)
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		rows, err := db.QueryContext(ctx, sqlQuery, args...)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := rows.Close(); cerr != nil {
+				log.FromCtx(ctx).Warn("failed to close sql.Rows", log.WithErr(cerr))
+			}
+		}()
+
+		for rows.Next() {
+			var name string
+			if err := rows.Scan(&name); err != nil {
+				return err
+			}
+			names = append(names, name)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, repos.WrapErr(opSuggestServiceNames, repos.KindUnknown, err)
+	}
+
+	return names, nil
+}
+
+func (r *subsRepo) TotalCostByCurrency(
+	ctx context.Context,
+	filter domain.SubscriptionFilter,
+	start, end time.Time,
+) (map[string]int, error) {
+	l := log.FromCtx(ctx).With(slog.String("op", opTotalCostByCurrency))
+	l.Debug("calculating total cost in db", slog.Any("filter", filter), slog.Time("start", start), slog.Time("end", end))
+
+	tenant, err := tenantOf(ctx, opTotalCostByCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := r.buildTotalCostQuery(tenant, filter, start, end)
+	if err != nil {
+		return nil, repos.WrapErr(opTotalCostByCurrency, repos.KindUnknown, err)
+	}
+
+	totals := make(map[string]int)
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		rows, err := db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := rows.Close(); cerr != nil {
+				log.FromCtx(ctx).Warn("failed to close sql.Rows", log.WithErr(cerr))
+			}
+		}()
+
+		for rows.Next() {
+			var currency string
+			var total int
+			if err := rows.Scan(&currency, &total); err != nil {
+				return err
+			}
+			totals[currency] = total
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, repos.WrapErr(opTotalCostByCurrency, repos.KindUnknown, err)
+	}
+
+	return totals, nil
 }
 
 func (r *subsRepo) listSubs(
 	ctx context.Context,
 	filter domain.SubscriptionFilter,
 	op string,
-	queryBuilder func(domain.SubscriptionFilter) (string, []any, error),
+	queryBuilder func(tenant string, filter domain.SubscriptionFilter) (string, []any, error),
 ) ([]domain.Subscription, error) {
 	l := log.FromCtx(ctx).With(slog.String("op", op))
 	l.Debug("listing subscriptions from db", slog.Any("filter", filter))
 
-	query, args, err := queryBuilder(filter)
+	tenant, err := tenantOf(ctx, op)
 	if err != nil {
-		return nil, repos.WrapErr(op, repos.KindUnknown, err)
+		return nil, err
 	}
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	query, args, err := queryBuilder(tenant, filter)
 	if err != nil {
 		return nil, repos.WrapErr(op, repos.KindUnknown, err)
 	}
-	defer func() {
-		if cerr := rows.Close(); cerr != nil {
-			log.FromCtx(ctx).Warn("failed to close sql.Rows", log.WithErr(cerr))
-		}
-	}()
 
 	subs := make([]domain.Subscription, 0)
-	for rows.Next() {
-		var sub domain.Subscription
-		if err := rows.Scan(
-			&sub.ID, &sub.ServiceName, &sub.MonthlyCost, &sub.UserID,
-			&sub.StartDate, &sub.EndDate, &sub.CreatedAt, &sub.UpdatedAt,
-		); err != nil {
-			return nil, repos.WrapErr(op, repos.KindUnknown, err)
+	err = inTenant(ctx, r.db, tenant, func(db DBTX) error {
+		rows, err := db.QueryContext(ctx, query, args...)
+		if err != nil {
+			return err
 		}
-		subs = append(subs, sub)
-	}
-
-	if err = rows.Err(); err != nil {
+		subs, err = scanSubscriptions(ctx, rows, subs)
+		return err
+	})
+	if err != nil {
 		return nil, repos.WrapErr(op, repos.KindUnknown, err)
 	}
 
@@ -1,83 +1,426 @@
 package postgres
 
 import (
+	"fmt"
+	"strings"
+	"time"
+
 	"github.com/Masterminds/squirrel"
 	"github.com/shrtyk/subscriptions-service/internal/core/domain"
 )
 
 const (
 	createQuery = `
-		INSERT INTO subscriptions (service_name, monthly_cost, user_id, start_date, end_date)
-		VALUES ($1, $2, $3, $4, $5)
-		RETURNING id, created_at, updated_at;
+		WITH created AS (
+			INSERT INTO subscriptions (service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, exclusive, tenant_id)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+			RETURNING id, price, start_date, version, created_at, updated_at
+		), initial_price AS (
+			INSERT INTO subscription_prices (subscription_id, effective_from, price)
+			SELECT id, date_trunc('month', start_date)::date, price FROM created
+		)
+		SELECT id, version, created_at, updated_at FROM created;
 	`
 
 	getByIDQuery = `
-		SELECT id, service_name, monthly_cost, user_id, start_date, end_date, created_at, updated_at
+		SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at
 		FROM subscriptions
-		WHERE id = $1;
+		WHERE id = $1 AND tenant_id = $2;
 	`
 
 	updateQuery = `
 		UPDATE subscriptions
-		SET service_name = $1, monthly_cost = $2, user_id = $3, start_date = $4, end_date = $5, updated_at = NOW()
-		WHERE id = $6;
+		SET service_name = $1, price = $2, billing_period = $3, currency = $4, user_id = $5, start_date = $6, end_date = $7, trial_end = $8,
+			version = version + 1, updated_at = NOW()
+		WHERE id = $9 AND version = $10 AND tenant_id = $11;
 	`
 
 	deleteQuery = `
-		DELETE FROM subscriptions WHERE id = $1;
+		DELETE FROM subscriptions WHERE id = $1 AND ($2::integer IS NULL OR version = $2) AND tenant_id = $3
+		RETURNING id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at;
+	`
+
+	existsQuery = `
+		SELECT EXISTS (SELECT 1 FROM subscriptions WHERE id = $1 AND tenant_id = $2);
+	`
+
+	// deleteBatchQuery pairs the ids of $1 with the versions of $2.
+	deleteBatchQuery = `
+		DELETE FROM subscriptions s
+		USING unnest($1::uuid[], $2::int[]) AS v (id, version)
+		WHERE s.id = v.id AND s.version = v.version AND s.tenant_id = $3
+		RETURNING s.id, s.service_name, s.price, s.billing_period, s.currency, s.user_id, s.start_date, s.end_date, s.trial_end, s.version, s.created_at, s.updated_at;
+	`
+
+	// createBatchQuery records the initial prices of the rows of a multi-row
+	// insert like createQuery does for one.
+	createBatchQuery = `
+		WITH created AS (
+			%s
+		), initial_price AS (
+			INSERT INTO subscription_prices (subscription_id, effective_from, price)
+			SELECT id, date_trunc('month', start_date)::date, price FROM created
+		)
+		SELECT id, version, created_at, updated_at FROM created;
 	`
+
+	findOverlappingQuery = `
+		SELECT id FROM subscriptions
+		WHERE exclusive AND user_id = $1 AND service_name = $2 AND id <> $3
+			AND daterange(start_date, end_date, '[]') && daterange($4::date, $5::date, '[]') AND tenant_id = $6
+		ORDER BY start_date
+		LIMIT 1;
+	`
+)
+
+// totalCostColumn counts the billing dates (start_date plus a whole number of
+// billing periods) within [billing_start, billing_end] as the charges up to
+// billing_end minus the ones before billing_start. sign is -1 for the windows
+// of paused months.
+var totalCostColumn = "COALESCE(SUM(GREATEST(CASE billing_period " +
+	"WHEN 'weekly' THEN FLOOR((billing_end - start_date) / 7.0) - FLOOR((billing_start - 1 - start_date) / 7.0) " +
+	"ELSE FLOOR(" + monthsUntil("billing_end") + " / " + periodMonths + ") - " +
+	"FLOOR(" + monthsUntil("(billing_start - 1)") + " / " + periodMonths + ") " +
+	"END, 0) * price * sign), 0)::bigint"
+
+const (
+	inTrialCond = "start_date <= CURRENT_DATE AND trial_end >= CURRENT_DATE"
+
+	periodMonths = "(CASE billing_period WHEN 'quarterly' THEN 3 WHEN 'yearly' THEN 12 ELSE 1 END)"
+
+	// pricePeriodsTable turns price changes into [effective_from, effective_to] ranges,
+	// effective_to is NULL for the current price.
+	pricePeriodsTable = "(SELECT subscription_id, price, effective_from, " +
+		"LEAD(effective_from) OVER (PARTITION BY subscription_id ORDER BY effective_from) - 1 AS effective_to " +
+		"FROM subscription_prices)"
 )
 
-func (r *subsRepo) buildListQuery(filter domain.SubscriptionFilter) (string, []any, error) {
+// monthsUntil mirrors its service layer counterpart: whole months from start_date
+// to date, with billing days clamped to the length of shorter months.
+func monthsUntil(date string) string {
+	return "((EXTRACT(YEAR FROM " + date + ") - EXTRACT(YEAR FROM start_date)) * 12 + " +
+		"EXTRACT(MONTH FROM " + date + ") - EXTRACT(MONTH FROM start_date) - " +
+		"CASE WHEN EXTRACT(DAY FROM " + date + ") < LEAST(EXTRACT(DAY FROM start_date), " +
+		"EXTRACT(DAY FROM " + endOfMonthExpr(date) + ")) THEN 1 ELSE 0 END)"
+}
+
+// buildListQuery selects one row more than pageSize to tell whether there is a next page.
+// The cursor in filter takes precedence over the page.
+func (r *subsRepo) buildListQuery(tenant string, filter domain.SubscriptionFilter, pageSize int) (string, []any, error) {
 	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
 
 	queryBuilder := psql.Select(
-		"id", "service_name", "monthly_cost", "user_id",
-		"start_date", "end_date", "created_at", "updated_at",
+		"id", "service_name", "price", "billing_period", "currency", "user_id",
+		"start_date", "end_date", "trial_end", "version", "created_at", "updated_at",
 	).From("subscriptions")
 
-	queryBuilder = applyFilter(queryBuilder, filter)
+	sort := filter.Sort
+	if len(sort) == 0 {
+		sort = defaultSort
+	}
 
-	pageSize := uint64(r.cfg.DefaultPageSize)
-	if filter.PageSize != nil && *filter.PageSize > 0 {
-		if *filter.PageSize > r.cfg.MaxPageSize {
-			pageSize = uint64(r.cfg.MaxPageSize)
-		} else {
-			pageSize = uint64(*filter.PageSize)
-		}
+	queryBuilder = applyFilter(queryBuilder, tenant, filter).
+		OrderBy(orderByClauses(sort)...).
+		Limit(uint64(pageSize) + 1)
+
+	if filter.After != nil {
+		queryBuilder = queryBuilder.Where(afterCursor(sort, *filter.After))
+		return queryBuilder.ToSql()
+	}
+
+	queryBuilder = queryBuilder.Offset(uint64((pageNumber(filter) - 1) * pageSize))
+
+	return queryBuilder.ToSql()
+}
+
+// maxBindParams is the most parameters the Postgres protocol lets a statement have.
+const maxBindParams = 65535
+
+var (
+	createBatchColumns = []string{
+		"id", "service_name", "price", "billing_period", "currency", "user_id",
+		"start_date", "end_date", "trial_end", "exclusive", "tenant_id",
+	}
+	// createBatchRows is the most rows one insert of CreateBatch can have.
+	createBatchRows = maxBindParams / len(createBatchColumns)
+)
+
+// buildCreateBatchQuery inserts subs with the ids they already have, so the
+// returned rows can be matched to them. subs must fit createBatchRows.
+func (r *subsRepo) buildCreateBatchQuery(tenant string, subs []*domain.Subscription) (string, []any, error) {
+	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+	queryBuilder := psql.Insert("subscriptions").Columns(createBatchColumns...)
+	for _, sub := range subs {
+		queryBuilder = queryBuilder.Values(
+			sub.ID, sub.ServiceName, sub.Price, string(sub.BillingPeriod), sub.Currency, sub.UserID,
+			sub.StartDate, sub.EndDate, sub.TrialEnd, r.cfg.ExclusiveSubscriptions, tenant,
+		)
 	}
-	queryBuilder = queryBuilder.Limit(pageSize)
 
-	page := 1
+	insert, args, err := queryBuilder.Suffix("RETURNING id, price, start_date, version, created_at, updated_at").ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+	return fmt.Sprintf(createBatchQuery, insert), args, nil
+}
+
+// pageNumber returns the 1-based page of filter.
+func pageNumber(filter domain.SubscriptionFilter) int {
 	if filter.Page != nil && *filter.Page > 0 {
-		page = *filter.Page
+		return *filter.Page
+	}
+	return 1
+}
+
+// buildCountQuery counts the subscriptions matching filter, ignoring its sort and pagination.
+func (r *subsRepo) buildCountQuery(tenant string, filter domain.SubscriptionFilter) (string, []any, error) {
+	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	return applyFilter(psql.Select("COUNT(*)").From("subscriptions"), tenant, filter).ToSql()
+}
+
+var defaultSort = []domain.SortKey{{Field: domain.SortCreatedAt}}
+
+// sortColumn is a sort field as an expression over subscriptions and over the
+// values of a cursor.
+type sortColumn struct {
+	expr   string
+	cursor func(c domain.Cursor) squirrel.Sqlizer
+}
+
+var sortColumns = map[domain.SortField]sortColumn{
+	domain.SortCreatedAt: {
+		expr:   "created_at",
+		cursor: func(c domain.Cursor) squirrel.Sqlizer { return squirrel.Expr("?", c.CreatedAt) },
+	},
+	domain.SortStartDate: {
+		expr:   "start_date",
+		cursor: func(c domain.Cursor) squirrel.Sqlizer { return squirrel.Expr("?", c.StartDate) },
+	},
+	domain.SortServiceName: {
+		expr:   "service_name",
+		cursor: func(c domain.Cursor) squirrel.Sqlizer { return squirrel.Expr("?", c.ServiceName) },
+	},
+	domain.SortMonthlyCost: {
+		expr: monthlyCostExpr("price", "billing_period"),
+		cursor: func(c domain.Cursor) squirrel.Sqlizer {
+			return squirrel.Expr(monthlyCostExpr("?::integer", "?::billing_period"), c.Price, string(c.BillingPeriod))
+		},
+	},
+}
+
+// monthlyCostExpr mirrors Subscription.MonthlyRate.
+func monthlyCostExpr(price, period string) string {
+	return "(" + price + "::numeric / CASE " + period + " WHEN 'weekly' THEN 12 / 52.0 " +
+		"WHEN 'quarterly' THEN 3 WHEN 'yearly' THEN 12 ELSE 1 END)"
+}
+
+// orderByClauses orders by the keys and then by id in the direction of the last key.
+func orderByClauses(sort []domain.SortKey) []string {
+	clauses := make([]string, 0, len(sort)+1)
+	for _, key := range sort {
+		clauses = append(clauses, sortColumns[key.Field].expr+direction(key.Desc))
+	}
+	return append(clauses, "id"+direction(sort[len(sort)-1].Desc))
+}
+
+func direction(desc bool) string {
+	if desc {
+		return " DESC"
 	}
-	offset := uint64((page - 1) * int(pageSize))
-	queryBuilder = queryBuilder.Offset(offset)
+	return ""
+}
+
+// afterCursor selects the rows after c in sort order. Keys of one direction
+// compare as a single row, which can use an index, mixed ones are expanded into
+// (k1 > v1) OR (k1 = v1 AND k2 > v2) OR ...
+func afterCursor(sort []domain.SortKey, c domain.Cursor) squirrel.Sqlizer {
+	type key struct {
+		expr, op string
+		value    squirrel.Sqlizer
+	}
+	keys := make([]key, 0, len(sort)+1)
+	mixed := false
+	for _, k := range sort {
+		keys = append(keys, key{sortColumns[k.Field].expr, comparison(k.Desc), sortColumns[k.Field].cursor(c)})
+		mixed = mixed || k.Desc != sort[0].Desc
+	}
+	keys = append(keys, key{"id", comparison(sort[len(sort)-1].Desc), squirrel.Expr("?", c.ID)})
+
+	if !mixed {
+		exprs := make([]string, 0, len(keys))
+		values := make([]any, 0, len(keys))
+		for _, k := range keys {
+			exprs = append(exprs, k.expr)
+			values = append(values, k.value)
+		}
+		return squirrel.Expr("("+strings.Join(exprs, ", ")+") "+keys[0].op+" ("+
+			strings.TrimSuffix(strings.Repeat("?, ", len(values)), ", ")+")", values...)
+	}
+
+	after := make(squirrel.Or, 0, len(keys))
+	for i, k := range keys {
+		and := make(squirrel.And, 0, i+1)
+		for _, prev := range keys[:i] {
+			and = append(and, squirrel.Expr(prev.expr+" = ?", prev.value))
+		}
+		after = append(after, append(and, squirrel.Expr(k.expr+" "+k.op+" ?", k.value)))
+	}
+	return after
+}
+
+func comparison(desc bool) string {
+	if desc {
+		return "<"
+	}
+	return ">"
+}
+
+// pageSize returns the requested page size, limited to the configured maximum.
+func (r *subsRepo) pageSize(size *int) int {
+	if size == nil || *size <= 0 {
+		return r.cfg.DefaultPageSize
+	}
+	return min(*size, r.cfg.MaxPageSize)
+}
+
+// buildSuggestQuery ranks the names that start with query before the most used ones.
+func (r *subsRepo) buildSuggestQuery(tenant, query string, limit int) (string, []any, error) {
+	escaped := escapeLike(query)
+	return squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("service_name").
+		From("subscriptions").
+		Where(squirrel.Eq{"tenant_id": tenant}).
+		Where("service_name ILIKE ?", "%"+escaped+"%").
+		GroupBy("service_name").
+		OrderByClause("service_name ILIKE ? DESC", escaped+"%").
+		OrderBy("COUNT(*) DESC", "service_name").
+		Limit(uint64(limit)).
+		ToSql()
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike makes s match literally in a LIKE pattern.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+func (r *subsRepo) buildListAllQuery(tenant string, filter domain.SubscriptionFilter) (string, []any, error) {
+	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+	queryBuilder := psql.Select(
+		"id", "service_name", "price", "billing_period", "currency", "user_id",
+		"start_date", "end_date", "trial_end", "version", "created_at", "updated_at",
+	).From("subscriptions")
+
+	queryBuilder = applyFilter(queryBuilder, tenant, filter)
 
 	return queryBuilder.ToSql()
 }
 
-func (r *subsRepo) buildListAllQuery(filter domain.SubscriptionFilter) (string, []any, error) {
+// buildStreamQuery is buildListQuery without the pagination.
+func (r *subsRepo) buildStreamQuery(tenant string, filter domain.SubscriptionFilter) (string, []any, error) {
 	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
 
 	queryBuilder := psql.Select(
-		"id", "service_name", "monthly_cost", "user_id",
-		"start_date", "end_date", "created_at", "updated_at",
+		"id", "service_name", "price", "billing_period", "currency", "user_id",
+		"start_date", "end_date", "trial_end", "version", "created_at", "updated_at",
 	).From("subscriptions")
 
-	queryBuilder = applyFilter(queryBuilder, filter)
+	sort := filter.Sort
+	if len(sort) == 0 {
+		sort = defaultSort
+	}
+
+	return applyFilter(queryBuilder, tenant, filter).OrderBy(orderByClauses(sort)...).ToSql()
+}
+
+// buildTotalCostQuery mirrors the charge counting of the service layer: every
+// subscription is billed on each of its billing dates within its active days in
+// the months from start to end, at the price in effect on that date. Charges in
+// paused months are subtracted, pauses of a subscription never overlap.
+// Totals are grouped by currency, conversion is left to the caller. The filter
+// selects the subscriptions apart from the joins, so it sees their own columns.
+func (r *subsRepo) buildTotalCostQuery(tenant string, filter domain.SubscriptionFilter, start, end time.Time) (string, []any, error) {
+	subIDs := applyFilter(squirrel.Select("id").From("subscriptions"), tenant, filter)
+
+	paused := chargeWindows("-1", "p.effective_from, ps.paused_from", "p.effective_to, ps.resumed_from - 1", start, end).
+		Join("subscription_pauses ps ON ps.subscription_id = s.id").
+		Where(squirrel.Expr("s.id IN (?)", subIDs))
+
+	billed := chargeWindows("1", "p.effective_from", "p.effective_to", start, end).
+		Where(squirrel.Expr("s.id IN (?)", subIDs)).
+		SuffixExpr(squirrel.Expr("UNION ALL ?", paused))
+
+	queryBuilder := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar).
+		Select("currency", totalCostColumn).
+		FromSelect(billed, "billed").
+		GroupBy("currency")
 
 	return queryBuilder.ToSql()
 }
 
-func applyFilter(queryBuilder squirrel.SelectBuilder, filter domain.SubscriptionFilter) squirrel.SelectBuilder {
+// chargeWindows selects the days from the latest of the from bounds to the earliest
+// of the to bounds of every price period, limited to the subscription after its
+// trial and the months from start to end.
+func chargeWindows(sign, from, to string, start, end time.Time) squirrel.SelectBuilder {
+	return squirrel.Select("p.price", "s.billing_period", "s.currency", "s.start_date", sign+" AS sign").
+		Column(squirrel.Expr("GREATEST(s.start_date, s.trial_end + 1, "+from+", date_trunc('month', ?::date)::date) AS billing_start", start)).
+		Column(squirrel.Expr("LEAST(s.end_date, "+to+", "+endOfMonthExpr("?::date")+") AS billing_end", end)).
+		From("subscriptions s").
+		Join(pricePeriodsTable + " p ON p.subscription_id = s.id")
+}
+
+func endOfMonthExpr(date string) string {
+	return "(date_trunc('month', " + date + ") + interval '1 month - 1 day')::date"
+}
+
+// applyFilter limits queryBuilder to the subscriptions of tenant matching filter.
+func applyFilter(queryBuilder squirrel.SelectBuilder, tenant string, filter domain.SubscriptionFilter) squirrel.SelectBuilder {
+	queryBuilder = queryBuilder.Where(squirrel.Eq{"tenant_id": tenant})
 	if filter.UserID != nil {
 		queryBuilder = queryBuilder.Where(squirrel.Eq{"user_id": *filter.UserID})
 	}
 	if filter.ServiceName != nil {
 		queryBuilder = queryBuilder.Where(squirrel.Eq{"service_name": *filter.ServiceName})
 	}
+	if filter.Query != nil {
+		queryBuilder = queryBuilder.Where("service_name ILIKE ?", "%"+escapeLike(*filter.Query)+"%")
+	}
+	if filter.InTrial != nil {
+		if *filter.InTrial {
+			queryBuilder = queryBuilder.Where(inTrialCond)
+		} else {
+			queryBuilder = queryBuilder.Where("NOT COALESCE(" + inTrialCond + ", false)")
+		}
+	}
+	if filter.ActiveAt != nil {
+		queryBuilder = queryBuilder.Where(
+			"start_date <= "+endOfMonthExpr("?::date")+" AND (end_date IS NULL OR end_date >= date_trunc('month', ?::date)::date)",
+			*filter.ActiveAt, *filter.ActiveAt)
+	}
+	if filter.MinCost != nil {
+		queryBuilder = queryBuilder.Where("ROUND("+monthlyCostExpr("price", "billing_period")+") >= ?", *filter.MinCost)
+	}
+	if filter.MaxCost != nil {
+		queryBuilder = queryBuilder.Where("ROUND("+monthlyCostExpr("price", "billing_period")+") <= ?", *filter.MaxCost)
+	}
+	if filter.StartedAfter != nil {
+		queryBuilder = queryBuilder.Where(squirrel.Gt{"start_date": *filter.StartedAfter})
+	}
+	if filter.StartedBefore != nil {
+		queryBuilder = queryBuilder.Where(squirrel.Lt{"start_date": *filter.StartedBefore})
+	}
+	if filter.EndsBefore != nil {
+		queryBuilder = queryBuilder.Where(squirrel.Lt{"end_date": *filter.EndsBefore})
+	}
+	if filter.HasEndDate != nil {
+		if *filter.HasEndDate {
+			queryBuilder = queryBuilder.Where("end_date IS NOT NULL")
+		} else {
+			queryBuilder = queryBuilder.Where("end_date IS NULL")
+		}
+	}
 	return queryBuilder
 }
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"database/sql/driver"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -22,24 +23,25 @@ import (
 
 func setup(t *testing.T) (*subsRepo, sqlmock.Sqlmock) {
 	t.Helper()
-	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
-	require.NoError(t, err)
+	tx, mock := setupTx(t)
 
 	cfg := config.RepoConfig{
-		DefaultPageSize: 10,
-		MaxPageSize:     100,
+		DefaultPageSize:        10,
+		MaxPageSize:            100,
+		ExclusiveSubscriptions: true,
 	}
-	repo := NewSubsRepo(db, &cfg)
+	return NewSubsRepo(tx, &cfg), mock
+}
 
-	t.Cleanup(func() {
-		mock.ExpectClose()
-		if cerr := db.Close(); cerr != nil {
-			assert.NoError(t, cerr)
-		}
-		assert.NoError(t, mock.ExpectationsWereMet())
-	})
+const testTenant = "acme"
 
-	return repo, mock
+var subColumns = []string{
+	"id", "service_name", "price", "billing_period", "currency", "user_id", "start_date", "end_date", "trial_end", "version", "created_at", "updated_at",
+}
+
+// tenantCtx is the context of requests of testTenant.
+func tenantCtx() context.Context {
+	return domain.PrincipalToCtx(context.Background(), &domain.Principal{TenantID: testTenant})
 }
 
 func ptr[T any](value T) *T {
@@ -47,13 +49,15 @@ func ptr[T any](value T) *T {
 }
 
 func TestSubsRepo_Create(t *testing.T) {
-	ctx := context.Background()
+	ctx := tenantCtx()
 
 	sub := &domain.Subscription{
-		ServiceName: "Test Service",
-		MonthlyCost: 100,
-		UserID:      uuid.New(),
-		StartDate:   time.Now(),
+		ServiceName:   "Test Service",
+		Price:         100,
+		BillingPeriod: domain.BillingMonthly,
+		Currency:      "RUB",
+		UserID:        uuid.New(),
+		StartDate:     time.Now(),
 	}
 
 	dbErr := errors.New("generic DB error")
@@ -69,16 +73,17 @@ func TestSubsRepo_Create(t *testing.T) {
 		{
 			name: "Success",
 			setupMock: func(mock sqlmock.Sqlmock, sub *domain.Subscription) {
-				rows := sqlmock.NewRows([]string{"id", "created_at", "updated_at"}).
-					AddRow(generatedID, generatedTime, generatedTime)
+				rows := sqlmock.NewRows([]string{"id", "version", "created_at", "updated_at"}).
+					AddRow(generatedID, 1, generatedTime, generatedTime)
 
 				mock.ExpectQuery(createQuery).
-					WithArgs(sub.ServiceName, sub.MonthlyCost, sub.UserID, sub.StartDate, sub.EndDate).
+					WithArgs(sub.ServiceName, sub.Price, string(sub.BillingPeriod), sub.Currency, sub.UserID, sub.StartDate, sub.EndDate, sub.TrialEnd, true, testTenant).
 					WillReturnRows(rows)
 			},
 			assertFunc: func(t *testing.T, err error, sub *domain.Subscription) {
 				require.NoError(t, err)
 				assert.Equal(t, generatedID, sub.ID)
+				assert.Equal(t, 1, sub.Version)
 				assert.Equal(t, generatedTime, sub.CreatedAt)
 				assert.Equal(t, generatedTime, sub.UpdatedAt)
 			},
@@ -88,7 +93,21 @@ func TestSubsRepo_Create(t *testing.T) {
 			setupMock: func(mock sqlmock.Sqlmock, sub *domain.Subscription) {
 				pgErr := &pgconn.PgError{Code: "23505"}
 				mock.ExpectQuery(createQuery).
-					WithArgs(sub.ServiceName, sub.MonthlyCost, sub.UserID, sub.StartDate, sub.EndDate).
+					WithArgs(sub.ServiceName, sub.Price, string(sub.BillingPeriod), sub.Currency, sub.UserID, sub.StartDate, sub.EndDate, sub.TrialEnd, true, testTenant).
+					WillReturnError(pgErr)
+			},
+			assertFunc: func(t *testing.T, err error, sub *domain.Subscription) {
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindDuplicate, baseErr.Kind)
+			},
+		},
+		{
+			name: "Overlapping",
+			setupMock: func(mock sqlmock.Sqlmock, sub *domain.Subscription) {
+				pgErr := &pgconn.PgError{Code: "23P01"}
+				mock.ExpectQuery(createQuery).
+					WithArgs(sub.ServiceName, sub.Price, string(sub.BillingPeriod), sub.Currency, sub.UserID, sub.StartDate, sub.EndDate, sub.TrialEnd, true, testTenant).
 					WillReturnError(pgErr)
 			},
 			assertFunc: func(t *testing.T, err error, sub *domain.Subscription) {
@@ -101,7 +120,7 @@ func TestSubsRepo_Create(t *testing.T) {
 			name: "Generic DB Error",
 			setupMock: func(mock sqlmock.Sqlmock, sub *domain.Subscription) {
 				mock.ExpectQuery(createQuery).
-					WithArgs(sub.ServiceName, sub.MonthlyCost, sub.UserID, sub.StartDate, sub.EndDate).
+					WithArgs(sub.ServiceName, sub.Price, string(sub.BillingPeriod), sub.Currency, sub.UserID, sub.StartDate, sub.EndDate, sub.TrialEnd, true, testTenant).
 					WillReturnError(dbErr)
 			},
 			assertFunc: func(t *testing.T, err error, sub *domain.Subscription) {
@@ -124,16 +143,260 @@ func TestSubsRepo_Create(t *testing.T) {
 	}
 }
 
+func TestSubsRepo_FindOverlapping(t *testing.T) {
+	ctx := tenantCtx()
+	sub := &domain.Subscription{
+		ID:          uuid.New(),
+		ServiceName: "Test Service",
+		UserID:      uuid.New(),
+		StartDate:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	otherID := uuid.New()
+
+	testCases := []struct {
+		name       string
+		setupMock  func(mock sqlmock.Sqlmock)
+		assertFunc func(t *testing.T, id uuid.UUID, err error)
+	}{
+		{
+			name: "Found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(findOverlappingQuery).
+					WithArgs(sub.UserID, sub.ServiceName, sub.ID, sub.StartDate, sub.EndDate, testTenant).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(otherID))
+			},
+			assertFunc: func(t *testing.T, id uuid.UUID, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, otherID, id)
+			},
+		},
+		{
+			name: "Not Found",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(findOverlappingQuery).
+					WithArgs(sub.UserID, sub.ServiceName, sub.ID, sub.StartDate, sub.EndDate, testTenant).
+					WillReturnError(sql.ErrNoRows)
+			},
+			assertFunc: func(t *testing.T, id uuid.UUID, err error) {
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindNotFound, baseErr.Kind)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := setup(t)
+			tc.setupMock(mock)
+			id, err := repo.FindOverlapping(ctx, sub)
+			tc.assertFunc(t, id, err)
+		})
+	}
+}
+
+// capturedArg matches any argument and records the last one it matched.
+type capturedArg struct {
+	value driver.Value
+}
+
+func (a *capturedArg) Match(v driver.Value) bool {
+	a.value = v
+	return true
+}
+
+func TestSubsRepo_CreateBatch(t *testing.T) {
+	ctx := tenantCtx()
+	userID := uuid.New()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	newSubs := func() []*domain.Subscription {
+		return []*domain.Subscription{
+			{ServiceName: "Okko", Price: 400, BillingPeriod: domain.BillingMonthly, Currency: "RUB", UserID: userID, StartDate: start},
+			{ServiceName: "Ivi", Price: 300, BillingPeriod: domain.BillingYearly, Currency: "RUB", UserID: userID, StartDate: start},
+		}
+	}
+	query, _, err := (&subsRepo{cfg: &config.RepoConfig{}}).buildCreateBatchQuery(testTenant, newSubs())
+	require.NoError(t, err)
+
+	dbErr := errors.New("generic DB error")
+	createdAt := time.Now()
+
+	testCases := []struct {
+		name       string
+		setupMock  func(mock sqlmock.Sqlmock, ids []*capturedArg)
+		assertFunc func(t *testing.T, err error, subs []*domain.Subscription, ids []*capturedArg)
+	}{
+		{
+			name: "Success",
+			setupMock: func(mock sqlmock.Sqlmock, ids []*capturedArg) {
+				mock.ExpectQuery(query).
+					WithArgs(
+						ids[0], "Okko", 400, "monthly", "RUB", userID, start, nil, nil, true, testTenant,
+						ids[1], "Ivi", 300, "yearly", "RUB", userID, start, nil, nil, true, testTenant,
+					).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "version", "created_at", "updated_at"}).
+						AddRow(uuid.New(), 1, createdAt, createdAt))
+			},
+			assertFunc: func(t *testing.T, err error, subs []*domain.Subscription, ids []*capturedArg) {
+				require.NoError(t, err)
+				for i, sub := range subs {
+					assert.Equal(t, byte(7), byte(sub.ID.Version()))
+					assert.Equal(t, sub.ID.String(), ids[i].value)
+				}
+				assert.NotEqual(t, subs[0].ID, subs[1].ID)
+				// Rows of other ids are not assigned to the subscriptions.
+				assert.Zero(t, subs[0].Version)
+			},
+		},
+		{
+			name: "Overlapping",
+			setupMock: func(mock sqlmock.Sqlmock, ids []*capturedArg) {
+				mock.ExpectQuery(query).WillReturnError(&pgconn.PgError{Code: "23P01"})
+			},
+			assertFunc: func(t *testing.T, err error, subs []*domain.Subscription, ids []*capturedArg) {
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindDuplicate, baseErr.Kind)
+			},
+		},
+		{
+			name: "Generic DB Error",
+			setupMock: func(mock sqlmock.Sqlmock, ids []*capturedArg) {
+				mock.ExpectQuery(query).WillReturnError(dbErr)
+			},
+			assertFunc: func(t *testing.T, err error, subs []*domain.Subscription, ids []*capturedArg) {
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindUnknown, baseErr.Kind)
+				assert.ErrorIs(t, err, dbErr)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := setup(t)
+			ids := []*capturedArg{{}, {}}
+			tc.setupMock(mock, ids)
+			subs := newSubs()
+			err := repo.CreateBatch(ctx, subs)
+			tc.assertFunc(t, err, subs, ids)
+		})
+	}
+}
+
+func TestSubsRepo_CreateBatch_Chunks(t *testing.T) {
+	ctx := tenantCtx()
+	subs := make([]*domain.Subscription, createBatchRows+1)
+	for i := range subs {
+		subs[i] = &domain.Subscription{
+			ServiceName: "Okko", Price: 400, BillingPeriod: domain.BillingMonthly, Currency: "RUB",
+			UserID: uuid.New(), StartDate: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+	}
+	repo := &subsRepo{cfg: &config.RepoConfig{}}
+	full, args, err := repo.buildCreateBatchQuery(testTenant, subs[:createBatchRows])
+	require.NoError(t, err)
+	require.LessOrEqual(t, len(args), maxBindParams)
+	rest, _, err := repo.buildCreateBatchQuery(testTenant, subs[createBatchRows:])
+	require.NoError(t, err)
+
+	tx, mock := setupTx(t)
+	repo.db = tx
+	columns := []string{"id", "version", "created_at", "updated_at"}
+	mock.ExpectQuery(full).WillReturnRows(sqlmock.NewRows(columns))
+	mock.ExpectQuery(rest).WillReturnRows(sqlmock.NewRows(columns))
+
+	require.NoError(t, repo.CreateBatch(ctx, subs))
+}
+
+// passThroughConverter hands arguments to the driver as they are, like pgx
+// does for the slices bound to arrays.
+type passThroughConverter struct{}
+
+func (passThroughConverter) ConvertValue(v any) (driver.Value, error) {
+	return v, nil
+}
+
+func TestSubsRepo_DeleteBatch(t *testing.T) {
+	ctx := tenantCtx()
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	versions := []int{3, 1}
+	dbErr := errors.New("db error")
+	now := time.Now()
+	userID := uuid.New()
+
+	testCases := []struct {
+		name       string
+		setupMock  func(mock sqlmock.Sqlmock)
+		assertFunc func(t *testing.T, deleted []domain.Subscription, err error)
+	}{
+		{
+			name: "Success",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(deleteBatchQuery).
+					WithArgs(ids, versions, testTenant).
+					WillReturnRows(sqlmock.NewRows(subColumns).AddRow(ids[1], "Okko", 300, "monthly", "RUB", userID, now, nil, nil, 1, now, now))
+			},
+			assertFunc: func(t *testing.T, deleted []domain.Subscription, err error) {
+				require.NoError(t, err)
+				require.Len(t, deleted, 1)
+				assert.Equal(t, ids[1], deleted[0].ID)
+				assert.Equal(t, userID, deleted[0].UserID)
+			},
+		},
+		{
+			name: "Generic DB Error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(deleteBatchQuery).WithArgs(ids, versions, testTenant).WillReturnError(dbErr)
+			},
+			assertFunc: func(t *testing.T, deleted []domain.Subscription, err error) {
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindUnknown, baseErr.Kind)
+				assert.ErrorIs(t, err, dbErr)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock, err := sqlmock.New(
+				sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual),
+				sqlmock.ValueConverterOption(passThroughConverter{}),
+			)
+			require.NoError(t, err)
+			mock.ExpectBegin()
+			tx, err := db.Begin()
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				mock.ExpectRollback()
+				assert.NoError(t, tx.Rollback())
+				mock.ExpectClose()
+				assert.NoError(t, db.Close())
+				assert.NoError(t, mock.ExpectationsWereMet())
+			})
+
+			tc.setupMock(mock)
+			deleted, err := NewSubsRepo(tx, &config.RepoConfig{}).DeleteBatch(ctx, ids, versions)
+			tc.assertFunc(t, deleted, err)
+		})
+	}
+}
+
 func TestSubsRepo_GetByID(t *testing.T) {
-	ctx := context.Background()
+	ctx := tenantCtx()
 
 	subID := uuid.New()
 	expectedSub := &domain.Subscription{
-		ID:          subID,
-		ServiceName: "Test Service",
-		MonthlyCost: 100,
-		UserID:      uuid.New(),
-		StartDate:   time.Now(),
+		ID:            subID,
+		ServiceName:   "Test Service",
+		Price:         100,
+		BillingPeriod: domain.BillingMonthly,
+		Currency:      "RUB",
+		UserID:        uuid.New(),
+		StartDate:     time.Now(),
+		TrialEnd:      ptr(time.Now().AddDate(0, 1, 0)),
 	}
 
 	testCases := []struct {
@@ -147,23 +410,26 @@ func TestSubsRepo_GetByID(t *testing.T) {
 			subID: subID,
 			setupMock: func(mock sqlmock.Sqlmock, id uuid.UUID) {
 				rows := sqlmock.NewRows(
-					[]string{"id", "service_name", "monthly_cost", "user_id",
-						"start_date", "end_date", "created_at", "updated_at"}).
-					AddRow(expectedSub.ID, expectedSub.ServiceName, expectedSub.MonthlyCost,
-						expectedSub.UserID, expectedSub.StartDate, expectedSub.EndDate, time.Now(), time.Now())
-				mock.ExpectQuery(getByIDQuery).WithArgs(id).WillReturnRows(rows)
+					[]string{"id", "service_name", "price", "billing_period", "currency", "user_id",
+						"start_date", "end_date", "trial_end", "version", "created_at", "updated_at"}).
+					AddRow(expectedSub.ID, expectedSub.ServiceName, expectedSub.Price, expectedSub.BillingPeriod, expectedSub.Currency,
+						expectedSub.UserID, expectedSub.StartDate, expectedSub.EndDate, expectedSub.TrialEnd, 3, time.Now(), time.Now())
+				mock.ExpectQuery(getByIDQuery).WithArgs(id, testTenant).WillReturnRows(rows)
 			},
 			assertFunc: func(t *testing.T, sub *domain.Subscription, err error) {
 				assert.NoError(t, err)
 				assert.Equal(t, expectedSub.ID, sub.ID)
 				assert.Equal(t, expectedSub.ServiceName, sub.ServiceName)
+				assert.Equal(t, expectedSub.Currency, sub.Currency)
+				assert.Equal(t, expectedSub.TrialEnd, sub.TrialEnd)
+				assert.Equal(t, 3, sub.Version)
 			},
 		},
 		{
 			name:  "Not Found",
 			subID: uuid.New(),
 			setupMock: func(mock sqlmock.Sqlmock, id uuid.UUID) {
-				mock.ExpectQuery(getByIDQuery).WithArgs(id).WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(getByIDQuery).WithArgs(id, testTenant).WillReturnError(sql.ErrNoRows)
 			},
 			assertFunc: func(t *testing.T, sub *domain.Subscription, err error) {
 				var baseErr *errkit.BaseErr[repos.RepoKind]
@@ -177,7 +443,7 @@ func TestSubsRepo_GetByID(t *testing.T) {
 			subID: subID,
 			setupMock: func(mock sqlmock.Sqlmock, id uuid.UUID) {
 				rows := sqlmock.NewRows([]string{"id"}).AddRow("not-a-uuid")
-				mock.ExpectQuery(getByIDQuery).WithArgs(id).WillReturnRows(rows)
+				mock.ExpectQuery(getByIDQuery).WithArgs(id, testTenant).WillReturnRows(rows)
 			},
 			assertFunc: func(t *testing.T, sub *domain.Subscription, err error) {
 				var baseErr *errkit.BaseErr[repos.RepoKind]
@@ -199,14 +465,16 @@ func TestSubsRepo_GetByID(t *testing.T) {
 }
 
 func TestSubsRepo_Update(t *testing.T) {
-	ctx := context.Background()
+	ctx := tenantCtx()
 
 	sub := &domain.Subscription{
-		ID:          uuid.New(),
-		ServiceName: "Updated Service",
-		MonthlyCost: 200,
-		UserID:      uuid.New(),
-		StartDate:   time.Now(),
+		ID:            uuid.New(),
+		ServiceName:   "Updated Service",
+		Price:         200,
+		BillingPeriod: domain.BillingYearly,
+		Currency:      "USD",
+		UserID:        uuid.New(),
+		StartDate:     time.Now(),
 	}
 
 	dbErr := errors.New("db error")
@@ -221,10 +489,10 @@ func TestSubsRepo_Update(t *testing.T) {
 	}{
 		{
 			name: "Success",
-			sub:  sub,
+			sub:  &domain.Subscription{ID: uuid.New(), Version: 2},
 			setupMock: func(mock sqlmock.Sqlmock, sub *domain.Subscription) {
 				mock.ExpectExec(updateQuery).
-					WithArgs(sub.ServiceName, sub.MonthlyCost, sub.UserID, sub.StartDate, sub.EndDate, sub.ID).
+					WithArgs(sub.ServiceName, sub.Price, string(sub.BillingPeriod), sub.Currency, sub.UserID, sub.StartDate, sub.EndDate, sub.TrialEnd, sub.ID, 2, testTenant).
 					WillReturnResult(sqlmock.NewResult(1, 1))
 			},
 			assertFunc: func(t *testing.T, err error) {
@@ -236,8 +504,9 @@ func TestSubsRepo_Update(t *testing.T) {
 			sub:  sub,
 			setupMock: func(mock sqlmock.Sqlmock, sub *domain.Subscription) {
 				mock.ExpectExec(updateQuery).
-					WithArgs(sub.ServiceName, sub.MonthlyCost, sub.UserID, sub.StartDate, sub.EndDate, sub.ID).
+					WithArgs(sub.ServiceName, sub.Price, string(sub.BillingPeriod), sub.Currency, sub.UserID, sub.StartDate, sub.EndDate, sub.TrialEnd, sub.ID, sub.Version, testTenant).
 					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(existsQuery).WithArgs(sub.ID, testTenant).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
 			},
 			assertFunc: func(t *testing.T, err error) {
 				var baseErr *errkit.BaseErr[repos.RepoKind]
@@ -245,12 +514,27 @@ func TestSubsRepo_Update(t *testing.T) {
 				assert.Equal(t, repos.KindNotFound, baseErr.Kind)
 			},
 		},
+		{
+			name: "Version Conflict",
+			sub:  sub,
+			setupMock: func(mock sqlmock.Sqlmock, sub *domain.Subscription) {
+				mock.ExpectExec(updateQuery).
+					WithArgs(sub.ServiceName, sub.Price, string(sub.BillingPeriod), sub.Currency, sub.UserID, sub.StartDate, sub.EndDate, sub.TrialEnd, sub.ID, sub.Version, testTenant).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(existsQuery).WithArgs(sub.ID, testTenant).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+			assertFunc: func(t *testing.T, err error) {
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindConflict, baseErr.Kind)
+			},
+		},
 		{
 			name: "Generic DB Error",
 			sub:  sub,
 			setupMock: func(mock sqlmock.Sqlmock, sub *domain.Subscription) {
 				mock.ExpectExec(updateQuery).
-					WithArgs(sub.ServiceName, sub.MonthlyCost, sub.UserID, sub.StartDate, sub.EndDate, sub.ID).
+					WithArgs(sub.ServiceName, sub.Price, string(sub.BillingPeriod), sub.Currency, sub.UserID, sub.StartDate, sub.EndDate, sub.TrialEnd, sub.ID, sub.Version, testTenant).
 					WillReturnError(dbErr)
 			},
 			assertFunc: func(t *testing.T, err error) {
@@ -266,7 +550,7 @@ func TestSubsRepo_Update(t *testing.T) {
 			sub:  sub,
 			setupMock: func(mock sqlmock.Sqlmock, sub *domain.Subscription) {
 				mock.ExpectExec(updateQuery).
-					WithArgs(sub.ServiceName, sub.MonthlyCost, sub.UserID, sub.StartDate, sub.EndDate, sub.ID).
+					WithArgs(sub.ServiceName, sub.Price, string(sub.BillingPeriod), sub.Currency, sub.UserID, sub.StartDate, sub.EndDate, sub.TrialEnd, sub.ID, sub.Version, testTenant).
 					WillReturnResult(sqlmock.NewErrorResult(rowsAffectedErr))
 			},
 			assertFunc: func(t *testing.T, err error) {
@@ -283,46 +567,107 @@ func TestSubsRepo_Update(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			repo, mock := setup(t)
 			tc.setupMock(mock, tc.sub)
+			version := tc.sub.Version
 			err := repo.Update(ctx, tc.sub)
 			tc.assertFunc(t, err)
+			if err == nil {
+				assert.Equal(t, version+1, tc.sub.Version)
+			}
 		})
 	}
 }
 
 func TestSubsRepo_Delete(t *testing.T) {
-	ctx := context.Background()
+	ctx := tenantCtx()
 	subID := uuid.New()
 
 	dbErr := errors.New("db error")
+	now := time.Now()
+	deletedRow := func(id uuid.UUID) *sqlmock.Rows {
+		return sqlmock.NewRows(subColumns).AddRow(id, "Okko", 300, "monthly", "RUB", uuid.New(), now, nil, nil, 2, now, now)
+	}
 
 	testCases := []struct {
 		name        string
 		subID       uuid.UUID
+		version     *int
 		setupMock   func(mock sqlmock.Sqlmock, id uuid.UUID)
-		assertFunc  func(t *testing.T, err error)
+		assertFunc  func(t *testing.T, deleted *domain.Subscription, err error)
 		expectedErr error
 	}{
 		{
 			name:  "Success",
 			subID: subID,
 			setupMock: func(mock sqlmock.Sqlmock, id uuid.UUID) {
-				mock.ExpectExec(deleteQuery).
-					WithArgs(id).
-					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(deleteQuery).
+					WithArgs(id, nil, testTenant).
+					WillReturnRows(deletedRow(id))
 			},
-			assertFunc: func(t *testing.T, err error) {
-				assert.NoError(t, err)
+			assertFunc: func(t *testing.T, deleted *domain.Subscription, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, subID, deleted.ID)
+				assert.Equal(t, 2, deleted.Version)
 			},
 		},
 		{
 			name:  "Not Found",
 			subID: subID,
 			setupMock: func(mock sqlmock.Sqlmock, id uuid.UUID) {
-				mock.ExpectExec(deleteQuery).
-					WithArgs(id).
-					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(deleteQuery).
+					WithArgs(id, nil, testTenant).
+					WillReturnError(sql.ErrNoRows)
 			},
-			assertFunc: func(t *testing.T, err error) {
+			assertFunc: func(t *testing.T, deleted *domain.Subscription, err error) {
+				assert.Nil(t, deleted)
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindNotFound, baseErr.Kind)
+			},
+		},
+		{
+			name:    "Version Matches",
+			subID:   subID,
+			version: ptr(2),
+			setupMock: func(mock sqlmock.Sqlmock, id uuid.UUID) {
+				mock.ExpectQuery(deleteQuery).
+					WithArgs(id, 2, testTenant).
+					WillReturnRows(deletedRow(id))
+			},
+			assertFunc: func(t *testing.T, deleted *domain.Subscription, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, subID, deleted.ID)
+				assert.Equal(t, 2, deleted.Version)
+			},
+		},
+		{
+			name:    "Version Conflict",
+			subID:   subID,
+			version: ptr(2),
+			setupMock: func(mock sqlmock.Sqlmock, id uuid.UUID) {
+				mock.ExpectQuery(deleteQuery).
+					WithArgs(id, 2, testTenant).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(existsQuery).WithArgs(id, testTenant).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+			assertFunc: func(t *testing.T, deleted *domain.Subscription, err error) {
+				assert.Nil(t, deleted)
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindConflict, baseErr.Kind)
+			},
+		},
+		{
+			name:    "Not Found With Version",
+			subID:   subID,
+			version: ptr(2),
+			setupMock: func(mock sqlmock.Sqlmock, id uuid.UUID) {
+				mock.ExpectQuery(deleteQuery).
+					WithArgs(id, 2, testTenant).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(existsQuery).WithArgs(id, testTenant).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+			assertFunc: func(t *testing.T, deleted *domain.Subscription, err error) {
+				assert.Nil(t, deleted)
 				var baseErr *errkit.BaseErr[repos.RepoKind]
 				require.ErrorAs(t, err, &baseErr)
 				assert.Equal(t, repos.KindNotFound, baseErr.Kind)
@@ -332,11 +677,12 @@ func TestSubsRepo_Delete(t *testing.T) {
 			name:  "Generic DB Error",
 			subID: subID,
 			setupMock: func(mock sqlmock.Sqlmock, id uuid.UUID) {
-				mock.ExpectExec(deleteQuery).
-					WithArgs(id).
+				mock.ExpectQuery(deleteQuery).
+					WithArgs(id, nil, testTenant).
 					WillReturnError(dbErr)
 			},
-			assertFunc: func(t *testing.T, err error) {
+			assertFunc: func(t *testing.T, deleted *domain.Subscription, err error) {
+				assert.Nil(t, deleted)
 				var baseErr *errkit.BaseErr[repos.RepoKind]
 				require.ErrorAs(t, err, &baseErr)
 				assert.Equal(t, repos.KindUnknown, baseErr.Kind)
@@ -350,20 +696,37 @@ func TestSubsRepo_Delete(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			repo, mock := setup(t)
 			tc.setupMock(mock, tc.subID)
-			err := repo.Delete(ctx, tc.subID)
-			tc.assertFunc(t, err)
+			deleted, err := repo.Delete(ctx, tc.subID, tc.version)
+			tc.assertFunc(t, deleted, err)
 		})
 	}
 }
 
 func TestSubsRepo_List(t *testing.T) {
 	repo, mock := setup(t)
-	ctx := context.Background()
+	ctx := tenantCtx()
 
 	userID := uuid.New()
 	serviceName := "Test Service"
 
-	mockCols := []string{"id", "service_name", "monthly_cost", "user_id", "start_date", "end_date", "created_at", "updated_at"}
+	cursor := domain.Cursor{
+		CreatedAt:     time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		StartDate:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		ServiceName:   serviceName,
+		Price:         100,
+		BillingPeriod: domain.BillingMonthly,
+		ID:            uuid.New(),
+	}
+	last := domain.Subscription{
+		ID: uuid.New(), ServiceName: serviceName, Price: 100, BillingPeriod: domain.BillingMonthly, Currency: "RUB", UserID: userID,
+		StartDate: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), CreatedAt: time.Date(2025, 3, 2, 12, 0, 0, 0, time.UTC),
+	}
+	march := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	jan31 := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	monthlyCost := "(price::numeric / CASE billing_period WHEN 'weekly' THEN 12 / 52.0 WHEN 'quarterly' THEN 3 WHEN 'yearly' THEN 12 ELSE 1 END)"
+	cursorMonthlyCost := "($3::integer::numeric / CASE $4::billing_period WHEN 'weekly' THEN 12 / 52.0 WHEN 'quarterly' THEN 3 WHEN 'yearly' THEN 12 ELSE 1 END)"
+
+	mockCols := []string{"id", "service_name", "price", "billing_period", "currency", "user_id", "start_date", "end_date", "trial_end", "version", "created_at", "updated_at"}
 
 	testCases := []struct {
 		name         string
@@ -372,61 +735,146 @@ func TestSubsRepo_List(t *testing.T) {
 		expectedArgs []any
 		mockRows     *sqlmock.Rows
 		mockErr      error
+		wantNext     *domain.Cursor
 	}{
 		{
 			name:         "No filter, default pagination",
 			filter:       domain.SubscriptionFilter{},
-			expectedSQL:  "SELECT id, service_name, monthly_cost, user_id, start_date, end_date, created_at, updated_at FROM subscriptions LIMIT 10 OFFSET 0",
-			expectedArgs: []any{},
+			expectedSQL:  "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1 ORDER BY created_at, id LIMIT 11 OFFSET 0",
+			expectedArgs: []any{testTenant},
 			mockRows:     sqlmock.NewRows(mockCols),
 		},
 		{
 			name:         "Filter by UserID",
 			filter:       domain.SubscriptionFilter{UserID: ptr(userID)},
-			expectedSQL:  "SELECT id, service_name, monthly_cost, user_id, start_date, end_date, created_at, updated_at FROM subscriptions WHERE user_id = $1 LIMIT 10 OFFSET 0",
-			expectedArgs: []any{userID},
-			mockRows:     sqlmock.NewRows(mockCols).AddRow(uuid.New(), serviceName, 100, userID, time.Now(), nil, time.Now(), time.Now()),
+			expectedSQL:  "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1 AND user_id = $2 ORDER BY created_at, id LIMIT 11 OFFSET 0",
+			expectedArgs: []any{testTenant, userID},
+			mockRows:     sqlmock.NewRows(mockCols).AddRow(uuid.New(), serviceName, 100, "monthly", "RUB", userID, time.Now(), nil, nil, 1, time.Now(), time.Now()),
 		},
 		{
 			name:         "Filter by ServiceName",
 			filter:       domain.SubscriptionFilter{ServiceName: ptr(serviceName)},
-			expectedSQL:  "SELECT id, service_name, monthly_cost, user_id, start_date, end_date, created_at, updated_at FROM subscriptions WHERE service_name = $1 LIMIT 10 OFFSET 0",
-			expectedArgs: []any{serviceName},
+			expectedSQL:  "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1 AND service_name = $2 ORDER BY created_at, id LIMIT 11 OFFSET 0",
+			expectedArgs: []any{testTenant, serviceName},
 			mockRows:     sqlmock.NewRows(mockCols),
 		},
 		{
 			name:         "Filter by UserID and ServiceName",
 			filter:       domain.SubscriptionFilter{UserID: ptr(userID), ServiceName: ptr(serviceName)},
-			expectedSQL:  "SELECT id, service_name, monthly_cost, user_id, start_date, end_date, created_at, updated_at FROM subscriptions WHERE user_id = $1 AND service_name = $2 LIMIT 10 OFFSET 0",
-			expectedArgs: []any{userID, serviceName},
+			expectedSQL:  "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1 AND user_id = $2 AND service_name = $3 ORDER BY created_at, id LIMIT 11 OFFSET 0",
+			expectedArgs: []any{testTenant, userID, serviceName},
+			mockRows:     sqlmock.NewRows(mockCols),
+		},
+		{
+			name:         "Filter in trial",
+			filter:       domain.SubscriptionFilter{UserID: ptr(userID), InTrial: ptr(true)},
+			expectedSQL:  "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1 AND user_id = $2 AND start_date <= CURRENT_DATE AND trial_end >= CURRENT_DATE ORDER BY created_at, id LIMIT 11 OFFSET 0",
+			expectedArgs: []any{testTenant, userID},
+			mockRows:     sqlmock.NewRows(mockCols).AddRow(uuid.New(), serviceName, 100, "monthly", "RUB", userID, time.Now(), nil, time.Now(), 1, time.Now(), time.Now()),
+		},
+		{
+			name:         "Filter not in trial",
+			filter:       domain.SubscriptionFilter{InTrial: ptr(false)},
+			expectedSQL:  "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1 AND NOT COALESCE(start_date <= CURRENT_DATE AND trial_end >= CURRENT_DATE, false) ORDER BY created_at, id LIMIT 11 OFFSET 0",
+			expectedArgs: []any{testTenant},
+			mockRows:     sqlmock.NewRows(mockCols),
+		},
+		{
+			name: "Active at, cost range and date windows",
+			filter: domain.SubscriptionFilter{
+				UserID:        ptr(userID),
+				ActiveAt:      ptr(march),
+				MinCost:       ptr(100),
+				MaxCost:       ptr(500),
+				StartedAfter:  ptr(jan31),
+				StartedBefore: ptr(march),
+				EndsBefore:    ptr(march),
+				HasEndDate:    ptr(true),
+			},
+			expectedSQL: "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions " +
+				"WHERE tenant_id = $1 AND user_id = $2 AND start_date <= (date_trunc('month', $3::date) + interval '1 month - 1 day')::date " +
+				"AND (end_date IS NULL OR end_date >= date_trunc('month', $4::date)::date) " +
+				"AND ROUND(" + monthlyCost + ") >= $5 AND ROUND(" + monthlyCost + ") <= $6 " +
+				"AND start_date > $7 AND start_date < $8 AND end_date < $9 AND end_date IS NOT NULL " +
+				"ORDER BY created_at, id LIMIT 11 OFFSET 0",
+			expectedArgs: []any{testTenant, userID, march, march, 100, 500, jan31, march, march},
+			mockRows:     sqlmock.NewRows(mockCols),
+		},
+		{
+			name:         "Query escapes LIKE wildcards",
+			filter:       domain.SubscriptionFilter{Query: ptr(`100%_off\`)},
+			expectedSQL:  "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1 AND service_name ILIKE $2 ORDER BY created_at, id LIMIT 11 OFFSET 0",
+			expectedArgs: []any{testTenant, `%100\%\_off\\%`},
+			mockRows:     sqlmock.NewRows(mockCols),
+		},
+		{
+			name:         "Without end date",
+			filter:       domain.SubscriptionFilter{HasEndDate: ptr(false)},
+			expectedSQL:  "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1 AND end_date IS NULL ORDER BY created_at, id LIMIT 11 OFFSET 0",
+			expectedArgs: []any{testTenant},
 			mockRows:     sqlmock.NewRows(mockCols),
 		},
 		{
 			name:         "Custom Page and PageSize",
 			filter:       domain.SubscriptionFilter{Page: ptr(3), PageSize: ptr(20)},
-			expectedSQL:  "SELECT id, service_name, monthly_cost, user_id, start_date, end_date, created_at, updated_at FROM subscriptions LIMIT 20 OFFSET 40",
-			expectedArgs: []any{},
+			expectedSQL:  "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1 ORDER BY created_at, id LIMIT 21 OFFSET 40",
+			expectedArgs: []any{testTenant},
 			mockRows:     sqlmock.NewRows(mockCols),
 		},
 		{
 			name:         "PageSize exceeds MaxPageSize",
 			filter:       domain.SubscriptionFilter{PageSize: ptr(200)},
-			expectedSQL:  "SELECT id, service_name, monthly_cost, user_id, start_date, end_date, created_at, updated_at FROM subscriptions LIMIT 100 OFFSET 0",
-			expectedArgs: []any{},
+			expectedSQL:  "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1 ORDER BY created_at, id LIMIT 101 OFFSET 0",
+			expectedArgs: []any{testTenant},
 			mockRows:     sqlmock.NewRows(mockCols),
 		},
 		{
 			name:         "Page 1 with custom PageSize",
 			filter:       domain.SubscriptionFilter{Page: ptr(1), PageSize: ptr(5)},
-			expectedSQL:  "SELECT id, service_name, monthly_cost, user_id, start_date, end_date, created_at, updated_at FROM subscriptions LIMIT 5 OFFSET 0",
-			expectedArgs: []any{},
+			expectedSQL:  "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1 ORDER BY created_at, id LIMIT 6 OFFSET 0",
+			expectedArgs: []any{testTenant},
+			mockRows:     sqlmock.NewRows(mockCols),
+		},
+		{
+			name:         "After cursor",
+			filter:       domain.SubscriptionFilter{UserID: ptr(userID), After: &cursor, PageSize: ptr(1)},
+			expectedSQL:  "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1 AND user_id = $2 AND (created_at, id) > ($3, $4) ORDER BY created_at, id LIMIT 2",
+			expectedArgs: []any{testTenant, userID, cursor.CreatedAt, cursor.ID},
+			mockRows: sqlmock.NewRows(mockCols).
+				AddRow(last.ID, serviceName, 100, "monthly", "RUB", userID, last.StartDate, nil, nil, 1, last.CreatedAt, time.Now()).
+				AddRow(uuid.New(), serviceName, 100, "monthly", "RUB", userID, time.Now(), nil, nil, 1, time.Now(), time.Now()),
+			wantNext: ptr(domain.CursorOf(last)),
+		},
+		{
+			name:         "Sorted by several keys",
+			filter:       domain.SubscriptionFilter{Sort: []domain.SortKey{{Field: domain.SortMonthlyCost, Desc: true}, {Field: domain.SortServiceName, Desc: true}}},
+			expectedSQL:  "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1 ORDER BY " + monthlyCost + " DESC, service_name DESC, id DESC LIMIT 11 OFFSET 0",
+			expectedArgs: []any{testTenant},
+			mockRows:     sqlmock.NewRows(mockCols),
+		},
+		{
+			name:   "After cursor, same directions",
+			filter: domain.SubscriptionFilter{Sort: []domain.SortKey{{Field: domain.SortStartDate, Desc: true}, {Field: domain.SortMonthlyCost, Desc: true}}, After: &cursor},
+			expectedSQL: "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions " +
+				"WHERE tenant_id = $1 AND (start_date, " + monthlyCost + ", id) < ($2, " + cursorMonthlyCost + ", $5) " +
+				"ORDER BY start_date DESC, " + monthlyCost + " DESC, id DESC LIMIT 11",
+			expectedArgs: []any{testTenant, cursor.StartDate, cursor.Price, string(cursor.BillingPeriod), cursor.ID},
+			mockRows:     sqlmock.NewRows(mockCols),
+		},
+		{
+			name:   "After cursor, mixed directions",
+			filter: domain.SubscriptionFilter{Sort: []domain.SortKey{{Field: domain.SortServiceName}, {Field: domain.SortStartDate, Desc: true}}, After: &cursor},
+			expectedSQL: "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions " +
+				"WHERE tenant_id = $1 AND ((service_name > $2) OR (service_name = $3 AND start_date < $4) OR (service_name = $5 AND start_date = $6 AND id < $7)) " +
+				"ORDER BY service_name, start_date DESC, id DESC LIMIT 11",
+			expectedArgs: []any{testTenant, serviceName, serviceName, cursor.StartDate, serviceName, cursor.StartDate, cursor.ID},
 			mockRows:     sqlmock.NewRows(mockCols),
 		},
 		{
 			name:         "DB Query Error",
 			filter:       domain.SubscriptionFilter{},
-			expectedSQL:  "SELECT id, service_name, monthly_cost, user_id, start_date, end_date, created_at, updated_at FROM subscriptions LIMIT 10 OFFSET 0",
-			expectedArgs: []any{},
+			expectedSQL:  "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1 ORDER BY created_at, id LIMIT 11 OFFSET 0",
+			expectedArgs: []any{testTenant},
 			mockErr:      errors.New("db query error"),
 		},
 	}
@@ -445,14 +893,82 @@ func TestSubsRepo_List(t *testing.T) {
 				query.WillReturnRows(tc.mockRows)
 			}
 
-			subs, err := repo.List(ctx, tc.filter)
+			page, err := repo.List(ctx, tc.filter)
 			if tc.mockErr != nil {
 				var baseErr *errkit.BaseErr[repos.RepoKind]
 				require.ErrorAs(t, err, &baseErr)
 				assert.Equal(t, repos.KindUnknown, baseErr.Kind)
-				assert.Nil(t, subs)
+				assert.Nil(t, page)
 			} else {
 				require.NoError(t, err)
+				assert.Equal(t, tc.wantNext, page.NextCursor)
+				if tc.filter.After == nil {
+					assert.Equal(t, pageNumber(tc.filter), page.Page)
+				} else {
+					assert.Zero(t, page.Page)
+				}
+				if tc.wantNext != nil {
+					assert.Len(t, page.Items, *tc.filter.PageSize)
+				}
+			}
+		})
+	}
+}
+
+func TestSubsRepo_SuggestServiceNames(t *testing.T) {
+	repo, mock := setup(t)
+	ctx := tenantCtx()
+
+	expectedSQL := "SELECT service_name FROM subscriptions WHERE tenant_id = $1 AND service_name ILIKE $2 GROUP BY service_name " +
+		"ORDER BY service_name ILIKE $3 DESC, COUNT(*) DESC, service_name"
+
+	testCases := []struct {
+		name          string
+		limit         *int
+		expectedLimit int
+		mockRows      *sqlmock.Rows
+		mockErr       error
+		expected      []string
+	}{
+		{
+			name:          "Default limit",
+			expectedLimit: 10,
+			mockRows:      sqlmock.NewRows([]string{"service_name"}).AddRow("Yandex Plus").AddRow("Kinopoisk Yandex"),
+			expected:      []string{"Yandex Plus", "Kinopoisk Yandex"},
+		},
+		{
+			name:          "Limit exceeds MaxPageSize",
+			limit:         ptr(500),
+			expectedLimit: 100,
+			mockRows:      sqlmock.NewRows([]string{"service_name"}),
+			expected:      []string{},
+		},
+		{
+			name:          "DB Query Error",
+			limit:         ptr(5),
+			expectedLimit: 5,
+			mockErr:       errors.New("db query error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			query := mock.ExpectQuery(fmt.Sprintf("%s LIMIT %d", expectedSQL, tc.expectedLimit)).WithArgs(testTenant, "%yan%", "yan%")
+			if tc.mockErr != nil {
+				query.WillReturnError(tc.mockErr)
+			} else {
+				query.WillReturnRows(tc.mockRows)
+			}
+
+			names, err := repo.SuggestServiceNames(ctx, "yan", tc.limit)
+			if tc.mockErr != nil {
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindUnknown, baseErr.Kind)
+				assert.Nil(t, names)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, names)
 			}
 		})
 	}
@@ -460,12 +976,17 @@ func TestSubsRepo_List(t *testing.T) {
 
 func TestSubsRepo_ListAll(t *testing.T) {
 	repo, mock := setup(t)
-	ctx := context.Background()
+	ctx := tenantCtx()
 
 	userID := uuid.New()
 	serviceName := "Test Service"
 
-	mockCols := []string{"id", "service_name", "monthly_cost", "user_id", "start_date", "end_date", "created_at", "updated_at"}
+	mockCols := []string{"id", "service_name", "price", "billing_period", "currency", "user_id", "start_date", "end_date", "trial_end", "version", "created_at", "updated_at"}
+
+	historyCols := []string{"subscription_id", "effective_from", "price", "created_at"}
+	pausesCols := []string{"subscription_id", "paused_from", "resumed_from", "created_at"}
+	subID := uuid.New()
+	now := time.Now()
 
 	testCases := []struct {
 		name        string
@@ -473,48 +994,79 @@ func TestSubsRepo_ListAll(t *testing.T) {
 		expectedSQL string
 		mockRows    *sqlmock.Rows
 		mockErr     error
+		historySQL  string
+		historyRows *sqlmock.Rows
+		historyErr  error
+		pausesSQL   string
+		pausesRows  *sqlmock.Rows
 	}{
 		{
 			name:        "Success - No filter",
 			filter:      domain.SubscriptionFilter{},
-			expectedSQL: "SELECT id, service_name, monthly_cost, user_id, start_date, end_date, created_at, updated_at FROM subscriptions",
+			expectedSQL: "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1",
 			mockRows:    sqlmock.NewRows(mockCols),
 		},
+		{
+			name:        "Success - With price history",
+			filter:      domain.SubscriptionFilter{UserID: ptr(userID)},
+			expectedSQL: "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1 AND user_id = $2",
+			mockRows: sqlmock.NewRows(mockCols).
+				AddRow(subID, serviceName, 150, "monthly", "RUB", userID, now, nil, nil, 1, now, now),
+			historySQL: "SELECT subscription_id, effective_from, price, created_at FROM subscription_prices " +
+				"WHERE subscription_id IN (SELECT id FROM subscriptions WHERE tenant_id = $1 AND user_id = $2) ORDER BY subscription_id, effective_from",
+			historyRows: sqlmock.NewRows(historyCols).
+				AddRow(subID, now.AddDate(-1, 0, 0), 100, now).
+				AddRow(subID, now, 150, now),
+			pausesSQL: "SELECT subscription_id, paused_from, resumed_from, created_at FROM subscription_pauses " +
+				"WHERE subscription_id IN (SELECT id FROM subscriptions WHERE tenant_id = $1 AND user_id = $2) ORDER BY subscription_id, paused_from",
+			pausesRows: sqlmock.NewRows(pausesCols).
+				AddRow(subID, now.AddDate(0, -3, 0), now.AddDate(0, -1, 0), now),
+		},
+		{
+			name:        "Price history Error",
+			filter:      domain.SubscriptionFilter{},
+			expectedSQL: "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1",
+			mockRows: sqlmock.NewRows(mockCols).
+				AddRow(subID, serviceName, 150, "monthly", "RUB", userID, now, nil, nil, 1, now, now),
+			historySQL: "SELECT subscription_id, effective_from, price, created_at FROM subscription_prices " +
+				"WHERE subscription_id IN (SELECT id FROM subscriptions WHERE tenant_id = $1) ORDER BY subscription_id, effective_from",
+			historyErr: errors.New("db query error"),
+		},
 		{
 			name:        "Success - Filter by UserID",
 			filter:      domain.SubscriptionFilter{UserID: ptr(userID)},
-			expectedSQL: "SELECT id, service_name, monthly_cost, user_id, start_date, end_date, created_at, updated_at FROM subscriptions WHERE user_id = $1",
+			expectedSQL: "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1 AND user_id = $2",
 			mockRows:    sqlmock.NewRows(mockCols),
 		},
 		{
 			name:        "Success - Filter by ServiceName",
 			filter:      domain.SubscriptionFilter{ServiceName: ptr(serviceName)},
-			expectedSQL: "SELECT id, service_name, monthly_cost, user_id, start_date, end_date, created_at, updated_at FROM subscriptions WHERE service_name = $1",
+			expectedSQL: "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1 AND service_name = $2",
 			mockRows:    sqlmock.NewRows(mockCols),
 		},
 		{
 			name:        "Success - Filter by UserID and ServiceName",
 			filter:      domain.SubscriptionFilter{UserID: ptr(userID), ServiceName: ptr(serviceName)},
-			expectedSQL: "SELECT id, service_name, monthly_cost, user_id, start_date, end_date, created_at, updated_at FROM subscriptions WHERE user_id = $1 AND service_name = $2",
+			expectedSQL: "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1 AND user_id = $2 AND service_name = $3",
 			mockRows:    sqlmock.NewRows(mockCols),
 		},
 		{
 			name:        "DB Query Error",
 			filter:      domain.SubscriptionFilter{},
-			expectedSQL: "SELECT id, service_name, monthly_cost, user_id, start_date, end_date, created_at, updated_at FROM subscriptions",
+			expectedSQL: "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1",
 			mockErr:     errors.New("db query error"),
 		},
 		{
 			name:        "Scan Error",
 			filter:      domain.SubscriptionFilter{},
-			expectedSQL: "SELECT id, service_name, monthly_cost, user_id, start_date, end_date, created_at, updated_at FROM subscriptions",
+			expectedSQL: "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions WHERE tenant_id = $1",
 			mockRows:    sqlmock.NewRows([]string{"id"}).AddRow("not-a-uuid"),
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			var expectedArgs []driver.Value
+			expectedArgs := []driver.Value{testTenant}
 			if tc.filter.UserID != nil {
 				expectedArgs = append(expectedArgs, *tc.filter.UserID)
 			}
@@ -530,9 +1082,21 @@ func TestSubsRepo_ListAll(t *testing.T) {
 				query.WillReturnRows(tc.mockRows)
 			}
 
+			if tc.historySQL != "" {
+				historyQuery := mock.ExpectQuery(tc.historySQL).WithArgs(expectedArgs...)
+				if tc.historyErr != nil {
+					historyQuery.WillReturnError(tc.historyErr)
+				} else {
+					historyQuery.WillReturnRows(tc.historyRows)
+				}
+			}
+			if tc.pausesSQL != "" {
+				mock.ExpectQuery(tc.pausesSQL).WithArgs(expectedArgs...).WillReturnRows(tc.pausesRows)
+			}
+
 			subs, err := repo.ListAll(ctx, tc.filter)
 
-			if tc.mockErr != nil || tc.name == "Scan Error" {
+			if tc.mockErr != nil || tc.historyErr != nil || tc.name == "Scan Error" {
 				var baseErr *errkit.BaseErr[repos.RepoKind]
 				require.ErrorAs(t, err, &baseErr)
 				assert.Equal(t, repos.KindUnknown, baseErr.Kind)
@@ -540,7 +1104,294 @@ func TestSubsRepo_ListAll(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 				assert.NotNil(t, subs)
+				if tc.historyRows != nil {
+					require.Len(t, subs, 1)
+					require.Len(t, subs[0].PriceHistory, 2)
+					assert.Equal(t, 100, subs[0].PriceHistory[0].Price)
+					assert.Equal(t, 150, subs[0].PriceHistory[1].Price)
+					require.Len(t, subs[0].Pauses, 1)
+					assert.NotNil(t, subs[0].Pauses[0].ResumedFrom)
+				}
+			}
+		})
+	}
+}
+
+func TestSubsRepo_Stream(t *testing.T) {
+	ctx := tenantCtx()
+	userID := uuid.New()
+	now := time.Now()
+	cols := []string{"id", "service_name", "price", "billing_period", "currency", "user_id", "start_date", "end_date", "trial_end", "version", "created_at", "updated_at"}
+	selectSQL := "SELECT id, service_name, price, billing_period, currency, user_id, start_date, end_date, trial_end, version, created_at, updated_at FROM subscriptions"
+	dbErr := errors.New("db error")
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	rows := func() *sqlmock.Rows {
+		return sqlmock.NewRows(cols).
+			AddRow(ids[0], "Okko", 300, "monthly", "RUB", userID, now, nil, nil, 1, now, now).
+			AddRow(ids[1], "Ivi", 400, "yearly", "RUB", userID, now, nil, nil, 2, now, now)
+	}
+
+	testCases := []struct {
+		name       string
+		filter     domain.SubscriptionFilter
+		limit      int
+		setupMock  func(mock sqlmock.Sqlmock)
+		assertFunc func(t *testing.T, got []uuid.UUID, err error)
+	}{
+		{
+			name:   "Success - Sorted Without Pagination",
+			filter: domain.SubscriptionFilter{UserID: &userID, Sort: []domain.SortKey{{Field: domain.SortServiceName, Desc: true}}, Page: ptr(3), PageSize: ptr(1)},
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL+" WHERE tenant_id = $1 AND user_id = $2 ORDER BY service_name DESC, id DESC").
+					WithArgs(testTenant, userID).
+					WillReturnRows(rows())
+			},
+			assertFunc: func(t *testing.T, got []uuid.UUID, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, ids, got)
+			},
+		},
+		{
+			name: "Stops Early",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL + " WHERE tenant_id = $1 ORDER BY created_at, id").WithArgs(testTenant).
+					WillReturnRows(rows()).
+					RowsWillBeClosed()
+			},
+			limit: 1,
+			assertFunc: func(t *testing.T, got []uuid.UUID, err error) {
+				require.NoError(t, err)
+				assert.Equal(t, ids[:1], got)
+			},
+		},
+		{
+			name: "Query Error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL + " WHERE tenant_id = $1 ORDER BY created_at, id").WithArgs(testTenant).WillReturnError(dbErr)
+			},
+			assertFunc: func(t *testing.T, got []uuid.UUID, err error) {
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindUnknown, baseErr.Kind)
+				assert.ErrorIs(t, err, dbErr)
+				assert.Empty(t, got)
+			},
+		},
+		{
+			name: "Rows Error",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectQuery(selectSQL + " WHERE tenant_id = $1 ORDER BY created_at, id").WithArgs(testTenant).
+					WillReturnRows(rows().RowError(1, dbErr))
+			},
+			assertFunc: func(t *testing.T, got []uuid.UUID, err error) {
+				assert.ErrorIs(t, err, dbErr)
+				assert.Equal(t, ids[:1], got)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := setup(t)
+			tc.setupMock(mock)
+
+			var got []uuid.UUID
+			var gotErr error
+			for sub, err := range repo.Stream(ctx, tc.filter) {
+				if err != nil {
+					gotErr = err
+					break
+				}
+				got = append(got, sub.ID)
+				if len(got) == tc.limit {
+					break
+				}
 			}
+			tc.assertFunc(t, got, gotErr)
 		})
 	}
 }
+
+func TestSubsRepo_Count(t *testing.T) {
+	repo, mock := setup(t)
+	ctx := tenantCtx()
+
+	userID := uuid.New()
+	cursor := domain.Cursor{BillingPeriod: domain.BillingMonthly, ID: uuid.New()}
+
+	testCases := []struct {
+		name         string
+		filter       domain.SubscriptionFilter
+		expectedSQL  string
+		expectedArgs []any
+		mockErr      error
+		expected     int
+	}{
+		{
+			name:         "No Filter",
+			filter:       domain.SubscriptionFilter{},
+			expectedSQL:  "SELECT COUNT(*) FROM subscriptions WHERE tenant_id = $1",
+			expectedArgs: []any{testTenant},
+			expected:     42,
+		},
+		{
+			name: "Pagination and sort are ignored",
+			filter: domain.SubscriptionFilter{
+				UserID:   &userID,
+				Sort:     []domain.SortKey{{Field: domain.SortServiceName}},
+				After:    &cursor,
+				Page:     ptr(3),
+				PageSize: ptr(5),
+			},
+			expectedSQL:  "SELECT COUNT(*) FROM subscriptions WHERE tenant_id = $1 AND user_id = $2",
+			expectedArgs: []any{testTenant, userID},
+			expected:     7,
+		},
+		{
+			name:         "DB Query Error",
+			filter:       domain.SubscriptionFilter{},
+			expectedSQL:  "SELECT COUNT(*) FROM subscriptions WHERE tenant_id = $1",
+			expectedArgs: []any{testTenant},
+			mockErr:      errors.New("db query error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			driverArgs := make([]driver.Value, len(tc.expectedArgs))
+			for i, v := range tc.expectedArgs {
+				driverArgs[i] = v
+			}
+
+			query := mock.ExpectQuery(tc.expectedSQL).WithArgs(driverArgs...)
+			if tc.mockErr != nil {
+				query.WillReturnError(tc.mockErr)
+			} else {
+				query.WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(tc.expected))
+			}
+
+			count, err := repo.Count(ctx, tc.filter)
+			if tc.mockErr != nil {
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindUnknown, baseErr.Kind)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, count)
+			}
+		})
+	}
+}
+
+func TestSubsRepo_TotalCostByCurrency(t *testing.T) {
+	ctx := tenantCtx()
+
+	userID := uuid.New()
+	serviceName := "Test Service"
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	chargeWindows := func(sign, from, to string, startArg, endArg int) string {
+		return fmt.Sprintf("SELECT p.price, s.billing_period, s.currency, s.start_date, %s AS sign, "+
+			"GREATEST(s.start_date, s.trial_end + 1, %s, date_trunc('month', $%d::date)::date) AS billing_start, "+
+			"LEAST(s.end_date, %s, (date_trunc('month', $%d::date) + interval '1 month - 1 day')::date) AS billing_end "+
+			"FROM subscriptions s JOIN "+pricePeriodsTable+" p ON p.subscription_id = s.id", sign, from, startArg, to, endArg)
+	}
+	billed := func(startArg int) string {
+		return chargeWindows("1", "p.effective_from", "p.effective_to", startArg, startArg+1)
+	}
+	paused := func(startArg int) string {
+		return chargeWindows("-1", "p.effective_from, ps.paused_from", "p.effective_to, ps.resumed_from - 1", startArg, startArg+1) +
+			" JOIN subscription_pauses ps ON ps.subscription_id = s.id"
+	}
+	// The filter selects the ids of the subscriptions on their own.
+	filtered := func(cond string) string {
+		return " WHERE s.id IN (SELECT id FROM subscriptions WHERE " + cond + ")"
+	}
+	selectPrefix := "SELECT currency, " + totalCostColumn + " FROM ("
+	groupBy := ") AS billed GROUP BY currency"
+
+	testCases := []struct {
+		name           string
+		filter         domain.SubscriptionFilter
+		expectedSQL    string
+		expectedArgs   []driver.Value
+		mockRows       *sqlmock.Rows
+		mockErr        error
+		expectedTotals map[string]int
+	}{
+		{
+			name:           "Success - No filter",
+			filter:         domain.SubscriptionFilter{},
+			expectedSQL:    selectPrefix + billed(1) + filtered("tenant_id = $3") + " UNION ALL " + paused(4) + filtered("tenant_id = $6") + groupBy,
+			expectedArgs:   []driver.Value{start, end, testTenant, start, end, testTenant},
+			mockRows:       sqlmock.NewRows([]string{"currency", "total"}).AddRow("RUB", 1200).AddRow("USD", 30),
+			expectedTotals: map[string]int{"RUB": 1200, "USD": 30},
+		},
+		{
+			name:   "Success - Filter by UserID and ServiceName",
+			filter: domain.SubscriptionFilter{UserID: ptr(userID), ServiceName: ptr(serviceName)},
+			expectedSQL: selectPrefix + billed(1) + filtered("tenant_id = $3 AND user_id = $4 AND service_name = $5") + " UNION ALL " +
+				paused(6) + filtered("tenant_id = $8 AND user_id = $9 AND service_name = $10") + groupBy,
+			expectedArgs:   []driver.Value{start, end, testTenant, userID, serviceName, start, end, testTenant, userID, serviceName},
+			mockRows:       sqlmock.NewRows([]string{"currency", "total"}),
+			expectedTotals: map[string]int{},
+		},
+		{
+			name:   "Success - Filter by the current monthly cost",
+			filter: domain.SubscriptionFilter{MinCost: ptr(100)},
+			expectedSQL: selectPrefix + billed(1) + filtered("tenant_id = $3 AND ROUND("+monthlyCostExpr("price", "billing_period")+") >= $4") + " UNION ALL " +
+				paused(5) + filtered("tenant_id = $7 AND ROUND("+monthlyCostExpr("price", "billing_period")+") >= $8") + groupBy,
+			expectedArgs:   []driver.Value{start, end, testTenant, 100, start, end, testTenant, 100},
+			mockRows:       sqlmock.NewRows([]string{"currency", "total"}),
+			expectedTotals: map[string]int{},
+		},
+		{
+			name:         "DB Query Error",
+			filter:       domain.SubscriptionFilter{},
+			expectedSQL:  selectPrefix + billed(1) + filtered("tenant_id = $3") + " UNION ALL " + paused(4) + filtered("tenant_id = $6") + groupBy,
+			expectedArgs: []driver.Value{start, end, testTenant, start, end, testTenant},
+			mockErr:      errors.New("db query error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, mock := setup(t)
+
+			query := mock.ExpectQuery(tc.expectedSQL).WithArgs(tc.expectedArgs...)
+			if tc.mockErr != nil {
+				query.WillReturnError(tc.mockErr)
+			} else {
+				query.WillReturnRows(tc.mockRows)
+			}
+
+			totals, err := repo.TotalCostByCurrency(ctx, tc.filter, start, end)
+			if tc.mockErr != nil {
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindUnknown, baseErr.Kind)
+				assert.ErrorIs(t, err, tc.mockErr)
+				assert.Nil(t, totals)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedTotals, totals)
+			}
+		})
+	}
+}
+
+func TestSubsRepo_NoTenant(t *testing.T) {
+	repo, _ := setup(t)
+	ctx := context.Background()
+
+	_, err := repo.GetByID(ctx, uuid.New())
+	assert.ErrorIs(t, err, errNoTenant)
+
+	_, err = repo.List(ctx, domain.SubscriptionFilter{})
+	assert.ErrorIs(t, err, errNoTenant)
+
+	for _, err := range repo.Stream(ctx, domain.SubscriptionFilter{}) {
+		assert.ErrorIs(t, err, errNoTenant)
+	}
+}
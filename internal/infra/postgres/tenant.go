@@ -0,0 +1,94 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/pkg/log"
+)
+
+const (
+	// setTenantQuery is SET LOCAL app.tenant_id, which takes no parameters. The
+	// row level security policies limit the transaction to the rows of that tenant.
+	setTenantQuery = `SELECT set_config('app.tenant_id', $1, true);`
+
+	// setCrossTenantRoleQuery switches the transaction to CrossTenantRole.
+	setCrossTenantRoleQuery = `SET LOCAL ROLE ` + CrossTenantRole + `;`
+)
+
+// CrossTenantRole bypasses the row level security policies. Only the queries
+// across tenants run as it, so the service role has to be a member of it.
+const CrossTenantRole = "subscriptions_cross_tenant"
+
+// errNoTenant fails the queries of callers without a tenant instead of running
+// them across tenants.
+var errNoTenant = errors.New("no tenant in context")
+
+// tenantOf returns the tenant the queries of op for ctx are limited to.
+func tenantOf(ctx context.Context, op string) (string, error) {
+	tenant, ok := domain.TenantFromCtx(ctx)
+	if !ok {
+		return "", repos.WrapErr(op, repos.KindUnknown, errNoTenant)
+	}
+	return string(tenant), nil
+}
+
+// SetTenant limits tx to the rows of tenant.
+func SetTenant(ctx context.Context, tx *sql.Tx, tenant domain.TenantID) error {
+	_, err := tx.ExecContext(ctx, setTenantQuery, string(tenant))
+	return err
+}
+
+// txBeginner is a pool, whose connections aren't limited to any tenant.
+type txBeginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// inTenant runs fn on db limited to the rows of tenant. Queries on a pool run
+// in a transaction of their own, transactions are limited by their provider.
+func inTenant(ctx context.Context, db DBTX, tenant string, fn func(db DBTX) error) error {
+	return inTx(ctx, db, func(tx *sql.Tx) error {
+		return SetTenant(ctx, tx, domain.TenantID(tenant))
+	}, fn)
+}
+
+// acrossTenants runs fn on db as CrossTenantRole.
+func acrossTenants(ctx context.Context, db DBTX, fn func(db DBTX) error) error {
+	return inTx(ctx, db, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, setCrossTenantRoleQuery)
+		return err
+	}, fn)
+}
+
+func inTx(ctx context.Context, db DBTX, prepare func(tx *sql.Tx) error, fn func(db DBTX) error) error {
+	pool, ok := db.(txBeginner)
+	if !ok {
+		return fn(db)
+	}
+
+	tx, err := pool.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := prepare(tx); err != nil {
+		rollback(ctx, tx)
+		return err
+	}
+	if err := fn(tx); err != nil {
+		rollback(ctx, tx)
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func rollback(ctx context.Context, tx *sql.Tx) {
+	if err := tx.Rollback(); err != nil {
+		log.FromCtx(ctx).Warn("failed to rollback transaction", log.WithErr(err))
+	}
+}
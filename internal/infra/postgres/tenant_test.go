@@ -0,0 +1,140 @@
+package postgres
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/shrtyk/subscriptions-service/internal/config"
+	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
+	"github.com/shrtyk/subscriptions-service/pkg/errkit"
+)
+
+// setupTx returns a transaction the repos under test run their queries in,
+// as they do in a unit of work.
+func setupTx(t *testing.T) (*sql.Tx, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+
+	mock.ExpectBegin()
+	tx, err := db.Begin()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		mock.ExpectRollback()
+		assert.NoError(t, tx.Rollback())
+		mock.ExpectClose()
+		if cerr := db.Close(); cerr != nil {
+			assert.NoError(t, cerr)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	return tx, mock
+}
+
+func setupPool(t *testing.T) (*sql.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		mock.ExpectClose()
+		if cerr := db.Close(); cerr != nil {
+			assert.NoError(t, cerr)
+		}
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+
+	return db, mock
+}
+
+func TestInTenant(t *testing.T) {
+	ctx := tenantCtx()
+	id := uuid.New()
+	dbErr := errors.New("db error")
+
+	testCases := []struct {
+		name      string
+		setupMock func(mock sqlmock.Sqlmock)
+		assert    func(t *testing.T, err error)
+	}{
+		{
+			name: "Pool queries run in a transaction of the tenant",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(setTenantQuery).WithArgs(testTenant).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(existsQuery).WithArgs(id, testTenant).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectCommit()
+			},
+			assert: func(t *testing.T, err error) {
+				var baseErr *errkit.BaseErr[repos.RepoKind]
+				require.ErrorAs(t, err, &baseErr)
+				assert.Equal(t, repos.KindConflict, baseErr.Kind)
+			},
+		},
+		{
+			name: "Failed query rolls back",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(setTenantQuery).WithArgs(testTenant).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(existsQuery).WithArgs(id, testTenant).WillReturnError(dbErr)
+				mock.ExpectRollback()
+			},
+			assert: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, dbErr)
+			},
+		},
+		{
+			name: "Failed tenant setting rolls back without querying",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin()
+				mock.ExpectExec(setTenantQuery).WithArgs(testTenant).WillReturnError(dbErr)
+				mock.ExpectRollback()
+			},
+			assert: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, dbErr)
+			},
+		},
+		{
+			name: "Failed begin",
+			setupMock: func(mock sqlmock.Sqlmock) {
+				mock.ExpectBegin().WillReturnError(dbErr)
+			},
+			assert: func(t *testing.T, err error) {
+				assert.ErrorIs(t, err, dbErr)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			db, mock := setupPool(t)
+			repo := NewSubsRepo(db, &config.RepoConfig{})
+			tc.setupMock(mock)
+
+			tc.assert(t, repo.notFoundOrConflict(ctx, opUpdate, testTenant, id))
+		})
+	}
+}
+
+func TestAcrossTenants(t *testing.T) {
+	db, mock := setupPool(t)
+	repo := NewIdempotencyRepo(db, &config.RepoConfig{})
+
+	mock.ExpectBegin()
+	mock.ExpectExec(setCrossTenantRoleQuery).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(purgeIdempotencyKeysQuery).WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	purged, err := repo.PurgeExpired(tenantCtx())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+}
@@ -6,15 +6,18 @@ import (
 	"fmt"
 
 	"github.com/shrtyk/subscriptions-service/internal/config"
+	"github.com/shrtyk/subscriptions-service/internal/core/domain"
 	"github.com/shrtyk/subscriptions-service/internal/core/ports/repos"
 	"github.com/shrtyk/subscriptions-service/internal/core/ports/tx"
 	"github.com/shrtyk/subscriptions-service/internal/infra/postgres"
 )
 
 type unitOfWork struct {
-	tx       *sql.Tx
-	repoCfg  *config.RepoConfig
-	subsRepo repos.SubscriptionRepository
+	tx              *sql.Tx
+	repoCfg         *config.RepoConfig
+	subsRepo        repos.SubscriptionRepository
+	idempotencyRepo repos.IdempotencyKeyRepository
+	auditRepo       repos.AuditRepository
 }
 
 func (uow *unitOfWork) Subscriptions() repos.SubscriptionRepository {
@@ -24,6 +27,20 @@ func (uow *unitOfWork) Subscriptions() repos.SubscriptionRepository {
 	return uow.subsRepo
 }
 
+func (uow *unitOfWork) IdempotencyKeys() repos.IdempotencyKeyRepository {
+	if uow.idempotencyRepo == nil {
+		uow.idempotencyRepo = postgres.NewIdempotencyRepo(uow.tx, uow.repoCfg)
+	}
+	return uow.idempotencyRepo
+}
+
+func (uow *unitOfWork) Audit() repos.AuditRepository {
+	if uow.auditRepo == nil {
+		uow.auditRepo = postgres.NewAuditRepo(uow.tx, uow.repoCfg)
+	}
+	return uow.auditRepo
+}
+
 type provider struct {
 	db      *sql.DB
 	repoCfg *config.RepoConfig
@@ -50,6 +67,15 @@ func (p *provider) WithTransaction(ctx context.Context, txFunc func(uow tx.UnitO
 		}
 	}()
 
+	if tenant, ok := domain.TenantFromCtx(ctx); ok {
+		if err := postgres.SetTenant(ctx, sqlTx, tenant); err != nil {
+			if rollbackErr := sqlTx.Rollback(); rollbackErr != nil {
+				return fmt.Errorf("transaction rollback failed after setting tenant: %v (original error: %w)", rollbackErr, err)
+			}
+			return fmt.Errorf("failed to set transaction tenant: %w", err)
+		}
+	}
+
 	uow := &unitOfWork{
 		tx:      sqlTx,
 		repoCfg: p.repoCfg,
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subscriptions
+ADD COLUMN currency CHAR(3) NOT NULL DEFAULT 'RUB';
+
+CREATE TABLE currency_rates (
+  from_currency CHAR(3) NOT NULL,
+  to_currency CHAR(3) NOT NULL,
+  rate NUMERIC(20, 10) NOT NULL CHECK (rate > 0),
+  updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (from_currency, to_currency)
+);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS currency_rates;
+
+ALTER TABLE subscriptions
+DROP COLUMN IF EXISTS currency;
+
+-- +goose StatementEnd
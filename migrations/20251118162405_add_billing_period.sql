@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE billing_period AS ENUM ('weekly', 'monthly', 'quarterly', 'yearly');
+
+ALTER TABLE subscriptions
+RENAME COLUMN monthly_cost TO price;
+
+ALTER TABLE subscriptions
+ADD COLUMN billing_period billing_period NOT NULL DEFAULT 'monthly';
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+UPDATE subscriptions
+SET price = CASE billing_period
+    WHEN 'weekly' THEN ROUND(price * 52 / 12.0)
+    WHEN 'quarterly' THEN ROUND(price / 3.0)
+    WHEN 'yearly' THEN ROUND(price / 12.0)
+    ELSE price
+  END;
+
+ALTER TABLE subscriptions
+DROP COLUMN IF EXISTS billing_period;
+
+ALTER TABLE subscriptions
+RENAME COLUMN price TO monthly_cost;
+
+DROP TYPE IF EXISTS billing_period;
+
+-- +goose StatementEnd
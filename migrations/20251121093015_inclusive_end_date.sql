@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- end_date becomes the last active day, month-only end dates covered the whole month.
+UPDATE subscriptions
+SET end_date = (date_trunc('month', end_date) + interval '1 month - 1 day')::date
+WHERE end_date IS NOT NULL;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+UPDATE subscriptions
+SET end_date = date_trunc('month', end_date)::date
+WHERE end_date IS NOT NULL;
+
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE subscription_prices (
+  subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+  effective_from DATE NOT NULL CHECK (effective_from = date_trunc('month', effective_from)),
+  price INTEGER NOT NULL,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (subscription_id, effective_from)
+);
+
+INSERT INTO subscription_prices (subscription_id, effective_from, price)
+SELECT id, date_trunc('month', start_date)::date, price
+FROM subscriptions;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS subscription_prices;
+
+-- +goose StatementEnd
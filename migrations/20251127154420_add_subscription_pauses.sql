@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE subscription_pauses (
+  subscription_id UUID NOT NULL REFERENCES subscriptions (id) ON DELETE CASCADE,
+  paused_from DATE NOT NULL CHECK (paused_from = date_trunc('month', paused_from)),
+  resumed_from DATE CHECK (resumed_from = date_trunc('month', resumed_from) AND resumed_from > paused_from),
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+  PRIMARY KEY (subscription_id, paused_from),
+  -- Open pauses last forever, the month they are resumed from isn't paused.
+  CONSTRAINT subscription_pauses_no_overlap EXCLUDE USING gist (
+    subscription_id WITH =,
+    daterange(paused_from, resumed_from) WITH &&
+  )
+);
+
+CREATE UNIQUE INDEX idx_subscription_pauses_open ON subscription_pauses (subscription_id) WHERE resumed_from IS NULL;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS subscription_pauses;
+
+-- +goose StatementEnd
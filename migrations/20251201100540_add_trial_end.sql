@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subscriptions
+ADD COLUMN trial_end DATE CHECK (trial_end >= start_date);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE subscriptions
+DROP COLUMN IF EXISTS trial_end;
+
+-- +goose StatementEnd
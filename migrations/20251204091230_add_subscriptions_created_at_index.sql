@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE INDEX idx_subscriptions_created_at_id ON subscriptions (created_at, id);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_subscriptions_created_at_id;
+
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE INDEX idx_subscriptions_service_name_trgm ON subscriptions USING GIN (service_name gin_trgm_ops);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_subscriptions_service_name_trgm;
+
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE subscriptions
+ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE subscriptions
+DROP COLUMN IF EXISTS version;
+
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+ALTER TABLE subscriptions
+ADD COLUMN exclusive BOOLEAN NOT NULL DEFAULT TRUE;
+
+-- Overlaps that predate the rule are kept, they just don't take part in it.
+UPDATE subscriptions s
+SET exclusive = FALSE
+WHERE EXISTS (
+  SELECT 1 FROM subscriptions o
+  WHERE o.id <> s.id
+    AND o.user_id = s.user_id
+    AND o.service_name = s.service_name
+    AND daterange(o.start_date, o.end_date, '[]') && daterange(s.start_date, s.end_date, '[]')
+);
+
+ALTER TABLE subscriptions
+ADD CONSTRAINT subscriptions_no_overlap EXCLUDE USING gist (
+  user_id WITH =,
+  service_name WITH =,
+  daterange(start_date, end_date, '[]') WITH &&
+) WHERE (exclusive);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE subscriptions
+DROP CONSTRAINT IF EXISTS subscriptions_no_overlap;
+
+ALTER TABLE subscriptions
+DROP COLUMN IF EXISTS exclusive;
+
+-- +goose StatementEnd
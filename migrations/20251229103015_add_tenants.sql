@@ -0,0 +1,219 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Rows that predate tenants belong to the default one.
+ALTER TABLE subscriptions
+ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT 'default';
+
+ALTER TABLE subscriptions
+ALTER COLUMN tenant_id DROP DEFAULT;
+
+ALTER TABLE subscriptions
+DROP CONSTRAINT subscriptions_no_overlap;
+
+ALTER TABLE subscriptions
+ADD CONSTRAINT subscriptions_no_overlap EXCLUDE USING gist (
+  tenant_id WITH =,
+  user_id WITH =,
+  service_name WITH =,
+  daterange(start_date, end_date, '[]') WITH &&
+) WHERE (exclusive);
+
+DROP INDEX idx_subscriptions_user_id;
+
+CREATE INDEX idx_subscriptions_tenant_id_user_id ON subscriptions (tenant_id, user_id);
+
+DROP INDEX idx_subscriptions_created_at_id;
+
+CREATE INDEX idx_subscriptions_tenant_id_created_at_id ON subscriptions (tenant_id, created_at, id);
+
+ALTER TABLE idempotency_keys
+ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT 'default';
+
+ALTER TABLE idempotency_keys
+ALTER COLUMN tenant_id DROP DEFAULT;
+
+ALTER TABLE idempotency_keys
+DROP CONSTRAINT idempotency_keys_pkey;
+
+ALTER TABLE idempotency_keys
+ADD PRIMARY KEY (tenant_id, key);
+
+ALTER TABLE currency_rates
+ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT 'default';
+
+ALTER TABLE currency_rates
+ALTER COLUMN tenant_id DROP DEFAULT;
+
+ALTER TABLE currency_rates
+DROP CONSTRAINT currency_rates_pkey;
+
+ALTER TABLE currency_rates
+ADD PRIMARY KEY (tenant_id, from_currency, to_currency);
+
+ALTER TABLE api_keys
+ADD COLUMN tenant_id VARCHAR(63) NOT NULL DEFAULT 'default';
+
+ALTER TABLE api_keys
+ALTER COLUMN tenant_id DROP DEFAULT;
+
+CREATE INDEX idx_api_keys_tenant_id ON api_keys (tenant_id);
+
+-- Queries set app.tenant_id in their transaction, then only rows of that
+-- tenant are visible to and can be written by them, whichever role connects.
+-- Without it no rows are. Prices and pauses are visible with their subscriptions.
+ALTER TABLE subscriptions ENABLE ROW LEVEL SECURITY;
+
+ALTER TABLE subscriptions FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON subscriptions
+USING (tenant_id = current_setting('app.tenant_id', TRUE));
+
+ALTER TABLE subscription_prices ENABLE ROW LEVEL SECURITY;
+
+ALTER TABLE subscription_prices FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON subscription_prices
+USING (EXISTS (SELECT 1 FROM subscriptions s WHERE s.id = subscription_prices.subscription_id));
+
+ALTER TABLE subscription_pauses ENABLE ROW LEVEL SECURITY;
+
+ALTER TABLE subscription_pauses FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON subscription_pauses
+USING (EXISTS (SELECT 1 FROM subscriptions s WHERE s.id = subscription_pauses.subscription_id));
+
+ALTER TABLE idempotency_keys ENABLE ROW LEVEL SECURITY;
+
+ALTER TABLE idempotency_keys FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON idempotency_keys
+USING (tenant_id = current_setting('app.tenant_id', TRUE));
+
+ALTER TABLE currency_rates ENABLE ROW LEVEL SECURITY;
+
+ALTER TABLE currency_rates FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON currency_rates
+USING (tenant_id = current_setting('app.tenant_id', TRUE));
+
+ALTER TABLE api_keys ENABLE ROW LEVEL SECURITY;
+
+ALTER TABLE api_keys FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON api_keys
+USING (tenant_id = current_setting('app.tenant_id', TRUE));
+
+-- Lookups of API keys and the purge of expired idempotency keys span tenants,
+-- they switch to this role, so the role of the service has to be its member.
+-- Only superusers create roles that bypass row level security.
+DO $$
+BEGIN
+  IF NOT EXISTS (SELECT FROM pg_roles WHERE rolname = 'subscriptions_cross_tenant') THEN
+    CREATE ROLE subscriptions_cross_tenant NOLOGIN BYPASSRLS;
+  END IF;
+END
+$$;
+
+GRANT SELECT, UPDATE ON api_keys TO subscriptions_cross_tenant;
+
+GRANT SELECT, DELETE ON idempotency_keys TO subscriptions_cross_tenant;
+
+GRANT subscriptions_cross_tenant TO CURRENT_USER;
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+REVOKE ALL ON idempotency_keys FROM subscriptions_cross_tenant;
+
+REVOKE ALL ON api_keys FROM subscriptions_cross_tenant;
+
+DROP ROLE IF EXISTS subscriptions_cross_tenant;
+
+DROP POLICY IF EXISTS tenant_isolation ON api_keys;
+
+ALTER TABLE api_keys NO FORCE ROW LEVEL SECURITY;
+
+ALTER TABLE api_keys DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON currency_rates;
+
+ALTER TABLE currency_rates NO FORCE ROW LEVEL SECURITY;
+
+ALTER TABLE currency_rates DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON idempotency_keys;
+
+ALTER TABLE idempotency_keys NO FORCE ROW LEVEL SECURITY;
+
+ALTER TABLE idempotency_keys DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON subscription_pauses;
+
+ALTER TABLE subscription_pauses NO FORCE ROW LEVEL SECURITY;
+
+ALTER TABLE subscription_pauses DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON subscription_prices;
+
+ALTER TABLE subscription_prices NO FORCE ROW LEVEL SECURITY;
+
+ALTER TABLE subscription_prices DISABLE ROW LEVEL SECURITY;
+
+DROP POLICY IF EXISTS tenant_isolation ON subscriptions;
+
+ALTER TABLE subscriptions NO FORCE ROW LEVEL SECURITY;
+
+ALTER TABLE subscriptions DISABLE ROW LEVEL SECURITY;
+
+DROP INDEX IF EXISTS idx_api_keys_tenant_id;
+
+ALTER TABLE api_keys
+DROP COLUMN IF EXISTS tenant_id;
+
+-- Rates of other tenants than the default one are dropped with their key.
+DELETE FROM currency_rates
+WHERE tenant_id <> 'default';
+
+ALTER TABLE currency_rates
+DROP CONSTRAINT currency_rates_pkey;
+
+ALTER TABLE currency_rates
+ADD PRIMARY KEY (from_currency, to_currency);
+
+ALTER TABLE currency_rates
+DROP COLUMN IF EXISTS tenant_id;
+
+DELETE FROM idempotency_keys
+WHERE tenant_id <> 'default';
+
+ALTER TABLE idempotency_keys
+DROP CONSTRAINT idempotency_keys_pkey;
+
+ALTER TABLE idempotency_keys
+ADD PRIMARY KEY (key);
+
+ALTER TABLE idempotency_keys
+DROP COLUMN IF EXISTS tenant_id;
+
+DROP INDEX IF EXISTS idx_subscriptions_tenant_id_created_at_id;
+
+CREATE INDEX idx_subscriptions_created_at_id ON subscriptions (created_at, id);
+
+DROP INDEX IF EXISTS idx_subscriptions_tenant_id_user_id;
+
+CREATE INDEX idx_subscriptions_user_id ON subscriptions (user_id);
+
+ALTER TABLE subscriptions
+DROP CONSTRAINT subscriptions_no_overlap;
+
+ALTER TABLE subscriptions
+ADD CONSTRAINT subscriptions_no_overlap EXCLUDE USING gist (
+  user_id WITH =,
+  service_name WITH =,
+  daterange(start_date, end_date, '[]') WITH &&
+) WHERE (exclusive);
+
+ALTER TABLE subscriptions
+DROP COLUMN IF EXISTS tenant_id;
+
+-- +goose StatementEnd
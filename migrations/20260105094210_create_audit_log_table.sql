@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Subscriptions are not referenced, their entries outlive them.
+CREATE TABLE audit_log (
+  id BIGINT GENERATED ALWAYS AS IDENTITY PRIMARY KEY,
+  tenant_id VARCHAR(63) NOT NULL,
+  subscription_id UUID NOT NULL,
+  operation VARCHAR(16) NOT NULL CHECK (operation IN ('create', 'update', 'delete', 'pause', 'resume')),
+  actor_id UUID,
+  actor_role VARCHAR(63) NOT NULL,
+  api_key_id UUID,
+  request_id VARCHAR(64) NOT NULL DEFAULT '',
+  before JSONB,
+  after JSONB,
+  created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_tenant_id_subscription_id ON audit_log (tenant_id, subscription_id, id);
+
+CREATE INDEX idx_audit_log_tenant_id_id ON audit_log (tenant_id, id);
+
+-- Entries are append-only, even for the owner of the table.
+CREATE FUNCTION audit_log_append_only () RETURNS TRIGGER AS $$
+BEGIN
+  RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_log_no_update BEFORE
+UPDATE
+OR DELETE ON audit_log FOR EACH ROW
+EXECUTE FUNCTION audit_log_append_only ();
+
+CREATE TRIGGER audit_log_no_truncate BEFORE TRUNCATE ON audit_log FOR EACH STATEMENT
+EXECUTE FUNCTION audit_log_append_only ();
+
+ALTER TABLE audit_log ENABLE ROW LEVEL SECURITY;
+
+ALTER TABLE audit_log FORCE ROW LEVEL SECURITY;
+
+CREATE POLICY tenant_isolation ON audit_log
+USING (tenant_id = current_setting('app.tenant_id', TRUE));
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS audit_log;
+
+DROP FUNCTION IF EXISTS audit_log_append_only ();
+
+-- +goose StatementEnd
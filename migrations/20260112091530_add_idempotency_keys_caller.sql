@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Keys belong to the caller that sent them, callers of a tenant picking the
+-- same key don't see each other's records. Keys reserved before have no caller.
+ALTER TABLE idempotency_keys
+ADD COLUMN caller VARCHAR(64) NOT NULL DEFAULT '';
+
+ALTER TABLE idempotency_keys
+ALTER COLUMN caller DROP DEFAULT;
+
+ALTER TABLE idempotency_keys
+DROP CONSTRAINT idempotency_keys_pkey;
+
+ALTER TABLE idempotency_keys
+ADD PRIMARY KEY (tenant_id, caller, key);
+
+-- +goose StatementEnd
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM idempotency_keys
+WHERE caller <> '';
+
+ALTER TABLE idempotency_keys
+DROP CONSTRAINT idempotency_keys_pkey;
+
+ALTER TABLE idempotency_keys
+ADD PRIMARY KEY (tenant_id, key);
+
+ALTER TABLE idempotency_keys
+DROP COLUMN IF EXISTS caller;
+
+-- +goose StatementEnd
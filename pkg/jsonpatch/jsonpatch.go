@@ -0,0 +1,387 @@
+// Package jsonpatch applies JSON Merge Patch (RFC 7396) and JSON Patch (RFC 6902)
+// documents to JSON documents.
+package jsonpatch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvalidPatch is returned for patches that are not valid JSON or not
+	// valid patch documents.
+	ErrInvalidPatch = errors.New("invalid patch")
+	// ErrTestFailed is returned when a test operation doesn't match the document.
+	ErrTestFailed = errors.New("test operation failed")
+)
+
+// MergePatch applies the merge patch to doc: members of patch replace the ones
+// of doc, recursively for objects, and null members remove them.
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+	p, err := decode(patch)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+	return json.Marshal(mergePatch(target, p))
+}
+
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any, len(p))
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergePatch(t[k], v)
+	}
+	return t
+}
+
+type operation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Apply applies the operations of the JSON patch to doc in order. The patch is
+// applied either as a whole or not at all.
+func Apply(doc, patch []byte) ([]byte, error) {
+	var ops []operation
+	if err := json.Unmarshal(patch, &ops); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	root, err := decode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, op := range ops {
+		if root, err = apply(root, op); err != nil {
+			return nil, fmt.Errorf("operation %d: %w", i, err)
+		}
+	}
+
+	return json.Marshal(root)
+}
+
+func apply(root any, op operation) (any, error) {
+	if op.Path == nil {
+		return nil, fmt.Errorf("%w: missing path", ErrInvalidPatch)
+	}
+	path, err := parsePointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		if op.Value == nil {
+			return nil, fmt.Errorf("%w: missing value", ErrInvalidPatch)
+		}
+		value, err := decode(op.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+		}
+		switch op.Op {
+		case "add":
+			return add(root, path, value)
+		case "replace":
+			return replace(root, path, value)
+		default:
+			current, err := get(root, path)
+			if err != nil {
+				return nil, err
+			}
+			if !equal(current, value) {
+				return nil, fmt.Errorf("%w: %q", ErrTestFailed, *op.Path)
+			}
+			return root, nil
+		}
+	case "remove":
+		root, _, err := remove(root, path)
+		return root, err
+	case "move", "copy":
+		if op.From == nil {
+			return nil, fmt.Errorf("%w: missing from", ErrInvalidPatch)
+		}
+		from, err := parsePointer(*op.From)
+		if err != nil {
+			return nil, err
+		}
+		if op.Op == "copy" {
+			value, err := get(root, from)
+			if err != nil {
+				return nil, err
+			}
+			return add(root, path, clone(value))
+		}
+		if len(path) > len(from) && slices.Equal(path[:len(from)], from) {
+			return nil, fmt.Errorf("%w: cannot move %q into itself", ErrInvalidPatch, *op.From)
+		}
+		root, value, err := remove(root, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(root, path, value)
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", ErrInvalidPatch, op.Op)
+	}
+}
+
+// parsePointer splits a JSON Pointer (RFC 6901) into unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("%w: path %q must start with /", ErrInvalidPatch, pointer)
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, t := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(t, "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+// errMissing is returned by modify for paths without a value or parent, the
+// callers replace it with the full path.
+var errMissing = errors.New("missing")
+
+func notFound(path []string, err error) error {
+	if errors.Is(err, errMissing) {
+		return fmt.Errorf("path %q not found", "/"+strings.Join(path, "/"))
+	}
+	return err
+}
+
+func get(node any, path []string) (any, error) {
+	for _, key := range path {
+		switch n := node.(type) {
+		case map[string]any:
+			child, ok := n[key]
+			if !ok {
+				return nil, notFound(path, errMissing)
+			}
+			node = child
+		case []any:
+			idx, err := index(key, len(n)-1)
+			if err != nil {
+				return nil, notFound(path, errMissing)
+			}
+			node = n[idx]
+		default:
+			return nil, notFound(path, errMissing)
+		}
+	}
+	return node, nil
+}
+
+// modify calls leaf with the parent of the value at path and the last token,
+// and returns node with that parent replaced by the result of leaf.
+func modify(node any, path []string, leaf func(parent any, key string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return leaf(node, path[0])
+	}
+
+	switch n := node.(type) {
+	case map[string]any:
+		child, ok := n[path[0]]
+		if !ok {
+			return nil, errMissing
+		}
+		child, err := modify(child, path[1:], leaf)
+		if err != nil {
+			return nil, err
+		}
+		n[path[0]] = child
+		return n, nil
+	case []any:
+		idx, err := index(path[0], len(n)-1)
+		if err != nil {
+			return nil, errMissing
+		}
+		child, err := modify(n[idx], path[1:], leaf)
+		if err != nil {
+			return nil, err
+		}
+		n[idx] = child
+		return n, nil
+	default:
+		return nil, errMissing
+	}
+}
+
+func add(root any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	root, err := modify(root, path, func(parent any, key string) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			p[key] = value
+			return p, nil
+		case []any:
+			if key == "-" {
+				return append(p, value), nil
+			}
+			idx, err := index(key, len(p))
+			if err != nil {
+				return nil, errMissing
+			}
+			return slices.Insert(p, idx, value), nil
+		default:
+			return nil, errMissing
+		}
+	})
+	return root, notFound(path, err)
+}
+
+func replace(root any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+	root, err := modify(root, path, func(parent any, key string) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			if _, ok := p[key]; !ok {
+				return nil, errMissing
+			}
+			p[key] = value
+			return p, nil
+		case []any:
+			idx, err := index(key, len(p)-1)
+			if err != nil {
+				return nil, errMissing
+			}
+			p[idx] = value
+			return p, nil
+		default:
+			return nil, errMissing
+		}
+	})
+	return root, notFound(path, err)
+}
+
+func remove(root any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, nil, fmt.Errorf("%w: cannot remove the whole document", ErrInvalidPatch)
+	}
+	var removed any
+	root, err := modify(root, path, func(parent any, key string) (any, error) {
+		switch p := parent.(type) {
+		case map[string]any:
+			v, ok := p[key]
+			if !ok {
+				return nil, errMissing
+			}
+			removed = v
+			delete(p, key)
+			return p, nil
+		case []any:
+			idx, err := index(key, len(p)-1)
+			if err != nil {
+				return nil, errMissing
+			}
+			removed = p[idx]
+			return slices.Delete(p, idx, idx+1), nil
+		default:
+			return nil, errMissing
+		}
+	})
+	return root, removed, notFound(path, err)
+}
+
+// index parses an array index token, which must be within [0, last].
+func index(token string, last int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	idx, err := strconv.Atoi(token)
+	if err != nil || idx < 0 || idx > last {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+	return idx, nil
+}
+
+func decode(b []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); !errors.Is(err, io.EOF) {
+		return nil, errors.New("trailing data after JSON value")
+	}
+	return v, nil
+}
+
+func clone(v any) any {
+	switch n := v.(type) {
+	case map[string]any:
+		c := make(map[string]any, len(n))
+		for k, v := range n {
+			c[k] = clone(v)
+		}
+		return c
+	case []any:
+		c := make([]any, len(n))
+		for i, v := range n {
+			c[i] = clone(v)
+		}
+		return c
+	default:
+		return v
+	}
+}
+
+// equal compares JSON values, numbers are equal when their values are.
+func equal(a, b any) bool {
+	an, aok := a.(json.Number)
+	bn, bok := b.(json.Number)
+	if aok && bok {
+		af, aerr := an.Float64()
+		bf, berr := bn.Float64()
+		return aerr == nil && berr == nil && af == bf
+	}
+
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			if w, ok := bv[k]; !ok || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		bv, ok := b.([]any)
+		return ok && slices.EqualFunc(av, bv, equal)
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
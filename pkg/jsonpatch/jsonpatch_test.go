@@ -0,0 +1,168 @@
+package jsonpatch
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergePatch(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "Replaces and removes members",
+			doc:   `{"title":"Goodbye!","author":{"givenName":"John","familyName":"Doe"},"tags":["example","sample"],"content":"This will be unchanged"}`,
+			patch: `{"title":"Hello!","phoneNumber":"+01-123-456-7890","author":{"familyName":null},"tags":["example"]}`,
+			want:  `{"title":"Hello!","author":{"givenName":"John"},"tags":["example"],"content":"This will be unchanged","phoneNumber":"+01-123-456-7890"}`,
+		},
+		{
+			name:  "Keeps big numbers",
+			doc:   `{"price":12345678901234567890}`,
+			patch: `{"name":"x"}`,
+			want:  `{"price":12345678901234567890,"name":"x"}`,
+		},
+		{
+			name:  "Non-object patch replaces the document",
+			doc:   `{"a":"b"}`,
+			patch: `["c"]`,
+			want:  `["c"]`,
+		},
+		{
+			name:    "Malformed patch",
+			doc:     `{}`,
+			patch:   `{"a":`,
+			wantErr: ErrInvalidPatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(got))
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		doc     string
+		patch   string
+		want    string
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:  "Add object member",
+			doc:   `{"foo":"bar"}`,
+			patch: `[{"op":"add","path":"/baz","value":"qux"}]`,
+			want:  `{"baz":"qux","foo":"bar"}`,
+		},
+		{
+			name:  "Add array element",
+			doc:   `{"foo":["bar","baz"]}`,
+			patch: `[{"op":"add","path":"/foo/1","value":"qux"},{"op":"add","path":"/foo/-","value":"end"}]`,
+			want:  `{"foo":["bar","qux","baz","end"]}`,
+		},
+		{
+			name:  "Remove and replace",
+			doc:   `{"baz":"qux","foo":"bar","list":[1,2,3]}`,
+			patch: `[{"op":"remove","path":"/baz"},{"op":"replace","path":"/foo","value":null},{"op":"remove","path":"/list/0"}]`,
+			want:  `{"foo":null,"list":[2,3]}`,
+		},
+		{
+			name:  "Move and copy",
+			doc:   `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch: `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"},{"op":"copy","from":"/qux","path":"/copied"}]`,
+			want:  `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"},"copied":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			name:  "Escaped pointer",
+			doc:   `{"a/b":1,"m~n":2}`,
+			patch: `[{"op":"replace","path":"/a~1b","value":3},{"op":"test","path":"/m~0n","value":2.0}]`,
+			want:  `{"a/b":3,"m~n":2}`,
+		},
+		{
+			name:    "Test failed",
+			doc:     `{"baz":"qux"}`,
+			patch:   `[{"op":"replace","path":"/baz","value":"boo"},{"op":"test","path":"/baz","value":"qux"}]`,
+			wantErr: ErrTestFailed,
+		},
+		{
+			name:    "Replace missing member",
+			doc:     `{"foo":{}}`,
+			patch:   `[{"op":"replace","path":"/foo/bar","value":1}]`,
+			wantMsg: `operation 0: path "/foo/bar" not found`,
+		},
+		{
+			name:    "Add to missing parent",
+			doc:     `{"foo":"bar"}`,
+			patch:   `[{"op":"add","path":"/baz/bat","value":"qux"}]`,
+			wantMsg: `operation 0: path "/baz/bat" not found`,
+		},
+		{
+			name:    "Array index out of bounds",
+			doc:     `{"foo":[1]}`,
+			patch:   `[{"op":"add","path":"/foo/2","value":3}]`,
+			wantMsg: `operation 0: path "/foo/2" not found`,
+		},
+		{
+			name:    "Move into itself",
+			doc:     `{"foo":{"bar":1}}`,
+			patch:   `[{"op":"move","from":"/foo","path":"/foo/bar/baz"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "Missing value",
+			doc:     `{}`,
+			patch:   `[{"op":"add","path":"/foo"}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "Unknown op",
+			doc:     `{}`,
+			patch:   `[{"op":"merge","path":"/foo","value":1}]`,
+			wantErr: ErrInvalidPatch,
+		},
+		{
+			name:    "Not an array",
+			doc:     `{}`,
+			patch:   `{"op":"add","path":"/foo","value":1}`,
+			wantErr: ErrInvalidPatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := Apply([]byte(tt.doc), []byte(tt.patch))
+			switch {
+			case tt.wantErr != nil:
+				require.ErrorIs(t, err, tt.wantErr)
+			case tt.wantMsg != "":
+				require.EqualError(t, err, tt.wantMsg)
+			default:
+				require.NoError(t, err)
+				assert.JSONEq(t, tt.want, string(got))
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+// Package jwt verifies JSON Web Tokens (RFC 7519) signed with HS256 or RS256.
+package jwt
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidToken is returned for tokens that are malformed, have a bad
+	// signature or claims the verifier doesn't accept.
+	ErrInvalidToken = errors.New("invalid token")
+	// ErrExpired is returned for tokens used after their exp claim.
+	ErrExpired = errors.New("token is expired")
+)
+
+const (
+	algHS256 = "HS256"
+	algRS256 = "RS256"
+)
+
+// Verifier checks the signature and registered claims of tokens.
+type Verifier struct {
+	issuer   string
+	audience string
+	secret   []byte
+	rsaKeys  map[string]*rsa.PublicKey
+	leeway   time.Duration
+	now      func() time.Time
+}
+
+type Option func(*Verifier)
+
+// WithIssuer makes the verifier require tokens with the iss claim.
+func WithIssuer(iss string) Option {
+	return func(v *Verifier) {
+		v.issuer = iss
+	}
+}
+
+// WithAudience makes the verifier require tokens with aud in the aud claim.
+func WithAudience(aud string) Option {
+	return func(v *Verifier) {
+		v.audience = aud
+	}
+}
+
+// WithHMACSecret accepts HS256 tokens signed with secret.
+func WithHMACSecret(secret []byte) Option {
+	return func(v *Verifier) {
+		v.secret = secret
+	}
+}
+
+// WithRSAKeys accepts RS256 tokens signed with the keys, by their key id.
+func WithRSAKeys(keys map[string]*rsa.PublicKey) Option {
+	return func(v *Verifier) {
+		v.rsaKeys = keys
+	}
+}
+
+// WithLeeway allows for clock skew when checking exp and nbf.
+func WithLeeway(d time.Duration) Option {
+	return func(v *Verifier) {
+		v.leeway = d
+	}
+}
+
+func NewVerifier(opts ...Option) *Verifier {
+	v := &Verifier{now: time.Now}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type registeredClaims struct {
+	Issuer    string   `json:"iss"`
+	Audience  audience `json:"aud"`
+	ExpiresAt *float64 `json:"exp"`
+	NotBefore *float64 `json:"nbf"`
+}
+
+// audience is either a string or an array of strings.
+type audience []string
+
+func (a *audience) UnmarshalJSON(b []byte) error {
+	var one string
+	if err := json.Unmarshal(b, &one); err == nil {
+		*a = audience{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return err
+	}
+	*a = many
+	return nil
+}
+
+// Verify checks token and decodes its claims into claims. Tokens must have an
+// exp claim.
+func (v *Verifier) Verify(token string, claims any) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return fmt.Errorf("%w: expected 3 parts", ErrInvalidToken)
+	}
+
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return fmt.Errorf("%w: header: %v", ErrInvalidToken, err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return fmt.Errorf("%w: signature: %v", ErrInvalidToken, err)
+	}
+	if err := v.verifySignature(h, parts[0]+"."+parts[1], sig); err != nil {
+		return err
+	}
+
+	var rc registeredClaims
+	if err := decodeSegment(parts[1], &rc); err != nil {
+		return fmt.Errorf("%w: claims: %v", ErrInvalidToken, err)
+	}
+	if err := v.validate(&rc); err != nil {
+		return err
+	}
+
+	if err := decodeSegment(parts[1], claims); err != nil {
+		return fmt.Errorf("%w: claims: %v", ErrInvalidToken, err)
+	}
+	return nil
+}
+
+func (v *Verifier) verifySignature(h header, signed string, sig []byte) error {
+	switch h.Alg {
+	case algHS256:
+		if len(v.secret) == 0 {
+			return fmt.Errorf("%w: %q is not accepted", ErrInvalidToken, h.Alg)
+		}
+		mac := hmac.New(sha256.New, v.secret)
+		mac.Write([]byte(signed))
+		if !hmac.Equal(sig, mac.Sum(nil)) {
+			return fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+		}
+		return nil
+	case algRS256:
+		key, err := v.rsaKey(h.Kid)
+		if err != nil {
+			return err
+		}
+		digest := sha256.Sum256([]byte(signed))
+		if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig); err != nil {
+			return fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %q is not accepted", ErrInvalidToken, h.Alg)
+	}
+}
+
+// rsaKey returns the key with kid. Tokens without a kid may only be used when
+// there is one key.
+func (v *Verifier) rsaKey(kid string) (*rsa.PublicKey, error) {
+	if kid == "" && len(v.rsaKeys) == 1 {
+		for _, key := range v.rsaKeys {
+			return key, nil
+		}
+	}
+	key, ok := v.rsaKeys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+	}
+	return key, nil
+}
+
+func (v *Verifier) validate(rc *registeredClaims) error {
+	now := v.now()
+	if rc.ExpiresAt == nil {
+		return fmt.Errorf("%w: missing exp", ErrInvalidToken)
+	}
+	if now.After(unixTime(*rc.ExpiresAt).Add(v.leeway)) {
+		return ErrExpired
+	}
+	if rc.NotBefore != nil && now.Add(v.leeway).Before(unixTime(*rc.NotBefore)) {
+		return fmt.Errorf("%w: not valid yet", ErrInvalidToken)
+	}
+	if v.issuer != "" && rc.Issuer != v.issuer {
+		return fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, rc.Issuer)
+	}
+	if v.audience != "" && !slices.Contains(rc.Audience, v.audience) {
+		return fmt.Errorf("%w: not meant for %q", ErrInvalidToken, v.audience)
+	}
+	return nil
+}
+
+func decodeSegment(seg string, v any) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.NewDecoder(bytes.NewReader(b)).Decode(v)
+}
+
+func unixTime(seconds float64) time.Time {
+	return time.UnixMilli(int64(seconds * 1000))
+}
@@ -0,0 +1,264 @@
+package jwt
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	testSecret = []byte("0123456789abcdef0123456789abcdef")
+	testNow    = time.Date(2025, 11, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func segment(t *testing.T, v any) string {
+	t.Helper()
+	b, err := json.Marshal(v)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func signHS256(t *testing.T, secret []byte, hdr, claims map[string]any) string {
+	t.Helper()
+	signed := segment(t, hdr) + "." + segment(t, claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signed))
+	return signed + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, hdr, claims map[string]any) string {
+	t.Helper()
+	signed := segment(t, hdr) + "." + segment(t, claims)
+	digest := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	claims := func(overrides map[string]any) map[string]any {
+		c := map[string]any{
+			"sub": "user-1",
+			"iss": "https://auth.example.com",
+			"aud": "subscriptions",
+			"exp": testNow.Add(time.Hour).Unix(),
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(c, k)
+				continue
+			}
+			c[k] = v
+		}
+		return c
+	}
+	hs := map[string]any{"alg": "HS256", "typ": "JWT"}
+	rs := map[string]any{"alg": "RS256", "kid": "k1"}
+
+	tests := []struct {
+		name    string
+		token   func(t *testing.T) string
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:  "HS256",
+			token: func(t *testing.T) string { return signHS256(t, testSecret, hs, claims(nil)) },
+		},
+		{
+			name:  "RS256",
+			token: func(t *testing.T) string { return signRS256(t, rsaKey, rs, claims(nil)) },
+		},
+		{
+			name: "Audience array",
+			token: func(t *testing.T) string {
+				return signHS256(t, testSecret, hs, claims(map[string]any{"aud": []string{"billing", "subscriptions"}}))
+			},
+		},
+		{
+			name: "Expired within leeway",
+			token: func(t *testing.T) string {
+				return signHS256(t, testSecret, hs, claims(map[string]any{"exp": testNow.Add(-10 * time.Second).Unix()}))
+			},
+		},
+		{
+			name: "Expired",
+			token: func(t *testing.T) string {
+				return signHS256(t, testSecret, hs, claims(map[string]any{"exp": testNow.Add(-time.Minute).Unix()}))
+			},
+			wantErr: ErrExpired,
+		},
+		{
+			name: "Not valid yet",
+			token: func(t *testing.T) string {
+				return signHS256(t, testSecret, hs, claims(map[string]any{"nbf": testNow.Add(time.Minute).Unix()}))
+			},
+			wantMsg: "invalid token: not valid yet",
+		},
+		{
+			name:    "Missing exp",
+			token:   func(t *testing.T) string { return signHS256(t, testSecret, hs, claims(map[string]any{"exp": nil})) },
+			wantMsg: "invalid token: missing exp",
+		},
+		{
+			name: "Wrong issuer",
+			token: func(t *testing.T) string {
+				return signHS256(t, testSecret, hs, claims(map[string]any{"iss": "https://evil.example.com"}))
+			},
+			wantMsg: `invalid token: unexpected issuer "https://evil.example.com"`,
+		},
+		{
+			name: "Wrong audience",
+			token: func(t *testing.T) string {
+				return signHS256(t, testSecret, hs, claims(map[string]any{"aud": "billing"}))
+			},
+			wantMsg: `invalid token: not meant for "subscriptions"`,
+		},
+		{
+			name:    "Wrong secret",
+			token:   func(t *testing.T) string { return signHS256(t, []byte("another secret"), hs, claims(nil)) },
+			wantMsg: "invalid token: signature mismatch",
+		},
+		{
+			name:    "Wrong RSA key",
+			token:   func(t *testing.T) string { return signRS256(t, otherKey, rs, claims(nil)) },
+			wantMsg: "invalid token: signature mismatch",
+		},
+		{
+			name: "Unknown key id",
+			token: func(t *testing.T) string {
+				return signRS256(t, rsaKey, map[string]any{"alg": "RS256", "kid": "k2"}, claims(nil))
+			},
+			wantMsg: `invalid token: unknown key "k2"`,
+		},
+		{
+			name: "Unsigned",
+			token: func(t *testing.T) string {
+				return segment(t, map[string]any{"alg": "none"}) + "." + segment(t, claims(nil)) + "."
+			},
+			wantMsg: `invalid token: "none" is not accepted`,
+		},
+		{
+			name:    "Malformed",
+			token:   func(t *testing.T) string { return "not-a-token" },
+			wantMsg: "invalid token: expected 3 parts",
+		},
+	}
+
+	v := NewVerifier(
+		WithIssuer("https://auth.example.com"),
+		WithAudience("subscriptions"),
+		WithHMACSecret(testSecret),
+		WithRSAKeys(map[string]*rsa.PublicKey{"k1": &rsaKey.PublicKey}),
+		WithLeeway(30*time.Second),
+	)
+	v.now = func() time.Time { return testNow }
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var got struct {
+				Subject string `json:"sub"`
+			}
+			err := v.Verify(tt.token(t), &got)
+			switch {
+			case tt.wantErr != nil:
+				require.ErrorIs(t, err, tt.wantErr)
+			case tt.wantMsg != "":
+				require.EqualError(t, err, tt.wantMsg)
+				require.ErrorIs(t, err, ErrInvalidToken)
+			default:
+				require.NoError(t, err)
+				assert.Equal(t, "user-1", got.Subject)
+			}
+		})
+	}
+
+	t.Run("HS256 not configured", func(t *testing.T) {
+		t.Parallel()
+
+		rsOnly := NewVerifier(WithRSAKeys(map[string]*rsa.PublicKey{"k1": &rsaKey.PublicKey}))
+		rsOnly.now = func() time.Time { return testNow }
+
+		err := rsOnly.Verify(signHS256(t, nil, hs, claims(nil)), &struct{}{})
+		require.EqualError(t, err, `invalid token: "HS256" is not accepted`)
+	})
+}
+
+func TestParseJWKS(t *testing.T) {
+	t.Parallel()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	n := base64.RawURLEncoding.EncodeToString(key.N.Bytes())
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes())
+
+	tests := []struct {
+		name     string
+		data     string
+		wantKids []string
+		wantMsg  string
+	}{
+		{
+			name: "RSA signing keys",
+			data: fmt.Sprintf(`{"keys":[
+				{"kty":"RSA","kid":"k1","use":"sig","alg":"RS256","n":%q,"e":%q},
+				{"kty":"RSA","kid":"k2","n":%q,"e":%q},
+				{"kty":"RSA","kid":"enc","use":"enc","n":%q,"e":%q},
+				{"kty":"EC","kid":"ec","crv":"P-256","x":"x","y":"y"}
+			]}`, n, e, n, e, n, e),
+			wantKids: []string{"k1", "k2"},
+		},
+		{
+			name:    "No RSA keys",
+			data:    `{"keys":[{"kty":"EC","kid":"ec"}]}`,
+			wantMsg: "invalid JWK set: no RSA signing keys",
+		},
+		{
+			name:    "Repeated key id",
+			data:    fmt.Sprintf(`{"keys":[{"kty":"RSA","kid":"k1","n":%q,"e":%q},{"kty":"RSA","kid":"k1","n":%q,"e":%q}]}`, n, e, n, e),
+			wantMsg: `invalid JWK set: repeated key id "k1"`,
+		},
+		{
+			name:    "Bad modulus",
+			data:    fmt.Sprintf(`{"keys":[{"kty":"RSA","kid":"k1","n":"!","e":%q}]}`, e),
+			wantMsg: `invalid JWK "k1": modulus: illegal base64 data at input byte 0`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			keys, err := ParseJWKS([]byte(tt.data))
+			if tt.wantMsg != "" {
+				require.EqualError(t, err, tt.wantMsg)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, keys, len(tt.wantKids))
+			for _, kid := range tt.wantKids {
+				assert.True(t, key.PublicKey.Equal(keys[kid]))
+			}
+		})
+	}
+}